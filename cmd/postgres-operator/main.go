@@ -31,6 +31,7 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/upgradecheck"
 	"github.com/crunchydata/postgres-operator/internal/util"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 var versionString string
@@ -86,6 +87,15 @@ func main() {
 	err = addControllersToManager(ctx, mgr)
 	assertNoError(err)
 
+	// Serve the admission webhooks when a directory of serving certificates
+	// is provided. The API server must be configured to call them; see the
+	// "config/webhook" target.
+	if certDir := os.Getenv("PGO_WEBHOOK_CERT_DIR"); certDir != "" {
+		log.Info("admission webhooks enabled")
+		mgr.GetWebhookServer().CertDir = certDir
+		assertNoError(addWebhooksToManager(mgr))
+	}
+
 	log.Info("starting controller runtime manager and will wait for signal to exit")
 
 	// Enable upgrade checking
@@ -116,7 +126,45 @@ func addControllersToManager(ctx context.Context, mgr manager.Manager) error {
 		Tracer:      otel.Tracer(postgrescluster.ControllerName),
 		IsOpenShift: isOpenshift(ctx, mgr.GetConfig()),
 	}
-	return r.SetupWithManager(mgr)
+	if err := r.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	backupReconciler := &postgrescluster.Reconciler{
+		Client:   mgr.GetClient(),
+		Owner:    postgrescluster.BackupControllerName,
+		Recorder: mgr.GetEventRecorderFor(postgrescluster.BackupControllerName),
+		Tracer:   otel.Tracer(postgrescluster.BackupControllerName),
+	}
+	if err := backupReconciler.SetupBackupControllerWithManager(mgr); err != nil {
+		return err
+	}
+
+	restoreReconciler := &postgrescluster.Reconciler{
+		Client:   mgr.GetClient(),
+		Owner:    postgrescluster.RestoreControllerName,
+		Recorder: mgr.GetEventRecorderFor(postgrescluster.RestoreControllerName),
+		Tracer:   otel.Tracer(postgrescluster.RestoreControllerName),
+	}
+	if err := restoreReconciler.SetupRestoreControllerWithManager(mgr); err != nil {
+		return err
+	}
+
+	upgradeReconciler := &postgrescluster.Reconciler{
+		Client:   mgr.GetClient(),
+		Owner:    postgrescluster.UpgradeControllerName,
+		Recorder: mgr.GetEventRecorderFor(postgrescluster.UpgradeControllerName),
+		Tracer:   otel.Tracer(postgrescluster.UpgradeControllerName),
+	}
+	return upgradeReconciler.SetupUpgradeControllerWithManager(mgr)
+}
+
+// addWebhooksToManager adds the defaulting and validating webhooks of every
+// PostgreSQL Operator API to the provided controller runtime manager.
+func addWebhooksToManager(mgr manager.Manager) error {
+	return cruntime.NewWebhookManagedBy(mgr).
+		For(&v1beta1.PostgresCluster{}).
+		Complete()
 }
 
 func isOpenshift(ctx context.Context, cfg *rest.Config) bool {
@@ -67,6 +67,20 @@ func PGBouncerContainerImage(cluster *v1beta1.PostgresCluster) string {
 	return defaultFromEnv(image, "RELATED_IMAGE_PGBOUNCER")
 }
 
+// PGBouncerExporterContainerImage returns the container image to use for the
+// PgBouncer exporter.
+func PGBouncerExporterContainerImage(cluster *v1beta1.PostgresCluster) string {
+	var image string
+	if cluster.Spec.Proxy != nil &&
+		cluster.Spec.Proxy.PGBouncer != nil &&
+		cluster.Spec.Proxy.PGBouncer.Monitoring != nil &&
+		cluster.Spec.Proxy.PGBouncer.Monitoring.Exporter != nil {
+		image = cluster.Spec.Proxy.PGBouncer.Monitoring.Exporter.Image
+	}
+
+	return defaultFromEnv(image, "RELATED_IMAGE_PGBOUNCER_EXPORTER")
+}
+
 // PGExporterContainerImage returns the container image to use for the
 // PostgreSQL Exporter.
 func PGExporterContainerImage(cluster *v1beta1.PostgresCluster) string {
@@ -103,6 +103,24 @@ func TestPGBouncerContainerImage(t *testing.T) {
 	assert.Equal(t, PGBouncerContainerImage(cluster), "spec-image")
 }
 
+func TestPGBouncerExporterContainerImage(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+
+	unsetEnv(t, "RELATED_IMAGE_PGBOUNCER_EXPORTER")
+	assert.Equal(t, PGBouncerExporterContainerImage(cluster), "")
+
+	setEnv(t, "RELATED_IMAGE_PGBOUNCER_EXPORTER", "")
+	assert.Equal(t, PGBouncerExporterContainerImage(cluster), "")
+
+	setEnv(t, "RELATED_IMAGE_PGBOUNCER_EXPORTER", "env-var-pgbouncer-exporter")
+	assert.Equal(t, PGBouncerExporterContainerImage(cluster), "env-var-pgbouncer-exporter")
+
+	assert.NilError(t, yaml.Unmarshal([]byte(`{
+		proxy: { pgBouncer: { monitoring: { exporter: { image: spec-image } } } },
+	}`), &cluster.Spec))
+	assert.Equal(t, PGBouncerExporterContainerImage(cluster), "spec-image")
+}
+
 func TestPGExporterContainerImage(t *testing.T) {
 	cluster := &v1beta1.PostgresCluster{}
 
@@ -280,13 +280,64 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 	// determine if the user wants to initialize the PG data directory
 	postgresDataInitRequested := cluster.Spec.DataSource != nil &&
 		(cluster.Spec.DataSource.PostgresCluster != nil ||
-			cluster.Spec.DataSource.PGBackRest != nil)
+			cluster.Spec.DataSource.PGBackRest != nil ||
+			(cluster.Spec.DataSource.Volumes != nil &&
+				cluster.Spec.DataSource.Volumes.VolumeSnapshot != nil))
 
-	// determine if the user has requested an in-place restore
-	restoreID := cluster.GetAnnotations()[naming.PGBackRestRestore]
-	restoreInPlaceRequested := restoreID != "" &&
-		cluster.Spec.Backups.PGBackRest.Restore != nil &&
-		*cluster.Spec.Backups.PGBackRest.Restore.Enabled
+	// check the restore status for the ID of the restore the cluster performed last
+	var restoreStatus *v1beta1.PGBackRestJobStatus
+	var restoreIDStatus string
+	if cluster.Status.PGBackRest != nil && cluster.Status.PGBackRest.Restore != nil {
+		restoreStatus = cluster.Status.PGBackRest.Restore
+		restoreIDStatus = restoreStatus.ID
+	}
+
+	// determine if the user has requested an in-place restore. A new value for the restore
+	// annotation takes precedence over PGBackRestRestoreRequests, as does a restore that
+	// the annotation has already started.
+	restoreAnnotation := cluster.GetAnnotations()[naming.PGBackRestRestore]
+	restoreID := restoreAnnotation
+	restoreInPlaceRequested := restoreID != "" && inPlaceRestoreEnabled(cluster)
+	if restoreInPlaceRequested {
+		if restoreIDStatus == restoreAnnotation {
+			restoreInPlaceRequested = !restoreStatus.Finished
+		} else if isRestoreObjectID(restoreIDStatus) {
+			restoreInPlaceRequested =
+				restoreAnnotation != cluster.Status.PGBackRest.RestoreAnnotation
+		}
+	}
+
+	// otherwise, determine if a validated PGBackRestRestoreRequest requests an in-place
+	// restore. Once the last one finishes, its ID is kept so that neither the annotation
+	// nor the data source of the cluster is restored again.
+	var restoreObject *v1beta1.PGBackRestRestoreRequest
+	var restoreObjectFinished bool
+	if !restoreInPlaceRequested {
+		if inPlaceRestoreEnabled(cluster) {
+			if restoreObject, err = r.pendingRestoreObject(ctx, cluster); err != nil {
+				return false, err
+			}
+		}
+		switch {
+		case restoreObject != nil:
+			restoreID = restoreObjectID(restoreObject)
+			restoreInPlaceRequested = true
+		case isRestoreObjectID(restoreIDStatus) && restoreStatus.Finished:
+			restoreID = restoreIDStatus
+			restoreObjectFinished = true
+		case restoreID == restoreIDStatus:
+			// the restore requested by the annotation has finished
+			restoreInPlaceRequested = restoreID != "" && inPlaceRestoreEnabled(cluster)
+		}
+	}
+
+	// check the target of a PGBackRestRestoreRequest once more before it begins, and leave
+	// the cluster as it is when the target is no longer recoverable
+	if restoreObject != nil && restoreID != restoreIDStatus {
+		if valid, err := r.revalidateRestoreObject(ctx, cluster, restoreObject); err != nil || !valid {
+			return false, err
+		}
+	}
 
 	// Set the proper data source for the restore based on whether we're initializing the PG
 	// data directory (e.g. for a new PostgreSQL cluster), or restoring an existing cluster
@@ -294,7 +345,17 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 	// PG data initialization or an in-place restore, then simply return.
 	var dataSource *v1beta1.PostgresClusterDataSource
 	var cloudDataSource *v1beta1.PGBackRestDataSource
+	var snapshotDataSource *v1beta1.DataSourceVolumeSnapshot
 	switch {
+	case postgresUpgradeInProgress(cluster):
+		// an in-place restore waits for any major upgrade to finish
+		return false, nil
+	case restoreObjectFinished:
+		// hold the cluster down when the restore failed so that another can be requested
+		return !meta.IsStatusConditionTrue(cluster.Status.Conditions,
+			ConditionPostgresDataInitialized), nil
+	case restoreObject != nil:
+		dataSource = restoreObjectDataSource(restoreObject)
 	case restoreInPlaceRequested:
 		dataSource = cluster.Spec.Backups.PGBackRest.Restore.PostgresClusterDataSource
 	case postgresDataInitRequested:
@@ -305,6 +366,9 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 		if dataSource == nil {
 			cloudDataSource = cluster.Spec.DataSource.PGBackRest
 		}
+		if dataSource == nil && cloudDataSource == nil {
+			snapshotDataSource = cluster.Spec.DataSource.Volumes.VolumeSnapshot
+		}
 	default:
 		return false, nil
 	}
@@ -327,12 +391,6 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 		restoringInPlace &&
 		(restoreCondition.Reason == ReasonReadyForRestore)
 
-	// check the restore status to see if the ID for the restore currently being requested (as
-	// provided by the user via annotation) has changed
-	var restoreIDStatus string
-	if cluster.Status.PGBackRest != nil && cluster.Status.PGBackRest.Restore != nil {
-		restoreIDStatus = cluster.Status.PGBackRest.Restore.ID
-	}
 	restoreIDChanged := (restoreID != restoreIDStatus)
 
 	// calculate the configHash for the options in the current data source, and if an existing
@@ -345,6 +403,9 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 	case cloudDataSource != nil:
 		configs = []string{cloudDataSource.Stanza, cloudDataSource.Repo.Name}
 		configs = append(configs, cloudDataSource.Options...)
+	case snapshotDataSource != nil:
+		configs = []string{snapshotDataSource.Name, snapshotDataSource.RepoName}
+		configs = append(configs, restoreTargetOptions(snapshotDataSource.Target)...)
 	}
 	configHash, err := hashFunc(configs)
 	if err != nil {
@@ -363,8 +424,12 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 	// - The restore ID has changed (i.e. the user provide a new value for the restore
 	//   annotation, indicating they want a new in-place restore)
 	if (restoringInPlace && (!readyForRestore || configChanged)) || restoreIDChanged {
-		if err := r.prepareForRestore(ctx, cluster, observed, endpoints,
-			restoreJob, restoreID); err != nil {
+		err := r.prepareForRestore(ctx, cluster, observed, endpoints, restoreJob, restoreID)
+		if restoreObject != nil {
+			// remember the annotation so that it does not request a restore when this one is done
+			cluster.Status.PGBackRest.RestoreAnnotation = restoreAnnotation
+		}
+		if err != nil {
 			return true, err
 		}
 		// return early and don't restore (i.e. populate the data dir) until the cluster is
@@ -389,6 +454,11 @@ func (r *Reconciler) reconcileDataSource(ctx context.Context,
 			configHash, clusterVolumes); err != nil {
 			return true, err
 		}
+	case snapshotDataSource != nil:
+		if err := r.reconcileVolumeSnapshotDataSource(ctx, cluster, snapshotDataSource,
+			configHash, clusterVolumes, rootCA); err != nil {
+			return true, err
+		}
 	}
 	// return early until the PG data directory is initialized
 	return true, nil
@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -142,9 +143,9 @@ func (r *Reconciler) Reconcile(
 		return *result, nil
 	}
 
-	// Perform initial validation on a cluster
-	// TODO: Move this to a defaulting (mutating admission) webhook
-	// to leverage regular validation.
+	// Perform initial validation on a cluster. The validating webhook rejects
+	// this configuration before it is stored, but the webhook is optional so
+	// check it here as well.
 	if cluster.Spec.Standby != nil &&
 		cluster.Spec.Standby.Enabled &&
 		cluster.Spec.Standby.Host == "" &&
@@ -192,6 +193,10 @@ func (r *Reconciler) Reconcile(
 		return result, err
 	}
 
+	// Resume PgBouncer when an earlier reconcile paused it and did not resume
+	// it in time.
+	result = updateReconcileResult(result, r.reconcilePGBouncerPause(ctx, cluster))
+
 	// if the cluster is paused, set a condition and return
 	if cluster.Spec.Paused != nil && *cluster.Spec.Paused {
 		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
@@ -210,11 +215,14 @@ func (r *Reconciler) Reconcile(
 	pgHBAs := postgres.NewHBAs()
 	pgmonitor.PostgreSQLHBAs(cluster, &pgHBAs)
 	pgbouncer.PostgreSQL(cluster, &pgHBAs)
+	addPostgresUserHBAs(cluster, &pgHBAs)
+	r.addSpecifiedHBAs(cluster, &pgHBAs)
 
 	pgParameters := postgres.NewParameters()
 	pgaudit.PostgreSQLParameters(&pgParameters)
 	pgbackrest.PostgreSQL(cluster, &pgParameters)
 	pgmonitor.PostgreSQLParameters(cluster, &pgParameters)
+	r.addSpecifiedParameters(cluster, &pgParameters)
 
 	if err == nil {
 		rootCA, err = r.reconcileRootCertificate(ctx, cluster)
@@ -278,6 +286,11 @@ func (r *Reconciler) Reconcile(
 			return patchClusterStatus()
 		}
 	}
+	// A major PostgreSQL upgrade holds the cluster down while it changes the
+	// data directory, so handle it before reconciling any instances.
+	if err == nil {
+		err = updateResult(r.reconcilePostgresUpgrade(ctx, cluster, instances, clusterVolumes))
+	}
 	if err == nil {
 		clusterConfigMap, err = r.reconcileClusterConfigMap(ctx, cluster, pgHBAs, pgParameters)
 	}
@@ -309,22 +322,25 @@ func (r *Reconciler) Reconcile(
 		exporterWebConfig, err = r.reconcileExporterWebConfig(ctx, cluster)
 	}
 	if err == nil {
-		err = r.reconcileInstanceSets(
+		err = updateResult(r.reconcileInstanceSets(
 			ctx, cluster, clusterConfigMap, clusterReplicationSecret,
 			rootCA, clusterPodService, instanceServiceAccount, instances,
-			patroniLeaderService, primaryCertificate, clusterVolumes, exporterWebConfig)
+			patroniLeaderService, primaryCertificate, clusterVolumes, exporterWebConfig))
 	}
 
 	if err == nil {
 		err = r.reconcilePostgresDatabases(ctx, cluster, instances)
 	}
 	if err == nil {
-		err = r.reconcilePostgresUsers(ctx, cluster, instances)
+		err = updateResult(r.reconcilePostgresUsers(ctx, cluster, instances, rootCA))
 	}
 
 	if err == nil {
 		err = updateResult(r.reconcilePGBackRest(ctx, cluster, instances, rootCA))
 	}
+	if err == nil {
+		err = updateResult(r.reconcileVolumeSnapshots(ctx, cluster, instances, clusterVolumes))
+	}
 	if err == nil {
 		err = r.reconcilePGBouncer(ctx, cluster, instances, primaryCertificate, rootCA)
 	}
@@ -338,10 +354,17 @@ func (r *Reconciler) Reconcile(
 		err = r.reconcilePGAdmin(ctx, cluster)
 	}
 	if err == nil {
+		r.observePendingRestart(ctx, cluster, instances)
+
 		// This is after [Reconciler.rolloutInstances] to ensure that recreating
 		// Pods takes precedence.
 		err = r.handlePatroniRestarts(ctx, cluster, instances)
 	}
+	if err == nil {
+		// Periodically report the backups in each repository in the status.
+		// This is last so that slow pgBackRest commands delay nothing else.
+		result = updateReconcileResult(result, r.reconcileRepoInventory(ctx, cluster))
+	}
 
 	// at this point everything reconciled successfully, and we can update the
 	// observedGeneration
@@ -403,6 +426,21 @@ func (r *Reconciler) setOwnerReference(
 	return controllerutil.SetOwnerReference(owner, controlled, r.Client.Scheme())
 }
 
+// setStatusCondition sets condition on cluster. A False condition is also
+// recorded as a Warning event when its reason or message changes, so that an
+// unchanged problem is reported once rather than on every reconcile.
+func (r *Reconciler) setStatusCondition(
+	cluster *v1beta1.PostgresCluster, condition metav1.Condition,
+) {
+	previous := meta.FindStatusCondition(cluster.Status.Conditions, condition.Type)
+	if condition.Status == metav1.ConditionFalse && (previous == nil ||
+		previous.Status != condition.Status ||
+		previous.Reason != condition.Reason || previous.Message != condition.Message) {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch
@@ -416,6 +454,7 @@ func (r *Reconciler) setOwnerReference(
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
 
 // SetupWithManager adds the PostgresCluster controller to the provided runtime manager
 func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
@@ -442,7 +481,14 @@ func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
 		opts.MaxConcurrentReconciles = 2
 	}
 
-	return builder.ControllerManagedBy(mgr).
+	// Clones in other namespaces are found by their data source.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(),
+		&v1beta1.PostgresCluster{}, indexCloneSource, cloneSourceKeys,
+	); err != nil {
+		return err
+	}
+
+	b := builder.ControllerManagedBy(mgr).
 		For(&v1beta1.PostgresCluster{}).
 		WithOptions(opts).
 		Owns(&corev1.ConfigMap{}).
@@ -461,5 +507,30 @@ func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
 		Watches(&source.Kind{Type: &corev1.Pod{}}, r.watchPods()).
 		Watches(&source.Kind{Type: &appsv1.StatefulSet{}},
 			r.controllerRefHandlerFuncs()). // watch all StatefulSets
-		Complete(r)
+		Watches(&source.Kind{Type: &v1beta1.PGBackRestRestoreRequest{}},
+			r.watchRestoresForCluster()).
+		Watches(&source.Kind{Type: &v1beta1.PGUpgrade{}},
+			r.watchUpgradesForCluster()).
+		Watches(&source.Kind{Type: &v1beta1.PostgresCluster{}},
+			r.watchClusterForClones()).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			r.watchConfigurationForClones()).
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			r.watchConfigurationForClones()).
+		Watches(&source.Kind{Type: &batchv1.Job{}},
+			r.watchVolumeSnapshotJobs()).
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			r.watchPostgresUserPasswordSecrets())
+
+	// The autoscaling/v2 API is served by Kubernetes 1.23 and newer. Watch
+	// HorizontalPodAutoscalers only when it is available so that the
+	// controller starts on older versions.
+	if _, err := mgr.GetRESTMapper().RESTMapping(
+		autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler").GroupKind(),
+		autoscalingv2.SchemeGroupVersion.Version,
+	); err == nil {
+		b = b.Owns(&autoscalingv2.HorizontalPodAutoscaler{})
+	}
+
+	return b.Complete(r)
 }
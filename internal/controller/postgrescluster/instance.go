@@ -503,7 +503,7 @@ func (r *Reconciler) reconcileInstanceSets(
 	primaryCertificate *corev1.SecretProjection,
 	clusterVolumes []corev1.PersistentVolumeClaim,
 	exporterWebConfig *corev1.ConfigMap,
-) error {
+) (reconcile.Result, error) {
 
 	// Go through the observed instances and check if a primary has been determined.
 	// If the cluster is being shutdown and this instance is the primary, store
@@ -545,7 +545,7 @@ func (r *Reconciler) reconcileInstanceSets(
 			err = r.reconcileInstanceSetPodDisruptionBudget(ctx, cluster, set)
 		}
 		if err != nil {
-			return err
+			return reconcile.Result{}, err
 		}
 	}
 
@@ -554,13 +554,13 @@ func (r *Reconciler) reconcileInstanceSets(
 	// which instance or instance set contains the primary pod.
 	err := r.scaleDownInstances(ctx, cluster, instances)
 	if err != nil {
-		return err
+		return reconcile.Result{}, err
 	}
 
 	// Cleanup Instance Set resources that are no longer needed
 	err = r.cleanupPodDisruptionBudgets(ctx, cluster)
 	if err != nil {
-		return err
+		return reconcile.Result{}, err
 	}
 
 	// Rollout changes to instances by calling rolloutInstance.
@@ -569,7 +569,16 @@ func (r *Reconciler) reconcileInstanceSets(
 			return r.rolloutInstance(ctx, cluster, instances, instance)
 		})
 
-	return err
+	// Nothing else triggers a reconcile when replication catches up, so check
+	// on any rollout that is waiting for it.
+	result := reconcile.Result{}
+	for _, status := range cluster.Status.InstanceSets {
+		if status.Rollout != nil && status.Rollout.State == rolloutWaitingForReplication {
+			result.RequeueAfter = 10 * time.Second
+		}
+	}
+
+	return result, err
 }
 
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=list
@@ -709,12 +718,22 @@ func (r *Reconciler) rolloutInstance(
 	//
 	// NOTE(cbandy): The StatefulSet controlling this Pod reflects this change
 	// in its Status and triggers another reconcile.
-	if primary && len(instances.forCluster) > 1 {
+	//
+	// The switchover goes to a replica that is already updated, when there is
+	// one, so the primary changes only once during a rollout.
+	if primary && len(instances.forCluster) > 1 && rolloutSwitchover(instance.Spec) {
 		var span trace.Span
 		ctx, span = r.Tracer.Start(ctx, "patroni-change-primary")
 		defer span.End()
 
-		success, err := patroni.Executor(exec).ChangePrimaryAndWait(ctx, pod.Name, "")
+		// Clients of PgBouncer wait rather than fail while the primary changes.
+		resume := r.pausePGBouncer(ctx, cluster)
+
+		api := r.patroniAPI(ctx, cluster, pod, naming.ContainerDatabase)
+		success, err := api.ChangePrimaryAndWait(ctx, pod.Name,
+			rolloutCandidate(instances, instance))
+		resume()
+
 		if err = errors.WithStack(err); err == nil && !success {
 			err = errors.New("unable to switchover")
 		}
@@ -790,9 +809,47 @@ func (r *Reconciler) rolloutInstance(
 		}))
 }
 
+// The states of an instance set rollout reported in its status.
+const (
+	rolloutProgressing            = "Progressing"
+	rolloutPaused                 = "Paused"
+	rolloutWaitingForAvailability = "WaitingForAvailability"
+	rolloutWaitingForReplication  = "WaitingForReplication"
+)
+
+// rolloutCandidate returns the name of the Pod that should become primary when
+// primary is redeployed. That is an available replica that already matches its
+// PodTemplate. It returns an empty string when there is none, and Patroni
+// chooses.
+func rolloutCandidate(instances *observedInstances, primary *Instance) string {
+	var candidates []string
+	for _, instance := range instances.forCluster {
+		available, known := instance.IsAvailable()
+		matches, matchKnown := instance.PodMatchesPodTemplate()
+		if instance != primary && len(instance.Pods) == 1 &&
+			known && available && matchKnown && matches {
+			candidates = append(candidates, instance.Pods[0].Name)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+	return candidates[0]
+}
+
+// rolloutSwitchover returns whether or not the primary of set should switch
+// over before it is redeployed.
+func rolloutSwitchover(set *v1beta1.PostgresInstanceSetSpec) bool {
+	return set == nil || set.Rollout == nil || set.Rollout.SwitchoverPrimary == nil ||
+		*set.Rollout.SwitchoverPrimary
+}
+
 // rolloutInstances compares instances to cluster and calls redeploy on those
 // that need their Pod recreated. It considers the overall availability of
-// cluster and minimizes Patroni failovers.
+// cluster and minimizes Patroni failovers. The rollout strategy of each set
+// limits how many of its instances are redeployed and when. The progress of
+// each set is recorded in cluster.Status.InstanceSets.
 func (r *Reconciler) rolloutInstances(
 	ctx context.Context,
 	cluster *v1beta1.PostgresCluster,
@@ -811,6 +868,9 @@ func (r *Reconciler) rolloutInstances(
 		numSpecified += int(*set.Replicas)
 	}
 
+	// The number of instances in each set that match their PodTemplate.
+	numUpdated := map[string]int{}
+
 	for _, instance := range instances.forCluster {
 		// Skip instances that have no set in cluster spec. They should not be
 		// redeployed and should not count toward availability.
@@ -831,10 +891,12 @@ func (r *Reconciler) rolloutInstances(
 		if matches, known := instance.PodMatchesPodTemplate(); known && !matches {
 			consider = append(consider, instance)
 			continue
+		} else if known {
+			numUpdated[instance.Spec.Name]++
 		}
 	}
 
-	const maxUnavailable = 1
+	const defaultMaxUnavailable = 1
 	numUnavailable := numSpecified - numAvailable
 
 	// When multiple instances need to redeploy, sort them so the lowest
@@ -850,17 +912,95 @@ func (r *Reconciler) rolloutInstances(
 		attribute.Int("considering", len(consider)),
 	)
 
+	// Record the first decision made about each set; instances are considered
+	// from lowest to highest priority.
+	rollouts := map[string]*v1beta1.PostgresInstanceSetRolloutStatus{}
+	decide := func(set, state, message string) {
+		if rollouts[set].State == "" {
+			rollouts[set].State, rollouts[set].Message = state, message
+		}
+	}
+	for _, instance := range consider {
+		rollouts[instance.Spec.Name] = &v1beta1.PostgresInstanceSetRolloutStatus{}
+	}
+
+	// Replication lag is observed at most once, and only when a set needs it.
+	var lag map[string]int64
+	var lagErr error
+	var lagObserved bool
+
 	// Redeploy instances up to the allowed maximum while "rolling over" any
 	// unavailable instances.
 	// - https://issue.k8s.io/67250
 	for _, instance := range consider {
-		if err == nil {
-			if available, known := instance.IsAvailable(); known && !available {
-				err = redeploy(ctx, instance)
-			} else if numUnavailable < maxUnavailable {
-				err = redeploy(ctx, instance)
-				numUnavailable++
+		if err != nil {
+			break
+		}
+
+		set := instance.Spec
+		strategy := set.Rollout
+		if strategy == nil {
+			strategy = &v1beta1.PostgresInstanceRolloutStrategy{}
+		}
+
+		if strategy.PauseAfter != nil && numUpdated[set.Name] >= int(*strategy.PauseAfter) {
+			decide(set.Name, rolloutPaused, fmt.Sprintf(
+				"Paused after %d of %d instances", numUpdated[set.Name], *set.Replicas))
+			continue
+		}
+
+		if available, known := instance.IsAvailable(); known && !available {
+			err = redeploy(ctx, instance)
+			numUpdated[set.Name]++
+			decide(set.Name, rolloutProgressing, "")
+			continue
+		}
+
+		maxUnavailable := defaultMaxUnavailable
+		if strategy.MaxUnavailable != nil {
+			maxUnavailable = int(*strategy.MaxUnavailable)
+		}
+		if numUnavailable >= maxUnavailable {
+			decide(set.Name, rolloutWaitingForAvailability, fmt.Sprintf(
+				"Waiting for instances to become available: %d unavailable", numUnavailable))
+			continue
+		}
+
+		// The primary is redeployed after every other instance so that it
+		// can switch over to an updated replica.
+		if primary, known := instance.IsPrimary(); known && primary &&
+			rolloutSwitchover(set) && len(consider) > 1 {
+			decide(set.Name, rolloutProgressing,
+				"Waiting for replicas to update before the primary")
+			continue
+		}
+
+		if strategy.MaxReplicationLag != nil {
+			if !lagObserved {
+				lag, lagErr = r.observeReplicationLag(ctx, instances)
+				lagObserved = true
 			}
+			if message := replicationLagExceeds(
+				instances, lag, lagErr, strategy.MaxReplicationLag.Value(),
+			); message != "" {
+				decide(set.Name, rolloutWaitingForReplication, message)
+				continue
+			}
+		}
+
+		err = redeploy(ctx, instance)
+		numUnavailable++
+		numUpdated[set.Name]++
+		decide(set.Name, rolloutProgressing, "")
+	}
+
+	for i := range cluster.Status.InstanceSets {
+		status := &cluster.Status.InstanceSets[i]
+		status.Rollout = rollouts[status.Name]
+
+		if status.Rollout != nil && status.Rollout.Message == "" {
+			status.Rollout.Message = fmt.Sprintf(
+				"%d of %d instances updated", numUpdated[status.Name], status.Replicas)
 		}
 	}
 
@@ -868,6 +1008,58 @@ func (r *Reconciler) rolloutInstances(
 	return err
 }
 
+// observeReplicationLag returns the replication lag of each replica in bytes,
+// keyed by Pod name, as reported by the primary.
+func (r *Reconciler) observeReplicationLag(
+	ctx context.Context, instances *observedInstances,
+) (map[string]int64, error) {
+	var pod *corev1.Pod
+	for _, instance := range instances.forCluster {
+		if primary, known := instance.IsPrimary(); known && primary && len(instance.Pods) == 1 {
+			pod = instance.Pods[0]
+		}
+	}
+	if pod == nil {
+		return nil, errors.New("unable to find the primary")
+	}
+
+	return postgres.Executor(func(
+		ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(pod.Namespace, pod.Name, naming.ContainerDatabase,
+			stdin, stdout, stderr, command...)
+	}).ReplicationLag(ctx)
+}
+
+// replicationLagExceeds returns a message when any available replica is
+// further behind than maximum bytes, or when lag could not be observed. It
+// returns an empty string otherwise.
+func replicationLagExceeds(
+	instances *observedInstances, lag map[string]int64, err error, maximum int64,
+) string {
+	if err != nil {
+		return fmt.Sprintf("Unable to determine replication lag: %v", err)
+	}
+
+	var names []string
+	for _, instance := range instances.forCluster {
+		primary, _ := instance.IsPrimary()
+		available, known := instance.IsAvailable()
+		if primary || !known || !available || len(instance.Pods) != 1 {
+			continue
+		}
+		if bytes, ok := lag[instance.Pods[0].Name]; !ok || bytes > maximum {
+			names = append(names, instance.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("Waiting for replication lag to be below %d bytes on %s",
+		maximum, strings.Join(names, ", "))
+}
+
 // scaleDownInstances removes extra instances from a cluster until it matches
 // the spec. This function can delete the primary instance and force the
 // cluster to failover under two conditions:
@@ -29,6 +29,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,11 +37,13 @@ import (
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/internal/util"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestReconcilerRolloutInstance(t *testing.T) {
 	ctx := context.Background()
+	assert.NilError(t, util.AddAndSetFeatureGates(""))
 	cluster := new(v1beta1.PostgresCluster)
 
 	t.Run("Singleton", func(t *testing.T) {
@@ -652,3 +655,278 @@ func TestReconcilerRolloutInstances(t *testing.T) {
 			}))
 	})
 }
+
+func TestReconcilerRolloutInstancesStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	accumulate := func(on *[]string) func(context.Context, *Instance) error {
+		return func(_ context.Context, i *Instance) error { *on = append(*on, i.Name); return nil }
+	}
+
+	// instance returns an Instance of set with one ready Pod at revision.
+	instance := func(name string, set *v1beta1.PostgresInstanceSetSpec, revision string, primary bool) *Instance {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+				Name:      name + "-0",
+				Labels:    map[string]string{"controller-revision-hash": revision},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{
+					Type:   corev1.PodReady,
+					Status: corev1.ConditionTrue,
+				}},
+			},
+		}
+		if primary {
+			pod.Labels["postgres-operator.crunchydata.com/role"] = "master"
+		}
+		return &Instance{
+			Name: name,
+			Spec: set,
+			Pods: []*corev1.Pod{pod},
+			Runner: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 1,
+					UpdateRevision:     "gamma",
+				},
+			},
+		}
+	}
+
+	// cluster returns a PostgresCluster with one instance set and its status.
+	cluster := func(replicas int32, strategy *v1beta1.PostgresInstanceRolloutStrategy) *v1beta1.PostgresCluster {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{
+			{Name: "00", Replicas: initialize.Int32(replicas), Rollout: strategy},
+		}
+		cluster.Status.InstanceSets = []v1beta1.PostgresInstanceSetStatus{
+			{Name: "00", Replicas: replicas},
+		}
+		return cluster
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		cluster := cluster(3, nil)
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance("a", set, "beta", true),
+			instance("b", set, "beta", false),
+			instance("c", set, "gamma", false),
+		}}
+
+		var redeploys []string
+		reconciler := &Reconciler{Tracer: otel.Tracer(t.Name())}
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.DeepEqual(t, redeploys, []string{"b"})
+
+		status := cluster.Status.InstanceSets[0].Rollout
+		assert.Assert(t, status != nil)
+		assert.Equal(t, status.State, "Progressing")
+		assert.Equal(t, status.Message, "2 of 3 instances updated")
+	})
+
+	t.Run("Updated", func(t *testing.T) {
+		cluster := cluster(1, nil)
+		cluster.Status.InstanceSets[0].Rollout = &v1beta1.PostgresInstanceSetRolloutStatus{
+			State: "Paused",
+		}
+		observed := &observedInstances{forCluster: []*Instance{
+			instance("a", &cluster.Spec.InstanceSets[0], "gamma", true),
+		}}
+
+		reconciler := &Reconciler{Tracer: otel.Tracer(t.Name())}
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed,
+			func(context.Context, *Instance) error {
+				t.Fatal("expected no redeploys")
+				return nil
+			}))
+		assert.Assert(t, cluster.Status.InstanceSets[0].Rollout == nil)
+	})
+
+	t.Run("MaxUnavailable", func(t *testing.T) {
+		cluster := cluster(4, &v1beta1.PostgresInstanceRolloutStrategy{
+			MaxUnavailable: initialize.Int32(2),
+		})
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance("a", set, "beta", true),
+			instance("b", set, "beta", false),
+			instance("c", set, "beta", false),
+			instance("d", set, "beta", false),
+		}}
+
+		var redeploys []string
+		reconciler := &Reconciler{Tracer: otel.Tracer(t.Name())}
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.DeepEqual(t, redeploys, []string{"b", "c"})
+		assert.Equal(t, cluster.Status.InstanceSets[0].Rollout.State, "Progressing")
+	})
+
+	t.Run("WaitingForAvailability", func(t *testing.T) {
+		cluster := cluster(3, nil)
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance("a", set, "beta", true),
+			instance("b", set, "beta", false),
+		}}
+
+		reconciler := &Reconciler{Tracer: otel.Tracer(t.Name())}
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed,
+			func(context.Context, *Instance) error {
+				t.Fatal("expected no redeploys")
+				return nil
+			}))
+
+		status := cluster.Status.InstanceSets[0].Rollout
+		assert.Equal(t, status.State, "WaitingForAvailability")
+		assert.Assert(t, cmp.Contains(status.Message, "1 unavailable"))
+	})
+
+	t.Run("PauseAfter", func(t *testing.T) {
+		cluster := cluster(3, &v1beta1.PostgresInstanceRolloutStrategy{
+			PauseAfter: initialize.Int32(1),
+		})
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance("a", set, "beta", true),
+			instance("b", set, "beta", false),
+			instance("c", set, "beta", false),
+		}}
+
+		var redeploys []string
+		reconciler := &Reconciler{Tracer: otel.Tracer(t.Name())}
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.DeepEqual(t, redeploys, []string{"b"}) // one canary
+
+		// The canary is recreated and ready. Nothing else happens.
+		observed.forCluster[1] = instance("b", set, "gamma", false)
+		redeploys = nil
+
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Assert(t, redeploys == nil)
+
+		status := cluster.Status.InstanceSets[0].Rollout
+		assert.Equal(t, status.State, "Paused")
+		assert.Equal(t, status.Message, "Paused after 1 of 3 instances")
+
+		// Raising the limit continues the rollout.
+		set.Rollout.PauseAfter = initialize.Int32(3)
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.DeepEqual(t, redeploys, []string{"c"})
+	})
+
+	t.Run("PrimaryLast", func(t *testing.T) {
+		cluster := cluster(2, &v1beta1.PostgresInstanceRolloutStrategy{
+			MaxUnavailable: initialize.Int32(2),
+		})
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance("a", set, "beta", true),
+			instance("b", set, "beta", false),
+		}}
+
+		var redeploys []string
+		reconciler := &Reconciler{Tracer: otel.Tracer(t.Name())}
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.DeepEqual(t, redeploys, []string{"b"})
+
+		// Without a switchover, the primary need not wait.
+		set.Rollout.SwitchoverPrimary = initialize.Bool(false)
+		redeploys = nil
+
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.DeepEqual(t, redeploys, []string{"b", "a"})
+	})
+
+	t.Run("MaxReplicationLag", func(t *testing.T) {
+		cluster := cluster(3, &v1beta1.PostgresInstanceRolloutStrategy{
+			MaxReplicationLag: resource.NewQuantity(1024, resource.BinarySI),
+		})
+		set := &cluster.Spec.InstanceSets[0]
+		observed := &observedInstances{forCluster: []*Instance{
+			instance("a", set, "gamma", true),
+			instance("b", set, "gamma", false),
+			instance("c", set, "beta", false),
+		}}
+
+		var lag string
+		reconciler := &Reconciler{Tracer: otel.Tracer(t.Name())}
+		reconciler.PodExec = func(
+			namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			// Execute on the primary.
+			assert.Equal(t, namespace, "ns1")
+			assert.Equal(t, pod, "a-0")
+			assert.Equal(t, container, "database")
+			assert.Assert(t, cmp.Contains(strings.Join(command, " "), "psql"))
+
+			_, _ = stdout.Write([]byte(lag))
+			return nil
+		}
+
+		var redeploys []string
+
+		// One replica is too far behind.
+		lag = "b-0|4096\nc-0|0\n"
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Assert(t, redeploys == nil)
+
+		status := cluster.Status.InstanceSets[0].Rollout
+		assert.Equal(t, status.State, "WaitingForReplication")
+		assert.Equal(t, status.Message,
+			"Waiting for replication lag to be below 1024 bytes on b")
+
+		// One replica is not replicating.
+		lag = "c-0|0\n"
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.Assert(t, redeploys == nil)
+		assert.Equal(t, cluster.Status.InstanceSets[0].Rollout.State, "WaitingForReplication")
+
+		// Every replica is caught up.
+		lag = "b-0|512\nc-0|0\n"
+		assert.NilError(t, reconciler.rolloutInstances(ctx, cluster, observed, accumulate(&redeploys)))
+		assert.DeepEqual(t, redeploys, []string{"c"})
+		assert.Equal(t, cluster.Status.InstanceSets[0].Rollout.State, "Progressing")
+	})
+}
+
+func TestRolloutCandidate(t *testing.T) {
+	set := &v1beta1.PostgresInstanceSetSpec{Name: "00"}
+	instance := func(name, revision string, primary bool) *Instance {
+		labels := map[string]string{"controller-revision-hash": revision}
+		if primary {
+			labels["postgres-operator.crunchydata.com/role"] = "master"
+		}
+		return &Instance{
+			Name: name,
+			Spec: set,
+			Pods: []*corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{Name: name + "-0", Labels: labels},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					}},
+				},
+			}},
+			Runner: &appsv1.StatefulSet{
+				Status: appsv1.StatefulSetStatus{UpdateRevision: "gamma"},
+			},
+		}
+	}
+
+	primary := instance("a", "beta", true)
+
+	observed := &observedInstances{forCluster: []*Instance{
+		primary, instance("c", "gamma", false), instance("b", "gamma", false),
+	}}
+	assert.Equal(t, rolloutCandidate(observed, primary), "b-0")
+
+	observed = &observedInstances{forCluster: []*Instance{
+		primary, instance("b", "beta", false),
+	}}
+	assert.Equal(t, rolloutCandidate(observed, primary), "",
+		"expected no candidate when replicas are outdated")
+}
@@ -19,10 +19,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,9 +37,16 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/patroni"
 	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/internal/util"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
+const (
+	// ConditionPendingRestart is the type used in a condition to indicate
+	// whether or not PostgreSQL must restart to apply changed parameters
+	ConditionPendingRestart = "PendingRestart"
+)
+
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=deletecollection
 
 func (r *Reconciler) deletePatroniArtifacts(
@@ -58,6 +68,61 @@ func (r *Reconciler) deletePatroniArtifacts(
 	return err
 }
 
+// patroniAPI is the part of Patroni that the controller calls. Both
+// [patroni.Executor] and [patroni.Client] implement it.
+type patroniAPI interface {
+	patroni.API
+
+	FailoverAndWait(ctx context.Context, target string) (bool, error)
+	GetTimeline(ctx context.Context) (int64, error)
+	RestartPendingMembers(ctx context.Context, role, scope string) error
+	SwitchoverAndWait(ctx context.Context, target string) (bool, error)
+}
+
+// patroniAPI returns the Patroni API of pod. When the PatroniRESTAPI feature
+// gate is enabled, it calls the REST API of pod over mutual TLS using the
+// certificates of its instance. Otherwise, or when those certificates are not
+// usable, it calls "patronictl" in container of pod.
+func (r *Reconciler) patroniAPI(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, pod *corev1.Pod, container string,
+) patroniAPI {
+	exec := patroni.Executor(func(
+		ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
+	})
+
+	if !util.DefaultMutableFeatureGate.Enabled(util.PatroniRESTAPI) {
+		return exec
+	}
+
+	port := int32(8008)
+	if cluster.Spec.Patroni != nil && cluster.Spec.Patroni.Port != nil {
+		port = *cluster.Spec.Patroni.Port
+	}
+
+	// Instance Pods have stable DNS names that match their certificates.
+	// See [naming.InstancePodDNSNames].
+	certificates := &corev1.Secret{ObjectMeta: naming.InstanceCertificates(&metav1.ObjectMeta{
+		Namespace: pod.Namespace,
+		Name:      pod.Labels[naming.LabelInstance],
+	})}
+	err := errors.WithStack(
+		r.Client.Get(ctx, client.ObjectKeyFromObject(certificates), certificates))
+
+	var api *patroni.Client
+	if err == nil {
+		api, err = patroni.NewClient(fmt.Sprintf("https://%s.%s.%s.svc:%d",
+			pod.Name, pod.Spec.Subdomain, pod.Namespace, port), certificates)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error(err, "unable to call the Patroni REST API; using patronictl")
+		return exec
+	}
+
+	return api
+}
+
 func (r *Reconciler) handlePatroniRestarts(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
 ) error {
@@ -99,14 +164,9 @@ func (r *Reconciler) handlePatroniRestarts(
 	// replicas here, replicas will typically restart first because we see them
 	// first.
 	if primaryNeedsRestart != nil {
-		exec := patroni.Executor(func(
-			ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
-		) error {
-			pod := primaryNeedsRestart.Pods[0]
-			return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
-		})
+		api := r.patroniAPI(ctx, cluster, primaryNeedsRestart.Pods[0], container)
 
-		return errors.WithStack(exec.RestartPendingMembers(ctx, "master", naming.PatroniScope(cluster)))
+		return errors.WithStack(api.RestartPendingMembers(ctx, "master", naming.PatroniScope(cluster)))
 	}
 
 	// When the primary does not need to restart but a replica does, restart all
@@ -124,20 +184,81 @@ func (r *Reconciler) handlePatroniRestarts(
 	// how we decide when to restart.
 	// - https://www.postgresql.org/docs/current/runtime-config-replication.html
 	if replicaNeedsRestart != nil {
-		exec := patroni.Executor(func(
-			ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
-		) error {
-			pod := replicaNeedsRestart.Pods[0]
-			return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
-		})
+		api := r.patroniAPI(ctx, cluster, replicaNeedsRestart.Pods[0], container)
 
-		return errors.WithStack(exec.RestartPendingMembers(ctx, "replica", naming.PatroniScope(cluster)))
+		return errors.WithStack(api.RestartPendingMembers(ctx, "replica", naming.PatroniScope(cluster)))
 	}
 
 	// Nothing needs to restart.
 	return nil
 }
 
+// +kubebuilder:rbac:groups="",resources="pods/exec",verbs={create}
+
+// observePendingRestart sets the ConditionPendingRestart condition on cluster
+// according to the instances that Patroni reports need to restart. The
+// parameters waiting for that restart are read from one of them when the
+// condition does not already list them.
+func (r *Reconciler) observePendingRestart(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+) {
+	var pending []*corev1.Pod
+	for _, instance := range instances.forCluster {
+		if len(instance.Pods) > 0 && patroni.PodRequiresRestart(instance.Pods[0]) {
+			pending = append(pending, instance.Pods[0])
+		}
+	}
+
+	if len(pending) == 0 {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    ConditionPendingRestart,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoPendingRestart",
+			Message: "No instances need to restart.",
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+		return
+	}
+
+	message := fmt.Sprintf("PostgreSQL must restart on %d instance(s).", len(pending))
+	prefix := strings.TrimSuffix(message, ".") + " to apply: "
+
+	// Parameters change with the spec, so keep the ones already read while
+	// neither the spec nor the number of pending instances has changed. This
+	// avoids an exec into PostgreSQL on every reconcile.
+	previous := meta.FindStatusCondition(cluster.Status.Conditions, ConditionPendingRestart)
+	if previous != nil && previous.Status == metav1.ConditionTrue &&
+		previous.ObservedGeneration == cluster.GetGeneration() &&
+		strings.HasPrefix(previous.Message, prefix) {
+		message = previous.Message
+	} else {
+		// NOTE: The pod may stop or restart before this finishes.
+		pod := pending[0]
+		names, err := postgres.Executor(func(
+			ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			return r.PodExec(pod.Namespace, pod.Name, naming.ContainerDatabase,
+				stdin, stdout, stderr, command...)
+		}).PendingRestart(ctx)
+
+		if err != nil {
+			logging.FromContext(ctx).Error(err, "unable to read parameters pending restart")
+		} else if len(names) > 0 {
+			message = prefix + strings.Join(names, ", ") + "."
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    ConditionPendingRestart,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ParametersChanged",
+		Message: message,
+
+		ObservedGeneration: cluster.GetGeneration(),
+	})
+}
+
 // +kubebuilder:rbac:groups="",resources=services,verbs=create;patch
 
 // reconcilePatroniDistributedConfiguration sets labels and ownership on the
@@ -176,7 +297,8 @@ func (r *Reconciler) reconcilePatroniDistributedConfiguration(
 	}
 
 	// TODO(cbandy): DCS "failover_path"; `failover` and `switchover` create "{scope}-failover" endpoints.
-	// TODO(cbandy): DCS "sync_path"; `synchronous_mode` uses "{scope}-sync" endpoints.
+	// NOTE: DCS "sync_path"; `synchronous_mode` uses "{scope}-sync" endpoints.
+	// See [Reconciler.reconcilePatroniStatus].
 
 	return err
 }
@@ -212,9 +334,7 @@ func (r *Reconciler) reconcilePatroniDynamicConfiguration(
 	// NOTE(cbandy): Despite the guards above, calling PodExec may still fail
 	// due to a missing or stopped container.
 
-	exec := func(_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
-		return r.PodExec(pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
-	}
+	api := r.patroniAPI(ctx, cluster, pod, naming.ContainerDatabase)
 
 	var configuration map[string]interface{}
 	if cluster.Spec.Patroni != nil {
@@ -222,8 +342,7 @@ func (r *Reconciler) reconcilePatroniDynamicConfiguration(
 	}
 	configuration = patroni.DynamicConfiguration(cluster, configuration, pgHBAs, pgParameters)
 
-	return errors.WithStack(
-		patroni.Executor(exec).ReplaceConfiguration(ctx, configuration))
+	return errors.WithStack(api.ReplaceConfiguration(ctx, configuration))
 }
 
 // generatePatroniLeaderLeaseService returns a v1.Service that exposes the
@@ -351,6 +470,22 @@ func (r *Reconciler) reconcilePatroniStatus(
 		}
 	}
 
+	// When synchronous mode is on, Patroni records the leader and its
+	// synchronous standbys in DCS.
+	// - https://github.com/zalando/patroni/blob/v2.1.3/patroni/dcs/kubernetes.py#L1154
+	sync := &corev1.Endpoints{ObjectMeta: naming.PatroniSync(cluster)}
+	if err == nil {
+		err = errors.WithStack(client.IgnoreNotFound(
+			r.Client.Get(ctx, client.ObjectKeyFromObject(sync), sync)))
+	}
+	if err == nil {
+		cluster.Status.Patroni.SynchronousStandbys = nil
+		if standbys := sync.Annotations["sync_standby"]; standbys != "" {
+			cluster.Status.Patroni.SynchronousStandbys = strings.Split(standbys, ",")
+			sort.Strings(cluster.Status.Patroni.SynchronousStandbys)
+		}
+	}
+
 	return result, err
 }
 
@@ -533,18 +668,14 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 	if runningPod == nil {
 		return errors.New("Could not find a running pod when attempting switchover.")
 	}
-	exec := func(_ context.Context, stdin io.Reader, stdout, stderr io.Writer,
-		command ...string) error {
-		return r.PodExec(runningPod.Namespace, runningPod.Name, naming.ContainerDatabase, stdin,
-			stdout, stderr, command...)
-	}
+	api := r.patroniAPI(ctx, cluster, runningPod, naming.ContainerDatabase)
 
 	// To ensure idempotency, the operator verifies that the timeline reported by Patroni
 	// matches the timeline that was present when the switchover was first requested.
 	// TODO(benjb): consider pulling the timeline from the pod annotation; manual experiments
 	// have shown that the annotation on the Leader pod is up to date during a switchover, but
 	// missing from the Replica pods.
-	timeline, err := patroni.Executor(exec).GetTimeline(ctx)
+	timeline, err := api.GetTimeline(ctx)
 
 	if err != nil {
 		return err
@@ -577,29 +708,33 @@ func (r *Reconciler) reconcilePatroniSwitchover(ctx context.Context,
 		return nil
 	}
 
-	// We have the pod executor, now we need to figure out which API call to use
-	// In the default case we will be using SwitchoverAndWait. This API call uses
-	// a Patronictl switchover to move to the target instance.
-	action := func(ctx context.Context, exec patroni.Executor, next string) (bool, error) {
-		success, err := exec.SwitchoverAndWait(ctx, next)
+	// We have the Patroni API, now we need to figure out which call to use.
+	// In the default case we will be using SwitchoverAndWait. This call uses
+	// a switchover to move to the target instance.
+	action := func(ctx context.Context, api patroniAPI, next string) (bool, error) {
+		success, err := api.SwitchoverAndWait(ctx, next)
 		return success, errors.WithStack(err)
 	}
 
 	if spec.Type == v1beta1.PatroniSwitchoverTypeFailover {
 		// When a failover has been requested we use FailoverAndWait to change the primary.
-		action = func(ctx context.Context, exec patroni.Executor, next string) (bool, error) {
-			success, err := exec.FailoverAndWait(ctx, next)
+		action = func(ctx context.Context, api patroniAPI, next string) (bool, error) {
+			success, err := api.FailoverAndWait(ctx, next)
 			return success, errors.WithStack(err)
 		}
 	}
 
-	// If target instance has not been provided, we will pass in an empty string to patronictl
+	// If target instance has not been provided, we will pass in an empty string to Patroni
 	nextPrimary := ""
 	if targetInstance != nil {
 		nextPrimary = targetInstance.Pods[0].Name
 	}
 
-	success, err := action(ctx, exec, nextPrimary)
+	// Clients of PgBouncer wait rather than fail while the primary changes.
+	resume := r.pausePGBouncer(ctx, cluster)
+	success, err := action(ctx, api, nextPrimary)
+	resume()
+
 	if err = errors.WithStack(err); err == nil && !success {
 		err = errors.New("unable to switchover")
 	}
@@ -33,15 +33,20 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
+	"github.com/crunchydata/postgres-operator/internal/util"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
@@ -527,9 +532,153 @@ func TestReconcilePatroniStatus(t *testing.T) {
 	}
 }
 
+func TestReconcilePatroniStatusSynchronous(t *testing.T) {
+	ctx := context.Background()
+	_, tClient := setupKubernetes(t)
+	require.ParallelCapacity(t, 0)
+
+	ns := setupNamespace(t, tClient)
+	r := &Reconciler{Client: tClient, Owner: client.FieldOwner(t.Name())}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = ns.Name, "sync-status"
+	cluster.Status.Patroni.SynchronousStandbys = []string{"stale"}
+
+	// No synchronous standbys when Patroni has not recorded any.
+	_, err := r.reconcilePatroniStatus(ctx, cluster, &observedInstances{})
+	assert.NilError(t, err)
+	assert.Assert(t, cluster.Status.Patroni.SynchronousStandbys == nil)
+
+	sync := &corev1.Endpoints{ObjectMeta: naming.PatroniSync(cluster)}
+	sync.Annotations = map[string]string{
+		"leader":       "sync-status-00-abcd-0",
+		"sync_standby": "sync-status-01-wxyz-0,sync-status-00-efgh-0",
+	}
+	assert.NilError(t, tClient.Create(ctx, sync))
+
+	_, err = r.reconcilePatroniStatus(ctx, cluster, &observedInstances{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cluster.Status.Patroni.SynchronousStandbys,
+		[]string{"sync-status-00-efgh-0", "sync-status-01-wxyz-0"})
+}
+
+func TestObservePendingRestart(t *testing.T) {
+	ctx := context.Background()
+	cluster := &v1beta1.PostgresCluster{}
+
+	pod := func(name, status string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1", Name: name,
+			Annotations: map[string]string{"status": status},
+		}}
+	}
+
+	t.Run("None", func(t *testing.T) {
+		r := &Reconciler{PodExec: func(
+			string, string, string, io.Reader, io.Writer, io.Writer, ...string,
+		) error {
+			t.Fatal("expected no exec")
+			return nil
+		}}
+		instances := &observedInstances{forCluster: []*Instance{
+			{Pods: []*corev1.Pod{pod("one", `{"role":"master"}`)}},
+		}}
+
+		r.observePendingRestart(ctx, cluster, instances)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionPendingRestart)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		r := &Reconciler{PodExec: func(
+			namespace, pod, container string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			assert.Equal(t, namespace, "ns1")
+			assert.Equal(t, pod, "two")
+			assert.Equal(t, container, "database")
+
+			_, _ = stdout.Write([]byte("max_connections\nshared_buffers\n"))
+			return nil
+		}}
+		instances := &observedInstances{forCluster: []*Instance{
+			{Pods: []*corev1.Pod{pod("one", `{"role":"master"}`)}},
+			{Pods: []*corev1.Pod{pod("two", `{"role":"replica","pending_restart":true}`)}},
+		}}
+
+		r.observePendingRestart(ctx, cluster, instances)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionPendingRestart)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Equal(t, condition.Reason, "ParametersChanged")
+		assert.Equal(t, condition.Message,
+			"PostgreSQL must restart on 1 instance(s) to apply: max_connections, shared_buffers.")
+
+		t.Run("Unchanged", func(t *testing.T) {
+			r := &Reconciler{PodExec: func(
+				string, string, string, io.Reader, io.Writer, io.Writer, ...string,
+			) error {
+				t.Fatal("expected no exec")
+				return nil
+			}}
+
+			r.observePendingRestart(ctx, cluster, instances)
+
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionPendingRestart)
+			assert.Assert(t, condition != nil)
+			assert.Equal(t, condition.Message,
+				"PostgreSQL must restart on 1 instance(s) to apply: max_connections, shared_buffers.")
+		})
+
+		t.Run("Changed", func(t *testing.T) {
+			var called bool
+			r := &Reconciler{PodExec: func(
+				_, _, _ string, _ io.Reader, stdout, _ io.Writer, _ ...string,
+			) error {
+				called = true
+				_, _ = stdout.Write([]byte("work_mem\n"))
+				return nil
+			}}
+
+			cluster := cluster.DeepCopy()
+			cluster.Generation++
+
+			r.observePendingRestart(ctx, cluster, instances)
+			assert.Assert(t, called, "expected exec after the spec changed")
+
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionPendingRestart)
+			assert.Assert(t, condition != nil)
+			assert.Equal(t, condition.Message,
+				"PostgreSQL must restart on 1 instance(s) to apply: work_mem.")
+		})
+	})
+
+	t.Run("ExecError", func(t *testing.T) {
+		cluster := &v1beta1.PostgresCluster{}
+		r := &Reconciler{PodExec: func(
+			string, string, string, io.Reader, io.Writer, io.Writer, ...string,
+		) error {
+			return errors.New("boom")
+		}}
+		instances := &observedInstances{forCluster: []*Instance{
+			{Pods: []*corev1.Pod{pod("one", `{"role":"master","pending_restart":true}`)}},
+		}}
+
+		r.observePendingRestart(ctx, cluster, instances)
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionPendingRestart)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionTrue)
+		assert.Equal(t, condition.Message, "PostgreSQL must restart on 1 instance(s).")
+	})
+}
+
 func TestReconcilePatroniSwitchover(t *testing.T) {
 	_, client := setupKubernetes(t)
 	require.ParallelCapacity(t, 0)
+	assert.NilError(t, util.AddAndSetFeatureGates(""))
 
 	var called, failover, callError, callFails bool
 	var timelineCallNoLeader, timelineCall bool
@@ -1005,3 +1154,51 @@ func TestReconcilePatroniSwitchover(t *testing.T) {
 		assert.Assert(t, cluster.Status.Patroni.SwitchoverTimeline == nil)
 	})
 }
+
+func TestReconcilerPatroniAPI(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { assert.NilError(t, util.AddAndSetFeatureGates("")) })
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.Patroni = &v1beta1.PatroniSpec{Port: initialize.Int32(9999)}
+
+	pod := new(corev1.Pod)
+	pod.Namespace, pod.Name = "ns1", "some-instance-0"
+	pod.Labels = map[string]string{naming.LabelInstance: "some-instance"}
+	pod.Spec.Subdomain = "some-pods"
+
+	root, err := pki.NewRootCertificateAuthority()
+	assert.NilError(t, err)
+	leaf, err := root.GenerateLeafCertificate("", nil)
+	assert.NilError(t, err)
+
+	certificates := &corev1.Secret{ObjectMeta: naming.InstanceCertificates(
+		&metav1.ObjectMeta{Namespace: "ns1", Name: "some-instance"})}
+	assert.NilError(t, patroni.InstanceCertificates(ctx,
+		root.Certificate, leaf.Certificate, leaf.PrivateKey, certificates))
+
+	t.Run("Disabled", func(t *testing.T) {
+		assert.NilError(t, util.AddAndSetFeatureGates(""))
+
+		r := &Reconciler{Client: fake.NewClientBuilder().WithObjects(certificates).Build()}
+		_, ok := r.patroniAPI(ctx, cluster, pod, "database").(patroni.Executor)
+		assert.Assert(t, ok, "expected patronictl")
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		assert.NilError(t, util.AddAndSetFeatureGates(string(util.PatroniRESTAPI+"=true")))
+
+		r := &Reconciler{Client: fake.NewClientBuilder().WithObjects(certificates).Build()}
+		api, ok := r.patroniAPI(ctx, cluster, pod, "database").(*patroni.Client)
+		assert.Assert(t, ok, "expected REST API")
+		assert.Equal(t, api.URL, "https://some-instance-0.some-pods.ns1.svc:9999")
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		assert.NilError(t, util.AddAndSetFeatureGates(string(util.PatroniRESTAPI+"=true")))
+
+		r := &Reconciler{Client: fake.NewClientBuilder().Build()}
+		_, ok := r.patroniAPI(ctx, cluster, pod, "database").(patroni.Executor)
+		assert.Assert(t, ok, "expected patronictl when certificates are missing")
+	})
+}
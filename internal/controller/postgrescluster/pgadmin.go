@@ -444,12 +444,14 @@ func (r *Reconciler) reconcilePGAdminUsers(
 	// Calculate a hash of the commands that should be executed in pgAdmin.
 
 	passwords := make(map[string]string, len(userSecrets))
+	logins := make(map[string]string, len(userSecrets))
 	for userName := range userSecrets {
 		passwords[userName] = string(userSecrets[userName].Data["password"])
+		logins[userName] = string(userSecrets[userName].Data["user"])
 	}
 
 	write := func(ctx context.Context, exec pgadmin.Executor) error {
-		return pgadmin.WriteUsersInPGAdmin(ctx, cluster, exec, specUsers, passwords)
+		return pgadmin.WriteUsersInPGAdmin(ctx, cluster, exec, specUsers, passwords, logins)
 	}
 
 	revision, err := safeHash32(func(hasher io.Writer) error {
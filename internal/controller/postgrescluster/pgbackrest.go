@@ -31,11 +31,13 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -74,6 +76,14 @@ const (
 	// pgBackRest repository host PostgresCluster is ready
 	ConditionRepoHostReady = "PGBackRestRepoHostReady"
 
+	// ConditionBackupsVerified is the type used in a condition to indicate whether or not the
+	// most recent verification of each repository with a "verify" schedule passed
+	ConditionBackupsVerified = "PGBackRestBackupsVerified"
+
+	// ConditionRepoVolumeCapacity is the type used in a condition to indicate whether or not
+	// all pgBackRest repository volumes are below their usage thresholds
+	ConditionRepoVolumeCapacity = "PGBackRestRepoVolumeCapacity"
+
 	// ConditionPGBackRestRestoreProgressing is the type used in a condition to indicate that
 	// and in-place pgBackRest restore is in progress
 	ConditionPGBackRestRestoreProgressing = "PGBackRestoreProgressing"
@@ -98,6 +108,10 @@ const (
 	// CronJob fails to create successfully
 	EventUnableToCreatePGBackRestCronJob = "UnableToCreatePGBackRestCronJob"
 
+	// EventRepoVolumeThresholdExceeded is the event reason utilized when the space used on a
+	// pgBackRest repository volume crosses its usage threshold
+	EventRepoVolumeThresholdExceeded = "RepoVolumeThresholdExceeded"
+
 	// ReasonReadyForRestore is the reason utilized within ConditionPGBackRestRestoreProgressing
 	// to indicate that the restore Job can proceed because the cluster is now ready to be
 	// restored (i.e. it has been properly prepared for a restore).
@@ -111,6 +125,19 @@ const (
 	incremental  = "incr"
 )
 
+// scheduled maintenance types
+const (
+	// expire is the type of the CronJob that expires backups according to retention
+	expire = "expire"
+
+	// verify is the type of the CronJob that test-restores the latest backup
+	verify = "verify"
+)
+
+// defaultRepoVolumeUsageThreshold is the percentage of a repository volume that may
+// be used when the spec does not say otherwise
+const defaultRepoVolumeUsageThreshold = 80
+
 // regexRepoIndex is the regex used to obtain the repo index from a pgBackRest repo name
 var regexRepoIndex = regexp.MustCompile(`\d+`)
 
@@ -120,6 +147,7 @@ type RepoResources struct {
 	cronjobs                []*batchv1.CronJob
 	manualBackupJobs        []*batchv1.Job
 	replicaCreateBackupJobs []*batchv1.Job
+	verifyJobs              []*batchv1.Job
 	hosts                   []*appsv1.StatefulSet
 	pvcs                    []*corev1.PersistentVolumeClaim
 }
@@ -350,6 +378,10 @@ func backupScheduleFound(repo v1beta1.PGBackRestRepo, backupType string) bool {
 			return repo.BackupSchedules.Differential != nil
 		case incremental:
 			return repo.BackupSchedules.Incremental != nil
+		case expire:
+			return repo.BackupSchedules.Expire != nil
+		case verify:
+			return repo.BackupSchedules.Verify != nil
 		default:
 			return false
 		}
@@ -379,6 +411,10 @@ func unstructuredToRepoResources(kind string, repoResources *RepoResources,
 				repoResources.manualBackupJobs =
 					append(repoResources.manualBackupJobs, &jobList.Items[i])
 			}
+			// and the Jobs of verify schedules
+			if job.GetLabels()[naming.LabelPGBackRestCronJob] == verify {
+				repoResources.verifyJobs = append(repoResources.verifyJobs, &jobList.Items[i])
+			}
 		}
 	case "PersistentVolumeClaimList":
 		var pvcList corev1.PersistentVolumeClaimList
@@ -665,6 +701,15 @@ func (r *Reconciler) generateRepoVolumeIntent(postgresCluster *v1beta1.PostgresC
 func generateBackupJobSpecIntent(postgresCluster *v1beta1.PostgresCluster,
 	repo v1beta1.PGBackRestRepo, serviceAccountName string,
 	labels, annotations map[string]string, opts ...string) (*batchv1.JobSpec, error) {
+	return generatePGBackRestJobSpecIntent(postgresCluster, repo, "backup",
+		serviceAccountName, labels, annotations, opts...)
+}
+
+// generatePGBackRestJobSpecIntent generates a JobSpec that runs the pgBackRest command against
+// repo, e.g. "backup" or "expire"
+func generatePGBackRestJobSpecIntent(postgresCluster *v1beta1.PostgresCluster,
+	repo v1beta1.PGBackRestRepo, command, serviceAccountName string,
+	labels, annotations map[string]string, opts ...string) (*batchv1.JobSpec, error) {
 
 	selector, containerName, err := getPGBackRestExecSelector(postgresCluster, repo)
 	if err != nil {
@@ -681,7 +726,7 @@ func generateBackupJobSpecIntent(postgresCluster *v1beta1.PostgresCluster,
 	container := corev1.Container{
 		Command: []string{"/opt/crunchy/bin/pgbackrest"},
 		Env: []corev1.EnvVar{
-			{Name: "COMMAND", Value: "backup"},
+			{Name: "COMMAND", Value: command},
 			{Name: "COMMAND_OPTS", Value: strings.Join(cmdOpts, " ")},
 			{Name: "COMPARE_HASH", Value: "true"},
 			{Name: "CONTAINER", Value: containerName},
@@ -821,6 +866,13 @@ func (r *Reconciler) observeRestoreEnv(ctx context.Context,
 		// update the data source initialized condition if the Job has finished running, and is
 		// therefore in a completed or failed
 		if completed {
+			// Server connections of PgBouncer were to the PostgreSQL that was
+			// replaced. Replace them once when the restore completes.
+			if !meta.IsStatusConditionTrue(cluster.Status.Conditions,
+				ConditionPostgresDataInitialized) {
+				r.reconnectPGBouncer(ctx, cluster)
+			}
+
 			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
 				ObservedGeneration: cluster.GetGeneration(),
 				Type:               ConditionPostgresDataInitialized,
@@ -1043,7 +1095,7 @@ func (r *Reconciler) reconcileRestoreJob(ctx context.Context,
 	var deltaOptFound, foundTarget bool
 	for _, opt := range opts {
 		switch {
-		case strings.Contains(opt, "--target"):
+		case strings.Contains(opt, "--target"), strings.Contains(opt, "--type=immediate"):
 			foundTarget = true
 		case strings.Contains(opt, "--delta"):
 			deltaOptFound = true
@@ -1054,7 +1106,8 @@ func (r *Reconciler) reconcileRestoreJob(ctx context.Context,
 	}
 
 	// Note on the pgBackRest option `--target-action` in the restore job:
-	// (a) `--target-action` is only allowed if `--target` and `type` are set;
+	// (a) `--target-action` is only allowed if `--target` and `type` are set, or
+	// if `type` is `immediate`;
 	// TODO(benjaminjb): ensure that `type` is set as well before accepting `target-action`
 	// (b) our restore job assumes the `hot_standby: on` default, which is true of Postgres >= 10;
 	// (c) pgBackRest passes the `--target-action` setting as `recovery-target-action`
@@ -1080,6 +1133,19 @@ func (r *Reconciler) reconcileRestoreJob(ctx context.Context,
 	// to do any escaping or use eval.
 	cmd := pgbackrest.RestoreCommand(pgdata, strings.Join(opts, " "))
 
+	return r.applyRestoreJob(ctx, cluster, sourceCluster, pgdataVolume, pgwalVolume,
+		dataSource, instanceName, configHash, cmd)
+}
+
+// applyRestoreJob applies the Job that runs cmd to populate the PGDATA directory of a
+// restored or bootstrapped instance. The Job has the pgBackRest configuration of
+// sourceCluster.
+func (r *Reconciler) applyRestoreJob(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, sourceCluster *v1beta1.PostgresCluster,
+	pgdataVolume, pgwalVolume *corev1.PersistentVolumeClaim,
+	dataSource *v1beta1.PostgresClusterDataSource,
+	instanceName, configHash string, cmd []string) error {
+
 	// create the volume resources required for the postgres data directory
 	dataVolumeMount := postgres.DataVolumeMount()
 	dataVolume := corev1.Volume{
@@ -1206,6 +1272,159 @@ func (r *Reconciler) generateRestoreJobIntent(cluster *v1beta1.PostgresCluster,
 	return nil
 }
 
+// generateVerifyJobSpecIntent generates a JobSpec that restores the latest backup in repo into
+// a temporary volume, starts PostgreSQL, and checks the restored data.
+func (r *Reconciler) generateVerifyJobSpecIntent(cluster *v1beta1.PostgresCluster,
+	repo v1beta1.PGBackRestRepo, labels, annotations map[string]string,
+) (*batchv1.JobSpec, error) {
+	verification := cluster.Spec.Backups.PGBackRest.Verification
+	if verification == nil {
+		verification = &v1beta1.PGBackRestVerification{}
+	}
+
+	// Stop recovery as soon as the backup is consistent.
+	pgdata := postgres.DataDirectory(cluster)
+	opts := []string{
+		"--stanza=" + pgbackrest.DefaultStanzaName,
+		"--pg1-path=" + pgdata,
+		"--repo=" + regexRepoIndex.FindString(repo.Name),
+		"--type=immediate",
+		"--target-action=promote",
+	}
+	cmd := pgbackrest.VerifyCommand(pgdata, strings.Join(opts, " "), verification.SQL)
+
+	// The restored data lives only as long as the Pod. A PersistentVolumeClaim
+	// spec becomes an ephemeral volume that is deleted along with the Pod.
+	// - https://docs.k8s.io/concepts/storage/ephemeral-volumes/#generic-ephemeral-volumes
+	dataVolumeMount := postgres.DataVolumeMount()
+	dataVolume := corev1.Volume{
+		Name: dataVolumeMount.Name,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	if verification.VolumeClaimSpec != nil {
+		dataVolume.VolumeSource = corev1.VolumeSource{
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels, Annotations: annotations},
+					Spec:       *verification.VolumeClaimSpec,
+				},
+			},
+		}
+	}
+
+	// schedule verification like other backup Jobs
+	jobOptions := &v1beta1.PostgresClusterDataSource{Resources: verification.Resources}
+	if jobs := cluster.Spec.Backups.PGBackRest.Jobs; jobs != nil {
+		jobOptions.Affinity = jobs.Affinity
+		jobOptions.PriorityClassName = jobs.PriorityClassName
+		jobOptions.Tolerations = jobs.Tolerations
+	}
+
+	job := &batchv1.Job{}
+	if err := r.generateRestoreJobIntent(cluster, "", "", cmd,
+		[]corev1.VolumeMount{dataVolumeMount}, []corev1.Volume{dataVolume},
+		jobOptions, job); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// replace the labels of a restore with those of the CronJob
+	job.Spec.Template.ObjectMeta = metav1.ObjectMeta{Labels: labels, Annotations: annotations}
+
+	pgbackrest.AddConfigToRestorePod(cluster, nil, &job.Spec.Template.Spec)
+	addNSSWrapper(
+		config.PGBackRestContainerImage(cluster),
+		cluster.Spec.ImagePullPolicy,
+		&job.Spec.Template)
+	addTMPEmptyDir(&job.Spec.Template)
+
+	return &job.Spec, nil
+}
+
+// setRepoVerificationStatus records the outcome of the most recent verify Job of each repository
+// in its status, and then summarizes those outcomes in the ConditionBackupsVerified condition.
+func setRepoVerificationStatus(postgresCluster *v1beta1.PostgresCluster, jobs []*batchv1.Job) {
+	var passed, failed []string
+
+	for i := range postgresCluster.Status.PGBackRest.Repos {
+		repoStatus := &postgresCluster.Status.PGBackRest.Repos[i]
+
+		scheduled := false
+		for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+			if repo.Name == repoStatus.Name {
+				scheduled = backupScheduleFound(repo, verify)
+			}
+		}
+		if !scheduled {
+			repoStatus.Verification = nil
+			continue
+		}
+
+		var latest *batchv1.Job
+		for _, job := range jobs {
+			if job.GetLabels()[naming.LabelPGBackRestRepo] == repoStatus.Name &&
+				(latest == nil || latest.CreationTimestamp.Before(&job.CreationTimestamp)) {
+				latest = job
+			}
+		}
+		if latest != nil {
+			status := repoStatus.Verification
+			if status == nil {
+				status = &v1beta1.RepoVerificationStatus{}
+			}
+			status.JobName = latest.Name
+			status.StartTime = latest.Status.StartTime.DeepCopy()
+			status.CompletionTime = nil
+			status.Finished = false
+			status.Passed = false
+
+			for _, condition := range latest.Status.Conditions {
+				if condition.Status != corev1.ConditionTrue {
+					continue
+				}
+				switch condition.Type {
+				case batchv1.JobComplete:
+					status.Finished, status.Passed = true, true
+					status.CompletionTime = latest.Status.CompletionTime.DeepCopy()
+					status.LastPassedTime = latest.Status.CompletionTime.DeepCopy()
+				case batchv1.JobFailed:
+					status.Finished = true
+					status.CompletionTime = condition.LastTransitionTime.DeepCopy()
+				}
+			}
+			repoStatus.Verification = status
+		}
+
+		if v := repoStatus.Verification; v != nil && v.Finished {
+			if v.Passed {
+				passed = append(passed, repoStatus.Name)
+			} else {
+				failed = append(failed, repoStatus.Name)
+			}
+		}
+	}
+
+	if len(passed) == 0 && len(failed) == 0 {
+		meta.RemoveStatusCondition(&postgresCluster.Status.Conditions, ConditionBackupsVerified)
+		return
+	}
+
+	condition := metav1.Condition{
+		ObservedGeneration: postgresCluster.GetGeneration(),
+		Type:               ConditionBackupsVerified,
+		Status:             metav1.ConditionTrue,
+		Reason:             "VerificationPassed",
+		Message:            "Latest verification passed for " + strings.Join(passed, ", "),
+	}
+	if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "VerificationFailed"
+		condition.Message = "Latest verification failed for " + strings.Join(failed, ", ")
+	}
+	meta.SetStatusCondition(&postgresCluster.Status.Conditions, condition)
+}
+
 // reconcilePGBackRest is responsible for reconciling any/all pgBackRest resources owned by a
 // specific PostgresCluster (e.g. Deployments, ConfigMaps, Secrets, etc.).  This function will
 // ensure various reconciliation logic is run as needed for each pgBackRest resource, while then
@@ -1335,20 +1554,27 @@ func (r *Reconciler) reconcilePGBackRest(ctx context.Context,
 		result = updateReconcileResult(result, reconcile.Result{RequeueAfter: 10 * time.Second})
 	}
 
+	// record the outcome of the Jobs created by verify schedules
+	setRepoVerificationStatus(postgresCluster, repoResources.verifyJobs)
+
 	// Reconcile the initial backup that is needed to enable replica creation using pgBackRest.
 	// This is done once stanza creation is successful
-	if err := r.reconcileReplicaCreateBackup(ctx, postgresCluster, instances,
+	if backupResult, err := r.reconcileReplicaCreateBackup(ctx, postgresCluster, instances,
 		repoResources.replicaCreateBackupJobs, sa, configHash, replicaCreateRepo); err != nil {
 		log.Error(err, "unable to reconcile replica creation backup")
 		result = updateReconcileResult(result, reconcile.Result{Requeue: true})
+	} else {
+		result = updateReconcileResult(result, backupResult)
 	}
 
 	// Reconcile a manual backup as defined in the spec, and triggered by the end-user via
 	// annotation
-	if err := r.reconcileManualBackup(ctx, postgresCluster, repoResources.manualBackupJobs,
-		sa, instances); err != nil {
+	if backupResult, err := r.reconcileManualBackup(ctx, postgresCluster,
+		repoResources.manualBackupJobs, sa, instances); err != nil {
 		log.Error(err, "unable to reconcile manual backup")
 		result = updateReconcileResult(result, reconcile.Result{Requeue: true})
+	} else {
+		result = updateReconcileResult(result, backupResult)
 	}
 
 	return result, nil
@@ -1459,31 +1685,58 @@ func (r *Reconciler) reconcilePostgresClusterDataSource(ctx context.Context,
 					"PostgresCluster %q does not exist", sourceClusterName)
 				return nil
 			}
+			if apierrors.IsForbidden(err) {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidDataSource",
+					"Unable to read PostgresCluster %q in namespace %q: %v",
+					sourceClusterName, sourceClusterNamespace, err)
+				return nil
+			}
 			return errors.WithStack(err)
 		}
 
+		// A cluster in another namespace must opt in to being cloned. This is
+		// checked on every reconcile so that revoking it stops the copies below.
+		if !cloneAllowed(sourceCluster, cluster.GetNamespace()) {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "CloneNotPermitted",
+				"PostgresCluster %q in namespace %q does not allow clones in namespace %q; "+
+					"see the %q annotation", sourceClusterName, sourceClusterNamespace,
+				cluster.GetNamespace(), naming.PGBackRestCloneNamespaces)
+			return nil
+		}
+
 		// Copy repository definitions and credentials from the source cluster.
 		// A copy is the only way to get this information across namespaces.
+		// This happens on every reconcile until the cluster is bootstrapped so
+		// that changes to the source, e.g. rotated credentials, are picked up.
 		if err := r.copyRestoreConfiguration(ctx, cluster, sourceCluster); err != nil {
 			return err
 		}
 	}
 
 	// verify the repo defined in the data source exists in the source cluster
-	var foundRepo bool
-	for _, repo := range sourceCluster.Spec.Backups.PGBackRest.Repos {
+	var foundRepo *v1beta1.PGBackRestRepo
+	for i, repo := range sourceCluster.Spec.Backups.PGBackRest.Repos {
 		if repo.Name == sourceRepoName {
-			foundRepo = true
+			foundRepo = &sourceCluster.Spec.Backups.PGBackRest.Repos[i]
 			break
 		}
 	}
-	if !foundRepo {
+	if foundRepo == nil {
 		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidDataSource",
 			"PostgresCluster %q does not have a repo named %q defined",
 			sourceClusterName, sourceRepoName)
 		return nil
 	}
 
+	// A volume cannot be mounted across namespaces, so only cloud-based repos
+	// can be restored from a cluster in another namespace.
+	if foundRepo.Volume != nil && sourceClusterNamespace != cluster.GetNamespace() {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidDataSource",
+			"Repo %q of PostgresCluster %q is a volume and cannot be restored in another namespace",
+			sourceRepoName, sourceClusterName)
+		return nil
+	}
+
 	// Define a fake STS to use when calling the reconcile functions below since when
 	// bootstrapping the cluster it will not exist until after the restore is complete.
 	fakeSTS := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
@@ -1627,6 +1880,24 @@ func (r *Reconciler) createRestoreConfig(ctx context.Context, postgresCluster *v
 		"", configHash, "", "", []string{})
 }
 
+// cloneAllowed returns whether or not sourceCluster may be used as the data
+// source of a PostgresCluster in namespace. Clusters can always be cloned within
+// their own namespace. Clones in other namespaces must be listed in the
+// [naming.PGBackRestCloneNamespaces] annotation of sourceCluster.
+func cloneAllowed(sourceCluster *v1beta1.PostgresCluster, namespace string) bool {
+	if sourceCluster.GetNamespace() == namespace {
+		return true
+	}
+	value := sourceCluster.GetAnnotations()[naming.PGBackRestCloneNamespaces]
+	for _, allowed := range strings.Split(value, ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // copyRestoreConfiguration copies pgBackRest configuration from another cluster for use by
 // the current PostgresCluster (e.g. when restoring across namespaces, and the configuration
 // for the source cluster needs to be copied into the PostgresCluster's local namespace).
@@ -2036,13 +2307,64 @@ func (r *Reconciler) reconcileDedicatedRepoHost(ctx context.Context,
 	return repoHost, nil
 }
 
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=list
+
+// activeBackupJob returns the name of a backup Job of cluster, other than the
+// Job named except, that has not yet finished. pgBackRest takes only one backup
+// of a stanza at a time, so this looks at the Jobs of replica-create, manual,
+// scheduled, and PGBackRestBackup backups alike. The second return value is
+// when the most recent of the other Jobs finished, if any have.
+func (r *Reconciler) activeBackupJob(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, except string,
+) (string, time.Time, error) {
+	var active string
+	var finished time.Time
+
+	backups, err := labels.NewRequirement(naming.LabelPGBackRestBackup, selection.Exists, nil)
+	if err != nil {
+		return active, finished, errors.WithStack(err)
+	}
+	scheduled, err := labels.NewRequirement(naming.LabelPGBackRestCronJob, selection.In,
+		[]string{full, differential, incremental})
+	if err != nil {
+		return active, finished, errors.WithStack(err)
+	}
+
+	for _, requirement := range []labels.Requirement{*backups, *scheduled} {
+		jobs := &batchv1.JobList{}
+		if err := r.Client.List(ctx, jobs,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingLabelsSelector{
+				Selector: naming.PGBackRestSelector(cluster.Name).Add(requirement),
+			},
+		); err != nil {
+			return active, finished, errors.WithStack(err)
+		}
+
+		for i := range jobs.Items {
+			job := &jobs.Items[i]
+			switch {
+			case job.Name == except:
+			case !jobCompleted(job) && !jobFailed(job):
+				if active == "" {
+					active = job.Name
+				}
+			case jobFinishedAt(job).After(finished):
+				finished = jobFinishedAt(job)
+			}
+		}
+	}
+	return active, finished, nil
+}
+
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;patch;delete
 
 // reconcileManualBackup is responsible for reconciling pgBackRest backups that are initiated
 // manually by the end-user
 func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 	postgresCluster *v1beta1.PostgresCluster, manualBackupJobs []*batchv1.Job,
-	serviceAccount *corev1.ServiceAccount, instances *observedInstances) error {
+	serviceAccount *corev1.ServiceAccount, instances *observedInstances,
+) (reconcile.Result, error) {
 
 	manualAnnotation := postgresCluster.GetAnnotations()[naming.PGBackRestBackup]
 	manualStatus := postgresCluster.Status.PGBackRest.ManualBackup
@@ -2094,7 +2416,7 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 		// per a new value for the annotation (unless the user manually deletes the Job).
 		if completed || failed {
 			if manualAnnotation != "" && backupID != manualAnnotation {
-				return errors.WithStack(r.Client.Delete(ctx, currentBackupJob,
+				return reconcile.Result{}, errors.WithStack(r.Client.Delete(ctx, currentBackupJob,
 					client.PropagationPolicy(metav1.DeletePropagationBackground)))
 			}
 		}
@@ -2121,7 +2443,7 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 	// Pods in the cluster for leader election events, and trigger reconciles accordingly.
 	if !clusterWritable || manualAnnotation == "" ||
 		postgresCluster.Spec.Backups.PGBackRest.Manual == nil {
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	// if there is an existing status, see if a new backup id has been provided, and if so reset
@@ -2141,7 +2463,7 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 	// if the status shows the Job is no longer in progress, then simply exit (which means a Job
 	// that has reached a "completed" or "failed" status is no longer reconciled)
 	if manualStatus != nil && manualStatus.Finished {
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	// determine if the dedicated repository host is ready (if enabled) using the repo host ready
@@ -2149,7 +2471,7 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 	if pgbackrest.DedicatedRepoHostEnabled(postgresCluster) {
 		condition := meta.FindStatusCondition(postgresCluster.Status.Conditions, ConditionRepoHostReady)
 		if condition == nil || condition.Status != metav1.ConditionTrue {
-			return nil
+			return reconcile.Result{}, nil
 		}
 	}
 
@@ -2158,7 +2480,7 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 	condition := meta.FindStatusCondition(postgresCluster.Status.Conditions,
 		ConditionReplicaCreate)
 	if condition == nil || condition.Status != metav1.ConditionTrue {
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	// Verify that status exists for the repo configured for the manual backup, and that a stanza
@@ -2178,12 +2500,12 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 		r.Recorder.Eventf(postgresCluster, corev1.EventTypeWarning, "InvalidBackupRepo",
 			"Unable to find status for %q as configured for a manual backup.  Please ensure "+
 				"this repo is defined in the spec.", repoName)
-		return nil
+		return reconcile.Result{}, nil
 	}
 	if !stanzaCreated {
 		r.Recorder.Eventf(postgresCluster, corev1.EventTypeWarning, "StanzaNotCreated",
 			"Stanza not created for %q as specified for a manual backup", repoName)
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	var repo v1beta1.PGBackRestRepo
@@ -2193,7 +2515,7 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 		}
 	}
 	if repo.Name == "" {
-		return errors.Errorf("repo %q is not defined for this cluster", repoName)
+		return reconcile.Result{}, errors.Errorf("repo %q is not defined for this cluster", repoName)
 	}
 
 	// Users should specify the repo for the command using the "manual.repoName" field in the spec,
@@ -2206,7 +2528,25 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 			r.Recorder.Eventf(postgresCluster, corev1.EventTypeWarning, "InvalidManualBackup",
 				"Option '--repo' is not allowed: please use the 'repoName' field instead.",
 				repoName)
-			return nil
+			return reconcile.Result{}, nil
+		}
+	}
+
+	// pgBackRest allows only one backup of a stanza at a time. Wait for any
+	// other backup Job of the cluster to finish before starting this one.
+	if currentBackupJob == nil {
+		active, _, err := r.activeBackupJob(ctx, postgresCluster, "")
+		if err != nil || active != "" {
+			if active != "" {
+				meta.SetStatusCondition(&postgresCluster.Status.Conditions, metav1.Condition{
+					ObservedGeneration: postgresCluster.GetGeneration(),
+					Type:               ConditionManualBackupSuccessful,
+					Status:             metav1.ConditionUnknown,
+					Reason:             "BackupInProgress",
+					Message:            fmt.Sprintf("Waiting for Job %q to finish", active),
+				})
+			}
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, err
 		}
 	}
 
@@ -2233,7 +2573,7 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 	spec, err := generateBackupJobSpecIntent(postgresCluster, repo,
 		serviceAccount.GetName(), labels, annotations, backupOpts...)
 	if err != nil {
-		return errors.WithStack(err)
+		return reconcile.Result{}, errors.WithStack(err)
 	}
 	backupJob.Spec = *spec
 
@@ -2241,15 +2581,15 @@ func (r *Reconciler) reconcileManualBackup(ctx context.Context,
 	backupJob.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
 	if err := controllerutil.SetControllerReference(postgresCluster, backupJob,
 		r.Client.Scheme()); err != nil {
-		return errors.WithStack(err)
+		return reconcile.Result{}, errors.WithStack(err)
 	}
 
 	// server-side apply the backup Job intent
 	if err := r.apply(ctx, backupJob); err != nil {
-		return errors.WithStack(err)
+		return reconcile.Result{}, errors.WithStack(err)
 	}
 
-	return nil
+	return reconcile.Result{}, nil
 }
 
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=create;patch;delete
@@ -2260,7 +2600,7 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	postgresCluster *v1beta1.PostgresCluster, instances *observedInstances,
 	replicaCreateBackupJobs []*batchv1.Job,
 	serviceAccount *corev1.ServiceAccount, configHash string,
-	replicaCreateRepo v1beta1.PGBackRestRepo) error {
+	replicaCreateRepo v1beta1.PGBackRestRepo) (reconcile.Result, error) {
 
 	var replicaCreateRepoStatus *v1beta1.RepoStatus
 	for i, repo := range postgresCluster.Status.PGBackRest.Repos {
@@ -2312,7 +2652,7 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	// operator always has a chance to reconcile when an instance becomes writable, we should watch
 	// Pods in the cluster for leader election events, and trigger reconciles accordingly.
 	if !clusterWritable || replicaCreateRepoStatus == nil || replicaCreateRepoStatus.ReplicaCreateBackupComplete {
-		return nil
+		return reconcile.Result{}, nil
 	}
 
 	// determine if the replica create repo is ready using the "PGBackRestReplicaRepoReady" condition
@@ -2326,7 +2666,7 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	// the pgBackRest backup
 	_, containerName, err := getPGBackRestExecSelector(postgresCluster, replicaCreateRepo)
 	if err != nil {
-		return errors.WithStack(err)
+		return reconcile.Result{}, errors.WithStack(err)
 	}
 
 	// determine if the dedicated repository host is ready using the repo host ready status
@@ -2366,15 +2706,15 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 			(job.GetAnnotations()[naming.PGBackRestConfigHash] != configHash) {
 			if err := r.Client.Delete(ctx, job,
 				client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
-				return errors.WithStack(err)
+				return reconcile.Result{}, errors.WithStack(err)
 			}
-			return nil
+			return reconcile.Result{}, nil
 		}
 
 		// if the Job completed then update status and return
 		if completed {
 			replicaCreateRepoStatus.ReplicaCreateBackupComplete = true
-			return nil
+			return reconcile.Result{}, nil
 		}
 	}
 
@@ -2382,7 +2722,15 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	// return if no job has been created and the replica repo or the dedicated repo host  is not
 	// ready
 	if job == nil && ((dedicatedEnabled && !dedicatedRepoReady) || !replicaRepoReady) {
-		return nil
+		return reconcile.Result{}, nil
+	}
+
+	// pgBackRest allows only one backup of a stanza at a time. Wait for any
+	// other backup Job of the cluster to finish before starting this one.
+	if job == nil {
+		if active, _, err := r.activeBackupJob(ctx, postgresCluster, ""); err != nil || active != "" {
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, err
+		}
 	}
 
 	// create the backup Job, and populate ObjectMeta based on whether or not a Job already exists
@@ -2409,7 +2757,7 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	spec, err := generateBackupJobSpecIntent(postgresCluster, replicaCreateRepo,
 		serviceAccount.GetName(), labels, annotations)
 	if err != nil {
-		return errors.WithStack(err)
+		return reconcile.Result{}, errors.WithStack(err)
 	}
 	backupJob.Spec = *spec
 
@@ -2417,14 +2765,14 @@ func (r *Reconciler) reconcileReplicaCreateBackup(ctx context.Context,
 	backupJob.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
 	if err := controllerutil.SetControllerReference(postgresCluster, backupJob,
 		r.Client.Scheme()); err != nil {
-		return errors.WithStack(err)
+		return reconcile.Result{}, errors.WithStack(err)
 	}
 
 	if err := r.apply(ctx, backupJob); err != nil {
-		return errors.WithStack(err)
+		return reconcile.Result{}, errors.WithStack(err)
 	}
 
-	return nil
+	return reconcile.Result{}, nil
 }
 
 // reconcileRepos is responsible for reconciling any pgBackRest repositories configured
@@ -2562,9 +2910,12 @@ func (r *Reconciler) reconcileStanzaCreate(ctx context.Context,
 			naming.ContainerDatabase, stdin, stdout, stderr, command...)
 	}
 
-	// Always attempt to create pgBackRest stanza first
+	// Always attempt to create pgBackRest stanza first, unless the cluster is
+	// starting up following a major PostgreSQL upgrade.
+	upgrade := postgresCluster.Status.PostgresUpgrade != nil &&
+		postgresCluster.Status.PostgresUpgrade.Phase == postgresUpgradeStartingUp
 	configHashMismatch, err := pgbackrest.Executor(exec).StanzaCreateOrUpgrade(ctx, configHash,
-		false)
+		upgrade)
 	if err != nil {
 		// record and log any errors resulting from running the stanza-create command
 		r.Recorder.Event(postgresCluster, corev1.EventTypeWarning, EventUnableToCreateStanzas,
@@ -2592,6 +2943,244 @@ func (r *Reconciler) reconcileStanzaCreate(ctx context.Context,
 	return false, nil
 }
 
+// repoInventoryInterval is how often pgBackRest is asked about the contents of
+// each repository.
+const repoInventoryInterval = 5 * time.Minute
+
+// repoInventoryTimeout is how long each command that gathers the inventory of
+// a repository may run.
+const repoInventoryTimeout = 30 * time.Second
+
+// repoInventoryBackups is the maximum number of backups listed in the inventory
+// of each repository.
+const repoInventoryBackups = 10
+
+// reconcileRepoInventory runs "pgbackrest info" against a repository that has
+// a stanza and records the result in the repository status. pgBackRest is asked
+// about each repository at most once every repoInventoryInterval and about one
+// repository per reconcile, so that a slow repository delays little else; the
+// result requeues for the rest.
+func (r *Reconciler) reconcileRepoInventory(ctx context.Context,
+	postgresCluster *v1beta1.PostgresCluster) reconcile.Result {
+
+	log := logging.FromContext(ctx).WithValues("reconcileResource", "repoInventory")
+	result := reconcile.Result{}
+	now := metav1.Now().Rfc3339Copy()
+	asked := false
+
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		var repoStatus *v1beta1.RepoStatus
+		for i := range postgresCluster.Status.PGBackRest.Repos {
+			if postgresCluster.Status.PGBackRest.Repos[i].Name == repo.Name {
+				repoStatus = &postgresCluster.Status.PGBackRest.Repos[i]
+			}
+		}
+		if repoStatus == nil {
+			continue
+		}
+		// an inventory is only meaningful once the stanza exists
+		if !repoStatus.StanzaCreated {
+			repoStatus.Inventory = nil
+			continue
+		}
+
+		// skip repos that were asked recently and requeue for the next interval
+		if inventory := repoStatus.Inventory; inventory != nil && inventory.UpdateTime != nil {
+			if next := inventory.UpdateTime.Add(repoInventoryInterval); next.After(now.Time) {
+				result = updateReconcileResult(result,
+					reconcile.Result{RequeueAfter: next.Sub(now.Time)})
+				continue
+			}
+		}
+
+		// ask about the next repo in another reconcile
+		if asked {
+			result = updateReconcileResult(result, reconcile.Result{Requeue: true})
+			continue
+		}
+
+		var info pgbackrest.InfoOutput
+		var archived time.Time
+		var used, capacity int64
+		var pod *corev1.Pod
+		selector, containerName, err := getPGBackRestExecSelector(postgresCluster, repo)
+		if err == nil {
+			pod, err = r.findRunningPod(ctx, postgresCluster, selector)
+		}
+		if err == nil && pod != nil {
+			asked = true
+			exec := pgbackrest.Executor(func(ctx context.Context,
+				stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+				// Coreutils `timeout` stops the command when the time runs out.
+				command = append([]string{"timeout",
+					fmt.Sprintf("%.0fs", repoInventoryTimeout.Seconds())}, command...)
+				return r.PodExec(pod.Namespace, pod.Name, containerName,
+					stdin, stdout, stderr, command...)
+			})
+			repoIndex := regexRepoIndex.FindString(repo.Name)
+			info, err = exec.Info(ctx, repoIndex)
+
+			// the recovery window ends when the newest WAL was archived
+			for i := range info {
+				if id, wal := info[i].ArchiveMax(); err == nil &&
+					info[i].Name == pgbackrest.DefaultStanzaName && wal != "" {
+					archived, err = exec.ArchiveTime(ctx, repoIndex, id, wal)
+				}
+			}
+
+			// volume repos are mounted in the repo host where the command ran
+			if err == nil && repo.Volume != nil {
+				used, capacity, err = exec.RepoVolumeUsage(ctx, repo.Name)
+			}
+		}
+
+		switch {
+		case err != nil:
+			// keep the previous inventory, if any, and try again next interval
+			log.Error(err, "unable to gather pgBackRest backups for "+repo.Name)
+			result = updateReconcileResult(result,
+				reconcile.Result{RequeueAfter: repoInventoryInterval})
+		case pod == nil:
+			// try again soon when there may be a Pod in which to run pgBackRest
+			result = updateReconcileResult(result,
+				reconcile.Result{RequeueAfter: time.Minute})
+		default:
+			repoStatus.Inventory = repoInventory(info, archived, now)
+			if repo.Volume != nil {
+				repoStatus.Inventory.VolumeCapacity = resource.NewQuantity(capacity, resource.BinarySI)
+				repoStatus.Inventory.VolumeUsed = resource.NewQuantity(used, resource.BinarySI)
+			}
+			result = updateReconcileResult(result,
+				reconcile.Result{RequeueAfter: repoInventoryInterval})
+		}
+	}
+
+	r.setRepoVolumeCapacityCondition(postgresCluster)
+
+	return result
+}
+
+// setRepoVolumeCapacityCondition compares the space used on each repository volume
+// to its threshold and sets the ConditionRepoVolumeCapacity condition accordingly. A
+// warning event is recorded when any volume first crosses its threshold.
+func (r *Reconciler) setRepoVolumeCapacityCondition(postgresCluster *v1beta1.PostgresCluster) {
+	condition := repoVolumeCapacityCondition(postgresCluster)
+	if condition == nil {
+		meta.RemoveStatusCondition(&postgresCluster.Status.Conditions, ConditionRepoVolumeCapacity)
+		return
+	}
+
+	if condition.Status == metav1.ConditionFalse && !meta.IsStatusConditionFalse(
+		postgresCluster.Status.Conditions, ConditionRepoVolumeCapacity) {
+		r.Recorder.Event(postgresCluster, corev1.EventTypeWarning,
+			EventRepoVolumeThresholdExceeded, condition.Message)
+	}
+	meta.SetStatusCondition(&postgresCluster.Status.Conditions, *condition)
+}
+
+// repoVolumeCapacityCondition returns the ConditionRepoVolumeCapacity condition for the
+// repository volumes of postgresCluster. It returns nil when the usage of no volume is known.
+func repoVolumeCapacityCondition(postgresCluster *v1beta1.PostgresCluster) *metav1.Condition {
+	var known bool
+	var exceeded []string
+
+	for _, repo := range postgresCluster.Spec.Backups.PGBackRest.Repos {
+		if repo.Volume == nil {
+			continue
+		}
+		threshold := int64(defaultRepoVolumeUsageThreshold)
+		if repo.Volume.UsageThreshold != nil {
+			threshold = int64(*repo.Volume.UsageThreshold)
+		}
+
+		for _, repoStatus := range postgresCluster.Status.PGBackRest.Repos {
+			inventory := repoStatus.Inventory
+			if repoStatus.Name != repo.Name || inventory == nil ||
+				inventory.VolumeCapacity == nil || inventory.VolumeCapacity.IsZero() {
+				continue
+			}
+			known = true
+
+			used, capacity := inventory.VolumeUsed.Value(), inventory.VolumeCapacity.Value()
+			if percent := used * 100 / capacity; percent >= threshold {
+				exceeded = append(exceeded, fmt.Sprintf("%s is %d%% full (%s of %s)",
+					repo.Name, percent, inventory.VolumeUsed, inventory.VolumeCapacity))
+			}
+		}
+	}
+	if !known {
+		return nil
+	}
+
+	condition := &metav1.Condition{
+		ObservedGeneration: postgresCluster.GetGeneration(),
+		Type:               ConditionRepoVolumeCapacity,
+		Status:             metav1.ConditionTrue,
+		Reason:             "BelowThreshold",
+		Message:            "pgBackRest repository volumes are below their usage thresholds",
+	}
+	if len(exceeded) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ThresholdExceeded"
+		condition.Message = "pgBackRest repository volume usage threshold exceeded: " +
+			strings.Join(exceeded, ", ")
+	}
+	return condition
+}
+
+// repoInventory summarizes the output of "pgbackrest info" for a single repository.
+// The recovery window ends when the newest WAL was archived, when that is known
+// and after the newest backup finished.
+func repoInventory(
+	info pgbackrest.InfoOutput, archived time.Time, now metav1.Time,
+) *v1beta1.RepoInventory {
+	inventory := &v1beta1.RepoInventory{UpdateTime: &now}
+
+	var backups []pgbackrest.InfoBackup
+	for i := range info {
+		if info[i].Name != pgbackrest.DefaultStanzaName {
+			continue
+		}
+		backups = append(backups, info[i].Backup...)
+
+		// WAL file names sort in the order they are written
+		for _, archive := range info[i].Archive {
+			if archive.Min != "" && (inventory.WALMin == "" || archive.Min < inventory.WALMin) {
+				inventory.WALMin = archive.Min
+			}
+			if archive.Max > inventory.WALMax {
+				inventory.WALMax = archive.Max
+			}
+		}
+	}
+	if len(backups) == 0 {
+		return inventory
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Timestamp.Stop < backups[j].Timestamp.Stop
+	})
+
+	oldest, newest := backupInfo(&backups[0]), backupInfo(&backups[len(backups)-1])
+	inventory.RecoveryWindow = &v1beta1.RepoRecoveryWindow{
+		StartTime: oldest.StopTime,
+		EndTime:   newest.StopTime,
+	}
+	if archived.After(newest.StopTime.Time) {
+		end := metav1.NewTime(archived)
+		inventory.RecoveryWindow.EndTime = &end
+	}
+
+	if len(backups) > repoInventoryBackups {
+		backups = backups[len(backups)-repoInventoryBackups:]
+	}
+	for i := range backups {
+		inventory.Backups = append(inventory.Backups, *backupInfo(&backups[i]))
+	}
+
+	return inventory
+}
+
 // getPGBackRestExecSelector returns a selector and container name that allows the proper
 // Pod (along with a specific container within it) to be found within the Kubernetes
 // cluster as needed to exec into the container and run a pgBackRest command.
@@ -2770,6 +3359,20 @@ func (r *Reconciler) reconcileScheduledBackups(
 					requeue = true
 				}
 			}
+			if repo.BackupSchedules.Expire != nil {
+				if err := r.reconcilePGBackRestCronJob(ctx, cluster, repo,
+					expire, repo.BackupSchedules.Expire, sa, cronjobs); err != nil {
+					log.Error(err, "unable to reconcile Expire for "+repo.Name)
+					requeue = true
+				}
+			}
+			if repo.BackupSchedules.Verify != nil {
+				if err := r.reconcilePGBackRestCronJob(ctx, cluster, repo,
+					verify, repo.BackupSchedules.Verify, sa, cronjobs); err != nil {
+					log.Error(err, "unable to reconcile Verify for "+repo.Name)
+					requeue = true
+				}
+			}
 		}
 	}
 	return requeue
@@ -2855,11 +3458,19 @@ func (r *Reconciler) reconcilePGBackRestCronJob(
 		return nil
 	}
 
-	// set backup type (i.e. "full", "diff", "incr")
-	backupOpts := []string{"--type=" + backupType}
-
-	jobSpec, err := generateBackupJobSpecIntent(cluster, repo,
-		serviceAccount.GetName(), labels, annotations, backupOpts...)
+	var jobSpec *batchv1.JobSpec
+	var err error
+	switch backupType {
+	case expire:
+		jobSpec, err = generatePGBackRestJobSpecIntent(cluster, repo, "expire",
+			serviceAccount.GetName(), labels, annotations)
+	case verify:
+		jobSpec, err = r.generateVerifyJobSpecIntent(cluster, repo, labels, annotations)
+	default:
+		// set backup type (i.e. "full", "diff", "incr")
+		jobSpec, err = generatePGBackRestJobSpecIntent(cluster, repo, "backup",
+			serviceAccount.GetName(), labels, annotations, "--type="+backupType)
+	}
 	if err != nil {
 		return errors.WithStack(err)
 	}
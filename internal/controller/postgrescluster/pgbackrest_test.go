@@ -46,7 +46,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -55,6 +57,7 @@ import (
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
 	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
@@ -905,7 +908,7 @@ func TestReconcileReplicaCreateBackup(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "hippo-sa"},
 	}
 
-	err := r.reconcileReplicaCreateBackup(ctx, postgresCluster, instances,
+	_, err := r.reconcileReplicaCreateBackup(ctx, postgresCluster, instances,
 		[]*batchv1.Job{}, sa, configHash, replicaCreateRepo)
 	assert.NilError(t, err)
 
@@ -985,7 +988,7 @@ func TestReconcileReplicaCreateBackup(t *testing.T) {
 		batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})
 
 	// call reconcile function again
-	err = r.reconcileReplicaCreateBackup(ctx, postgresCluster, instances,
+	_, err = r.reconcileReplicaCreateBackup(ctx, postgresCluster, instances,
 		[]*batchv1.Job{&backupJob}, sa, configHash, replicaCreateRepo)
 	assert.NilError(t, err)
 
@@ -1361,7 +1364,7 @@ func TestReconcileManualBackup(t *testing.T) {
 					}
 				}
 
-				err := r.reconcileManualBackup(ctx, postgresCluster, currentJobs, sa, instances)
+				_, err := r.reconcileManualBackup(ctx, postgresCluster, currentJobs, sa, instances)
 
 				if tc.expectReconcile {
 
@@ -2663,6 +2666,19 @@ volumes:
 		assert.NilError(t, err)
 		assert.DeepEqual(t, job.Template.Spec.Tolerations, tolerations)
 	})
+
+	t.Run("Expire", func(t *testing.T) {
+		job, err := generatePGBackRestJobSpecIntent(
+			&v1beta1.PostgresCluster{}, v1beta1.PGBackRestRepo{Name: "repo2"},
+			"expire", "",
+			nil, nil,
+		)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, job.Template.Spec.Containers[0].Env[:2], []corev1.EnvVar{
+			{Name: "COMMAND", Value: "expire"},
+			{Name: "COMMAND_OPTS", Value: "--stanza=db --repo=2"},
+		})
+	})
 }
 
 func TestGenerateRepoHostIntent(t *testing.T) {
@@ -3733,3 +3749,312 @@ func TestSetScheduledJobStatus(t *testing.T) {
 		assert.Assert(t, len(postgresCluster.Status.PGBackRest.ScheduledBackups) == 0)
 	})
 }
+
+func TestRepoInventory(t *testing.T) {
+	now := metav1.NewTime(time.Unix(1654200000, 0))
+
+	t.Run("Empty", func(t *testing.T) {
+		inventory := repoInventory(nil, time.Time{}, now)
+		assert.Equal(t, inventory.UpdateTime.Time, now.Time)
+		assert.Assert(t, inventory.Backups == nil)
+		assert.Assert(t, inventory.RecoveryWindow == nil)
+	})
+
+	info := pgbackrest.InfoOutput{{
+		Name: "db",
+		Archive: []pgbackrest.InfoArchive{
+			{ID: "13-1", Min: "000000010000000000000004", Max: "000000010000000000000009"},
+			{ID: "13-2", Min: "000000020000000000000002", Max: "00000002000000000000000C"},
+		},
+	}, {
+		Name:   "other",
+		Backup: []pgbackrest.InfoBackup{{Label: "ignored"}},
+	}}
+	for i := 0; i < repoInventoryBackups+2; i++ {
+		var b pgbackrest.InfoBackup
+		b.Label = strconv.Itoa(i)
+		b.Timestamp.Start = 1654100000 + int64(i)*100
+		b.Timestamp.Stop = b.Timestamp.Start + 50
+		b.Info.Size = 1024
+		info[0].Backup = append(info[0].Backup, b)
+	}
+	// pgBackRest lists backups oldest first, but do not depend on it
+	info[0].Backup[0], info[0].Backup[5] = info[0].Backup[5], info[0].Backup[0]
+
+	inventory := repoInventory(info, time.Time{}, now)
+	assert.Equal(t, inventory.WALMin, "000000010000000000000004")
+	assert.Equal(t, inventory.WALMax, "00000002000000000000000C")
+
+	assert.Equal(t, len(inventory.Backups), repoInventoryBackups)
+	assert.Equal(t, inventory.Backups[0].Label, "2", "expected the most recent")
+	assert.Equal(t, inventory.Backups[repoInventoryBackups-1].Label, "11")
+	assert.Equal(t, inventory.Backups[0].DatabaseSize.String(), "1Ki")
+
+	assert.Assert(t, inventory.RecoveryWindow != nil)
+	assert.Equal(t, inventory.RecoveryWindow.StartTime.Unix(), int64(1654100050),
+		"expected the oldest backup, even when it is not listed")
+	assert.Equal(t, inventory.RecoveryWindow.EndTime.Unix(), int64(1654101150),
+		"expected the newest backup when archive time is unknown")
+
+	t.Run("Archived", func(t *testing.T) {
+		inventory := repoInventory(info, time.Unix(1654101500, 0), now)
+		assert.Assert(t, inventory.RecoveryWindow != nil)
+		assert.Equal(t, inventory.RecoveryWindow.EndTime.Unix(), int64(1654101500),
+			"expected when the newest WAL was archived")
+	})
+}
+
+func TestReconcileRepoInventory(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", S3: &v1beta1.RepoS3{}},
+		{Name: "repo2", GCS: &v1beta1.RepoGCS{}},
+	}
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{Repos: []v1beta1.RepoStatus{
+		{Name: "repo1", StanzaCreated: true},
+		{Name: "repo2", StanzaCreated: true},
+	}}
+
+	pod := &corev1.Pod{}
+	pod.Namespace, pod.Name = "ns1", "hippo-pod"
+	pod.Labels = map[string]string{
+		naming.LabelCluster:  cluster.Name,
+		naming.LabelInstance: "hippo-abcd",
+		naming.LabelRole:     naming.RolePatroniLeader,
+	}
+	pod.Status.Phase = corev1.PodRunning
+
+	var commands [][]string
+	r := &Reconciler{
+		Client:   fake.NewClientBuilder().WithObjects(pod).Build(),
+		Recorder: record.NewFakeRecorder(10),
+		PodExec: func(
+			_, _, _ string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			commands = append(commands, command)
+			switch command[3] {
+			case "info":
+				_, _ = stdout.Write([]byte(`[{"name": "db",
+					"archive": [{"id": "14-1", "min": "000000010000000000000001", "max": "000000010000000000000006"}],
+					"backup": [{"label": "one", "timestamp": {"start": 1654100000, "stop": 1654100050}}]
+				}]`))
+			case "repo-ls":
+				_, _ = stdout.Write([]byte(`{"000000010000000000000006-abc.gz": {"type": "file", "time": 1654101500}}`))
+			}
+			return nil
+		},
+	}
+
+	result := r.reconcileRepoInventory(ctx, cluster)
+	assert.Assert(t, result.Requeue, "expected to requeue for the next repo")
+	assert.Equal(t, len(commands), 2)
+	for _, command := range commands {
+		assert.DeepEqual(t, command[:2], []string{"timeout", "30s"})
+	}
+	assert.Equal(t, commands[0][3], "info")
+	assert.Equal(t, commands[1][3], "repo-ls")
+
+	repos := cluster.Status.PGBackRest.Repos
+	assert.Assert(t, repos[0].Inventory != nil)
+	assert.Assert(t, repos[1].Inventory == nil, "expected one repo per reconcile")
+	assert.Equal(t, repos[0].Inventory.RecoveryWindow.EndTime.Unix(), int64(1654101500))
+
+	commands = nil
+	result = r.reconcileRepoInventory(ctx, cluster)
+	assert.Assert(t, !result.Requeue)
+	assert.Assert(t, result.RequeueAfter > 0 && result.RequeueAfter <= repoInventoryInterval)
+	assert.Equal(t, len(commands), 2)
+	assert.Assert(t, cmp.Contains(commands[0], "--repo=2"))
+	assert.Assert(t, repos[1].Inventory != nil)
+}
+
+func TestRepoVolumeCapacityCondition(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", Volume: &v1beta1.RepoPVC{}},
+		{Name: "repo2", Volume: &v1beta1.RepoPVC{UsageThreshold: initialize.Int32(50)}},
+		{Name: "repo3", S3: &v1beta1.RepoS3{}},
+	}
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+		Repos: []v1beta1.RepoStatus{{Name: "repo1"}, {Name: "repo2"}, {Name: "repo3"}},
+	}
+
+	assert.Assert(t, repoVolumeCapacityCondition(cluster) == nil,
+		"expected nothing when usage is unknown")
+
+	usage := func(used, capacity string) *v1beta1.RepoInventory {
+		u, c := resource.MustParse(used), resource.MustParse(capacity)
+		return &v1beta1.RepoInventory{VolumeUsed: &u, VolumeCapacity: &c}
+	}
+
+	cluster.Status.PGBackRest.Repos[0].Inventory = usage("7Gi", "10Gi")
+	cluster.Status.PGBackRest.Repos[1].Inventory = usage("4Gi", "10Gi")
+
+	condition := repoVolumeCapacityCondition(cluster)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+
+	cluster.Status.PGBackRest.Repos[0].Inventory = usage("8Gi", "10Gi")
+	cluster.Status.PGBackRest.Repos[1].Inventory = usage("6Gi", "10Gi")
+
+	condition = repoVolumeCapacityCondition(cluster)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, "ThresholdExceeded")
+	assert.Equal(t, condition.Message, "pgBackRest repository volume usage threshold exceeded: "+
+		"repo1 is 80% full (8Gi of 10Gi), repo2 is 60% full (6Gi of 10Gi)")
+}
+
+func TestGenerateVerifyJobSpecIntent(t *testing.T) {
+	_, cc := setupKubernetes(t)
+	require.ParallelCapacity(t, 0)
+
+	r := Reconciler{Client: cc}
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Name = "hippo"
+	cluster.Spec.PostgresVersion = 14
+	repo := v1beta1.PGBackRestRepo{Name: "repo2"}
+	labels := map[string]string{"some": "label"}
+
+	t.Run("Defaults", func(t *testing.T) {
+		spec, err := r.generateVerifyJobSpecIntent(cluster, repo, labels, nil)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, spec.Template.Labels, labels)
+
+		container := spec.Template.Spec.Containers[0]
+		assert.Equal(t, container.Name, naming.PGBackRestRestoreContainerName)
+		assert.DeepEqual(t, container.Command[4:], []string{"-", "/pgdata/pg14",
+			"--stanza=db --pg1-path=/pgdata/pg14 --repo=2 --type=immediate --target-action=promote",
+			""})
+
+		var data *corev1.Volume
+		for i := range spec.Template.Spec.Volumes {
+			if spec.Template.Spec.Volumes[i].Name == "postgres-data" {
+				data = &spec.Template.Spec.Volumes[i]
+			}
+		}
+		assert.Assert(t, data != nil)
+		assert.Assert(t, data.EmptyDir != nil)
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Verification = &v1beta1.PGBackRestVerification{
+			SQL: "SELECT true",
+			VolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			},
+		}
+		cluster.Spec.Backups.PGBackRest.Jobs = &v1beta1.BackupJobs{
+			PriorityClassName: initialize.String("some-priority-class"),
+		}
+
+		spec, err := r.generateVerifyJobSpecIntent(cluster, repo, labels, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, spec.Template.Spec.PriorityClassName, "some-priority-class")
+
+		container := spec.Template.Spec.Containers[0]
+		assert.Equal(t, container.Command[len(container.Command)-1], "SELECT true")
+
+		var data *corev1.Volume
+		for i := range spec.Template.Spec.Volumes {
+			if spec.Template.Spec.Volumes[i].Name == "postgres-data" {
+				data = &spec.Template.Spec.Volumes[i]
+			}
+		}
+		assert.Assert(t, data != nil)
+		assert.Assert(t, data.Ephemeral != nil)
+		assert.DeepEqual(t, data.Ephemeral.VolumeClaimTemplate.Spec.AccessModes,
+			[]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce})
+		assert.DeepEqual(t, data.Ephemeral.VolumeClaimTemplate.Labels, labels)
+	})
+}
+
+func TestSetRepoVerificationStatus(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", BackupSchedules: &v1beta1.PGBackRestBackupSchedules{
+			Verify: initialize.String("0 1 * * *"),
+		}},
+		{Name: "repo2"},
+	}
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+		Repos: []v1beta1.RepoStatus{{Name: "repo1"}, {
+			Name:         "repo2",
+			Verification: &v1beta1.RepoVerificationStatus{Finished: true},
+		}},
+	}
+
+	setRepoVerificationStatus(cluster, nil)
+	assert.Assert(t, cluster.Status.PGBackRest.Repos[0].Verification == nil)
+	assert.Assert(t, cluster.Status.PGBackRest.Repos[1].Verification == nil,
+		"expected removal without a verify schedule")
+	assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+		ConditionBackupsVerified) == nil)
+
+	earlier, later := metav1.Unix(1654100000, 0), metav1.Unix(1654200000, 0)
+	job := func(name string, created metav1.Time, condition batchv1.JobConditionType) *batchv1.Job {
+		job := &batchv1.Job{}
+		job.Name = name
+		job.CreationTimestamp = created
+		job.Labels = map[string]string{naming.LabelPGBackRestRepo: "repo1"}
+		job.Status.StartTime = &created
+		if condition != "" {
+			job.Status.Conditions = []batchv1.JobCondition{{
+				Type: condition, Status: corev1.ConditionTrue, LastTransitionTime: later,
+			}}
+		}
+		if condition == batchv1.JobComplete {
+			job.Status.CompletionTime = &later
+		}
+		return job
+	}
+
+	setRepoVerificationStatus(cluster, []*batchv1.Job{
+		job("old", earlier, batchv1.JobFailed),
+		job("new", later, batchv1.JobComplete),
+	})
+	status := cluster.Status.PGBackRest.Repos[0].Verification
+	assert.Assert(t, status != nil)
+	assert.Equal(t, status.JobName, "new")
+	assert.Assert(t, status.Finished && status.Passed)
+	assert.Equal(t, status.LastPassedTime.Time, later.Time)
+	assert.Assert(t, meta.IsStatusConditionTrue(cluster.Status.Conditions,
+		ConditionBackupsVerified))
+
+	latest := metav1.Unix(1654300000, 0)
+	setRepoVerificationStatus(cluster, []*batchv1.Job{job("newer", latest, "")})
+	assert.Assert(t, !status.Finished && !status.Passed)
+	assert.Equal(t, status.LastPassedTime.Unix(), int64(1654200000),
+		"expected the previous success")
+	assert.Assert(t, meta.FindStatusCondition(cluster.Status.Conditions,
+		ConditionBackupsVerified) == nil, "expected no condition while running")
+
+	setRepoVerificationStatus(cluster, []*batchv1.Job{job("newer", latest, batchv1.JobFailed)})
+	assert.Assert(t, status.Finished && !status.Passed)
+	assert.Equal(t, status.CompletionTime.Time, later.Time)
+	assert.Assert(t, meta.IsStatusConditionFalse(cluster.Status.Conditions,
+		ConditionBackupsVerified))
+}
+
+func TestCloneAllowed(t *testing.T) {
+	source := &v1beta1.PostgresCluster{}
+	source.Namespace = "prod"
+
+	assert.Assert(t, cloneAllowed(source, "prod"), "same namespace")
+	assert.Assert(t, !cloneAllowed(source, "dev1"), "no annotation")
+
+	source.Annotations = map[string]string{
+		naming.PGBackRestCloneNamespaces: "dev1, dev2",
+	}
+	assert.Assert(t, cloneAllowed(source, "dev1"))
+	assert.Assert(t, cloneAllowed(source, "dev2"))
+	assert.Assert(t, !cloneAllowed(source, "dev3"))
+	assert.Assert(t, !cloneAllowed(source, ""))
+
+	source.Annotations[naming.PGBackRestCloneNamespaces] = "*"
+	assert.Assert(t, cloneAllowed(source, "dev3"))
+}
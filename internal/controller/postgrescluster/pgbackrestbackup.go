@@ -0,0 +1,492 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// BackupControllerName is the name of the PGBackRestBackup controller
+	BackupControllerName = "pgbackrestbackup-controller"
+
+	// backupInfoTimeout is how long after its Job completes that pgBackRest
+	// is asked about a backup before giving up.
+	backupInfoTimeout = 10 * time.Minute
+)
+
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=pgbackrestbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=pgbackrestbackups/status,verbs=patch
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=postgresclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// ReconcileBackup runs the pgBackRest backup described by a PGBackRestBackup
+// and keeps its status up to date. Each PGBackRestBackup runs at most one Job
+// that is never replaced, so the object is a record of that one backup.
+func (r *Reconciler) ReconcileBackup(
+	ctx context.Context, request reconcile.Request) (reconcile.Result, error,
+) {
+	ctx, span := r.Tracer.Start(ctx, "ReconcileBackup")
+	log := logging.FromContext(ctx)
+	defer span.End()
+
+	backup := &v1beta1.PGBackRestBackup{}
+	if err := r.Client.Get(ctx, request.NamespacedName, backup); err != nil {
+		// NotFound cannot be fixed by requeuing so ignore it.
+		if err = client.IgnoreNotFound(err); err != nil {
+			log.Error(err, "unable to fetch PGBackRestBackup")
+			span.RecordError(err)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Keep a copy of backup prior to any manipulations.
+	before := backup.DeepCopy()
+
+	result, err := r.reconcileBackupJob(ctx, backup)
+
+	if !equality.Semantic.DeepEqual(before.Status, backup.Status) {
+		if err := errors.WithStack(r.Client.Status().Patch(
+			ctx, backup, client.MergeFrom(before), r.Owner)); err != nil {
+			log.Error(err, "patching backup status")
+			return result, err
+		}
+		log.V(1).Info("patched backup status")
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	}
+	return result, err
+}
+
+// reconcileBackupJob creates the backup Job of backup once its cluster is able to
+// take a backup. It then copies the status of the Job to backup and, once the Job
+// completes, details of the backup as reported by pgBackRest.
+func (r *Reconciler) reconcileBackupJob(
+	ctx context.Context, backup *v1beta1.PGBackRestBackup,
+) (reconcile.Result, error) {
+	backup.Status.ObservedGeneration = backup.GetGeneration()
+
+	// Nothing more happens once the Job has finished and its results are known.
+	if backup.Status.Finished && (backup.Status.Backup != nil ||
+		meta.IsStatusConditionFalse(backup.Status.Conditions, v1beta1.PGBackRestBackupSucceeded)) {
+		return reconcile.Result{}, nil
+	}
+
+	waiting := func(reason, message string) {
+		meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+			ObservedGeneration: backup.GetGeneration(),
+			Type:               v1beta1.PGBackRestBackupSucceeded,
+			Status:             metav1.ConditionUnknown,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	cluster := &v1beta1.PostgresCluster{}
+	err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: backup.GetNamespace(), Name: backup.Spec.PostgresClusterName,
+	}, cluster)
+	if err != nil {
+		// A missing cluster may be created later; the cluster watch will
+		// trigger another reconcile when it is.
+		if client.IgnoreNotFound(err) == nil {
+			waiting("ClusterNotFound", fmt.Sprintf(
+				"PostgresCluster %q not found", backup.Spec.PostgresClusterName))
+		}
+		return reconcile.Result{}, client.IgnoreNotFound(errors.WithStack(err))
+	}
+
+	var repo *v1beta1.PGBackRestRepo
+	for i := range cluster.Spec.Backups.PGBackRest.Repos {
+		if cluster.Spec.Backups.PGBackRest.Repos[i].Name == backup.Spec.RepoName {
+			repo = &cluster.Spec.Backups.PGBackRest.Repos[i]
+		}
+	}
+	if repo == nil {
+		waiting("RepoNotFound", fmt.Sprintf(
+			"PostgresCluster %q does not define %q", cluster.Name, backup.Spec.RepoName))
+		return reconcile.Result{}, nil
+	}
+
+	job := &batchv1.Job{ObjectMeta: naming.PGBackRestBackupObjectJob(backup)}
+	err = r.Client.Get(ctx, client.ObjectKeyFromObject(job), job)
+	if err != nil && client.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, errors.WithStack(err)
+	}
+
+	if err == nil {
+		// Ignore a Job that belongs to something else.
+		if !metav1.IsControlledBy(job, backup) {
+			waiting("JobConflict", fmt.Sprintf(
+				"Job %q exists but does not belong to this backup", job.Name))
+			return reconcile.Result{}, nil
+		}
+		return r.observeBackupJob(ctx, cluster, *repo, backup, job)
+	}
+
+	if reason, message := backupObjectBlocked(cluster, backup); reason != "" {
+		waiting(reason, message)
+		return reconcile.Result{}, nil
+	}
+
+	// pgBackRest allows only one backup of a stanza at a time. Wait for any
+	// other backup Job of the cluster to finish before starting this one.
+	if active, _, err := r.activeBackupJob(ctx, cluster, ""); err != nil || active != "" {
+		if active != "" {
+			waiting("BackupInProgress", fmt.Sprintf(
+				"Waiting for Job %q to finish", active))
+		}
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, err
+	}
+
+	// pgBackRest connects to a PostgreSQL instance that is not in recovery to
+	// initiate a backup.
+	primary, err := naming.AsSelector(naming.ClusterPrimary(cluster.Name))
+	if err != nil {
+		return reconcile.Result{}, errors.WithStack(err)
+	}
+	if pod, err := r.findRunningPod(ctx, cluster, primary); err != nil || pod == nil {
+		if err == nil {
+			waiting("ClusterNotWritable", "Waiting for a writable PostgreSQL instance")
+		}
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, err
+	}
+
+	if err := r.applyBackupObjectJob(ctx, cluster, *repo, backup, job); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	backup.Status.JobName = job.Name
+	waiting("Running", fmt.Sprintf("Job %q is running", job.Name))
+	return reconcile.Result{}, nil
+}
+
+// backupObjectBlocked returns a reason and message when cluster is not able to
+// take the backup described by backup. It returns empty strings otherwise.
+func backupObjectBlocked(
+	cluster *v1beta1.PostgresCluster, backup *v1beta1.PGBackRestBackup,
+) (string, string) {
+	// Users should specify the repo for the command using the "repoName" field,
+	// and not using the "--repo" option in the "options" field.
+	for _, opt := range backup.Spec.Options {
+		if strings.Contains(opt, "--repo") {
+			return "InvalidOptions",
+				"Option '--repo' is not allowed: please use the 'repoName' field instead."
+		}
+	}
+
+	if cluster.GetDeletionTimestamp() != nil {
+		return "ClusterDeleting", "PostgresCluster is being deleted"
+	}
+	if cluster.Spec.Shutdown != nil && *cluster.Spec.Shutdown {
+		return "ClusterShutdown", "PostgresCluster is shut down"
+	}
+	if cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled {
+		return "ClusterStandby", "PostgresCluster is a standby"
+	}
+
+	// Wait for the dedicated repository host, when there is one.
+	if pgbackrest.DedicatedRepoHostEnabled(cluster) {
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionRepoHostReady)
+		if condition == nil || condition.Status != metav1.ConditionTrue {
+			return "RepoHostNotReady", "Waiting for the pgBackRest repository host"
+		}
+	}
+
+	// Wait for the backup taken to create replicas. This also ensures that the
+	// cluster has been bootstrapped.
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionReplicaCreate)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return "ReplicaCreateBackupIncomplete",
+			"Waiting for the initial backup of the PostgresCluster"
+	}
+
+	for _, status := range cluster.Status.PGBackRest.Repos {
+		if status.Name == backup.Spec.RepoName && status.StanzaCreated {
+			return "", ""
+		}
+	}
+	return "StanzaNotCreated", fmt.Sprintf(
+		"Waiting for the pgBackRest stanza of %q", backup.Spec.RepoName)
+}
+
+// findRunningPod returns a running Pod of cluster that matches selector, if any.
+func (r *Reconciler) findRunningPod(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, selector labels.Selector,
+) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning &&
+			pods.Items[i].GetDeletionTimestamp() == nil {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// applyBackupObjectJob creates the pgBackRest backup Job of backup.
+func (r *Reconciler) applyBackupObjectJob(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, repo v1beta1.PGBackRestRepo,
+	backup *v1beta1.PGBackRestBackup, job *batchv1.Job,
+) error {
+	labels := naming.Merge(cluster.Spec.Metadata.GetLabelsOrNil(),
+		cluster.Spec.Backups.PGBackRest.Metadata.GetLabelsOrNil(),
+		naming.PGBackRestBackupJobLabels(cluster.Name, repo.Name,
+			naming.BackupDeclarative))
+	annotations := naming.Merge(cluster.Spec.Metadata.GetAnnotationsOrNil(),
+		cluster.Spec.Backups.PGBackRest.Metadata.GetAnnotationsOrNil())
+
+	job.Labels = labels
+	job.Annotations = annotations
+
+	var opts []string
+	if backup.Spec.Type != "" {
+		opts = append(opts, "--type="+backup.Spec.Type)
+	}
+	opts = append(opts, backup.Spec.Options...)
+
+	spec, err := generateBackupJobSpecIntent(cluster, repo,
+		naming.PGBackRestRBAC(cluster).Name, labels, annotations, opts...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	job.Spec = *spec
+
+	// The backup controls the Job, and the cluster also owns it so that the
+	// Job is garbage collected when either is deleted.
+	job.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
+	err = controllerutil.SetControllerReference(backup, job, r.Client.Scheme())
+	if err == nil {
+		err = r.setOwnerReference(cluster, job)
+	}
+	if err == nil {
+		err = r.apply(ctx, job)
+	}
+	return errors.WithStack(err)
+}
+
+// observeBackupJob copies the status of job to backup. Once job completes, it
+// also looks up the backup it took in the pgBackRest repository. The backup is
+// marked as failed when pgBackRest does not list it within backupInfoTimeout
+// of job, or any other backup Job of cluster, finishing.
+func (r *Reconciler) observeBackupJob(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, repo v1beta1.PGBackRestRepo,
+	backup *v1beta1.PGBackRestBackup, job *batchv1.Job,
+) (reconcile.Result, error) {
+	completed, failed := jobCompleted(job), jobFailed(job)
+
+	backup.Status.JobName = job.Name
+	backup.Status.StartTime = job.Status.StartTime
+	backup.Status.CompletionTime = job.Status.CompletionTime
+	backup.Status.Active = job.Status.Active
+	backup.Status.Succeeded = job.Status.Succeeded
+	backup.Status.Failed = job.Status.Failed
+	backup.Status.Finished = completed || failed
+
+	condition := metav1.Condition{
+		ObservedGeneration: backup.GetGeneration(),
+		Type:               v1beta1.PGBackRestBackupSucceeded,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Running",
+		Message:            fmt.Sprintf("Job %q is running", job.Name),
+	}
+	if completed {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "BackupComplete"
+		condition.Message = "Backup completed successfully"
+	}
+	if failed {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "BackupFailed"
+		condition.Message = "Backup did not complete successfully"
+	}
+	meta.SetStatusCondition(&backup.Status.Conditions, condition)
+
+	if !completed || backup.Status.Backup != nil {
+		return reconcile.Result{}, nil
+	}
+
+	selector, container, err := getPGBackRestExecSelector(cluster, repo)
+	if err != nil {
+		return reconcile.Result{}, errors.WithStack(err)
+	}
+	pod, err := r.findRunningPod(ctx, cluster, selector)
+	if err != nil || pod == nil {
+		return reconcile.Result{RequeueAfter: time.Minute}, err
+	}
+
+	info, err := pgbackrest.Executor(func(
+		ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
+	}).Info(ctx, regexRepoIndex.FindString(repo.Name))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	backup.Status.Backup = backupInfoForJob(info, job)
+	if backup.Status.Backup != nil {
+		return reconcile.Result{}, nil
+	}
+
+	// pgBackRest may not list the backup yet. Ask again later, but give up
+	// once it has had plenty of time. Other backups hold the stanza lock while
+	// they run, so that time starts over when the latest of them finishes.
+	active, finished, err := r.activeBackupJob(ctx, cluster, job.Name)
+	if err != nil || active != "" {
+		return reconcile.Result{RequeueAfter: time.Minute}, err
+	}
+	if job.Status.CompletionTime != nil && finished.Before(job.Status.CompletionTime.Time) {
+		finished = job.Status.CompletionTime.Time
+	}
+	if !finished.IsZero() && time.Since(finished) > backupInfoTimeout {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "BackupNotFound"
+		condition.Message = fmt.Sprintf(
+			"Job %q completed, but pgBackRest does not list the backup it took", job.Name)
+		meta.SetStatusCondition(&backup.Status.Conditions, condition)
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
+
+// backupInfoForJob returns the most recent backup in info that ran while job
+// was running. It returns nil when there is no such backup.
+func backupInfoForJob(info pgbackrest.InfoOutput, job *batchv1.Job) *v1beta1.PGBackRestBackupInfo {
+	var found *pgbackrest.InfoBackup
+	for i := range info {
+		if info[i].Name != pgbackrest.DefaultStanzaName {
+			continue
+		}
+		for j := range info[i].Backup {
+			b := &info[i].Backup[j]
+			if job.Status.StartTime != nil &&
+				b.Timestamp.Start < job.Status.StartTime.Unix() {
+				continue
+			}
+			if job.Status.CompletionTime != nil &&
+				b.Timestamp.Stop > job.Status.CompletionTime.Unix() {
+				continue
+			}
+			if found == nil || b.Timestamp.Start > found.Timestamp.Start {
+				found = b
+			}
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	return backupInfo(found)
+}
+
+// backupInfo converts the pgBackRest description of a backup to its API form.
+func backupInfo(b *pgbackrest.InfoBackup) *v1beta1.PGBackRestBackupInfo {
+	start := metav1.Unix(b.Timestamp.Start, 0).Rfc3339Copy()
+	stop := metav1.Unix(b.Timestamp.Stop, 0).Rfc3339Copy()
+
+	return &v1beta1.PGBackRestBackupInfo{
+		Label:        b.Label,
+		Type:         b.Type,
+		StartTime:    &start,
+		StopTime:     &stop,
+		Duration:     &metav1.Duration{Duration: stop.Sub(start.Time)},
+		DatabaseSize: resource.NewQuantity(b.Info.Size, resource.BinarySI),
+		RepoSize:     resource.NewQuantity(b.Info.Repository.Size, resource.BinarySI),
+		WALStart:     b.Archive.Start,
+		WALStop:      b.Archive.Stop,
+	}
+}
+
+// watchClusterForBackups returns a handler.EventHandler that queues the
+// unfinished PGBackRestBackups of a PostgresCluster whenever it changes.
+func (r *Reconciler) watchClusterForBackups() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(cluster client.Object) []reconcile.Request {
+		ctx := context.Background()
+		backups := &v1beta1.PGBackRestBackupList{}
+		if err := r.Client.List(ctx, backups,
+			client.InNamespace(cluster.GetNamespace())); err != nil {
+			logging.FromContext(ctx).Error(err, "listing PGBackRestBackups")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for i := range backups.Items {
+			if backups.Items[i].Spec.PostgresClusterName == cluster.GetName() &&
+				!backups.Items[i].Status.Finished {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(&backups.Items[i]),
+				})
+			}
+		}
+		return requests
+	})
+}
+
+// SetupBackupControllerWithManager adds the PGBackRestBackup controller to the
+// provided runtime manager
+func (r *Reconciler) SetupBackupControllerWithManager(mgr manager.Manager) error {
+	if r.PodExec == nil {
+		var err error
+		r.PodExec, err = newPodExecutor(mgr.GetConfig())
+		if err != nil {
+			return err
+		}
+	}
+
+	return builder.ControllerManagedBy(mgr).
+		Named(BackupControllerName).
+		For(&v1beta1.PGBackRestBackup{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &v1beta1.PostgresCluster{}},
+			r.watchClusterForBackups()).
+		Complete(reconcile.Func(r.ReconcileBackup))
+}
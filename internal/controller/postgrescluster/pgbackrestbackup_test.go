@@ -0,0 +1,360 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestBackupObjectBlocked(t *testing.T) {
+	ready := func() *v1beta1.PostgresCluster {
+		cluster := new(v1beta1.PostgresCluster)
+		cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{{
+			Name: "repo1", Volume: &v1beta1.RepoPVC{},
+		}}
+		cluster.Status.Conditions = []metav1.Condition{
+			{Type: ConditionRepoHostReady, Status: metav1.ConditionTrue},
+			{Type: ConditionReplicaCreate, Status: metav1.ConditionTrue},
+		}
+		cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+			Repos: []v1beta1.RepoStatus{{Name: "repo1", StanzaCreated: true}},
+		}
+		return cluster
+	}
+	backup := new(v1beta1.PGBackRestBackup)
+	backup.Spec.RepoName = "repo1"
+
+	t.Run("Ready", func(t *testing.T) {
+		reason, message := backupObjectBlocked(ready(), backup)
+		assert.Equal(t, reason, "")
+		assert.Equal(t, message, "")
+	})
+
+	for _, tt := range []struct {
+		reason string
+		change func(*v1beta1.PostgresCluster, *v1beta1.PGBackRestBackup)
+	}{
+		{
+			reason: "InvalidOptions",
+			change: func(_ *v1beta1.PostgresCluster, b *v1beta1.PGBackRestBackup) {
+				b.Spec.Options = []string{"--repo=2"}
+			},
+		},
+		{
+			reason: "ClusterDeleting",
+			change: func(c *v1beta1.PostgresCluster, _ *v1beta1.PGBackRestBackup) {
+				now := metav1.Now()
+				c.DeletionTimestamp = &now
+			},
+		},
+		{
+			reason: "ClusterShutdown",
+			change: func(c *v1beta1.PostgresCluster, _ *v1beta1.PGBackRestBackup) {
+				c.Spec.Shutdown = initialize.Bool(true)
+			},
+		},
+		{
+			reason: "ClusterStandby",
+			change: func(c *v1beta1.PostgresCluster, _ *v1beta1.PGBackRestBackup) {
+				c.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true}
+			},
+		},
+		{
+			reason: "RepoHostNotReady",
+			change: func(c *v1beta1.PostgresCluster, _ *v1beta1.PGBackRestBackup) {
+				c.Status.Conditions[0].Status = metav1.ConditionFalse
+			},
+		},
+		{
+			reason: "ReplicaCreateBackupIncomplete",
+			change: func(c *v1beta1.PostgresCluster, _ *v1beta1.PGBackRestBackup) {
+				c.Status.Conditions = c.Status.Conditions[:1]
+			},
+		},
+		{
+			reason: "StanzaNotCreated",
+			change: func(c *v1beta1.PostgresCluster, _ *v1beta1.PGBackRestBackup) {
+				c.Status.PGBackRest.Repos[0].StanzaCreated = false
+			},
+		},
+	} {
+		t.Run(tt.reason, func(t *testing.T) {
+			cluster, backup := ready(), backup.DeepCopy()
+			tt.change(cluster, backup)
+
+			reason, message := backupObjectBlocked(cluster, backup)
+			assert.Equal(t, reason, tt.reason)
+			assert.Assert(t, message != "")
+		})
+	}
+}
+
+func TestBackupInfoForJob(t *testing.T) {
+	started := metav1.NewTime(time.Unix(1654100000, 0))
+	completed := metav1.NewTime(time.Unix(1654100200, 0))
+	job := new(batchv1.Job)
+	job.Status.StartTime = &started
+	job.Status.CompletionTime = &completed
+
+	backup := func(label string, start, stop int64) pgbackrest.InfoBackup {
+		var b pgbackrest.InfoBackup
+		b.Label, b.Type = label, "incr"
+		b.Timestamp.Start, b.Timestamp.Stop = start, stop
+		b.Archive.Start, b.Archive.Stop = "000000010000000000000003", "000000010000000000000004"
+		b.Info.Size, b.Info.Repository.Size = 25165824, 3145728
+		return b
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.Assert(t, backupInfoForJob(nil, job) == nil)
+	})
+
+	t.Run("Outside", func(t *testing.T) {
+		info := pgbackrest.InfoOutput{{
+			Name: "db",
+			Backup: []pgbackrest.InfoBackup{
+				backup("older", 1654099990, 1654099995),
+				backup("later", 1654100300, 1654100330),
+			},
+		}}
+		assert.Assert(t, backupInfoForJob(info, job) == nil)
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		info := pgbackrest.InfoOutput{{
+			Name: "db",
+			Backup: []pgbackrest.InfoBackup{
+				backup("older", 1654099990, 1654099995),
+				backup("newest", 1654100100, 1654100130),
+				backup("newer", 1654100010, 1654100020),
+				backup("later", 1654100300, 1654100330),
+			},
+		}}
+
+		result := backupInfoForJob(info, job)
+		assert.Assert(t, result != nil)
+		assert.Equal(t, result.Label, "newest")
+		assert.Equal(t, result.Type, "incr")
+		assert.Equal(t, result.StartTime.Unix(), int64(1654100100))
+		assert.Equal(t, result.StopTime.Unix(), int64(1654100130))
+		assert.Equal(t, result.Duration.Duration, 30*time.Second)
+		assert.Equal(t, result.DatabaseSize.String(), "24Mi")
+		assert.Equal(t, result.RepoSize.String(), "3Mi")
+		assert.Equal(t, result.WALStart, "000000010000000000000003")
+		assert.Equal(t, result.WALStop, "000000010000000000000004")
+	})
+}
+
+func TestObserveBackupJobNotListed(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	repo := v1beta1.PGBackRestRepo{Name: "repo1", S3: &v1beta1.RepoS3{}}
+
+	pod := &corev1.Pod{}
+	pod.Namespace, pod.Name = "ns1", "hippo-pod"
+	pod.Labels = map[string]string{
+		naming.LabelCluster:  cluster.Name,
+		naming.LabelInstance: "hippo-abcd",
+		naming.LabelRole:     naming.RolePatroniLeader,
+	}
+	pod.Status.Phase = corev1.PodRunning
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(pod).Build(),
+		PodExec: func(
+			_, _, _ string, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			// The repository has no backups yet.
+			_, _ = stdout.Write([]byte(`[{"name": "db", "backup": []}]`))
+			return nil
+		},
+	}
+
+	completedAt := func(completed time.Time) *batchv1.Job {
+		job := &batchv1.Job{}
+		job.Name = "backup-job"
+		job.Status.StartTime = &metav1.Time{Time: completed.Add(-time.Minute)}
+		job.Status.CompletionTime = &metav1.Time{Time: completed}
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		return job
+	}
+
+	t.Run("Recent", func(t *testing.T) {
+		backup := &v1beta1.PGBackRestBackup{}
+		result, err := r.observeBackupJob(ctx, cluster, repo, backup, completedAt(time.Now()))
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, time.Minute, "expected to ask again")
+		assert.Assert(t, backup.Status.Backup == nil)
+		assert.Assert(t, meta.IsStatusConditionTrue(
+			backup.Status.Conditions, v1beta1.PGBackRestBackupSucceeded))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		backup := &v1beta1.PGBackRestBackup{}
+		result, err := r.observeBackupJob(ctx, cluster, repo, backup,
+			completedAt(time.Now().Add(-backupInfoTimeout-time.Minute)))
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, time.Duration(0))
+
+		condition := meta.FindStatusCondition(
+			backup.Status.Conditions, v1beta1.PGBackRestBackupSucceeded)
+		assert.Assert(t, condition != nil)
+		assert.Equal(t, condition.Status, metav1.ConditionFalse)
+		assert.Equal(t, condition.Reason, "BackupNotFound")
+	})
+
+	// Another backup Job of the cluster that holds the stanza lock.
+	other := &batchv1.Job{}
+	other.Namespace, other.Name = "ns1", "other-backup"
+	other.Labels = naming.PGBackRestBackupJobLabels(cluster.Name, repo.Name, naming.BackupManual)
+
+	t.Run("OtherBackupActive", func(t *testing.T) {
+		r := *r
+		r.Client = fake.NewClientBuilder().WithObjects(pod, other.DeepCopy()).Build()
+
+		backup := &v1beta1.PGBackRestBackup{}
+		result, err := r.observeBackupJob(ctx, cluster, repo, backup,
+			completedAt(time.Now().Add(-backupInfoTimeout-time.Minute)))
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, time.Minute, "expected to ask again")
+		assert.Assert(t, meta.IsStatusConditionTrue(
+			backup.Status.Conditions, v1beta1.PGBackRestBackupSucceeded))
+	})
+
+	t.Run("OtherBackupFinished", func(t *testing.T) {
+		finished := other.DeepCopy()
+		finished.Status.Conditions = []batchv1.JobCondition{{
+			Type: batchv1.JobComplete, Status: corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+		}}
+
+		r := *r
+		r.Client = fake.NewClientBuilder().WithObjects(pod, finished).Build()
+
+		backup := &v1beta1.PGBackRestBackup{}
+		result, err := r.observeBackupJob(ctx, cluster, repo, backup,
+			completedAt(time.Now().Add(-backupInfoTimeout-time.Minute)))
+		assert.NilError(t, err)
+		assert.Equal(t, result.RequeueAfter, time.Minute,
+			"expected the time to start over when the other backup finished")
+		assert.Assert(t, meta.IsStatusConditionTrue(
+			backup.Status.Conditions, v1beta1.PGBackRestBackupSucceeded))
+	})
+}
+
+func TestActiveBackupJob(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+
+	finishedAt := metav1.NewTime(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	job := func(name string, labels map[string]string, finished bool) *batchv1.Job {
+		job := &batchv1.Job{}
+		job.Namespace, job.Name, job.Labels = "ns1", name, labels
+		if finished {
+			job.Status.Conditions = []batchv1.JobCondition{{
+				Type: batchv1.JobFailed, Status: corev1.ConditionTrue,
+				LastTransitionTime: finishedAt,
+			}}
+		}
+		return job
+	}
+
+	r := &Reconciler{Client: fake.NewClientBuilder().WithObjects(
+		job("manual", naming.PGBackRestBackupJobLabels(
+			cluster.Name, "repo1", naming.BackupManual), true),
+		job("scheduled", naming.PGBackRestCronJobLabels(cluster.Name, "repo1", full), false),
+		job("verify", naming.PGBackRestCronJobLabels(cluster.Name, "repo1", verify), false),
+		job("elsewhere", naming.PGBackRestBackupJobLabels(
+			"other", "repo1", naming.BackupReplicaCreate), false),
+	).Build()}
+
+	active, finished, err := r.activeBackupJob(ctx, cluster, "")
+	assert.NilError(t, err)
+	assert.Equal(t, active, "scheduled", "expected scheduled backups to count")
+	assert.Assert(t, finished.Equal(finishedAt.Time))
+
+	active, _, err = r.activeBackupJob(ctx, cluster, "scheduled")
+	assert.NilError(t, err)
+	assert.Equal(t, active, "", "expected other clusters and maintenance to be ignored")
+}
+
+func TestReconcileManualBackupWaits(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Annotations = map[string]string{naming.PGBackRestBackup: "one"}
+	cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+		{Name: "repo1", S3: &v1beta1.RepoS3{}},
+	}
+	cluster.Spec.Backups.PGBackRest.Manual = &v1beta1.PGBackRestManualBackup{RepoName: "repo1"}
+	cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+		Repos: []v1beta1.RepoStatus{{Name: "repo1", StanzaCreated: true}},
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type: ConditionReplicaCreate, Status: metav1.ConditionTrue, Reason: "testing",
+	})
+
+	instances := &observedInstances{forCluster: []*Instance{{
+		Name: "instance1",
+		Pods: []*corev1.Pod{{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"status": `"role":"master"`},
+		}}},
+	}}}
+
+	// A scheduled backup is running.
+	scheduled := &batchv1.Job{}
+	scheduled.Namespace, scheduled.Name = "ns1", "hippo-repo1-full-123"
+	scheduled.Labels = naming.PGBackRestCronJobLabels(cluster.Name, "repo1", full)
+
+	r := &Reconciler{Client: fake.NewClientBuilder().WithObjects(scheduled).Build()}
+
+	result, err := r.reconcileManualBackup(ctx, cluster, nil, &corev1.ServiceAccount{}, instances)
+	assert.NilError(t, err)
+	assert.Assert(t, result.RequeueAfter > 0, "expected to check again")
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionManualBackupSuccessful)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionUnknown)
+	assert.Equal(t, condition.Reason, "BackupInProgress")
+	assert.Assert(t, strings.Contains(condition.Message, scheduled.Name))
+
+	jobs := &batchv1.JobList{}
+	assert.NilError(t, r.Client.List(ctx, jobs))
+	assert.Equal(t, len(jobs.Items), 1, "expected no manual backup Job")
+}
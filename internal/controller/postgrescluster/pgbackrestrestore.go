@@ -0,0 +1,622 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// RestoreControllerName is the name of the PGBackRestRestoreRequest controller
+	RestoreControllerName = "pgbackrestrestore-controller"
+)
+
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=pgbackrestrestorerequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=pgbackrestrestorerequests/status,verbs=patch
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=postgresclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// ReconcileRestore validates the target of a PGBackRestRestoreRequest against the
+// backups in its repository and then follows the progress of the in-place
+// restore that the PostgresCluster controller performs once it is validated.
+func (r *Reconciler) ReconcileRestore(
+	ctx context.Context, request reconcile.Request) (reconcile.Result, error,
+) {
+	ctx, span := r.Tracer.Start(ctx, "ReconcileRestore")
+	log := logging.FromContext(ctx)
+	defer span.End()
+
+	restore := &v1beta1.PGBackRestRestoreRequest{}
+	if err := r.Client.Get(ctx, request.NamespacedName, restore); err != nil {
+		// NotFound cannot be fixed by requeuing so ignore it.
+		if err = client.IgnoreNotFound(err); err != nil {
+			log.Error(err, "unable to fetch PGBackRestRestoreRequest")
+			span.RecordError(err)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Keep a copy of restore prior to any manipulations.
+	before := restore.DeepCopy()
+
+	result, err := r.reconcileRestoreObject(ctx, restore)
+
+	if !equality.Semantic.DeepEqual(before.Status, restore.Status) {
+		if err := errors.WithStack(r.Client.Status().Patch(
+			ctx, restore, client.MergeFrom(before), r.Owner)); err != nil {
+			log.Error(err, "patching restore status")
+			return result, err
+		}
+		log.V(1).Info("patched restore status")
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	}
+	return result, err
+}
+
+// reconcileRestoreObject updates the status of restore according to its cluster.
+func (r *Reconciler) reconcileRestoreObject(
+	ctx context.Context, restore *v1beta1.PGBackRestRestoreRequest,
+) (reconcile.Result, error) {
+	restore.Status.ObservedGeneration = restore.GetGeneration()
+
+	// Nothing more happens once the restore has finished.
+	if restore.Status.Finished {
+		return reconcile.Result{}, nil
+	}
+
+	setValidated := func(status metav1.ConditionStatus, reason, message string) {
+		setRestoreValidated(restore, status, reason, message)
+	}
+
+	cluster := &v1beta1.PostgresCluster{}
+	err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: restore.GetNamespace(), Name: restore.Spec.PostgresClusterName,
+	}, cluster)
+	if err != nil {
+		// A missing cluster may be created later; the cluster watch will
+		// trigger another reconcile when it is.
+		if client.IgnoreNotFound(err) == nil {
+			setValidated(metav1.ConditionUnknown, "ClusterNotFound", fmt.Sprintf(
+				"PostgresCluster %q not found", restore.Spec.PostgresClusterName))
+		}
+		return reconcile.Result{}, client.IgnoreNotFound(errors.WithStack(err))
+	}
+
+	// Once the cluster starts this restore, report its progress.
+	if status := cluster.Status.PGBackRest; status != nil &&
+		status.Restore != nil && status.Restore.ID == restoreObjectID(restore) {
+		observeRestoreProgress(restore, status.Restore)
+		return reconcile.Result{}, nil
+	}
+
+	// Validate the target whenever the spec changes, and keep trying while
+	// the verdict is unknown.
+	validated := meta.FindStatusCondition(restore.Status.Conditions,
+		v1beta1.PGBackRestRestoreValidated)
+	if validated == nil || validated.Status == metav1.ConditionUnknown ||
+		validated.ObservedGeneration != restore.GetGeneration() {
+
+		result, err := r.validateRestoreObject(ctx, cluster, restore, setValidated)
+		if err != nil || result.RequeueAfter > 0 {
+			return result, err
+		}
+	}
+
+	// Like the annotation, a request is performed only when in-place restores
+	// are enabled on the cluster. The cluster watch triggers another reconcile
+	// when they are.
+	if !restore.Spec.DryRun && meta.IsStatusConditionTrue(
+		restore.Status.Conditions, v1beta1.PGBackRestRestoreValidated) {
+		pending := metav1.Condition{
+			ObservedGeneration: restore.GetGeneration(),
+			Type:               v1beta1.PGBackRestRestoreSucceeded,
+			Status:             metav1.ConditionUnknown,
+			Reason:             "Pending",
+			Message:            "Waiting for the PostgresCluster to begin the restore",
+		}
+		if !inPlaceRestoreEnabled(cluster) {
+			pending.Reason = "RestoreNotEnabled"
+			pending.Message = fmt.Sprintf(
+				"In-place restores are not enabled; set spec.backups.pgbackrest.restore.enabled on PostgresCluster %q",
+				cluster.Name)
+		}
+		meta.SetStatusCondition(&restore.Status.Conditions, pending)
+	}
+	return reconcile.Result{}, nil
+}
+
+// validateRestoreObject checks the target of restore against the backups in
+// its repository and records the verdict using setValidated.
+func (r *Reconciler) validateRestoreObject(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, restore *v1beta1.PGBackRestRestoreRequest,
+	setValidated func(status metav1.ConditionStatus, reason, message string),
+) (reconcile.Result, error) {
+	restore.Status.Backup = nil
+
+	var repo *v1beta1.PGBackRestRepo
+	for i := range cluster.Spec.Backups.PGBackRest.Repos {
+		if cluster.Spec.Backups.PGBackRest.Repos[i].Name == restore.Spec.RepoName {
+			repo = &cluster.Spec.Backups.PGBackRest.Repos[i]
+		}
+	}
+	if repo == nil {
+		setValidated(metav1.ConditionFalse, "RepoNotFound", fmt.Sprintf(
+			"PostgresCluster %q does not define %q", cluster.Name, restore.Spec.RepoName))
+		return reconcile.Result{}, nil
+	}
+
+	if reason, message := restoreObjectInvalid(restore); reason != "" {
+		setValidated(metav1.ConditionFalse, reason, message)
+		return reconcile.Result{}, nil
+	}
+
+	// Any running instance can read a cloud repository, but only the dedicated
+	// repository host has a volume repository.
+	selector, container := naming.ClusterInstances(cluster.Name), naming.ContainerDatabase
+	if repo.Volume != nil {
+		selector = metav1.LabelSelector{
+			MatchLabels: naming.PGBackRestDedicatedLabels(cluster.Name),
+		}
+		container = naming.PGBackRestRepoContainerName
+	}
+	s, err := naming.AsSelector(selector)
+	if err != nil {
+		return reconcile.Result{}, errors.WithStack(err)
+	}
+	pod, err := r.findRunningPod(ctx, cluster, s)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if pod == nil {
+		setValidated(metav1.ConditionUnknown, "RepoUnreachable",
+			"Waiting for a running Pod that can read the pgBackRest repository")
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	info, err := pgbackrest.Executor(func(
+		ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(pod.Namespace, pod.Name, container, stdin, stdout, stderr, command...)
+	}).Info(ctx, regexRepoIndex.FindString(repo.Name))
+	if err != nil {
+		setValidated(metav1.ConditionUnknown, "RepoUnreachable", err.Error())
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	backup, reason, message := validateRestoreTarget(info, restore.Spec.Target, time.Now())
+	if reason != "" {
+		setValidated(metav1.ConditionFalse, reason, message)
+		return reconcile.Result{}, nil
+	}
+
+	restore.Status.Backup = backupInfo(backup)
+	setValidated(metav1.ConditionTrue, "TargetRecoverable", fmt.Sprintf(
+		"The target can be recovered from backup %q", backup.Label))
+	return reconcile.Result{}, nil
+}
+
+// revalidateRestoreObject checks the target of restore again right before
+// cluster begins it. It returns false and fails restore when the target is no
+// longer recoverable. It also returns false when the repository cannot be read;
+// the PGBackRestRestoreRequest controller then tries again.
+func (r *Reconciler) revalidateRestoreObject(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, restore *v1beta1.PGBackRestRestoreRequest,
+) (bool, error) {
+	before := restore.DeepCopy()
+
+	var valid metav1.ConditionStatus
+	_, err := r.validateRestoreObject(ctx, cluster, restore,
+		func(status metav1.ConditionStatus, reason, message string) {
+			valid = status
+			setRestoreValidated(restore, status, reason, message)
+		})
+	if err != nil {
+		return false, err
+	}
+	if valid == metav1.ConditionFalse {
+		meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+			ObservedGeneration: restore.GetGeneration(),
+			Type:               v1beta1.PGBackRestRestoreSucceeded,
+			Status:             metav1.ConditionFalse,
+			Reason:             "NotValidated",
+			Message:            "The target was no longer recoverable when the restore was to begin",
+		})
+	}
+
+	if !equality.Semantic.DeepEqual(before.Status, restore.Status) {
+		err = errors.WithStack(r.Client.Status().Patch(
+			ctx, restore, client.MergeFrom(before), r.Owner))
+	}
+	return err == nil && valid == metav1.ConditionTrue, err
+}
+
+// setRestoreValidated sets the Validated condition of restore.
+func setRestoreValidated(
+	restore *v1beta1.PGBackRestRestoreRequest,
+	status metav1.ConditionStatus, reason, message string,
+) {
+	meta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		ObservedGeneration: restore.GetGeneration(),
+		Type:               v1beta1.PGBackRestRestoreValidated,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// restoreObjectInvalid returns a reason and message when the spec of restore
+// cannot be used without looking at its repository. It returns empty strings
+// otherwise.
+func restoreObjectInvalid(restore *v1beta1.PGBackRestRestoreRequest) (string, string) {
+	target := restore.Spec.Target
+	if target != nil {
+		var set []string
+		if target.Time != nil {
+			set = append(set, "time")
+		}
+		if target.LSN != "" {
+			set = append(set, "lsn")
+		}
+		if target.XID != "" {
+			set = append(set, "xid")
+		}
+		if len(set) > 1 {
+			return "InvalidTarget", fmt.Sprintf(
+				"Only one of %s may be set", strings.Join(set, ", "))
+		}
+	}
+
+	for _, opt := range restore.Spec.Options {
+		switch {
+		case strings.Contains(opt, "--repo"):
+			return "InvalidOptions",
+				"Option '--repo' is not allowed: please use the 'repoName' field instead."
+		case target != nil && (strings.Contains(opt, "--type") ||
+			strings.Contains(opt, "--target") || strings.Contains(opt, "--set")):
+			return "InvalidOptions", fmt.Sprintf(
+				"Option %q is not allowed: please use the 'target' field instead.", opt)
+		}
+	}
+	return "", ""
+}
+
+// validateRestoreTarget returns the backup in info that pgBackRest would use
+// to recover target. When target cannot be recovered, it returns a reason and
+// message explaining why.
+func validateRestoreTarget(
+	info pgbackrest.InfoOutput, target *v1beta1.PGBackRestRestoreTarget, now time.Time,
+) (*pgbackrest.InfoBackup, string, string) {
+	var stanza *pgbackrest.InfoStanza
+	for i := range info {
+		if info[i].Name == pgbackrest.DefaultStanzaName {
+			stanza = &info[i]
+		}
+	}
+	if stanza == nil {
+		return nil, "StanzaNotFound", fmt.Sprintf(
+			"The repository has no stanza named %q", pgbackrest.DefaultStanzaName)
+	}
+
+	// Codes other than "ok" and "backup/expire running" indicate that the
+	// repository cannot be used.
+	// - https://pgbackrest.org/command.html#command-info/category-command/option-output
+	if stanza.Status.Code != 0 && stanza.Status.Code != 6 {
+		return nil, "RepoUnavailable", fmt.Sprintf(
+			"pgBackRest reported: %s", stanza.Status.Message)
+	}
+
+	// Consider the backups in the order they were taken.
+	backups := make([]*pgbackrest.InfoBackup, 0, len(stanza.Backup))
+	for i := range stanza.Backup {
+		backups = append(backups, &stanza.Backup[i])
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Timestamp.Stop < backups[j].Timestamp.Stop
+	})
+	if len(backups) == 0 {
+		return nil, "NoBackups", "The repository has no backups"
+	}
+
+	if target != nil && target.BackupLabel != "" {
+		var labeled *pgbackrest.InfoBackup
+		for _, b := range backups {
+			if b.Label == target.BackupLabel {
+				labeled = b
+			}
+		}
+		if labeled == nil {
+			return nil, "BackupNotFound", fmt.Sprintf(
+				"The repository has no backup labeled %q", target.BackupLabel)
+		}
+		backups = []*pgbackrest.InfoBackup{labeled}
+	}
+
+	// Recovery to a point requires a backup that finished before that point.
+	// pgBackRest chooses the most recent one.
+	var chosen *pgbackrest.InfoBackup
+	switch {
+	case target != nil && target.Time != nil:
+		if target.Time.Time.After(now) {
+			return nil, "TargetInFuture", fmt.Sprintf(
+				"The target time %s has not happened yet", target.Time.UTC().Format(time.RFC3339))
+		}
+		for _, b := range backups {
+			if b.Timestamp.Stop <= target.Time.Unix() {
+				chosen = b
+			}
+		}
+		if chosen == nil {
+			return nil, "TargetBeforeBackups", fmt.Sprintf(
+				"The earliest recoverable time is %s",
+				time.Unix(backups[0].Timestamp.Stop, 0).UTC().Format(time.RFC3339))
+		}
+
+	case target != nil && target.LSN != "":
+		lsn, err := pgbackrest.ParseLSN(target.LSN)
+		if err != nil {
+			return nil, "InvalidTarget", err.Error()
+		}
+		for _, b := range backups {
+			if stop, err := pgbackrest.ParseLSN(b.LSN.Stop); err == nil && stop <= lsn {
+				chosen = b
+			}
+		}
+		if chosen == nil {
+			return nil, "TargetBeforeBackups", fmt.Sprintf(
+				"The earliest recoverable LSN is %s", backups[0].LSN.Stop)
+		}
+
+	default:
+		// The recoverable range of transaction IDs is not reported by pgBackRest,
+		// so check only that there is a backup.
+		chosen = backups[len(backups)-1]
+	}
+
+	// Recovery cannot go past the newest archived WAL, and a backup is only
+	// consistent once the WAL written during it is archived. The info output
+	// has no times for archived WAL, so a time target is checked only against
+	// the backup it needs.
+	if end, ok := stanza.ArchiveEnd(); ok {
+		if stop, err := pgbackrest.ParseLSN(chosen.LSN.Stop); err == nil && stop > end {
+			return nil, "WALNotArchived", fmt.Sprintf(
+				"The WAL needed to recover backup %q has not been archived", chosen.Label)
+		}
+		if target != nil && target.LSN != "" {
+			if lsn, _ := pgbackrest.ParseLSN(target.LSN); lsn > end {
+				return nil, "TargetAfterArchive", fmt.Sprintf(
+					"The latest archived LSN is %s", pgbackrest.FormatLSN(end))
+			}
+		}
+	}
+
+	return chosen, "", ""
+}
+
+// inPlaceRestoreEnabled returns whether or not cluster allows in-place restores.
+func inPlaceRestoreEnabled(cluster *v1beta1.PostgresCluster) bool {
+	restore := cluster.Spec.Backups.PGBackRest.Restore
+	return restore != nil && restore.Enabled != nil && *restore.Enabled
+}
+
+// restoreTargetOptions returns the pgBackRest restore options that recover
+// PostgreSQL to target.
+// - https://pgbackrest.org/command.html#command-restore
+func restoreTargetOptions(target *v1beta1.PGBackRestRestoreTarget) []string {
+	if target == nil {
+		return nil
+	}
+
+	var opts []string
+	if target.BackupLabel != "" {
+		opts = append(opts, "--set="+target.BackupLabel)
+	}
+
+	switch {
+	case target.Time != nil:
+		// pgBackRest passes this to PostgreSQL which expects a space between
+		// the date and time.
+		opts = append(opts, "--type=time",
+			`--target="`+target.Time.UTC().Format("2006-01-02 15:04:05-07")+`"`)
+	case target.LSN != "":
+		opts = append(opts, "--type=lsn", "--target="+target.LSN)
+	case target.XID != "":
+		opts = append(opts, "--type=xid", "--target="+target.XID)
+	case target.BackupLabel != "":
+		opts = append(opts, "--type=immediate")
+	}
+	return opts
+}
+
+// restoreObjectID returns the identifier of restore in the restore status of
+// its PostgresCluster.
+func restoreObjectID(restore *v1beta1.PGBackRestRestoreRequest) string {
+	return "~pgbackrestrestore-" + string(restore.GetUID())
+}
+
+// isRestoreObjectID returns whether or not id identifies a
+// PGBackRestRestoreRequest in the restore status of a PostgresCluster.
+func isRestoreObjectID(id string) bool {
+	return strings.HasPrefix(id, "~pgbackrestrestore-")
+}
+
+// restoreObjectDataSource returns the in-place data source that performs restore.
+func restoreObjectDataSource(
+	restore *v1beta1.PGBackRestRestoreRequest,
+) *v1beta1.PostgresClusterDataSource {
+	options := restoreTargetOptions(restore.Spec.Target)
+	options = append(options, restore.Spec.Options...)
+
+	return &v1beta1.PostgresClusterDataSource{
+		RepoName: restore.Spec.RepoName,
+		Options:  options,
+	}
+}
+
+// observeRestoreProgress copies the in-place restore status of a cluster to restore.
+func observeRestoreProgress(restore *v1beta1.PGBackRestRestoreRequest, status *v1beta1.PGBackRestJobStatus) {
+	restore.Status.StartTime = status.StartTime
+	restore.Status.CompletionTime = status.CompletionTime
+	restore.Status.Finished = status.Finished
+
+	condition := metav1.Condition{
+		ObservedGeneration: restore.GetGeneration(),
+		Type:               v1beta1.PGBackRestRestoreSucceeded,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Running",
+		Message:            "Restoring the PostgresCluster in-place",
+	}
+	if status.Finished && status.Succeeded > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "RestoreComplete"
+		condition.Message = "Restore completed successfully"
+	} else if status.Finished {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RestoreFailed"
+		condition.Message = "Restore did not complete successfully"
+	}
+	meta.SetStatusCondition(&restore.Status.Conditions, condition)
+}
+
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=pgbackrestrestorerequests,verbs=list
+
+// pendingRestoreObject returns the PGBackRestRestoreRequest that cluster should
+// perform, if any. That is the one in progress or else the oldest that has
+// been validated.
+func (r *Reconciler) pendingRestoreObject(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (*v1beta1.PGBackRestRestoreRequest, error) {
+	restores := &v1beta1.PGBackRestRestoreRequestList{}
+	if err := r.Client.List(ctx, restores,
+		client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var inProgress string
+	if cluster.Status.PGBackRest != nil && cluster.Status.PGBackRest.Restore != nil {
+		inProgress = cluster.Status.PGBackRest.Restore.ID
+	}
+
+	var pending []*v1beta1.PGBackRestRestoreRequest
+	for i := range restores.Items {
+		restore := &restores.Items[i]
+		if restore.Spec.PostgresClusterName != cluster.Name ||
+			restore.Spec.DryRun || restore.Status.Finished ||
+			restore.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if restoreObjectID(restore) == inProgress {
+			return restore, nil
+		}
+
+		validated := meta.FindStatusCondition(restore.Status.Conditions,
+			v1beta1.PGBackRestRestoreValidated)
+		if validated != nil && validated.Status == metav1.ConditionTrue &&
+			validated.ObservedGeneration == restore.GetGeneration() {
+			pending = append(pending, restore)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i].GetCreationTimestamp(), pending[j].GetCreationTimestamp()
+		return a.Before(&b) || (a.Equal(&b) && pending[i].Name < pending[j].Name)
+	})
+	return pending[0], nil
+}
+
+// watchRestoresForCluster returns a handler.EventHandler that queues the
+// PostgresCluster of a PGBackRestRestoreRequest whenever it changes.
+func (*Reconciler) watchRestoresForCluster() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		if restore, ok := object.(*v1beta1.PGBackRestRestoreRequest); ok {
+			return []reconcile.Request{{NamespacedName: client.ObjectKey{
+				Namespace: restore.Namespace, Name: restore.Spec.PostgresClusterName,
+			}}}
+		}
+		return nil
+	})
+}
+
+// watchClusterForRestores returns a handler.EventHandler that queues the
+// unfinished PGBackRestRestoreRequests of a PostgresCluster whenever it changes.
+func (r *Reconciler) watchClusterForRestores() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(cluster client.Object) []reconcile.Request {
+		ctx := context.Background()
+		restores := &v1beta1.PGBackRestRestoreRequestList{}
+		if err := r.Client.List(ctx, restores,
+			client.InNamespace(cluster.GetNamespace())); err != nil {
+			logging.FromContext(ctx).Error(err, "listing PGBackRestRestoreRequests")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for i := range restores.Items {
+			if restores.Items[i].Spec.PostgresClusterName == cluster.GetName() &&
+				!restores.Items[i].Status.Finished {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(&restores.Items[i]),
+				})
+			}
+		}
+		return requests
+	})
+}
+
+// SetupRestoreControllerWithManager adds the PGBackRestRestoreRequest controller to
+// the provided runtime manager
+func (r *Reconciler) SetupRestoreControllerWithManager(mgr manager.Manager) error {
+	if r.PodExec == nil {
+		var err error
+		r.PodExec, err = newPodExecutor(mgr.GetConfig())
+		if err != nil {
+			return err
+		}
+	}
+
+	return builder.ControllerManagedBy(mgr).
+		Named(RestoreControllerName).
+		For(&v1beta1.PGBackRestRestoreRequest{}).
+		Watches(&source.Kind{Type: &v1beta1.PostgresCluster{}},
+			r.watchClusterForRestores()).
+		Complete(reconcile.Func(r.ReconcileRestore))
+}
@@ -0,0 +1,420 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestRestoreObjectInvalid(t *testing.T) {
+	restore := new(v1beta1.PGBackRestRestoreRequest)
+
+	reason, _ := restoreObjectInvalid(restore)
+	assert.Equal(t, reason, "")
+
+	t.Run("ManyTargets", func(t *testing.T) {
+		restore := restore.DeepCopy()
+		now := metav1.Now()
+		restore.Spec.Target = &v1beta1.PGBackRestRestoreTarget{Time: &now, XID: "100"}
+
+		reason, message := restoreObjectInvalid(restore)
+		assert.Equal(t, reason, "InvalidTarget")
+		assert.Equal(t, message, "Only one of time, xid may be set")
+	})
+
+	t.Run("Repo", func(t *testing.T) {
+		restore := restore.DeepCopy()
+		restore.Spec.Options = []string{"--repo=1"}
+
+		reason, _ := restoreObjectInvalid(restore)
+		assert.Equal(t, reason, "InvalidOptions")
+	})
+
+	t.Run("TargetOptions", func(t *testing.T) {
+		restore := restore.DeepCopy()
+		restore.Spec.Options = []string{"--type=time"}
+
+		reason, _ := restoreObjectInvalid(restore)
+		assert.Equal(t, reason, "", "allowed without a target")
+
+		restore.Spec.Target = &v1beta1.PGBackRestRestoreTarget{XID: "100"}
+		reason, _ = restoreObjectInvalid(restore)
+		assert.Equal(t, reason, "InvalidOptions")
+	})
+}
+
+func TestRestoreTargetOptions(t *testing.T) {
+	assert.Assert(t, restoreTargetOptions(nil) == nil)
+
+	when := metav1.NewTime(time.Date(2022, 6, 1, 12, 30, 5, 0, time.FixedZone("", -4*3600)))
+	for _, tt := range []struct {
+		name   string
+		target v1beta1.PGBackRestRestoreTarget
+		expect []string
+	}{
+		{
+			name:   "Time",
+			target: v1beta1.PGBackRestRestoreTarget{Time: &when},
+			expect: []string{"--type=time", `--target="2022-06-01 16:30:05+00"`},
+		},
+		{
+			name:   "LSN",
+			target: v1beta1.PGBackRestRestoreTarget{LSN: "0/3000000"},
+			expect: []string{"--type=lsn", "--target=0/3000000"},
+		},
+		{
+			name:   "XID",
+			target: v1beta1.PGBackRestRestoreTarget{XID: "1234"},
+			expect: []string{"--type=xid", "--target=1234"},
+		},
+		{
+			name:   "Backup",
+			target: v1beta1.PGBackRestRestoreTarget{BackupLabel: "20220601-161839F"},
+			expect: []string{"--set=20220601-161839F", "--type=immediate"},
+		},
+		{
+			name: "BackupAndTime",
+			target: v1beta1.PGBackRestRestoreTarget{
+				BackupLabel: "20220601-161839F", Time: &when,
+			},
+			expect: []string{
+				"--set=20220601-161839F", "--type=time", `--target="2022-06-01 16:30:05+00"`,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, restoreTargetOptions(&tt.target), tt.expect)
+		})
+	}
+}
+
+func TestValidateRestoreTarget(t *testing.T) {
+	now := time.Unix(1654200000, 0)
+	backup := func(label string, stop int64, lsn string) pgbackrest.InfoBackup {
+		var b pgbackrest.InfoBackup
+		b.Label = label
+		b.Timestamp.Start, b.Timestamp.Stop = stop-10, stop
+		b.LSN.Stop = lsn
+		return b
+	}
+	info := pgbackrest.InfoOutput{{
+		Name: "db",
+		Backup: []pgbackrest.InfoBackup{
+			backup("second", 1654100200, "0/5000100"),
+			backup("first", 1654100100, "0/3000100"),
+		},
+	}}
+
+	t.Run("NoStanza", func(t *testing.T) {
+		_, reason, _ := validateRestoreTarget(nil, nil, now)
+		assert.Equal(t, reason, "StanzaNotFound")
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		info := pgbackrest.InfoOutput{{Name: "db"}}
+		info[0].Status.Code = 3
+		info[0].Status.Message = "missing stanza data"
+
+		_, reason, message := validateRestoreTarget(info, nil, now)
+		assert.Equal(t, reason, "RepoUnavailable")
+		assert.Assert(t, message == "pgBackRest reported: missing stanza data")
+	})
+
+	t.Run("NoBackups", func(t *testing.T) {
+		_, reason, _ := validateRestoreTarget(pgbackrest.InfoOutput{{Name: "db"}}, nil, now)
+		assert.Equal(t, reason, "NoBackups")
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		b, reason, _ := validateRestoreTarget(info, nil, now)
+		assert.Equal(t, reason, "")
+		assert.Equal(t, b.Label, "second")
+	})
+
+	t.Run("BackupLabel", func(t *testing.T) {
+		b, reason, _ := validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{BackupLabel: "first"}, now)
+		assert.Equal(t, reason, "")
+		assert.Equal(t, b.Label, "first")
+
+		_, reason, _ = validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{BackupLabel: "missing"}, now)
+		assert.Equal(t, reason, "BackupNotFound")
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		between := metav1.NewTime(time.Unix(1654100150, 0))
+		b, reason, _ := validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{Time: &between}, now)
+		assert.Equal(t, reason, "")
+		assert.Equal(t, b.Label, "first")
+
+		before := metav1.NewTime(time.Unix(1654100000, 0))
+		_, reason, message := validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{Time: &before}, now)
+		assert.Equal(t, reason, "TargetBeforeBackups")
+		assert.Equal(t, message, "The earliest recoverable time is 2022-06-01T16:15:00Z")
+
+		future := metav1.NewTime(now.Add(time.Hour))
+		_, reason, _ = validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{Time: &future}, now)
+		assert.Equal(t, reason, "TargetInFuture")
+
+		// The labeled backup must finish before the target.
+		_, reason, _ = validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{Time: &between, BackupLabel: "second"}, now)
+		assert.Equal(t, reason, "TargetBeforeBackups")
+	})
+
+	t.Run("LSN", func(t *testing.T) {
+		b, reason, _ := validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{LSN: "0/6000000"}, now)
+		assert.Equal(t, reason, "")
+		assert.Equal(t, b.Label, "second")
+
+		_, reason, message := validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{LSN: "0/2000000"}, now)
+		assert.Equal(t, reason, "TargetBeforeBackups")
+		assert.Equal(t, message, "The earliest recoverable LSN is 0/3000100")
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		info := pgbackrest.InfoOutput{{
+			Name: "db",
+			Archive: []pgbackrest.InfoArchive{{
+				ID: "14-1", Min: "000000010000000000000001", Max: "000000010000000000000005",
+			}},
+			Backup: []pgbackrest.InfoBackup{
+				backup("first", 1654100100, "0/3000100"),
+				backup("second", 1654100200, "0/5000100"),
+			},
+		}}
+		info[0].Backup[0].Archive.Stop = "000000010000000000000003"
+		info[0].Backup[1].Archive.Stop = "000000010000000000000005"
+
+		b, reason, _ := validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{LSN: "0/5FFFFFF"}, now)
+		assert.Equal(t, reason, "")
+		assert.Equal(t, b.Label, "second")
+
+		_, reason, message := validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{LSN: "0/7000000"}, now)
+		assert.Equal(t, reason, "TargetAfterArchive")
+		assert.Equal(t, message, "The latest archived LSN is 0/6000000")
+
+		// The latest backup needs WAL that is not yet archived.
+		info[0].Archive[0].Max = "000000010000000000000004"
+		_, reason, _ = validateRestoreTarget(info, nil, now)
+		assert.Equal(t, reason, "WALNotArchived")
+
+		b, reason, _ = validateRestoreTarget(info,
+			&v1beta1.PGBackRestRestoreTarget{BackupLabel: "first"}, now)
+		assert.Equal(t, reason, "")
+		assert.Equal(t, b.Label, "first")
+	})
+}
+
+func TestObserveRestoreProgress(t *testing.T) {
+	restore := new(v1beta1.PGBackRestRestoreRequest)
+	status := &v1beta1.PGBackRestJobStatus{Active: 1}
+
+	observeRestoreProgress(restore, status)
+	assert.Assert(t, !restore.Status.Finished)
+	assert.Assert(t, meta.IsStatusConditionPresentAndEqual(restore.Status.Conditions,
+		v1beta1.PGBackRestRestoreSucceeded, metav1.ConditionUnknown))
+
+	status.Finished, status.Failed = true, 1
+	observeRestoreProgress(restore, status)
+	assert.Assert(t, restore.Status.Finished)
+	assert.Assert(t, meta.IsStatusConditionFalse(restore.Status.Conditions,
+		v1beta1.PGBackRestRestoreSucceeded))
+
+	status.Succeeded = 1
+	observeRestoreProgress(restore, status)
+	assert.Assert(t, meta.IsStatusConditionTrue(restore.Status.Conditions,
+		v1beta1.PGBackRestRestoreSucceeded))
+}
+
+func TestReconcileDataSourceRestoreObjects(t *testing.T) {
+	ctx := context.Background()
+
+	scheme, err := runtime.CreatePostgresOperatorScheme()
+	assert.NilError(t, err)
+
+	pod := &corev1.Pod{}
+	pod.Namespace, pod.Name = "ns1", "hippo-pod"
+	pod.Labels = map[string]string{
+		naming.LabelCluster:  "hippo",
+		naming.LabelInstance: "hippo-abcd",
+		naming.LabelRole:     naming.RolePatroniLeader,
+	}
+	pod.Status.Phase = corev1.PodRunning
+
+	// newCluster returns a running cluster that finished the restore with id.
+	newCluster := func(id string) *v1beta1.PostgresCluster {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Namespace, cluster.Name = "ns1", "hippo"
+		cluster.Spec.InstanceSets = []v1beta1.PostgresInstanceSetSpec{{Name: "00"}}
+		cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+			{Name: "repo1", S3: &v1beta1.RepoS3{}},
+		}
+		cluster.Spec.Backups.PGBackRest.Restore = &v1beta1.PGBackRestRestore{
+			Enabled: initialize.Bool(true),
+			PostgresClusterDataSource: &v1beta1.PostgresClusterDataSource{
+				RepoName: "repo1",
+			},
+		}
+		cluster.Status.PGBackRest = &v1beta1.PGBackRestStatus{
+			Restore: &v1beta1.PGBackRestJobStatus{ID: id, Finished: true},
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: ConditionPostgresDataInitialized, Status: metav1.ConditionTrue,
+			Reason: "PGBackRestRestoreComplete",
+		})
+		return cluster
+	}
+
+	// newRestore returns a validated PGBackRestRestoreRequest for the cluster.
+	newRestore := func(name string) *v1beta1.PGBackRestRestoreRequest {
+		restore := &v1beta1.PGBackRestRestoreRequest{}
+		restore.Namespace, restore.Name = "ns1", name
+		restore.UID = types.UID("uid-" + name)
+		restore.Spec.PostgresClusterName = "hippo"
+		restore.Spec.RepoName = "repo1"
+		setRestoreValidated(restore, metav1.ConditionTrue, "TargetRecoverable", "")
+		return restore
+	}
+
+	newReconciler := func(objects ...client.Object) (*Reconciler, *int) {
+		var infos int
+		return &Reconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(append(objects, pod)...).Build(),
+			Recorder: record.NewFakeRecorder(10),
+			PodExec: func(
+				_, _, _ string, _ io.Reader, stdout, _ io.Writer, _ ...string,
+			) error {
+				infos++
+				_, _ = stdout.Write([]byte(`[{"name": "db", "status": {"code": 0},
+					"backup": [{"label": "one", "timestamp": {"start": 1654100000, "stop": 1654100050}}]
+				}]`))
+				return nil
+			},
+		}, &infos
+	}
+
+	t.Run("AnnotationThenRequest", func(t *testing.T) {
+		cluster := newCluster("id1")
+		cluster.Annotations = map[string]string{naming.PGBackRestRestore: "id1"}
+		restore := newRestore("after-annotation")
+		r, infos := newReconciler(restore)
+
+		returnEarly, err := r.reconcileDataSource(ctx, cluster, &observedInstances{}, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, returnEarly, "expected to prepare for the request")
+		assert.Equal(t, *infos, 1, "expected the target to be validated again")
+		assert.Equal(t, cluster.Status.PGBackRest.Restore.ID, restoreObjectID(restore))
+		assert.Equal(t, cluster.Status.PGBackRest.RestoreAnnotation, "id1")
+
+		// The request finishes.
+		cluster.Status.PGBackRest.Restore.Finished = true
+		meta.RemoveStatusCondition(&cluster.Status.Conditions,
+			ConditionPGBackRestRestoreProgressing)
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: ConditionPostgresDataInitialized, Status: metav1.ConditionTrue,
+			Reason: "PGBackRestRestoreComplete",
+		})
+		assert.NilError(t, r.Client.Get(ctx, client.ObjectKeyFromObject(restore), restore))
+		restore.Status.Finished = true
+		assert.NilError(t, r.Client.Status().Update(ctx, restore))
+
+		returnEarly, err = r.reconcileDataSource(ctx, cluster, &observedInstances{}, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, !returnEarly, "expected the leftover annotation to be ignored")
+		assert.Equal(t, cluster.Status.PGBackRest.Restore.ID, restoreObjectID(restore))
+
+		// A new value for the annotation requests another restore.
+		cluster.Annotations[naming.PGBackRestRestore] = "id2"
+		returnEarly, err = r.reconcileDataSource(ctx, cluster, &observedInstances{}, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, returnEarly)
+		assert.Equal(t, cluster.Status.PGBackRest.Restore.ID, "id2")
+	})
+
+	t.Run("FinishedWithDataSource", func(t *testing.T) {
+		restore := newRestore("finished")
+		restore.Status.Finished = true
+		cluster := newCluster(restoreObjectID(restore))
+		cluster.Spec.DataSource = &v1beta1.DataSource{
+			PostgresCluster: &v1beta1.PostgresClusterDataSource{
+				ClusterName: "prod", RepoName: "repo1",
+			},
+		}
+		r, infos := newReconciler(restore)
+
+		returnEarly, err := r.reconcileDataSource(ctx, cluster, &observedInstances{}, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, !returnEarly, "expected the cluster to keep running")
+		assert.Equal(t, *infos, 0)
+		assert.Equal(t, cluster.Status.PGBackRest.Restore.ID, restoreObjectID(restore))
+
+		// Disabling in-place restores does not bootstrap from the data source either.
+		cluster.Spec.Backups.PGBackRest.Restore.Enabled = initialize.Bool(false)
+		returnEarly, err = r.reconcileDataSource(ctx, cluster, &observedInstances{}, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, !returnEarly)
+		assert.Equal(t, cluster.Status.PGBackRest.Restore.ID, restoreObjectID(restore))
+	})
+
+	t.Run("NoLongerValid", func(t *testing.T) {
+		cluster := newCluster("id1")
+		restore := newRestore("invalid")
+		restore.Spec.RepoName = "repo2"
+		r, _ := newReconciler(restore)
+
+		returnEarly, err := r.reconcileDataSource(ctx, cluster, &observedInstances{}, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, !returnEarly, "expected the cluster to keep running")
+		assert.Equal(t, cluster.Status.PGBackRest.Restore.ID, "id1")
+
+		assert.NilError(t, r.Client.Get(ctx, client.ObjectKeyFromObject(restore), restore))
+		assert.Assert(t, meta.IsStatusConditionFalse(restore.Status.Conditions,
+			v1beta1.PGBackRestRestoreValidated))
+		succeeded := meta.FindStatusCondition(restore.Status.Conditions,
+			v1beta1.PGBackRestRestoreSucceeded)
+		assert.Assert(t, succeeded != nil)
+		assert.Equal(t, succeeded.Status, metav1.ConditionFalse)
+		assert.Equal(t, succeeded.Reason, "NotValidated")
+	})
+}
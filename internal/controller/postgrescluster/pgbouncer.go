@@ -19,15 +19,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/logging"
@@ -38,6 +42,16 @@ import (
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
+const (
+	// ConditionPGBouncerAuthenticationRulesValid is the type used in a condition
+	// to indicate whether or not the PgBouncer authentication rules are valid
+	ConditionPGBouncerAuthenticationRulesValid = "PGBouncerAuthenticationRulesValid"
+
+	// pgBouncerResumeTimeout is how long PgBouncer has to resume after being
+	// paused. It is also how long to wait before trying again.
+	pgBouncerResumeTimeout = 30 * time.Second
+)
+
 // reconcilePGBouncer writes the objects necessary to run a PgBouncer Pod.
 func (r *Reconciler) reconcilePGBouncer(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
@@ -45,23 +59,39 @@ func (r *Reconciler) reconcilePGBouncer(
 	root *pki.RootCertificateAuthority,
 ) error {
 	var (
-		configmap *corev1.ConfigMap
-		secret    *corev1.Secret
+		configmap       *corev1.ConfigMap
+		readOnlyService *corev1.Service
+		secret          *corev1.Secret
 	)
 
 	service, err := r.reconcilePGBouncerService(ctx, cluster)
+	if err == nil {
+		readOnlyService, err = r.reconcilePGBouncerReadOnlyService(ctx, cluster)
+	}
 	if err == nil {
 		configmap, err = r.reconcilePGBouncerConfigMap(ctx, cluster)
 	}
 	if err == nil {
-		secret, err = r.reconcilePGBouncerSecret(ctx, cluster, root, service)
+		secret, err = r.reconcilePGBouncerSecret(ctx, cluster, root, service, readOnlyService)
+	}
+	if err == nil {
+		err = r.reconcilePGBouncerAdminSecret(ctx, cluster, secret, service)
 	}
 	if err == nil {
 		err = r.reconcilePGBouncerDeployment(ctx, cluster, primaryCertificate, configmap, secret)
 	}
+	if err == nil {
+		err = r.reconcilePGBouncerReadOnlyDeployment(ctx, cluster, primaryCertificate, configmap, secret)
+	}
+	if err == nil {
+		err = r.reconcilePGBouncerAutoscaler(ctx, cluster)
+	}
 	if err == nil {
 		err = r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster)
 	}
+	if err == nil {
+		err = r.reconcilePGBouncerReadOnlyPodDisruptionBudget(ctx, cluster)
+	}
 	if err == nil {
 		err = r.reconcilePGBouncerInPostgreSQL(ctx, cluster, instances, secret)
 	}
@@ -79,6 +109,8 @@ func (r *Reconciler) reconcilePGBouncerConfigMap(
 	configmap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
 
 	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionPGBouncerAuthenticationRulesValid)
+
 		// PgBouncer is disabled; delete the ConfigMap if it exists. Check the
 		// client cache first using Get.
 		key := client.ObjectKeyFromObject(configmap)
@@ -105,6 +137,27 @@ func (r *Reconciler) reconcilePGBouncerConfigMap(
 	if err == nil {
 		pgbouncer.ConfigMap(cluster, configmap)
 	}
+	if auth := cluster.Spec.Proxy.PGBouncer.Authentication; auth == nil || len(auth.Rules) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionPGBouncerAuthenticationRulesValid)
+	} else if invalid := pgbouncer.ValidateAuthentication(cluster); invalid != nil {
+		r.setStatusCondition(cluster, metav1.Condition{
+			Type:    ConditionPGBouncerAuthenticationRulesValid,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidPGBouncerAuthenticationRules",
+			Message: "Ignoring all rules: " + invalid.Error(),
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+	} else {
+		r.setStatusCondition(cluster, metav1.Condition{
+			Type:    ConditionPGBouncerAuthenticationRulesValid,
+			Status:  metav1.ConditionTrue,
+			Reason:  "AuthenticationRulesValid",
+			Message: "All specified authentication rules are valid.",
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+	}
 	if err == nil {
 		err = errors.WithStack(r.apply(ctx, configmap))
 	}
@@ -198,7 +251,7 @@ func (r *Reconciler) reconcilePGBouncerInPostgreSQL(
 // reconcilePGBouncerSecret writes the Secret for a PgBouncer Pod.
 func (r *Reconciler) reconcilePGBouncerSecret(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
-	root *pki.RootCertificateAuthority, service *corev1.Service,
+	root *pki.RootCertificateAuthority, service, readOnlyService *corev1.Service,
 ) (*corev1.Secret, error) {
 	existing := &corev1.Secret{ObjectMeta: naming.ClusterPGBouncer(cluster)}
 	err := errors.WithStack(
@@ -237,7 +290,7 @@ func (r *Reconciler) reconcilePGBouncerSecret(
 		})
 
 	if err == nil {
-		err = pgbouncer.Secret(ctx, cluster, root, existing, service, intent)
+		err = pgbouncer.Secret(ctx, cluster, root, existing, service, readOnlyService, intent)
 	}
 	if err == nil {
 		err = errors.WithStack(r.apply(ctx, intent))
@@ -246,6 +299,49 @@ func (r *Reconciler) reconcilePGBouncerSecret(
 	return intent, err
 }
 
+// reconcilePGBouncerAdminSecret writes the Secret with credentials for the
+// PgBouncer admin console. The passwords come from secret.
+func (r *Reconciler) reconcilePGBouncerAdminSecret(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	secret *corev1.Secret, service *corev1.Service,
+) error {
+	intent := &corev1.Secret{ObjectMeta: naming.ClusterPGBouncerAdmin(cluster)}
+	intent.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	intent.Type = corev1.SecretTypeOpaque
+
+	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil {
+		// PgBouncer is disabled; delete the Secret if it exists. Check the
+		// client cache first using Get.
+		key := client.ObjectKeyFromObject(intent)
+		err := errors.WithStack(r.Client.Get(ctx, key, intent))
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, intent))
+		}
+		return client.IgnoreNotFound(err)
+	}
+
+	err := errors.WithStack(r.setControllerReference(cluster, intent))
+
+	intent.Annotations = naming.Merge(
+		cluster.Spec.Metadata.GetAnnotationsOrNil(),
+		cluster.Spec.Proxy.PGBouncer.Metadata.GetAnnotationsOrNil())
+	intent.Labels = naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		cluster.Spec.Proxy.PGBouncer.Metadata.GetLabelsOrNil(),
+		map[string]string{
+			naming.LabelCluster: cluster.Name,
+			naming.LabelRole:    naming.RolePGBouncer,
+		})
+
+	if err == nil {
+		pgbouncer.AdminSecret(cluster, secret, service, intent)
+	}
+	if err == nil {
+		err = errors.WithStack(r.apply(ctx, intent))
+	}
+	return err
+}
+
 // generatePGBouncerService returns a v1.Service that exposes PgBouncer pods.
 // The ServiceType comes from the cluster proxy spec.
 func (r *Reconciler) generatePGBouncerService(
@@ -258,6 +354,37 @@ func (r *Reconciler) generatePGBouncerService(
 		return service, false, nil
 	}
 
+	service, err := r.populatePGBouncerService(cluster, service,
+		naming.RolePGBouncer, cluster.Spec.Proxy.PGBouncer.Service)
+
+	return service, true, err
+}
+
+// generatePGBouncerReadOnlyService returns a v1.Service that exposes PgBouncer
+// pods that connect to replicas. The ServiceType comes from the read-only spec.
+func (r *Reconciler) generatePGBouncerReadOnlyService(
+	cluster *v1beta1.PostgresCluster) (*corev1.Service, bool, error,
+) {
+	service := &corev1.Service{ObjectMeta: naming.ClusterPGBouncerReadOnly(cluster)}
+	service.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+
+	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil ||
+		cluster.Spec.Proxy.PGBouncer.ReadOnly == nil {
+		return service, false, nil
+	}
+
+	service, err := r.populatePGBouncerService(cluster, service,
+		naming.RolePGBouncerReadOnly, cluster.Spec.Proxy.PGBouncer.ReadOnly.Service)
+
+	return service, true, err
+}
+
+// populatePGBouncerService fills in service so that it selects PgBouncer pods
+// with role. It returns nil when spec is invalid.
+func (r *Reconciler) populatePGBouncerService(
+	cluster *v1beta1.PostgresCluster, service *corev1.Service,
+	role string, spec *v1beta1.ServiceSpec,
+) (*corev1.Service, error) {
 	service.Annotations = naming.Merge(
 		cluster.Spec.Metadata.GetAnnotationsOrNil(),
 		cluster.Spec.Proxy.PGBouncer.Metadata.GetAnnotationsOrNil())
@@ -265,7 +392,7 @@ func (r *Reconciler) generatePGBouncerService(
 		cluster.Spec.Metadata.GetLabelsOrNil(),
 		cluster.Spec.Proxy.PGBouncer.Metadata.GetLabelsOrNil())
 
-	if spec := cluster.Spec.Proxy.PGBouncer.Service; spec != nil {
+	if spec != nil {
 		service.Annotations = naming.Merge(service.Annotations,
 			spec.Metadata.GetAnnotationsOrNil())
 		service.Labels = naming.Merge(service.Labels,
@@ -276,7 +403,7 @@ func (r *Reconciler) generatePGBouncerService(
 	service.Labels = naming.Merge(service.Labels,
 		map[string]string{
 			naming.LabelCluster: cluster.Name,
-			naming.LabelRole:    naming.RolePGBouncer,
+			naming.LabelRole:    role,
 		})
 
 	// Allocate an IP address and/or node port and let Kubernetes manage the
@@ -284,7 +411,7 @@ func (r *Reconciler) generatePGBouncerService(
 	// - https://docs.k8s.io/concepts/services-networking/service/#defining-a-service
 	service.Spec.Selector = map[string]string{
 		naming.LabelCluster: cluster.Name,
-		naming.LabelRole:    naming.RolePGBouncer,
+		naming.LabelRole:    role,
 	}
 
 	// The TargetPort must be the name (not the number) of the PgBouncer
@@ -297,7 +424,7 @@ func (r *Reconciler) generatePGBouncerService(
 		TargetPort: intstr.FromString(naming.PortPGBouncer),
 	}
 
-	if spec := cluster.Spec.Proxy.PGBouncer.Service; spec == nil {
+	if spec == nil {
 		service.Spec.Type = corev1.ServiceTypeClusterIP
 	} else {
 		service.Spec.Type = corev1.ServiceType(spec.Type)
@@ -311,7 +438,7 @@ func (r *Reconciler) generatePGBouncerService(
 				// and event could potentially be removed in favor of that validation
 				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "MisconfiguredClusterIP",
 					"NodePort cannot be set with type ClusterIP on Service %q", service.Name)
-				return nil, true, fmt.Errorf("NodePort cannot be set with type ClusterIP on Service %q", service.Name)
+				return nil, fmt.Errorf("NodePort cannot be set with type ClusterIP on Service %q", service.Name)
 			}
 			servicePort.NodePort = *spec.NodePort
 		}
@@ -320,7 +447,7 @@ func (r *Reconciler) generatePGBouncerService(
 
 	err := errors.WithStack(r.setControllerReference(cluster, service))
 
-	return service, true, err
+	return service, err
 }
 
 // +kubebuilder:rbac:groups="",resources="services",verbs={get}
@@ -349,6 +476,30 @@ func (r *Reconciler) reconcilePGBouncerService(
 	return service, err
 }
 
+// reconcilePGBouncerReadOnlyService writes the Service that resolves to
+// PgBouncer that connects to replicas.
+func (r *Reconciler) reconcilePGBouncerReadOnlyService(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (*corev1.Service, error) {
+	service, specified, err := r.generatePGBouncerReadOnlyService(cluster)
+
+	if err == nil && !specified {
+		// Read-only PgBouncer is disabled; delete the Service if it exists.
+		// Check the client cache first using Get.
+		key := client.ObjectKeyFromObject(service)
+		err := errors.WithStack(r.Client.Get(ctx, key, service))
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, service))
+		}
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	if err == nil {
+		err = errors.WithStack(r.apply(ctx, service))
+	}
+	return service, err
+}
+
 // generatePGBouncerDeployment returns an appsv1.Deployment that runs PgBouncer pods.
 func (r *Reconciler) generatePGBouncerDeployment(
 	cluster *v1beta1.PostgresCluster,
@@ -362,6 +513,53 @@ func (r *Reconciler) generatePGBouncerDeployment(
 		return deploy, false, nil
 	}
 
+	// Leave replicas to the autoscaler, when there is one.
+	replicas := cluster.Spec.Proxy.PGBouncer.Replicas
+	if pgBouncerAutoscaled(cluster) {
+		replicas = nil
+	}
+
+	err := r.populatePGBouncerDeployment(cluster, deploy, naming.RolePGBouncer, replicas)
+
+	if err == nil {
+		pgbouncer.Pod(cluster, configmap, primaryCertificate, secret, &deploy.Spec.Template.Spec)
+	}
+
+	return deploy, true, err
+}
+
+// generatePGBouncerReadOnlyDeployment returns an appsv1.Deployment that runs
+// PgBouncer pods that connect to replicas.
+func (r *Reconciler) generatePGBouncerReadOnlyDeployment(
+	cluster *v1beta1.PostgresCluster,
+	primaryCertificate *corev1.SecretProjection,
+	configmap *corev1.ConfigMap, secret *corev1.Secret,
+) (*appsv1.Deployment, bool, error) {
+	deploy := &appsv1.Deployment{ObjectMeta: naming.ClusterPGBouncerReadOnly(cluster)}
+	deploy.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+
+	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil ||
+		cluster.Spec.Proxy.PGBouncer.ReadOnly == nil {
+		return deploy, false, nil
+	}
+
+	err := r.populatePGBouncerDeployment(cluster, deploy,
+		naming.RolePGBouncerReadOnly, cluster.Spec.Proxy.PGBouncer.ReadOnly.Replicas)
+
+	if err == nil {
+		pgbouncer.ReadOnlyPod(cluster, configmap, primaryCertificate, secret, &deploy.Spec.Template.Spec)
+	}
+
+	return deploy, true, err
+}
+
+// populatePGBouncerDeployment fills in deploy so that it runs replicas of
+// PgBouncer pods labeled with role. The caller is responsible for the pods'
+// containers and volumes.
+func (r *Reconciler) populatePGBouncerDeployment(
+	cluster *v1beta1.PostgresCluster, deploy *appsv1.Deployment,
+	role string, replicas *int32,
+) error {
 	deploy.Annotations = naming.Merge(
 		cluster.Spec.Metadata.GetAnnotationsOrNil(),
 		cluster.Spec.Proxy.PGBouncer.Metadata.GetAnnotationsOrNil())
@@ -370,12 +568,12 @@ func (r *Reconciler) generatePGBouncerDeployment(
 		cluster.Spec.Proxy.PGBouncer.Metadata.GetLabelsOrNil(),
 		map[string]string{
 			naming.LabelCluster: cluster.Name,
-			naming.LabelRole:    naming.RolePGBouncer,
+			naming.LabelRole:    role,
 		})
 	deploy.Spec.Selector = &metav1.LabelSelector{
 		MatchLabels: map[string]string{
 			naming.LabelCluster: cluster.Name,
-			naming.LabelRole:    naming.RolePGBouncer,
+			naming.LabelRole:    role,
 		},
 	}
 	deploy.Spec.Template.Annotations = naming.Merge(
@@ -386,14 +584,19 @@ func (r *Reconciler) generatePGBouncerDeployment(
 		cluster.Spec.Proxy.PGBouncer.Metadata.GetLabelsOrNil(),
 		map[string]string{
 			naming.LabelCluster: cluster.Name,
-			naming.LabelRole:    naming.RolePGBouncer,
+			naming.LabelRole:    role,
 		})
 
+	// add the proper label to support Pod discovery by Prometheus per pgMonitor configuration
+	if pgbouncer.ExporterEnabled(cluster) {
+		deploy.Spec.Template.Labels[naming.LabelPGMonitorDiscovery] = "true"
+	}
+
 	// if the shutdown flag is set, set pgBouncer replicas to 0
 	if cluster.Spec.Shutdown != nil && *cluster.Spec.Shutdown {
 		deploy.Spec.Replicas = initialize.Int32(0)
 	} else {
-		deploy.Spec.Replicas = cluster.Spec.Proxy.PGBouncer.Replicas
+		deploy.Spec.Replicas = replicas
 	}
 
 	// Don't clutter the namespace with extra ReplicaSets.
@@ -452,13 +655,7 @@ func (r *Reconciler) generatePGBouncerDeployment(
 	// set the image pull secrets, if any exist
 	deploy.Spec.Template.Spec.ImagePullSecrets = cluster.Spec.ImagePullSecrets
 
-	err := errors.WithStack(r.setControllerReference(cluster, deploy))
-
-	if err == nil {
-		pgbouncer.Pod(cluster, configmap, primaryCertificate, secret, &deploy.Spec.Template.Spec)
-	}
-
-	return deploy, true, err
+	return errors.WithStack(r.setControllerReference(cluster, deploy))
 }
 
 // +kubebuilder:rbac:groups="apps",resources="deployments",verbs={get}
@@ -520,6 +717,121 @@ func (r *Reconciler) reconcilePGBouncerDeployment(
 	return err
 }
 
+// reconcilePGBouncerReadOnlyDeployment writes the Deployment that runs
+// PgBouncer that connects to replicas.
+func (r *Reconciler) reconcilePGBouncerReadOnlyDeployment(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	primaryCertificate *corev1.SecretProjection,
+	configmap *corev1.ConfigMap, secret *corev1.Secret,
+) error {
+	deploy, specified, err := r.generatePGBouncerReadOnlyDeployment(
+		cluster, primaryCertificate, configmap, secret)
+
+	// Set observations whether the deployment exists or not.
+	defer func() {
+		cluster.Status.Proxy.PGBouncer.ReadOnlyReplicas = deploy.Status.Replicas
+		cluster.Status.Proxy.PGBouncer.ReadOnlyReadyReplicas = deploy.Status.ReadyReplicas
+	}()
+
+	if err == nil && !specified {
+		// Read-only PgBouncer is disabled; delete the Deployment if it exists.
+		// Check the client cache first using Get.
+		key := client.ObjectKeyFromObject(deploy)
+		err := errors.WithStack(r.Client.Get(ctx, key, deploy))
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, deploy))
+		}
+		if err != nil {
+			deploy.Status = appsv1.DeploymentStatus{}
+		}
+		return client.IgnoreNotFound(err)
+	}
+
+	if err == nil {
+		err = errors.WithStack(r.apply(ctx, deploy))
+	}
+	return err
+}
+
+// pgBouncerAutoscaled returns true when the PgBouncer Deployment of cluster
+// should be scaled by a HorizontalPodAutoscaler rather than its replicas field.
+func pgBouncerAutoscaled(cluster *v1beta1.PostgresCluster) bool {
+	return cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil &&
+		cluster.Spec.Proxy.PGBouncer.Autoscaling != nil &&
+		pgbouncer.ValidateAutoscaling(cluster) == nil
+}
+
+// +kubebuilder:rbac:groups="autoscaling",resources="horizontalpodautoscalers",verbs={get}
+// +kubebuilder:rbac:groups="autoscaling",resources="horizontalpodautoscalers",verbs={create,delete,patch}
+
+// reconcilePGBouncerAutoscaler writes the HorizontalPodAutoscaler that scales
+// the PgBouncer Deployment. It deletes the autoscaler when autoscaling is not
+// specified or cannot be used.
+//
+// NOTE: The autoscaling/v2 API is served by Kubernetes 1.23 and newer.
+func (r *Reconciler) reconcilePGBouncerAutoscaler(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) error {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: naming.ClusterPGBouncer(cluster)}
+	hpa.SetGroupVersionKind(autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"))
+
+	// Set observations whether the autoscaler exists or not.
+	defer func() {
+		if cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil {
+			cluster.Status.Proxy.PGBouncer.DesiredReplicas = hpa.Status.DesiredReplicas
+		}
+	}()
+
+	if invalid := pgbouncer.ValidateAutoscaling(cluster); invalid != nil {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidPGBouncerAutoscaling",
+			"Using replicas instead: "+invalid.Error())
+	}
+
+	if !pgBouncerAutoscaled(cluster) {
+		// Autoscaling is disabled; delete the autoscaler if it exists. Check
+		// the client cache first using Get.
+		key := client.ObjectKeyFromObject(hpa)
+		err := errors.WithStack(r.Client.Get(ctx, key, hpa))
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, hpa))
+		}
+		hpa.Status = autoscalingv2.HorizontalPodAutoscalerStatus{}
+
+		// There is nothing to delete when the API is not served.
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return client.IgnoreNotFound(err)
+	}
+
+	err := errors.WithStack(r.setControllerReference(cluster, hpa))
+
+	hpa.Annotations = naming.Merge(
+		cluster.Spec.Metadata.GetAnnotationsOrNil(),
+		cluster.Spec.Proxy.PGBouncer.Metadata.GetAnnotationsOrNil())
+	hpa.Labels = naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		cluster.Spec.Proxy.PGBouncer.Metadata.GetLabelsOrNil(),
+		map[string]string{
+			naming.LabelCluster: cluster.Name,
+			naming.LabelRole:    naming.RolePGBouncer,
+		})
+
+	hpa.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+		APIVersion: appsv1.SchemeGroupVersion.String(),
+		Kind:       "Deployment",
+		Name:       naming.ClusterPGBouncer(cluster).Name,
+	}
+	hpa.Spec.MinReplicas = cluster.Spec.Proxy.PGBouncer.Autoscaling.MinReplicas
+	hpa.Spec.MaxReplicas = cluster.Spec.Proxy.PGBouncer.Autoscaling.MaxReplicas
+	hpa.Spec.Metrics = pgbouncer.AutoscalerMetrics(cluster)
+
+	if err == nil {
+		err = errors.WithStack(r.apply(ctx, hpa))
+	}
+	return err
+}
+
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;patch;get;delete
 
 // reconcilePGBouncerPodDisruptionBudget creates a PDB for the PGBouncer deployment.
@@ -529,9 +841,58 @@ func (r *Reconciler) reconcilePGBouncerDeployment(
 func (r *Reconciler) reconcilePGBouncerPodDisruptionBudget(
 	ctx context.Context,
 	cluster *v1beta1.PostgresCluster,
+) error {
+	specified := cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil
+
+	var replicas *int32
+	var minAvailable *intstr.IntOrString
+	if specified {
+		replicas = cluster.Spec.Proxy.PGBouncer.Replicas
+		minAvailable = cluster.Spec.Proxy.PGBouncer.MinAvailable
+	}
+	if pgBouncerAutoscaled(cluster) {
+		// The autoscaler scales down to one pod when its minimum is omitted.
+		replicas = cluster.Spec.Proxy.PGBouncer.Autoscaling.MinReplicas
+		if replicas == nil {
+			replicas = initialize.Int32(1)
+		}
+	}
+
+	return r.reconcilePGBouncerPodDisruptionBudgetFor(ctx, cluster, specified,
+		naming.ClusterPGBouncer(cluster), naming.RolePGBouncer,
+		naming.ClusterPGBouncerSelector(cluster), replicas, minAvailable)
+}
+
+// reconcilePGBouncerReadOnlyPodDisruptionBudget creates a PDB for the PgBouncer
+// deployment that connects to replicas. See [Reconciler.reconcilePGBouncerPodDisruptionBudget].
+func (r *Reconciler) reconcilePGBouncerReadOnlyPodDisruptionBudget(
+	ctx context.Context,
+	cluster *v1beta1.PostgresCluster,
+) error {
+	specified := cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil &&
+		cluster.Spec.Proxy.PGBouncer.ReadOnly != nil
+
+	var replicas *int32
+	var minAvailable *intstr.IntOrString
+	if specified {
+		replicas = cluster.Spec.Proxy.PGBouncer.ReadOnly.Replicas
+		minAvailable = cluster.Spec.Proxy.PGBouncer.ReadOnly.MinAvailable
+	}
+
+	return r.reconcilePGBouncerPodDisruptionBudgetFor(ctx, cluster, specified,
+		naming.ClusterPGBouncerReadOnly(cluster), naming.RolePGBouncerReadOnly,
+		naming.ClusterPGBouncerReadOnlySelector(cluster), replicas, minAvailable)
+}
+
+// reconcilePGBouncerPodDisruptionBudgetFor creates or deletes the PDB named by
+// meta for PgBouncer pods with role.
+func (r *Reconciler) reconcilePGBouncerPodDisruptionBudgetFor(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, specified bool,
+	meta metav1.ObjectMeta, role string, selector metav1.LabelSelector,
+	replicas *int32, specMinAvailable *intstr.IntOrString,
 ) error {
 	deleteExistingPDB := func(cluster *v1beta1.PostgresCluster) error {
-		existing := &policyv1.PodDisruptionBudget{ObjectMeta: naming.ClusterPGBouncer(cluster)}
+		existing := &policyv1.PodDisruptionBudget{ObjectMeta: meta}
 		err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(existing), existing))
 		if err == nil {
 			err = errors.WithStack(r.deleteControlled(ctx, cluster, existing))
@@ -539,36 +900,33 @@ func (r *Reconciler) reconcilePGBouncerPodDisruptionBudget(
 		return client.IgnoreNotFound(err)
 	}
 
-	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil {
+	if !specified {
 		return deleteExistingPDB(cluster)
 	}
 
-	if cluster.Spec.Proxy.PGBouncer.Replicas == nil {
+	if replicas == nil {
 		// Replicas should always have a value because of defaults in the spec
 		return errors.New("Replicas should be defined")
 	}
-	minAvailable := getMinAvailable(cluster.Spec.Proxy.PGBouncer.MinAvailable,
-		*cluster.Spec.Proxy.PGBouncer.Replicas)
+	minAvailable := getMinAvailable(specMinAvailable, *replicas)
 
 	// If 'minAvailable' is set to '0', we will not reconcile the PDB. If one
 	// already exists, we will remove it.
 	scaled, err := intstr.GetScaledValueFromIntOrPercent(minAvailable,
-		int(*cluster.Spec.Proxy.PGBouncer.Replicas), true)
+		int(*replicas), true)
 	if err == nil && scaled <= 0 {
 		return deleteExistingPDB(cluster)
 	}
 
-	meta := naming.ClusterPGBouncer(cluster)
 	meta.Labels = naming.Merge(cluster.Spec.Metadata.GetLabelsOrNil(),
 		cluster.Spec.Proxy.PGBouncer.Metadata.GetLabelsOrNil(),
 		map[string]string{
 			naming.LabelCluster: cluster.Name,
-			naming.LabelRole:    naming.RolePGBouncer,
+			naming.LabelRole:    role,
 		})
 	meta.Annotations = naming.Merge(cluster.Spec.Metadata.GetAnnotationsOrNil(),
 		cluster.Spec.Proxy.PGBouncer.Metadata.GetAnnotationsOrNil())
 
-	selector := naming.ClusterPGBouncerSelector(cluster)
 	pdb := &policyv1.PodDisruptionBudget{}
 	if err == nil {
 		pdb, err = r.generatePodDisruptionBudget(cluster, meta, minAvailable, selector)
@@ -579,3 +937,201 @@ func (r *Reconciler) reconcilePGBouncerPodDisruptionBudget(
 	}
 	return err
 }
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// pgBouncerConsoles returns an Executor for each running PgBouncer Pod of
+// cluster, including those of the read-only PgBouncer, and the Secret holding
+// the admin console password. It returns nothing when PgBouncer is disabled or
+// has not been deployed yet.
+func (r *Reconciler) pgBouncerConsoles(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) ([]pgbouncer.Executor, *corev1.Secret, error) {
+	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil {
+		return nil, nil, nil
+	}
+
+	secret := &corev1.Secret{ObjectMeta: naming.ClusterPGBouncer(cluster)}
+	err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret))
+	if apierrors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+
+	var consoles []pgbouncer.Executor
+	for _, selector := range []metav1.LabelSelector{
+		naming.ClusterPGBouncerSelector(cluster),
+		naming.ClusterPGBouncerReadOnlySelector(cluster),
+	} {
+		pods := &corev1.PodList{}
+		if err == nil {
+			err = errors.WithStack(r.Client.List(ctx, pods,
+				client.InNamespace(cluster.Namespace),
+				client.MatchingLabels(selector.MatchLabels)))
+		}
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+				continue
+			}
+			consoles = append(consoles, func(
+				_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+			) error {
+				return r.PodExec(pod.Namespace, pod.Name, naming.ContainerPGBouncer,
+					stdin, stdout, stderr, command...)
+			})
+		}
+	}
+
+	return consoles, secret, err
+}
+
+// pausePGBouncer pauses every PgBouncer Pod of cluster so that clients wait
+// rather than fail while the PostgreSQL primary changes. It returns a function
+// that resumes them. Nothing is paused when the pause timeout is omitted or
+// when PgBouncer cannot pause before it; the change then happens without it.
+// The time by which PgBouncer should resume is stored in the status of cluster
+// before pausing so that a later reconcile can resume it when this one does not.
+func (r *Reconciler) pausePGBouncer(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (resume func()) {
+	resume = func() {}
+	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil ||
+		cluster.Spec.Proxy.PGBouncer.Config.PauseTimeout == nil ||
+		cluster.Spec.Proxy.PGBouncer.Config.PauseTimeout.Duration <= 0 {
+		return
+	}
+
+	log := logging.FromContext(ctx)
+	consoles, secret, err := r.pgBouncerConsoles(ctx, cluster)
+	if err != nil {
+		log.Error(err, "unable to pause PgBouncer")
+		return
+	}
+	if len(consoles) == 0 {
+		return
+	}
+
+	// Every Pod pauses within the same timeout. Patroni then changes the
+	// primary within two "loop_wait".
+	timeout := cluster.Spec.Proxy.PGBouncer.Config.PauseTimeout.Duration
+	deadline := time.Now().Add(timeout)
+
+	var loopWait time.Duration
+	if cluster.Spec.Patroni != nil && cluster.Spec.Patroni.SyncPeriodSeconds != nil {
+		loopWait = time.Duration(*cluster.Spec.Patroni.SyncPeriodSeconds) * time.Second
+	}
+	pausedUntil := metav1.NewTime(deadline.Add(2*loopWait + pgBouncerResumeTimeout)).Rfc3339Copy()
+
+	if err := r.setPGBouncerPausedUntil(ctx, cluster, &pausedUntil); err != nil {
+		log.Error(err, "unable to pause PgBouncer")
+		return
+	}
+
+	unpause := func() { r.resumePGBouncer(ctx, cluster, consoles, secret) }
+
+	for i := 0; err == nil && i < len(consoles); i++ {
+		if remaining := time.Until(deadline); remaining > 0 {
+			err = errors.WithStack(consoles[i].Pause(ctx, cluster, secret, remaining))
+		} else {
+			err = errors.New("timed out")
+		}
+	}
+
+	if err != nil {
+		// Some Pods may have paused; resume them all before continuing.
+		log.Error(err, "unable to pause PgBouncer; continuing without it")
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "PGBouncerNotPaused",
+			"PgBouncer did not pause within %v", timeout)
+		unpause()
+		return
+	}
+
+	return unpause
+}
+
+// setPGBouncerPausedUntil stores pausedUntil in the status of cluster right
+// away, before the rest of the status.
+func (r *Reconciler) setPGBouncerPausedUntil(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, pausedUntil *metav1.Time,
+) error {
+	// Patch a copy so that other changes to status are kept for later.
+	intent := cluster.DeepCopy()
+	intent.Status.Proxy.PGBouncer.PausedUntil = pausedUntil
+
+	err := errors.WithStack(r.Client.Status().Patch(ctx, intent, client.MergeFrom(cluster), r.Owner))
+	if err == nil {
+		cluster.Status.Proxy.PGBouncer.PausedUntil = pausedUntil
+	}
+	return err
+}
+
+// resumePGBouncer resumes PgBouncer through consoles and clears the time by
+// which it should resume from the status of cluster. It does so even when ctx
+// is cancelled; PgBouncer would otherwise stay paused.
+func (r *Reconciler) resumePGBouncer(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	consoles []pgbouncer.Executor, secret *corev1.Secret,
+) {
+	log := logging.FromContext(ctx)
+	ctx, cancel := context.WithTimeout(
+		logging.NewContext(context.Background(), log), pgBouncerResumeTimeout)
+	defer cancel()
+
+	resumed := true
+	for _, console := range consoles {
+		if err := console.Resume(ctx, cluster, secret); err != nil {
+			log.Error(err, "unable to resume PgBouncer")
+			resumed = false
+		}
+	}
+	if resumed {
+		cluster.Status.Proxy.PGBouncer.PausedUntil = nil
+	}
+}
+
+// reconcilePGBouncerPause resumes PgBouncer once the time by which it should
+// have resumed has passed, e.g. when the operator stopped while the PostgreSQL
+// primary was changing. It returns when to check again.
+func (r *Reconciler) reconcilePGBouncerPause(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) reconcile.Result {
+	pausedUntil := cluster.Status.Proxy.PGBouncer.PausedUntil
+	if pausedUntil == nil {
+		return reconcile.Result{}
+	}
+	if remaining := time.Until(pausedUntil.Time); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}
+	}
+
+	consoles, secret, err := r.pgBouncerConsoles(ctx, cluster)
+	if err != nil {
+		logging.FromContext(ctx).Error(err, "unable to resume PgBouncer")
+		return reconcile.Result{RequeueAfter: pgBouncerResumeTimeout}
+	}
+
+	r.resumePGBouncer(ctx, cluster, consoles, secret)
+	if cluster.Status.Proxy.PGBouncer.PausedUntil != nil {
+		return reconcile.Result{RequeueAfter: pgBouncerResumeTimeout}
+	}
+	return reconcile.Result{}
+}
+
+// reconnectPGBouncer tells every PgBouncer Pod of cluster to replace its server
+// connections, e.g. after PostgreSQL is restored in place. Errors are logged
+// rather than returned; PgBouncer eventually replaces broken connections.
+func (r *Reconciler) reconnectPGBouncer(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) {
+	log := logging.FromContext(ctx)
+	consoles, secret, err := r.pgBouncerConsoles(ctx, cluster)
+
+	for i := 0; err == nil && i < len(consoles); i++ {
+		err = errors.WithStack(consoles[i].Reconnect(ctx, cluster, secret))
+	}
+	if err != nil {
+		log.Error(err, "unable to reconnect PgBouncer")
+	}
+}
@@ -20,20 +20,27 @@ package postgrescluster
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/testing/require"
+	"github.com/crunchydata/postgres-operator/internal/util"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
@@ -500,6 +507,22 @@ topologySpreadConstraints:
 			assert.Assert(t, deploy.Spec.Template.Spec.TopologySpreadConstraints == nil)
 		})
 	})
+
+	t.Run("Monitoring", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Proxy.PGBouncer.Monitoring = &v1beta1.PGBouncerMonitoringSpec{
+			Exporter: &v1beta1.PGBouncerExporterSpec{},
+		}
+
+		deploy, specified, err := reconciler.generatePGBouncerDeployment(
+			cluster, primary, configmap, secret)
+		assert.NilError(t, err)
+		assert.Assert(t, specified)
+
+		// Prometheus discovers pods by this label.
+		assert.Equal(t, deploy.Spec.Template.Labels["postgres-operator.crunchydata.com/crunchy-postgres-exporter"], "true")
+		assert.Assert(t, deploy.Labels["postgres-operator.crunchydata.com/crunchy-postgres-exporter"] == "")
+	})
 }
 
 func TestReconcilePGBouncerDisruptionBudget(t *testing.T) {
@@ -623,4 +646,369 @@ func TestReconcilePGBouncerDisruptionBudget(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("autoscaled without minimum", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Namespace = ns.Name
+		cluster.Spec.Proxy.PGBouncer.Replicas = nil
+		cluster.Spec.Proxy.PGBouncer.Monitoring = &v1beta1.PGBouncerMonitoringSpec{
+			Exporter: &v1beta1.PGBouncerExporterSpec{},
+		}
+		cluster.Spec.Proxy.PGBouncer.Autoscaling = &v1beta1.PGBouncerAutoscalingSpec{
+			MaxReplicas:          3,
+			TargetWaitingClients: initialize.Int32(1),
+		}
+
+		// One pod is the minimum, so there is no PDB by default.
+		assert.NilError(t, r.reconcilePGBouncerPodDisruptionBudget(ctx, cluster))
+		assert.Assert(t, !foundPDB(cluster))
+	})
+}
+
+func TestReconcilePGBouncerAutoscaler(t *testing.T) {
+	ctx := context.Background()
+	_, cc := setupKubernetes(t)
+	require.ParallelCapacity(t, 0)
+	assert.NilError(t, util.AddAndSetFeatureGates(""))
+
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		Client:   cc,
+		Owner:    client.FieldOwner(t.Name()),
+		Recorder: recorder,
+	}
+
+	foundHPA := func(cluster *v1beta1.PostgresCluster) (*autoscalingv2.HorizontalPodAutoscaler, bool) {
+		got := &autoscalingv2.HorizontalPodAutoscaler{}
+		err := r.Client.Get(ctx, naming.AsObjectKey(naming.ClusterPGBouncer(cluster)), got)
+		return got, !apierrors.IsNotFound(err)
+	}
+
+	ns := setupNamespace(t, cc)
+
+	t.Run("empty", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Namespace = ns.Name
+		cluster.Spec.Proxy = nil
+
+		assert.NilError(t, r.reconcilePGBouncerAutoscaler(ctx, cluster))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Namespace = ns.Name
+		cluster.Spec.Proxy.PGBouncer.Autoscaling = &v1beta1.PGBouncerAutoscalingSpec{
+			MaxReplicas: 3,
+		}
+
+		assert.NilError(t, r.reconcilePGBouncerAutoscaler(ctx, cluster))
+		_, found := foundHPA(cluster)
+		assert.Assert(t, !found)
+
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "InvalidPGBouncerAutoscaling"))
+
+		// The Deployment keeps its replicas.
+		cluster.Default()
+		deploy, _, err := r.generatePGBouncerDeployment(cluster,
+			new(corev1.SecretProjection), new(corev1.ConfigMap), new(corev1.Secret))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, deploy.Spec.Replicas, cluster.Spec.Proxy.PGBouncer.Replicas)
+	})
+
+	t.Run("created", func(t *testing.T) {
+		cluster := testCluster()
+		cluster.Namespace = ns.Name
+		cluster.Spec.Proxy.PGBouncer.Monitoring = &v1beta1.PGBouncerMonitoringSpec{
+			Exporter: &v1beta1.PGBouncerExporterSpec{},
+		}
+		cluster.Spec.Proxy.PGBouncer.Autoscaling = &v1beta1.PGBouncerAutoscalingSpec{
+			MinReplicas:             initialize.Int32(2),
+			MaxReplicas:             5,
+			TargetClientConnections: initialize.Int32(50),
+		}
+
+		assert.NilError(t, r.Client.Create(ctx, cluster))
+		t.Cleanup(func() { assert.Check(t, r.Client.Delete(ctx, cluster)) })
+
+		assert.NilError(t, r.reconcilePGBouncerAutoscaler(ctx, cluster))
+		hpa, found := foundHPA(cluster)
+		assert.Assert(t, found)
+
+		assert.DeepEqual(t, hpa.Spec.ScaleTargetRef, autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: cluster.Name + "-pgbouncer",
+		})
+		assert.Equal(t, *hpa.Spec.MinReplicas, int32(2))
+		assert.Equal(t, hpa.Spec.MaxReplicas, int32(5))
+		assert.Equal(t, len(hpa.Spec.Metrics), 1)
+
+		// The Deployment leaves replicas to the autoscaler.
+		cluster.Default()
+		deploy, _, err := r.generatePGBouncerDeployment(cluster,
+			new(corev1.SecretProjection), new(corev1.ConfigMap), new(corev1.Secret))
+		assert.NilError(t, err)
+		assert.Assert(t, deploy.Spec.Replicas == nil)
+
+		t.Run("deleted", func(t *testing.T) {
+			cluster.Spec.Proxy.PGBouncer.Autoscaling = nil
+			err := r.reconcilePGBouncerAutoscaler(ctx, cluster)
+			if apierrors.IsConflict(err) {
+				// Another controller may have updated the object; try again.
+				err = r.reconcilePGBouncerAutoscaler(ctx, cluster)
+			}
+			assert.NilError(t, err, errors.Unwrap(err))
+
+			_, found := foundHPA(cluster)
+			assert.Assert(t, !found)
+			assert.Equal(t, cluster.Status.Proxy.PGBouncer.DesiredReplicas, int32(0))
+		})
+	})
+}
+
+func TestGeneratePGBouncerReadOnly(t *testing.T) {
+	_, cc := setupKubernetes(t)
+	require.ParallelCapacity(t, 0)
+
+	reconciler := &Reconciler{Client: cc}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace = "ns4"
+	cluster.Name = "pg2"
+	cluster.Spec.Proxy = &v1beta1.PostgresProxySpec{
+		PGBouncer: &v1beta1.PGBouncerPodSpec{},
+	}
+	cluster.Default()
+
+	t.Run("Unspecified", func(t *testing.T) {
+		service, specified, err := reconciler.generatePGBouncerReadOnlyService(cluster)
+		assert.NilError(t, err)
+		assert.Assert(t, !specified)
+		assert.Equal(t, service.Name, "pg2-pgbouncer-ro")
+
+		deploy, specified, err := reconciler.generatePGBouncerReadOnlyDeployment(cluster, nil, nil, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, !specified)
+		assert.Equal(t, deploy.Name, "pg2-pgbouncer-ro")
+	})
+
+	cluster.Spec.Proxy.PGBouncer.ReadOnly = &v1beta1.PGBouncerReadOnlySpec{
+		Service: &v1beta1.ServiceSpec{Type: "NodePort"},
+	}
+	cluster.Default()
+
+	selector := map[string]string{
+		"postgres-operator.crunchydata.com/cluster": "pg2",
+		"postgres-operator.crunchydata.com/role":    "pgbouncer-ro",
+	}
+
+	t.Run("Service", func(t *testing.T) {
+		service, specified, err := reconciler.generatePGBouncerReadOnlyService(cluster)
+		assert.NilError(t, err)
+		assert.Assert(t, specified)
+
+		assert.Equal(t, service.Name, "pg2-pgbouncer-ro")
+		assert.DeepEqual(t, service.Labels, selector)
+		assert.DeepEqual(t, service.Spec.Selector, selector)
+		assert.Equal(t, service.Spec.Type, corev1.ServiceTypeNodePort)
+		assert.Equal(t, service.Spec.Ports[0].Port, *cluster.Spec.Proxy.PGBouncer.Port)
+	})
+
+	t.Run("Deployment", func(t *testing.T) {
+		configmap := &corev1.ConfigMap{}
+		configmap.Name = "some-cm"
+
+		secret := &corev1.Secret{}
+		secret.Name = "some-secret"
+
+		deploy, specified, err := reconciler.generatePGBouncerReadOnlyDeployment(
+			cluster, &corev1.SecretProjection{}, configmap, secret)
+		assert.NilError(t, err)
+		assert.Assert(t, specified)
+
+		assert.Equal(t, deploy.Name, "pg2-pgbouncer-ro")
+		assert.DeepEqual(t, deploy.Spec.Selector.MatchLabels, selector)
+		assert.DeepEqual(t, deploy.Spec.Template.Labels, selector)
+		assert.Equal(t, *deploy.Spec.Replicas, int32(1))
+		assert.Assert(t, len(deploy.Spec.Template.Spec.Containers) > 0)
+
+		t.Run("Shutdown", func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Spec.Shutdown = initialize.Bool(true)
+
+			deploy, _, err := reconciler.generatePGBouncerReadOnlyDeployment(
+				cluster, &corev1.SecretProjection{}, configmap, secret)
+			assert.NilError(t, err)
+			assert.Equal(t, *deploy.Spec.Replicas, int32(0))
+		})
+	})
+}
+
+func TestPausePGBouncer(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.Proxy = &v1beta1.PostgresProxySpec{PGBouncer: &v1beta1.PGBouncerPodSpec{
+		ReadOnly: &v1beta1.PGBouncerReadOnlySpec{},
+	}}
+	cluster.Default()
+
+	secret := &corev1.Secret{ObjectMeta: naming.ClusterPGBouncer(cluster)}
+	secret.Data = map[string][]byte{"pgbouncer-admin-password": []byte("pass")}
+
+	pod := func(name, role string, phase corev1.PodPhase) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Namespace, pod.Name = cluster.Namespace, name
+		pod.Labels = map[string]string{
+			naming.LabelCluster: cluster.Name,
+			naming.LabelRole:    role,
+		}
+		pod.Status.Phase = phase
+		return pod
+	}
+
+	scheme, err := runtime.CreatePostgresOperatorScheme()
+	assert.NilError(t, err)
+
+	// commands returns a Reconciler that records the console commands sent to
+	// each Pod. Commands fail on Pods named in failing.
+	commands := func(sent map[string][]string, failing ...string) *Reconciler {
+		return &Reconciler{
+			Recorder: record.NewFakeRecorder(10),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				cluster.DeepCopy(), secret,
+				pod("rw", naming.RolePGBouncer, corev1.PodRunning),
+				pod("ro", naming.RolePGBouncerReadOnly, corev1.PodRunning),
+				pod("pending", naming.RolePGBouncer, corev1.PodPending),
+			).Build(),
+			PodExec: func(
+				namespace, pod, container string,
+				stdin io.Reader, _, _ io.Writer, command ...string,
+			) error {
+				assert.Equal(t, namespace, "ns1")
+				assert.Equal(t, container, "pgbouncer")
+
+				b, _ := io.ReadAll(stdin)
+				lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+				sent[pod] = append(sent[pod], lines[1:]...)
+
+				for _, name := range failing {
+					if name == pod {
+						return errors.New("boom")
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		sent := map[string][]string{}
+		commands(sent).pausePGBouncer(ctx, cluster)()
+		assert.Equal(t, len(sent), 0)
+	})
+
+	cluster.Spec.Proxy.PGBouncer.Config.PauseTimeout = &metav1.Duration{Duration: time.Minute}
+
+	t.Run("Paused", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		sent := map[string][]string{}
+		reconciler := commands(sent)
+
+		resume := reconciler.pausePGBouncer(ctx, cluster)
+		assert.DeepEqual(t, sent, map[string][]string{
+			"rw": {"PAUSE;"},
+			"ro": {"PAUSE;"},
+		})
+
+		// The time by which to resume is stored before anything else.
+		pausedUntil := cluster.Status.Proxy.PGBouncer.PausedUntil
+		assert.Assert(t, pausedUntil != nil)
+		assert.Assert(t, time.Until(pausedUntil.Time) > time.Minute,
+			"expected the pause timeout and more, got %v", pausedUntil)
+
+		stored := &v1beta1.PostgresCluster{}
+		assert.NilError(t, reconciler.Client.Get(ctx, client.ObjectKeyFromObject(cluster), stored))
+		assert.DeepEqual(t, stored.Status.Proxy.PGBouncer.PausedUntil, pausedUntil)
+
+		resume()
+		assert.DeepEqual(t, sent, map[string][]string{
+			"rw": {"PAUSE;", "RECONNECT;", "RESUME;"},
+			"ro": {"PAUSE;", "RECONNECT;", "RESUME;"},
+		})
+		assert.Assert(t, cluster.Status.Proxy.PGBouncer.PausedUntil == nil)
+	})
+
+	t.Run("ResumeCancelled", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		sent := map[string][]string{}
+		reconciler := commands(sent)
+
+		ctx, cancel := context.WithCancel(ctx)
+		resume := reconciler.pausePGBouncer(ctx, cluster)
+		cancel()
+
+		// PgBouncer resumes even after the reconcile is cancelled.
+		resume()
+		assert.DeepEqual(t, sent["rw"], []string{"PAUSE;", "RECONNECT;", "RESUME;"})
+		assert.Assert(t, cluster.Status.Proxy.PGBouncer.PausedUntil == nil)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+
+		// Nothing happens before the time by which to resume.
+		sent := map[string][]string{}
+		cluster.Status.Proxy.PGBouncer.PausedUntil = &metav1.Time{Time: time.Now().Add(time.Minute)}
+		result := commands(sent).reconcilePGBouncerPause(ctx, cluster)
+		assert.Assert(t, result.RequeueAfter > 50*time.Second && result.RequeueAfter <= time.Minute,
+			"expected to check again then, got %v", result.RequeueAfter)
+		assert.Equal(t, len(sent), 0)
+
+		// PgBouncer resumes once that time has passed, e.g. after the
+		// operator restarted while PgBouncer was paused.
+		cluster.Status.Proxy.PGBouncer.PausedUntil = &metav1.Time{Time: time.Now().Add(-time.Second)}
+		result = commands(sent).reconcilePGBouncerPause(ctx, cluster)
+		assert.Equal(t, result.RequeueAfter, time.Duration(0))
+		assert.DeepEqual(t, sent, map[string][]string{
+			"rw": {"RECONNECT;", "RESUME;"},
+			"ro": {"RECONNECT;", "RESUME;"},
+		})
+		assert.Assert(t, cluster.Status.Proxy.PGBouncer.PausedUntil == nil)
+
+		// Failures are tried again later.
+		sent = map[string][]string{}
+		cluster.Status.Proxy.PGBouncer.PausedUntil = &metav1.Time{Time: time.Now().Add(-time.Second)}
+		result = commands(sent, "ro").reconcilePGBouncerPause(ctx, cluster)
+		assert.Assert(t, result.RequeueAfter > 0)
+		assert.Assert(t, cluster.Status.Proxy.PGBouncer.PausedUntil != nil)
+	})
+
+	t.Run("NotPaused", func(t *testing.T) {
+		sent := map[string][]string{}
+		reconciler := commands(sent, "ro")
+
+		// Pods that paused are resumed before the primary changes.
+		resume := reconciler.pausePGBouncer(ctx, cluster)
+		assert.DeepEqual(t, sent, map[string][]string{
+			"rw": {"PAUSE;", "RECONNECT;", "RESUME;"},
+			"ro": {"PAUSE;", "RECONNECT;", "RESUME;"},
+		})
+
+		resume()
+		assert.Equal(t, len(sent["rw"]), 3, "expected nothing more")
+
+		recorder := reconciler.Recorder.(*record.FakeRecorder)
+		assert.Equal(t, len(recorder.Events), 1)
+		assert.Assert(t, strings.Contains(<-recorder.Events, "PGBouncerNotPaused"))
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		sent := map[string][]string{}
+		commands(sent).reconnectPGBouncer(ctx, cluster)
+		assert.DeepEqual(t, sent, map[string][]string{
+			"rw": {"RECONNECT;"},
+			"ro": {"RECONNECT;"},
+		})
+	})
 }
@@ -0,0 +1,721 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crunchydata/postgres-operator/internal/config"
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// UpgradeControllerName is the name of the PGUpgrade controller
+	UpgradeControllerName = "pgupgrade-controller"
+
+	// ConditionPostgresUpgradeProgressing is the type used in a condition to
+	// indicate that a major PostgreSQL upgrade of the cluster is in progress.
+	ConditionPostgresUpgradeProgressing = "PostgresUpgradeProgressing"
+
+	// EventUpgradeStarted, EventUpgradeSucceeded, and EventUpgradeFailed are
+	// the reasons of events about a major PostgreSQL upgrade.
+	EventUpgradeStarted   = "PostgresUpgradeStarted"
+	EventUpgradeSucceeded = "PostgresUpgradeSucceeded"
+	EventUpgradeFailed    = "PostgresUpgradeFailed"
+)
+
+// The phases of a major PostgreSQL upgrade in the status of a cluster. The
+// cluster is held down while the upgrade is in the first three.
+const (
+	postgresUpgradeShuttingDown      = "ShuttingDown"
+	postgresUpgradeUpgrading         = "Upgrading"
+	postgresUpgradeWaitingForVersion = "WaitingForVersion"
+	postgresUpgradeStartingUp        = "StartingUp"
+	postgresUpgradeSucceeded         = "Succeeded"
+	postgresUpgradeFailed            = "Failed"
+)
+
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=pgupgrades,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=pgupgrades/status,verbs=patch
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=postgresclusters,verbs=get;list;watch
+
+// ReconcileUpgrade validates a PGUpgrade against its PostgresCluster and then
+// follows the progress of the upgrade that the PostgresCluster controller
+// performs once it is validated.
+func (r *Reconciler) ReconcileUpgrade(
+	ctx context.Context, request reconcile.Request) (reconcile.Result, error,
+) {
+	ctx, span := r.Tracer.Start(ctx, "ReconcileUpgrade")
+	log := logging.FromContext(ctx)
+	defer span.End()
+
+	upgrade := &v1beta1.PGUpgrade{}
+	if err := r.Client.Get(ctx, request.NamespacedName, upgrade); err != nil {
+		// NotFound cannot be fixed by requeuing so ignore it.
+		if err = client.IgnoreNotFound(err); err != nil {
+			log.Error(err, "unable to fetch PGUpgrade")
+			span.RecordError(err)
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Keep a copy of upgrade prior to any manipulations.
+	before := upgrade.DeepCopy()
+
+	err := r.reconcileUpgradeObject(ctx, upgrade)
+
+	if !equality.Semantic.DeepEqual(before.Status, upgrade.Status) {
+		if err := errors.WithStack(r.Client.Status().Patch(
+			ctx, upgrade, client.MergeFrom(before), r.Owner)); err != nil {
+			log.Error(err, "patching upgrade status")
+			return reconcile.Result{}, err
+		}
+		log.V(1).Info("patched upgrade status")
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	}
+	return reconcile.Result{}, err
+}
+
+// reconcileUpgradeObject updates the status of upgrade according to its cluster.
+func (r *Reconciler) reconcileUpgradeObject(
+	ctx context.Context, upgrade *v1beta1.PGUpgrade,
+) error {
+	upgrade.Status.ObservedGeneration = upgrade.GetGeneration()
+
+	// Nothing more happens once the upgrade has finished.
+	if upgrade.Status.Finished {
+		return nil
+	}
+
+	setValidated := func(status metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&upgrade.Status.Conditions, metav1.Condition{
+			ObservedGeneration: upgrade.GetGeneration(),
+			Type:               v1beta1.PGUpgradeValidated,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	cluster := &v1beta1.PostgresCluster{}
+	err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: upgrade.GetNamespace(), Name: upgrade.Spec.PostgresClusterName,
+	}, cluster)
+	if err != nil {
+		// A missing cluster may be created later; the cluster watch will
+		// trigger another reconcile when it is.
+		if client.IgnoreNotFound(err) == nil {
+			setValidated(metav1.ConditionUnknown, "ClusterNotFound", fmt.Sprintf(
+				"PostgresCluster %q not found", upgrade.Spec.PostgresClusterName))
+		}
+		return client.IgnoreNotFound(errors.WithStack(err))
+	}
+
+	// Once the cluster starts this upgrade, report its progress.
+	if status := cluster.Status.PostgresUpgrade; status != nil &&
+		status.ID == upgradeObjectID(upgrade) {
+		observeUpgradeProgress(upgrade, status)
+		return nil
+	}
+
+	// Wait for any other upgrade of the cluster to finish.
+	if status := cluster.Status.PostgresUpgrade; status != nil && !status.Finished {
+		setValidated(metav1.ConditionUnknown, "UpgradeInProgress", fmt.Sprintf(
+			"PostgresCluster %q is being upgraded to PostgreSQL %d",
+			cluster.Name, status.ToPostgresVersion))
+		return nil
+	}
+
+	if reason, message := upgradeObjectInvalid(cluster, upgrade); reason != "" {
+		setValidated(metav1.ConditionFalse, reason, message)
+		return nil
+	}
+
+	setValidated(metav1.ConditionTrue, "UpgradeReady", fmt.Sprintf(
+		"PostgresCluster %q can be upgraded from PostgreSQL %d to %d", cluster.Name,
+		upgrade.Spec.FromPostgresVersion, upgrade.Spec.ToPostgresVersion))
+	meta.SetStatusCondition(&upgrade.Status.Conditions, metav1.Condition{
+		ObservedGeneration: upgrade.GetGeneration(),
+		Type:               v1beta1.PGUpgradeSucceeded,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Pending",
+		Message:            "Waiting for the PostgresCluster to begin the upgrade",
+	})
+	return nil
+}
+
+// upgradeObjectInvalid returns a reason and message when upgrade cannot be
+// performed on cluster. It returns empty strings otherwise.
+func upgradeObjectInvalid(
+	cluster *v1beta1.PostgresCluster, upgrade *v1beta1.PGUpgrade,
+) (string, string) {
+	from, to := upgrade.Spec.FromPostgresVersion, upgrade.Spec.ToPostgresVersion
+
+	switch {
+	case to <= from:
+		return "InvalidVersion", fmt.Sprintf(
+			"toPostgresVersion (%d) must be greater than fromPostgresVersion (%d)", to, from)
+
+	case cluster.Spec.PostgresVersion != from:
+		return "VersionMismatch", fmt.Sprintf(
+			"PostgresCluster %q uses PostgreSQL %d, not %d",
+			cluster.Name, cluster.Spec.PostgresVersion, from)
+
+	case cluster.Spec.Standby != nil && cluster.Spec.Standby.Enabled:
+		return "StandbyCluster",
+			"A standby cluster cannot be upgraded; upgrade the cluster it follows instead"
+
+	case !patroni.ClusterBootstrapped(cluster):
+		return "ClusterNotBootstrapped", fmt.Sprintf(
+			"PostgresCluster %q has not been bootstrapped", cluster.Name)
+
+	case meta.IsStatusConditionTrue(cluster.Status.Conditions,
+		ConditionPGBackRestRestoreProgressing):
+		return "RestoreInProgress", fmt.Sprintf(
+			"PostgresCluster %q is being restored", cluster.Name)
+
+	case upgradeImage(cluster, upgrade) == "":
+		return "ImageNotFound", fmt.Sprintf(
+			"No image for PostgreSQL %d: set the image of the PGUpgrade", to)
+	}
+	return "", ""
+}
+
+// upgradeImage returns the image that runs PostgreSQL after upgrade. That is
+// the image in its spec or else the default image of the new version.
+func upgradeImage(cluster *v1beta1.PostgresCluster, upgrade *v1beta1.PGUpgrade) string {
+	if upgrade.Spec.Image != "" {
+		return upgrade.Spec.Image
+	}
+
+	// The image of the cluster is for the current version.
+	upgraded := cluster.DeepCopy()
+	upgraded.Spec.Image = ""
+	upgraded.Spec.PostgresVersion = upgrade.Spec.ToPostgresVersion
+	return config.PostgresContainerImage(upgraded)
+}
+
+// upgradeObjectID returns the identifier of upgrade in the upgrade status of
+// its PostgresCluster.
+func upgradeObjectID(upgrade *v1beta1.PGUpgrade) string {
+	return string(upgrade.GetUID())
+}
+
+// observeUpgradeProgress copies the upgrade status of a cluster to upgrade.
+func observeUpgradeProgress(upgrade *v1beta1.PGUpgrade, status *v1beta1.PostgresUpgradeStatus) {
+	upgrade.Status.StartTime = status.StartTime
+	upgrade.Status.CompletionTime = status.CompletionTime
+	upgrade.Status.Finished = status.Finished
+
+	progressing := metav1.Condition{
+		ObservedGeneration: upgrade.GetGeneration(),
+		Type:               v1beta1.PGUpgradeProgressing,
+		Status:             metav1.ConditionTrue,
+		Reason:             status.Phase,
+		Message:            status.Message,
+	}
+	succeeded := metav1.Condition{
+		ObservedGeneration: upgrade.GetGeneration(),
+		Type:               v1beta1.PGUpgradeSucceeded,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Running",
+		Message:            "Upgrading the PostgresCluster",
+	}
+	switch status.Phase {
+	case postgresUpgradeSucceeded:
+		progressing.Status = metav1.ConditionFalse
+		succeeded.Status = metav1.ConditionTrue
+		succeeded.Reason = "UpgradeComplete"
+		succeeded.Message = status.Message
+	case postgresUpgradeFailed:
+		progressing.Status = metav1.ConditionFalse
+		succeeded.Status = metav1.ConditionFalse
+		succeeded.Reason = "UpgradeFailed"
+		succeeded.Message = status.Message
+	}
+	meta.SetStatusCondition(&upgrade.Status.Conditions, progressing)
+	meta.SetStatusCondition(&upgrade.Status.Conditions, succeeded)
+}
+
+// postgresUpgradeInProgress returns true when a major PostgreSQL upgrade of
+// cluster has started but not finished.
+func postgresUpgradeInProgress(cluster *v1beta1.PostgresCluster) bool {
+	status := cluster.Status.PostgresUpgrade
+	return status != nil && !status.Finished
+}
+
+// setPostgresUpgradePhase records phase and message in the upgrade status and
+// conditions of cluster. The upgrade is finished when phase is "Succeeded" or
+// "Failed".
+func setPostgresUpgradePhase(cluster *v1beta1.PostgresCluster, phase, message string) {
+	status := cluster.Status.PostgresUpgrade
+	status.Phase = phase
+	status.Message = message
+
+	condition := metav1.Condition{
+		ObservedGeneration: cluster.GetGeneration(),
+		Type:               ConditionPostgresUpgradeProgressing,
+		Status:             metav1.ConditionTrue,
+		Reason:             phase,
+		Message:            message,
+	}
+	if phase == postgresUpgradeSucceeded || phase == postgresUpgradeFailed {
+		now := metav1.Now()
+		status.Finished = true
+		status.CompletionTime = &now
+		condition.Status = metav1.ConditionFalse
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=pgupgrades,verbs=list
+
+// pendingUpgradeObject returns the PGUpgrade that cluster should perform, if
+// any. That is the one in progress or else the oldest that has been validated.
+func (r *Reconciler) pendingUpgradeObject(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) (*v1beta1.PGUpgrade, error) {
+	upgrades := &v1beta1.PGUpgradeList{}
+	if err := r.Client.List(ctx, upgrades,
+		client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var inProgress string
+	if postgresUpgradeInProgress(cluster) {
+		inProgress = cluster.Status.PostgresUpgrade.ID
+	}
+
+	var pending []*v1beta1.PGUpgrade
+	for i := range upgrades.Items {
+		upgrade := &upgrades.Items[i]
+		if upgrade.Spec.PostgresClusterName != cluster.Name ||
+			upgrade.Status.Finished || upgrade.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if upgradeObjectID(upgrade) == inProgress {
+			return upgrade, nil
+		}
+
+		validated := meta.FindStatusCondition(upgrade.Status.Conditions,
+			v1beta1.PGUpgradeValidated)
+		if validated != nil && validated.Status == metav1.ConditionTrue &&
+			validated.ObservedGeneration == upgrade.GetGeneration() {
+			pending = append(pending, upgrade)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i].GetCreationTimestamp(), pending[j].GetCreationTimestamp()
+		return a.Before(&b) || (a.Equal(&b) && pending[i].Name < pending[j].Name)
+	})
+	return pending[0], nil
+}
+
+// +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;create;patch;delete
+
+// reconcilePostgresUpgrade performs the major PostgreSQL upgrade of a validated
+// PGUpgrade. The cluster is shut down using the same path as spec.shutdown, then
+// a Job runs pg_upgrade on the data volume of the last primary. Once that
+// succeeds, the other instances lose their data volumes and Patroni forgets the
+// cluster so that it bootstraps again from the upgraded data directory. The
+// cluster stays down until spec.postgresVersion is the new version. While the
+// upgrade is in progress, this changes cluster.Spec.Shutdown in memory only.
+func (r *Reconciler) reconcilePostgresUpgrade(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	instances *observedInstances, clusterVolumes []corev1.PersistentVolumeClaim,
+) (reconcile.Result, error) {
+	upgrade, err := r.pendingUpgradeObject(ctx, cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	status := cluster.Status.PostgresUpgrade
+	if status == nil || status.Finished {
+		if upgrade == nil || (status != nil && status.ID == upgradeObjectID(upgrade)) {
+			return reconcile.Result{}, nil
+		}
+
+		now := metav1.Now()
+		status = &v1beta1.PostgresUpgradeStatus{
+			ID:                  upgradeObjectID(upgrade),
+			FromPostgresVersion: upgrade.Spec.FromPostgresVersion,
+			ToPostgresVersion:   upgrade.Spec.ToPostgresVersion,
+			StartTime:           &now,
+		}
+		cluster.Status.PostgresUpgrade = status
+		setPostgresUpgradePhase(cluster, postgresUpgradeShuttingDown, "Shutting down the cluster")
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, EventUpgradeStarted,
+			"Upgrading PostgreSQL %d to %d", status.FromPostgresVersion, status.ToPostgresVersion)
+	}
+
+	fail := func(message string) {
+		setPostgresUpgradePhase(cluster, postgresUpgradeFailed, message)
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, EventUpgradeFailed, message)
+	}
+	holdDown := func() { cluster.Spec.Shutdown = initialize.Bool(true) }
+
+	existing := &batchv1.Job{ObjectMeta: naming.PGUpgradeJob(cluster)}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, errors.WithStack(err)
+		}
+		existing = nil
+	}
+
+	switch status.Phase {
+	case postgresUpgradeShuttingDown:
+		if cluster.Spec.PostgresVersion != status.FromPostgresVersion {
+			fail(fmt.Sprintf("spec.postgresVersion changed from %d before the upgrade began."+
+				" No data was changed; create another PGUpgrade to try again.",
+				status.FromPostgresVersion))
+			return reconcile.Result{}, nil
+		}
+
+		holdDown()
+		for _, instance := range instances.forCluster {
+			if len(instance.Pods) > 0 {
+				return reconcile.Result{}, nil
+			}
+		}
+		if cluster.Status.StartupInstance == "" {
+			fail("Unable to determine the primary instance of the cluster." +
+				" No data was changed; create another PGUpgrade to try again.")
+			return reconcile.Result{}, nil
+		}
+
+		// Remove the Job of any earlier upgrade. Its deletion triggers another reconcile.
+		if existing != nil && existing.GetLabels()[naming.LabelPGUpgrade] != status.ID {
+			err := r.Client.Delete(ctx, existing,
+				client.PropagationPolicy(metav1.DeletePropagationBackground))
+			return reconcile.Result{}, errors.WithStack(client.IgnoreNotFound(err))
+		}
+
+		setPostgresUpgradePhase(cluster, postgresUpgradeUpgrading, fmt.Sprintf(
+			"Running pg_upgrade on instance %q", cluster.Status.StartupInstance))
+		fallthrough
+
+	case postgresUpgradeUpgrading:
+		holdDown()
+		if existing == nil {
+			if upgrade == nil {
+				fail("The PGUpgrade was deleted before pg_upgrade started." +
+					" No data was changed; create another PGUpgrade to try again.")
+				return reconcile.Result{}, nil
+			}
+			return reconcile.Result{}, r.applyUpgradeJob(ctx, cluster, upgrade, clusterVolumes)
+		}
+		if jobFailed(existing) {
+			fail(fmt.Sprintf("pg_upgrade did not succeed; see the logs of Job %q."+
+				" The data of PostgreSQL %d was left in place, and the cluster is starting"+
+				" with it again. If PostgreSQL reports that it cannot find its control file,"+
+				" rename global/pg_control.old to global/pg_control in its data directory."+
+				" Resolve the problem then create another PGUpgrade to try again.",
+				existing.Name, status.FromPostgresVersion))
+			return reconcile.Result{}, nil
+		}
+		if !jobCompleted(existing) {
+			return reconcile.Result{}, nil
+		}
+
+		// Patroni would start the old cluster again unless it forgets about it.
+		// Patroni does not set owners on these objects, so check on them again soon.
+		if endpoints, _, err := r.observeRestoreEnv(ctx, cluster); err != nil {
+			return reconcile.Result{}, err
+		} else if len(endpoints) > 0 {
+			for i := range endpoints {
+				if err := r.Client.Delete(ctx, &endpoints[i]); client.IgnoreNotFound(err) != nil {
+					return reconcile.Result{}, errors.WithStack(err)
+				}
+			}
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		// Replicas are created again from the upgraded primary.
+		for i := range clusterVolumes {
+			pvc := &clusterVolumes[i]
+			role := pvc.GetLabels()[naming.LabelRole]
+			if pvc.GetLabels()[naming.LabelInstance] == cluster.Status.StartupInstance ||
+				(role != naming.RolePostgresData && role != naming.RolePostgresWAL) {
+				continue
+			}
+			if err := r.Client.Delete(ctx, pvc); client.IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, errors.WithStack(err)
+			}
+		}
+
+		// The cluster is no longer bootstrapped, and its stanzas must be
+		// upgraded. The pgBouncer and exporter hashes are no longer valid.
+		cluster.Status.Patroni.SystemIdentifier = ""
+		cluster.Status.PostgresVersion = status.ToPostgresVersion
+		if cluster.Status.PGBackRest != nil {
+			for i := range cluster.Status.PGBackRest.Repos {
+				cluster.Status.PGBackRest.Repos[i].StanzaCreated = false
+			}
+		}
+		cluster.Status.Proxy.PGBouncer.PostgreSQLRevision = ""
+		cluster.Status.Monitoring.ExporterConfiguration = ""
+
+		setPostgresUpgradePhase(cluster, postgresUpgradeWaitingForVersion, fmt.Sprintf(
+			"pg_upgrade succeeded. Set spec.postgresVersion to %d to start the cluster.",
+			status.ToPostgresVersion))
+		fallthrough
+
+	case postgresUpgradeWaitingForVersion:
+		if cluster.Spec.PostgresVersion != status.ToPostgresVersion {
+			holdDown()
+			return reconcile.Result{}, nil
+		}
+
+		setPostgresUpgradePhase(cluster, postgresUpgradeStartingUp, fmt.Sprintf(
+			"Starting PostgreSQL %d", status.ToPostgresVersion))
+		fallthrough
+
+	case postgresUpgradeStartingUp:
+		stanzasCreated := cluster.Status.PGBackRest != nil
+		if stanzasCreated {
+			for _, repo := range cluster.Status.PGBackRest.Repos {
+				stanzasCreated = stanzasCreated && repo.StanzaCreated
+			}
+		}
+		if !patroni.ClusterBootstrapped(cluster) || !stanzasCreated {
+			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+
+		message := fmt.Sprintf("Upgraded PostgreSQL %d to %d",
+			status.FromPostgresVersion, status.ToPostgresVersion)
+		setPostgresUpgradePhase(cluster, postgresUpgradeSucceeded, message)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, EventUpgradeSucceeded, message)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// applyUpgradeJob creates the Job that runs pg_upgrade on the volumes of the
+// startup instance of cluster.
+func (r *Reconciler) applyUpgradeJob(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, upgrade *v1beta1.PGUpgrade,
+	clusterVolumes []corev1.PersistentVolumeClaim,
+) error {
+	var pgdata, pgwal *corev1.PersistentVolumeClaim
+	for i := range clusterVolumes {
+		if clusterVolumes[i].GetLabels()[naming.LabelInstance] == cluster.Status.StartupInstance {
+			switch clusterVolumes[i].GetLabels()[naming.LabelRole] {
+			case naming.RolePostgresData:
+				pgdata = &clusterVolumes[i]
+			case naming.RolePostgresWAL:
+				pgwal = &clusterVolumes[i]
+			}
+		}
+	}
+	if pgdata == nil {
+		return errors.Errorf("unable to find the data volume of instance %q",
+			cluster.Status.StartupInstance)
+	}
+
+	job := generateUpgradeJob(cluster, upgrade, pgdata, pgwal)
+	err := errors.WithStack(r.setControllerReference(cluster, job))
+	if err == nil {
+		err = errors.WithStack(r.apply(ctx, job))
+	}
+	return err
+}
+
+// generateUpgradeJob returns the Job that upgrades the data volume pgdata, and
+// pgwal when it is not nil, according to upgrade.
+func generateUpgradeJob(
+	cluster *v1beta1.PostgresCluster, upgrade *v1beta1.PGUpgrade,
+	pgdata, pgwal *corev1.PersistentVolumeClaim,
+) *batchv1.Job {
+	from, to := upgrade.Spec.FromPostgresVersion, upgrade.Spec.ToPostgresVersion
+
+	job := &batchv1.Job{ObjectMeta: naming.PGUpgradeJob(cluster)}
+	job.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
+
+	job.Annotations = naming.Merge(cluster.Spec.Metadata.GetAnnotationsOrNil())
+	job.Labels = naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		naming.PGUpgradeJobLabels(cluster.Name, upgradeObjectID(upgrade)),
+		map[string]string{naming.LabelStartupInstance: cluster.Status.StartupInstance},
+	)
+
+	dataVolumeMount := postgres.DataVolumeMount()
+	volumes := []corev1.Volume{{
+		Name: dataVolumeMount.Name,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: pgdata.GetName(),
+			},
+		},
+	}}
+	volumeMounts := []corev1.VolumeMount{dataVolumeMount}
+
+	if pgwal != nil {
+		walVolumeMount := postgres.WALVolumeMount()
+		volumes = append(volumes, corev1.Volume{
+			Name: walVolumeMount.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pgwal.GetName(),
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, walVolumeMount)
+	}
+
+	// The old binaries are copied out of the current image into a volume
+	// that is mounted where they were in the new image.
+	volumes = append(volumes, corev1.Volume{
+		Name: naming.ContainerPGUpgradeBinaries,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	volumeMounts = append(volumeMounts, corev1.VolumeMount{
+		Name:      naming.ContainerPGUpgradeBinaries,
+		MountPath: postgres.UpgradeBinariesDirectory(from),
+	})
+
+	// The current image is that of the old version until spec.postgresVersion
+	// changes, and the upgrade does not start once it has.
+	current := config.PostgresContainerImage(cluster)
+
+	job.Spec.Template.Annotations = job.Annotations
+	job.Spec.Template.Labels = job.Labels
+	job.Spec.Template.Spec = corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Command:         postgres.UpgradeBinariesCommand(from, "/binaries"),
+			Image:           current,
+			ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+			Name:            naming.ContainerPGUpgradeBinaries,
+			Resources:       upgrade.Spec.Resources,
+			SecurityContext: initialize.RestrictedSecurityContext(),
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      naming.ContainerPGUpgradeBinaries,
+				MountPath: "/binaries",
+			}},
+		}},
+		Containers: []corev1.Container{{
+			Command:         postgres.UpgradeCommand(from, to),
+			Image:           upgradeImage(cluster, upgrade),
+			ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+			Name:            naming.ContainerPGUpgrade,
+			Resources:       upgrade.Spec.Resources,
+			SecurityContext: initialize.RestrictedSecurityContext(),
+			VolumeMounts:    volumeMounts,
+		}},
+		RestartPolicy: corev1.RestartPolicyNever,
+		Volumes:       volumes,
+	}
+
+	// Set the image pull secrets, if any exist.
+	// This is set here rather than using the service account due to the lack
+	// of propagation to existing pods when the CRD is updated:
+	// https://github.com/kubernetes/kubernetes/issues/88456
+	job.Spec.Template.Spec.ImagePullSecrets = cluster.Spec.ImagePullSecrets
+
+	// The upgrade does not make any Kubernetes API calls.
+	job.Spec.Template.Spec.AutomountServiceAccountToken = initialize.Bool(false)
+
+	// Do not add environment variables describing services in this namespace.
+	job.Spec.Template.Spec.EnableServiceLinks = initialize.Bool(false)
+
+	job.Spec.Template.Spec.SecurityContext = postgres.PodSecurityContext(cluster)
+
+	// pg_upgrade must run as the PostgreSQL bootstrap user, "postgres".
+	addNSSWrapper(current, cluster.Spec.ImagePullPolicy, &job.Spec.Template)
+	addTMPEmptyDir(&job.Spec.Template)
+
+	return job
+}
+
+// watchUpgradesForCluster returns a handler.EventHandler that queues the
+// PostgresCluster of a PGUpgrade whenever it changes.
+func (*Reconciler) watchUpgradesForCluster() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		if upgrade, ok := object.(*v1beta1.PGUpgrade); ok {
+			return []reconcile.Request{{NamespacedName: client.ObjectKey{
+				Namespace: upgrade.Namespace, Name: upgrade.Spec.PostgresClusterName,
+			}}}
+		}
+		return nil
+	})
+}
+
+// watchClusterForUpgrades returns a handler.EventHandler that queues the
+// unfinished PGUpgrades of a PostgresCluster whenever it changes.
+func (r *Reconciler) watchClusterForUpgrades() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(cluster client.Object) []reconcile.Request {
+		ctx := context.Background()
+		upgrades := &v1beta1.PGUpgradeList{}
+		if err := r.Client.List(ctx, upgrades,
+			client.InNamespace(cluster.GetNamespace())); err != nil {
+			logging.FromContext(ctx).Error(err, "listing PGUpgrades")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for i := range upgrades.Items {
+			if upgrades.Items[i].Spec.PostgresClusterName == cluster.GetName() &&
+				!upgrades.Items[i].Status.Finished {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(&upgrades.Items[i]),
+				})
+			}
+		}
+		return requests
+	})
+}
+
+// SetupUpgradeControllerWithManager adds the PGUpgrade controller to the
+// provided runtime manager
+func (r *Reconciler) SetupUpgradeControllerWithManager(mgr manager.Manager) error {
+	return builder.ControllerManagedBy(mgr).
+		Named(UpgradeControllerName).
+		For(&v1beta1.PGUpgrade{}).
+		Watches(&source.Kind{Type: &v1beta1.PostgresCluster{}},
+			r.watchClusterForUpgrades()).
+		Complete(reconcile.Func(r.ReconcileUpgrade))
+}
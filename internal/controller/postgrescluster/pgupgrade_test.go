@@ -0,0 +1,212 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestUpgradeObjectInvalid(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Name = "hippo"
+	cluster.Spec.PostgresVersion = 13
+	cluster.Status.Patroni.SystemIdentifier = "12345"
+
+	upgrade := new(v1beta1.PGUpgrade)
+	upgrade.Spec.FromPostgresVersion = 13
+	upgrade.Spec.ToPostgresVersion = 14
+	upgrade.Spec.Image = "example.com/postgres:14"
+
+	reason, _ := upgradeObjectInvalid(cluster, upgrade)
+	assert.Equal(t, reason, "")
+
+	t.Run("Downgrade", func(t *testing.T) {
+		upgrade := upgrade.DeepCopy()
+		upgrade.Spec.ToPostgresVersion = 12
+
+		reason, _ := upgradeObjectInvalid(cluster, upgrade)
+		assert.Equal(t, reason, "InvalidVersion")
+	})
+
+	t.Run("VersionMismatch", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.PostgresVersion = 12
+
+		reason, message := upgradeObjectInvalid(cluster, upgrade)
+		assert.Equal(t, reason, "VersionMismatch")
+		assert.Equal(t, message, `PostgresCluster "hippo" uses PostgreSQL 12, not 13`)
+	})
+
+	t.Run("Standby", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Standby = &v1beta1.PostgresStandbySpec{Enabled: true}
+
+		reason, _ := upgradeObjectInvalid(cluster, upgrade)
+		assert.Equal(t, reason, "StandbyCluster")
+	})
+
+	t.Run("NotBootstrapped", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Status.Patroni.SystemIdentifier = ""
+
+		reason, _ := upgradeObjectInvalid(cluster, upgrade)
+		assert.Equal(t, reason, "ClusterNotBootstrapped")
+	})
+
+	t.Run("Restoring", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type: ConditionPGBackRestRestoreProgressing, Status: metav1.ConditionTrue,
+			Reason: ReasonReadyForRestore,
+		})
+
+		reason, _ := upgradeObjectInvalid(cluster, upgrade)
+		assert.Equal(t, reason, "RestoreInProgress")
+	})
+
+	t.Run("Image", func(t *testing.T) {
+		upgrade := upgrade.DeepCopy()
+		upgrade.Spec.Image = ""
+
+		t.Setenv("RELATED_IMAGE_POSTGRES_14", "")
+		reason, _ := upgradeObjectInvalid(cluster, upgrade)
+		assert.Equal(t, reason, "ImageNotFound")
+
+		t.Setenv("RELATED_IMAGE_POSTGRES_14", "example.com/default:14")
+		reason, _ = upgradeObjectInvalid(cluster, upgrade)
+		assert.Equal(t, reason, "")
+		assert.Equal(t, upgradeImage(cluster, upgrade), "example.com/default:14")
+
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Image = "example.com/custom:13"
+		assert.Equal(t, upgradeImage(cluster, upgrade), "example.com/default:14",
+			"expected the image of the new version")
+	})
+}
+
+func TestObserveUpgradeProgress(t *testing.T) {
+	upgrade := new(v1beta1.PGUpgrade)
+	status := &v1beta1.PostgresUpgradeStatus{
+		Phase: postgresUpgradeUpgrading, Message: "Running pg_upgrade",
+	}
+
+	observeUpgradeProgress(upgrade, status)
+	assert.Assert(t, !upgrade.Status.Finished)
+	assert.Assert(t, meta.IsStatusConditionTrue(upgrade.Status.Conditions,
+		v1beta1.PGUpgradeProgressing))
+	assert.Assert(t, meta.IsStatusConditionPresentAndEqual(upgrade.Status.Conditions,
+		v1beta1.PGUpgradeSucceeded, metav1.ConditionUnknown))
+	assert.Equal(t, meta.FindStatusCondition(upgrade.Status.Conditions,
+		v1beta1.PGUpgradeProgressing).Reason, postgresUpgradeUpgrading)
+
+	status.Phase, status.Finished, status.Message = postgresUpgradeFailed, true, "rollback"
+	observeUpgradeProgress(upgrade, status)
+	assert.Assert(t, upgrade.Status.Finished)
+	assert.Assert(t, meta.IsStatusConditionFalse(upgrade.Status.Conditions,
+		v1beta1.PGUpgradeProgressing))
+	assert.Assert(t, meta.IsStatusConditionFalse(upgrade.Status.Conditions,
+		v1beta1.PGUpgradeSucceeded))
+	assert.Equal(t, meta.FindStatusCondition(upgrade.Status.Conditions,
+		v1beta1.PGUpgradeSucceeded).Message, "rollback")
+
+	status.Phase = postgresUpgradeSucceeded
+	observeUpgradeProgress(upgrade, status)
+	assert.Assert(t, meta.IsStatusConditionTrue(upgrade.Status.Conditions,
+		v1beta1.PGUpgradeSucceeded))
+}
+
+func TestSetPostgresUpgradePhase(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Status.PostgresUpgrade = &v1beta1.PostgresUpgradeStatus{ID: "abc"}
+	assert.Assert(t, postgresUpgradeInProgress(cluster))
+
+	setPostgresUpgradePhase(cluster, postgresUpgradeShuttingDown, "stopping")
+	assert.Equal(t, cluster.Status.PostgresUpgrade.Phase, postgresUpgradeShuttingDown)
+	assert.Equal(t, cluster.Status.PostgresUpgrade.Message, "stopping")
+	assert.Assert(t, postgresUpgradeInProgress(cluster))
+	assert.Assert(t, meta.IsStatusConditionTrue(cluster.Status.Conditions,
+		ConditionPostgresUpgradeProgressing))
+
+	setPostgresUpgradePhase(cluster, postgresUpgradeSucceeded, "done")
+	assert.Assert(t, cluster.Status.PostgresUpgrade.Finished)
+	assert.Assert(t, cluster.Status.PostgresUpgrade.CompletionTime != nil)
+	assert.Assert(t, !postgresUpgradeInProgress(cluster))
+	assert.Assert(t, meta.IsStatusConditionFalse(cluster.Status.Conditions,
+		ConditionPostgresUpgradeProgressing))
+}
+
+func TestGenerateUpgradeJob(t *testing.T) {
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.PostgresVersion = 13
+	cluster.Spec.Image = "example.com/postgres:13"
+	cluster.Status.StartupInstance = "hippo-00-abcd"
+
+	upgrade := new(v1beta1.PGUpgrade)
+	upgrade.UID = "some-uid"
+	upgrade.Spec.FromPostgresVersion = 13
+	upgrade.Spec.ToPostgresVersion = 14
+	upgrade.Spec.Image = "example.com/postgres:14"
+
+	pgdata := new(corev1.PersistentVolumeClaim)
+	pgdata.Name = "hippo-00-abcd-pgdata"
+	pgwal := new(corev1.PersistentVolumeClaim)
+	pgwal.Name = "hippo-00-abcd-pgwal"
+
+	job := generateUpgradeJob(cluster, upgrade, pgdata, pgwal)
+	assert.Equal(t, job.Name, "hippo-pgupgrade")
+	assert.Equal(t, job.Labels[naming.LabelPGUpgrade], "some-uid")
+	assert.Equal(t, job.Labels[naming.LabelStartupInstance], "hippo-00-abcd")
+
+	spec := job.Spec.Template.Spec
+	assert.Equal(t, spec.RestartPolicy, corev1.RestartPolicyNever)
+	assert.Equal(t, len(spec.Containers), 1)
+	assert.Equal(t, spec.Containers[0].Name, naming.ContainerPGUpgrade)
+	assert.Equal(t, spec.Containers[0].Image, "example.com/postgres:14")
+
+	// The old binaries come from the current image.
+	assert.Equal(t, spec.InitContainers[0].Name, naming.ContainerPGUpgradeBinaries)
+	assert.Equal(t, spec.InitContainers[0].Image, "example.com/postgres:13")
+
+	mounts := map[string]string{}
+	for _, mount := range spec.Containers[0].VolumeMounts {
+		mounts[mount.Name] = mount.MountPath
+	}
+	assert.Equal(t, mounts["postgres-data"], "/pgdata")
+	assert.Equal(t, mounts["postgres-wal"], "/pgwal")
+	assert.Equal(t, mounts[naming.ContainerPGUpgradeBinaries], "/usr/pgsql-13")
+
+	var nss bool
+	for _, env := range spec.Containers[0].Env {
+		nss = nss || env.Name == "NSS_WRAPPER_PASSWD"
+	}
+	assert.Assert(t, nss, "expected nss_wrapper for the postgres user")
+
+	t.Run("NoWAL", func(t *testing.T) {
+		job := generateUpgradeJob(cluster, upgrade, pgdata, nil)
+		for _, volume := range job.Spec.Template.Spec.Volumes {
+			assert.Assert(t, volume.Name != "postgres-wal")
+		}
+	})
+}
@@ -136,6 +136,14 @@ func (r *Reconciler) reconcileClusterCertificate(
 	dnsNames := naming.ServiceDNSNames(ctx, primaryService)
 	dnsFQDN := dnsNames[0]
 
+	// Read-only PgBouncer connects to replicas through the replica Service
+	// and verifies the server certificate against that name.
+	if cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil &&
+		cluster.Spec.Proxy.PGBouncer.ReadOnly != nil {
+		dnsNames = append(dnsNames, naming.ServiceDNSNames(ctx,
+			&corev1.Service{ObjectMeta: naming.ClusterReplicaService(cluster)})...)
+	}
+
 	if err == nil {
 		// Unmarshal and validate the stored leaf. These first errors can
 		// be ignored because they result in an invalid leaf which is then
@@ -23,20 +23,26 @@ import (
 	"net"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crunchydata/postgres-operator/internal/initialize"
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/pgaudit"
+	"github.com/crunchydata/postgres-operator/internal/pki"
 	"github.com/crunchydata/postgres-operator/internal/postgis"
 	"github.com/crunchydata/postgres-operator/internal/postgres"
 	pgpassword "github.com/crunchydata/postgres-operator/internal/postgres/password"
@@ -44,9 +50,202 @@ import (
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
+const (
+	// ConditionParametersValid is the type used in a condition to indicate
+	// whether or not the parameters in the spec are valid
+	ConditionParametersValid = "ParametersValid"
+)
+
+// addSpecifiedParameters adds the parameters in the spec of cluster to
+// parameters. Those that are not valid for the PostgreSQL version of cluster
+// or that would override a mandatory value are left out and reported in the
+// ConditionParametersValid condition.
+func (r *Reconciler) addSpecifiedParameters(
+	cluster *v1beta1.PostgresCluster, parameters *postgres.Parameters,
+) {
+	names := make([]string, 0, len(cluster.Spec.Config.Parameters))
+	for name := range cluster.Spec.Config.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var invalid []string
+	for _, name := range names {
+		parameter := cluster.Spec.Config.Parameters[name]
+		value := parameter.String()
+		err := postgres.ValidateParameter(cluster.Spec.PostgresVersion, name, value)
+
+		// Specified libraries are loaded after any mandatory ones, so that
+		// one parameter is allowed. See [patroni.DynamicConfiguration].
+		if err == nil && parameters.Mandatory.Has(name) &&
+			!strings.EqualFold(name, "shared_preload_libraries") {
+			err = fmt.Errorf("parameter %q is managed by the operator", name)
+		}
+
+		if err == nil {
+			parameters.Specified.Add(name, value)
+		} else {
+			invalid = append(invalid, err.Error())
+		}
+	}
+
+	switch {
+	case len(names) == 0:
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionParametersValid)
+	case len(invalid) > 0:
+		r.setStatusCondition(cluster, metav1.Condition{
+			Type:    ConditionParametersValid,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidParameters",
+			Message: "Ignoring parameters: " + strings.Join(invalid, "; "),
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+	default:
+		r.setStatusCondition(cluster, metav1.Condition{
+			Type:    ConditionParametersValid,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ParametersValid",
+			Message: "All specified parameters are valid.",
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+	}
+}
+
+const (
+	// ConditionAuthenticationRulesValid is the type used in a condition to
+	// indicate whether or not the authentication rules in the spec are valid
+	ConditionAuthenticationRulesValid = "AuthenticationRulesValid"
+)
+
+// addSpecifiedHBAs adds the authentication rules in the spec of cluster to
+// hbas. The rules are a single policy, so none are added when any is invalid;
+// the problems are reported in the ConditionAuthenticationRulesValid condition
+// instead.
+func (r *Reconciler) addSpecifiedHBAs(
+	cluster *v1beta1.PostgresCluster, hbas *postgres.HBAs,
+) {
+	if cluster.Spec.Authentication == nil || len(cluster.Spec.Authentication.Rules) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionAuthenticationRulesValid)
+		return
+	}
+
+	var invalid []string
+	var specified []postgres.HostBasedAuthentication
+	for i := range cluster.Spec.Authentication.Rules {
+		records, err := hbasFromRule(cluster.Spec.Authentication.Rules[i])
+		if err == nil {
+			specified = append(specified, records...)
+		} else {
+			invalid = append(invalid, fmt.Sprintf("rules[%d]: %v", i, err))
+		}
+	}
+
+	if len(invalid) > 0 {
+		r.setStatusCondition(cluster, metav1.Condition{
+			Type:    ConditionAuthenticationRulesValid,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidAuthenticationRules",
+			Message: "Ignoring all rules: " + strings.Join(invalid, "; "),
+
+			ObservedGeneration: cluster.GetGeneration(),
+		})
+		return
+	}
+
+	r.setStatusCondition(cluster, metav1.Condition{
+		Type:    ConditionAuthenticationRulesValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AuthenticationRulesValid",
+		Message: "All specified authentication rules are valid.",
+
+		ObservedGeneration: cluster.GetGeneration(),
+	})
+	hbas.Specified = append(hbas.Specified, specified...)
+}
+
+// hbasFromRule returns the HostBasedAuthentication records that implement rule,
+// one for each of its networks.
+func hbasFromRule(rule v1beta1.PostgresHBARuleSpec) ([]postgres.HostBasedAuthentication, error) {
+	var databases, roles []string
+	for _, name := range rule.Databases {
+		databases = append(databases, string(name))
+	}
+	for _, name := range rule.Users {
+		roles = append(roles, string(name))
+	}
+	for _, name := range rule.Groups {
+		roles = append(roles, string(name))
+	}
+
+	err := postgres.ValidateHBARule(rule.Connection, rule.Method, rule.Networks,
+		append(databases, roles...)...)
+
+	switch {
+	case err != nil:
+		return nil, err
+	case rule.Method == "peer" && rule.Connection != "local":
+		return nil, errors.New(`the "peer" method requires a local connection`)
+	case rule.Method == "ldap" && rule.Options["ldapserver"] == "" && rule.Options["ldapurl"] == "":
+		return nil, errors.New(`the "ldap" method requires an ldapserver or ldapurl option`)
+	}
+
+	for k, v := range rule.Options {
+		if !hbaOptionName.MatchString(k) {
+			return nil, fmt.Errorf("invalid option name %q", k)
+		}
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, fmt.Errorf("option %q cannot contain line breaks", k)
+		}
+	}
+
+	hba := postgres.NewHBA()
+	switch rule.Connection {
+	case "local":
+		hba.Local()
+	case "host":
+		hba.TCP()
+	case "hostnossl":
+		hba.NoSSL()
+	default:
+		hba.TLS()
+	}
+	hba.Method(rule.Method)
+
+	if len(databases) > 0 {
+		hba.Databases(databases...)
+	}
+	for _, name := range rule.Users {
+		hba.Users(string(name))
+	}
+	for _, name := range rule.Groups {
+		hba.Roles(string(name))
+	}
+	if len(rule.Options) > 0 {
+		hba.Options(rule.Options)
+	}
+
+	if len(rule.Networks) == 0 {
+		return []postgres.HostBasedAuthentication{*hba}, nil
+	}
+
+	records := make([]postgres.HostBasedAuthentication, len(rule.Networks))
+	for i, network := range rule.Networks {
+		records[i] = *hba
+		records[i].Network(network)
+	}
+	return records, nil
+}
+
+// hbaOptionName matches the names of authentication options in pg_hba.conf.
+var hbaOptionName = regexp.MustCompile(`^[a-z_]+$`)
+
 // generatePostgresUserSecret returns a Secret containing a password and
 // connection details for the first database in spec. When existing is nil or
 // lacks a password or verifier, a new password and verifier are generated.
+// Users that authenticate with only a certificate have no password. Users
+// with a password from another Secret never have one generated.
 func (r *Reconciler) generatePostgresUserSecret(
 	cluster *v1beta1.PostgresCluster, spec *v1beta1.PostgresUserSpec, existing *corev1.Secret,
 ) (*corev1.Secret, error) {
@@ -62,18 +261,37 @@ func (r *Reconciler) generatePostgresUserSecret(
 	hostname := primary.Name + "." + primary.Namespace + ".svc"
 	port := fmt.Sprint(*cluster.Spec.Port)
 
+	passwordless := spec.Certificate != nil && !spec.Certificate.RequirePassword
+	external := spec.Password != nil && spec.Password.SecretRef != nil
+
+	// Users with a rotation grace period log in through one of two roles.
+	// Certificates are issued for the user, so those users always log in as
+	// the user.
+	login := username
+	if existing != nil && spec.Certificate == nil &&
+		string(existing.Data["user"]) == postgresUserAlternate(username) {
+		login = postgresUserAlternate(username)
+	}
+
 	intent.Data["host"] = []byte(hostname)
 	intent.Data["port"] = []byte(port)
-	intent.Data["user"] = []byte(username)
+	intent.Data["user"] = []byte(login)
 
-	// Use the existing password and verifier.
-	if existing != nil {
+	// Use the existing password and verifier. Keep the previous user and
+	// password, if any, during their grace period.
+	if existing != nil && !passwordless {
 		intent.Data["password"] = existing.Data["password"]
 		intent.Data["verifier"] = existing.Data["verifier"]
+
+		if previous := existing.Data["previous-user"]; len(previous) > 0 && !external {
+			intent.Data["previous-user"] = previous
+			intent.Data["previous-password"] = existing.Data["previous-password"]
+			intent.Data["previous-verifier"] = existing.Data["previous-verifier"]
+		}
 	}
 
 	// When password is unset, generate a new one according to the specified policy.
-	if len(intent.Data["password"]) == 0 {
+	if len(intent.Data["password"]) == 0 && !passwordless && !external {
 		// NOTE: The tests around ASCII passwords are lacking. When changing
 		// this, make sure that ASCII is the default.
 		generate := util.GenerateASCIIPassword
@@ -96,7 +314,7 @@ func (r *Reconciler) generatePostgresUserSecret(
 	// generate a verifier based on the current password.
 	// NOTE(cbandy): We don't have a function to compare a plaintext
 	// password to a SCRAM verifier.
-	if len(intent.Data["verifier"]) == 0 {
+	if len(intent.Data["verifier"]) == 0 && len(intent.Data["password"]) != 0 {
 		verifier, err := pgpassword.NewSCRAMPassword(string(intent.Data["password"])).Build()
 		if err != nil {
 			return nil, errors.WithStack(err)
@@ -104,6 +322,12 @@ func (r *Reconciler) generatePostgresUserSecret(
 		intent.Data["verifier"] = []byte(verifier)
 	}
 
+	// Connection URIs include the password when there is one.
+	userinfo := url.UserPassword(login, string(intent.Data["password"]))
+	if len(intent.Data["password"]) == 0 {
+		userinfo = url.User(login)
+	}
+
 	// When a database has been specified, include it and a connection URI.
 	// - https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING
 	if len(spec.Databases) > 0 {
@@ -112,7 +336,7 @@ func (r *Reconciler) generatePostgresUserSecret(
 		intent.Data["dbname"] = []byte(database)
 		intent.Data["uri"] = []byte((&url.URL{
 			Scheme: "postgresql",
-			User:   url.UserPassword(username, string(intent.Data["password"])),
+			User:   userinfo,
 			Host:   net.JoinHostPort(hostname, port),
 			Path:   database,
 		}).String())
@@ -120,8 +344,10 @@ func (r *Reconciler) generatePostgresUserSecret(
 		// The JDBC driver requires a different URI scheme and query component.
 		// - https://jdbc.postgresql.org/documentation/use/#connection-parameters
 		query := url.Values{}
-		query.Set("user", username)
-		query.Set("password", string(intent.Data["password"]))
+		query.Set("user", login)
+		if len(intent.Data["password"]) != 0 {
+			query.Set("password", string(intent.Data["password"]))
+		}
 		intent.Data["jdbc-uri"] = []byte((&url.URL{
 			Scheme:   "jdbc:postgresql",
 			Host:     net.JoinHostPort(hostname, port),
@@ -131,7 +357,9 @@ func (r *Reconciler) generatePostgresUserSecret(
 	}
 
 	// When PgBouncer is enabled, include values for connecting through it.
-	if cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil {
+	// PgBouncer cannot present the certificate of a user, so those users
+	// cannot connect through it.
+	if cluster.Spec.Proxy != nil && cluster.Spec.Proxy.PGBouncer != nil && spec.Certificate == nil {
 		pgBouncer := naming.ClusterPGBouncer(cluster)
 		hostname := pgBouncer.Name + "." + pgBouncer.Namespace + ".svc"
 		port := fmt.Sprint(*cluster.Spec.Proxy.PGBouncer.Port)
@@ -144,7 +372,7 @@ func (r *Reconciler) generatePostgresUserSecret(
 
 			intent.Data["pgbouncer-uri"] = []byte((&url.URL{
 				Scheme: "postgresql",
-				User:   url.UserPassword(username, string(intent.Data["password"])),
+				User:   userinfo,
 				Host:   net.JoinHostPort(hostname, port),
 				Path:   database,
 			}).String())
@@ -155,8 +383,10 @@ func (r *Reconciler) generatePostgresUserSecret(
 			// - https://jdbc.postgresql.org/documentation/use/#connection-parameters
 			// - https://www.pgbouncer.org/faq.html#how-to-use-prepared-statements-with-transaction-pooling
 			query := url.Values{}
-			query.Set("user", username)
-			query.Set("password", string(intent.Data["password"]))
+			query.Set("user", login)
+			if len(intent.Data["password"]) != 0 {
+				query.Set("password", string(intent.Data["password"]))
+			}
 			query.Set("prepareThreshold", "0")
 			intent.Data["pgbouncer-jdbc-uri"] = []byte((&url.URL{
 				Scheme:   "jdbc:postgresql",
@@ -205,6 +435,7 @@ func (r *Reconciler) reconcilePostgresDatabases(
 	// Gather the list of database that should exist in PostgreSQL.
 
 	databases := sets.String{}
+	users := cluster.Spec.Users
 	if cluster.Spec.Users == nil {
 		// Users are unspecified; create one database matching the cluster name
 		// if it is also a valid database name.
@@ -220,6 +451,10 @@ func (r *Reconciler) reconcilePostgresDatabases(
 					fmt.Sprintf("should be at most %d chars long", 63)).Error())
 		} else {
 			databases.Insert(cluster.Name)
+			users = []v1beta1.PostgresUserSpec{{
+				Name:      v1beta1.PostgresIdentifier(cluster.Name),
+				Databases: []v1beta1.PostgresIdentifier{v1beta1.PostgresIdentifier(cluster.Name)},
+			}}
 		}
 	} else {
 		for _, user := range cluster.Spec.Users {
@@ -229,10 +464,24 @@ func (r *Reconciler) reconcilePostgresDatabases(
 		}
 	}
 
+	// Databases in the spec are created with their owners and options, so
+	// they are not created again for users.
+	for _, database := range cluster.Spec.Databases {
+		databases.Delete(string(database.Name))
+	}
+
 	// Calculate a hash of the SQL that should be executed in PostgreSQL.
 
 	var pgAuditOK, postgisInstallOK bool
 	create := func(ctx context.Context, exec postgres.Executor) error {
+		// Write the specified databases first so that pgAudit and PostGIS
+		// are installed in them, too, regardless of their template.
+		if err := postgres.WriteDatabasesInPostgreSQL(ctx, exec,
+			cluster.Spec.Databases, users, cluster.Spec.DatabaseManagement,
+		); err != nil {
+			return err
+		}
+
 		if pgAuditOK = pgaudit.EnableInPostgreSQL(ctx, exec) == nil; !pgAuditOK {
 			// pgAudit can only be enabled after its shared library is loaded,
 			// but early versions of PGO do not load it automatically. Assume
@@ -292,12 +541,317 @@ func (r *Reconciler) reconcilePostgresDatabases(
 	return err
 }
 
+// generatePostgresUserCertificate adds a TLS client certificate for the user
+// in spec to intent. The certificate in existing is kept until it is due for
+// renewal.
+func generatePostgresUserCertificate(
+	root *pki.RootCertificateAuthority, spec *v1beta1.PostgresUserSpec,
+	existing, intent *corev1.Secret,
+) error {
+	leaf := &pki.LeafCertificate{}
+	commonName := string(spec.Name)
+	dnsNames := []string{commonName}
+
+	if existing != nil {
+		// These errors can be ignored because they result in an invalid leaf
+		// which is then correctly regenerated.
+		_ = leaf.Certificate.UnmarshalText(existing.Data[clusterCertFile])
+		_ = leaf.PrivateKey.UnmarshalText(existing.Data[clusterKeyFile])
+	}
+
+	leaf, err := root.RegenerateLeafWhenNecessary(leaf, commonName, dnsNames)
+	err = errors.WithStack(err)
+
+	if err == nil {
+		intent.Data[clusterCertFile], err = leaf.Certificate.MarshalText()
+		err = errors.WithStack(err)
+	}
+	if err == nil {
+		intent.Data[clusterKeyFile], err = leaf.PrivateKey.MarshalText()
+		err = errors.WithStack(err)
+	}
+	if err == nil {
+		intent.Data[rootCertFile], err = root.Certificate.MarshalText()
+		err = errors.WithStack(err)
+	}
+	return err
+}
+
+// addPostgresUserHBAs adds records to hbas that require a certificate from
+// each user in cluster that has one. These users cannot connect without TLS.
+func addPostgresUserHBAs(cluster *v1beta1.PostgresCluster, hbas *postgres.HBAs) {
+	for _, user := range cluster.Spec.Users {
+		if user.Certificate == nil {
+			continue
+		}
+
+		name := string(user.Name)
+		hba := *postgres.NewHBA().TLS().User(name).Method("cert")
+
+		// The "clientcert" option of password methods verifies the common
+		// name of the certificate since PostgreSQL 12.
+		// - https://www.postgresql.org/docs/current/auth-cert.html
+		if user.Certificate.RequirePassword {
+			option := "verify-full"
+			if cluster.Spec.PostgresVersion < 12 {
+				option = "1"
+			}
+			hba.Method("md5").Options(map[string]string{"clientcert": option})
+		}
+
+		hbas.Mandatory = append(hbas.Mandatory, hba,
+			*postgres.NewHBA().TCP().User(name).Method("reject"))
+	}
+}
+
+// copyPostgresUserPassword returns a copy of existing with the password and
+// verifier that ref identifies in source. The verifier of existing is kept when
+// the password has not changed so that PostgreSQL is not updated needlessly.
+func copyPostgresUserPassword(
+	ref *v1beta1.PostgresPasswordSecretReference, existing, source *corev1.Secret,
+) (*corev1.Secret, error) {
+	passwordKey, verifierKey := ref.PasswordKey, ref.VerifierKey
+	if passwordKey == "" && verifierKey == "" {
+		passwordKey = "password"
+	}
+
+	var password, verifier []byte
+	if passwordKey != "" {
+		if password = source.Data[passwordKey]; len(password) == 0 {
+			return nil, fmt.Errorf("expected a password in key %q of Secret %q",
+				passwordKey, source.Name)
+		}
+	}
+	if verifierKey != "" {
+		// PostgreSQL hashes any value it does not recognize as a verifier, so
+		// a password in this key would quietly become the password.
+		if verifier = source.Data[verifierKey]; !bytes.HasPrefix(verifier, []byte("SCRAM-SHA-256$")) {
+			return nil, fmt.Errorf("expected a SCRAM-SHA-256 verifier in key %q of Secret %q",
+				verifierKey, source.Name)
+		}
+	}
+
+	intent := new(corev1.Secret)
+	if existing != nil {
+		intent = existing.DeepCopy()
+	}
+	initialize.ByteMap(&intent.Data)
+
+	if verifier == nil && bytes.Equal(password, intent.Data["password"]) {
+		verifier = intent.Data["verifier"]
+	}
+
+	intent.Data["password"], intent.Data["verifier"] = password, verifier
+
+	// Passwords from another Secret have no grace period.
+	delete(intent.Data, "previous-user")
+	delete(intent.Data, "previous-password")
+	delete(intent.Data, "previous-verifier")
+
+	return intent, nil
+}
+
+// externalPostgresUserPassword returns existing with the password from the
+// Secret that spec refers to. When that Secret is missing or lacks a password,
+// existing is returned unchanged and a warning event is emitted.
+func (r *Reconciler) externalPostgresUserPassword(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	spec *v1beta1.PostgresUserSpec, existing *corev1.Secret,
+) (*corev1.Secret, error) {
+	source := &corev1.Secret{}
+	source.Namespace, source.Name = cluster.Namespace, spec.Password.SecretRef.Name
+
+	err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(source), source))
+	if apierrors.IsNotFound(err) {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidPasswordSecret",
+			"Secret %q for the password of user %q was not found", source.Name, spec.Name)
+		return existing, nil
+	}
+
+	var intent *corev1.Secret
+	if err == nil {
+		intent, err = copyPostgresUserPassword(spec.Password.SecretRef, existing, source)
+
+		if err != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidPasswordSecret",
+				"Unable to use the password of user %q: %v", spec.Name, err)
+			return existing, nil
+		}
+	}
+	return intent, err
+}
+
+// postgresUserAlternate returns the name of the second role through which the
+// user named name logs in. Specified user names cannot contain underscores, so
+// this is never the name of another specified user.
+func postgresUserAlternate(name string) string {
+	const suffix = "_alt"
+	if len(name) > 63-len(suffix) {
+		name = name[:63-len(suffix)]
+	}
+	return name + suffix
+}
+
+// postgresUserGracePeriod returns how long the previous password of spec keeps
+// working after it rotates. Users that authenticate with a certificate or have
+// a password from another Secret have none.
+func postgresUserGracePeriod(spec *v1beta1.PostgresUserSpec) time.Duration {
+	if spec.Certificate != nil || spec.Password == nil ||
+		spec.Password.SecretRef != nil || spec.Password.RotationGracePeriod == nil {
+		return 0
+	}
+	return spec.Password.RotationGracePeriod.Duration
+}
+
+// rotatePostgresUserPassword returns the Secret from which to generate the
+// password of spec at now. When the password is due to be rotated, that is a
+// copy of existing without a password or verifier. With a grace period, the
+// new password is for the other login role of the user, and the current user,
+// password, and verifier move to "previous-" keys until the grace period ends.
+// Without one, the old password stops working as soon as the new verifier is
+// loaded. The time and trigger of the latest rotation are recorded in status.
+// The second return value is how long until the Secret should be looked at
+// again, if ever.
+func rotatePostgresUserPassword(
+	spec *v1beta1.PostgresUserSpec, existing *corev1.Secret,
+	status *v1beta1.PostgresUserPasswordStatus, now time.Time,
+) (*corev1.Secret, time.Duration) {
+	// Users that authenticate with only a certificate have no password, and
+	// passwords from another Secret are rotated by whatever manages it.
+	if (spec.Certificate != nil && !spec.Certificate.RequirePassword) ||
+		(spec.Password != nil && spec.Password.SecretRef != nil) {
+		status.RotatedAt, status.RotationTrigger = nil, ""
+		return existing, 0
+	}
+
+	var interval time.Duration
+	if spec.Password != nil && spec.Password.RotationInterval != nil {
+		interval = spec.Password.RotationInterval.Duration
+	}
+	grace := postgresUserGracePeriod(spec)
+
+	var trigger string
+	if existing != nil {
+		trigger = existing.Annotations[naming.PostgresPasswordRotation]
+	}
+
+	// A new password is about to be generated, so the rotation begins now.
+	// Remember the trigger so it does not rotate the password again.
+	if existing == nil || len(existing.Data["password"]) == 0 {
+		status.RotatedAt, status.RotationTrigger = &metav1.Time{Time: now}, trigger
+		return existing, interval
+	}
+
+	// Secrets that predate rotation were last rotated when they were created.
+	if status.RotatedAt == nil {
+		status.RotatedAt = existing.CreationTimestamp.DeepCopy()
+	}
+
+	rotated := status.RotatedAt.Time
+	requested := trigger != "" && trigger != status.RotationTrigger
+	expired := interval > 0 && !now.Before(rotated.Add(interval))
+
+	intent := existing.DeepCopy()
+	if requested || expired {
+		delete(intent.Data, "previous-user")
+		delete(intent.Data, "previous-password")
+		delete(intent.Data, "previous-verifier")
+
+		// Give the new password to the login role that does not have the
+		// current one.
+		if grace > 0 {
+			name := string(spec.Name)
+			current, next := name, postgresUserAlternate(name)
+			if string(intent.Data["user"]) == next {
+				current, next = next, name
+			}
+			intent.Data["previous-user"] = []byte(current)
+			intent.Data["previous-password"] = intent.Data["password"]
+			intent.Data["previous-verifier"] = intent.Data["verifier"]
+			intent.Data["user"] = []byte(next)
+		}
+		delete(intent.Data, "password")
+		delete(intent.Data, "verifier")
+
+		rotated = now
+		status.RotatedAt, status.RotationTrigger = &metav1.Time{Time: now}, trigger
+	}
+
+	// The previous password is kept only during the grace period.
+	var next time.Duration
+	if _, ok := intent.Data["previous-user"]; ok {
+		if remaining := rotated.Add(grace).Sub(now); remaining > 0 {
+			next = remaining
+		} else {
+			delete(intent.Data, "previous-user")
+			delete(intent.Data, "previous-password")
+			delete(intent.Data, "previous-verifier")
+		}
+	}
+	if remaining := rotated.Add(interval).Sub(now); interval > 0 &&
+		(next == 0 || remaining < next) {
+		next = remaining
+	}
+
+	return intent, next
+}
+
+// postgresUserLogins returns the verifiers of the roles through which users
+// log in, keyed by role name, and the alternate login roles of users. The
+// previous password of a user stops working when the grace period after its
+// latest rotation in status ends.
+func postgresUserLogins(
+	specUsers []v1beta1.PostgresUserSpec, userSecrets map[string]*corev1.Secret,
+	status []v1beta1.PostgresUserPasswordStatus,
+) (map[string]string, map[string]postgres.AlternateLogin) {
+	rotated := make(map[string]time.Time, len(status))
+	for _, s := range status {
+		if s.RotatedAt != nil {
+			rotated[s.Name] = s.RotatedAt.Time
+		}
+	}
+
+	verifiers := make(map[string]string, len(userSecrets))
+	alternates := make(map[string]postgres.AlternateLogin)
+	for i := range specUsers {
+		name := string(specUsers[i].Name)
+		secret := userSecrets[name]
+		if secret == nil {
+			continue
+		}
+
+		login := string(secret.Data["user"])
+		if login == "" {
+			login = name
+		}
+		previous := string(secret.Data["previous-user"])
+
+		verifiers[login] = string(secret.Data["verifier"])
+		if previous != "" && previous != login {
+			verifiers[previous] = string(secret.Data["previous-verifier"])
+		}
+
+		// Keep the alternate role while it may have a password.
+		grace := postgresUserGracePeriod(&specUsers[i])
+		alternate := postgresUserAlternate(name)
+		if grace > 0 || login == alternate || previous == alternate {
+			login := postgres.AlternateLogin{Name: alternate, Current: login}
+			if previous != "" && !rotated[name].IsZero() {
+				login.Expires = rotated[name].Add(grace)
+			}
+			alternates[name] = login
+		}
+	}
+	return verifiers, alternates
+}
+
 // reconcilePostgresUsers writes the objects necessary to manage users and their
-// passwords in PostgreSQL.
+// passwords in PostgreSQL. It requeues when a password is next due to rotate.
 func (r *Reconciler) reconcilePostgresUsers(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
-) error {
-	users, secrets, err := r.reconcilePostgresUserSecrets(ctx, cluster)
+	root *pki.RootCertificateAuthority,
+) (reconcile.Result, error) {
+	users, secrets, next, err := r.reconcilePostgresUserSecrets(ctx, cluster, root)
 	if err == nil {
 		err = r.reconcilePostgresUsersInPostgreSQL(ctx, cluster, instances, users, secrets)
 	}
@@ -308,7 +862,7 @@ func (r *Reconciler) reconcilePostgresUsers(
 		// are available here, too.
 		err = r.reconcilePGAdminUsers(ctx, cluster, users, secrets)
 	}
-	return err
+	return reconcile.Result{RequeueAfter: next}, err
 }
 
 // +kubebuilder:rbac:groups="",resources="secrets",verbs={list}
@@ -316,12 +870,14 @@ func (r *Reconciler) reconcilePostgresUsers(
 
 // reconcilePostgresUserSecrets writes Secrets for the PostgreSQL users
 // specified in cluster and deletes existing Secrets that are not specified.
-// It returns the user specifications it acted on (because defaults) and the
-// Secrets it wrote.
+// It returns the user specifications it acted on (because defaults), the
+// Secrets it wrote, and how long until a password is next due to rotate.
+// Users that request a certificate get one signed by root.
 func (r *Reconciler) reconcilePostgresUserSecrets(
 	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	root *pki.RootCertificateAuthority,
 ) (
-	[]v1beta1.PostgresUserSpec, map[string]*corev1.Secret, error,
+	[]v1beta1.PostgresUserSpec, map[string]*corev1.Secret, time.Duration, error,
 ) {
 	// When users are unspecified, create one user matching the cluster name if
 	// it is also a valid user name.
@@ -402,6 +958,16 @@ func (r *Reconciler) reconcilePostgresUserSecrets(
 		}
 	}
 
+	// Index the password status of each user.
+	passwordStatus := make(map[string]v1beta1.PostgresUserPasswordStatus)
+	for _, status := range cluster.Status.UserPasswords {
+		passwordStatus[status.Name] = status
+	}
+
+	var next time.Duration
+	now := time.Now()
+	passwords := make([]v1beta1.PostgresUserPasswordStatus, 0, len(userSpecs))
+
 	// Reconcile each PostgreSQL user in the cluster spec.
 	for userName, user := range userSpecs {
 		secret := userSecrets[userName]
@@ -412,15 +978,47 @@ func (r *Reconciler) reconcilePostgresUserSecrets(
 			secret = defaultSecret
 		}
 
+		status := passwordStatus[userName]
+		status.Name = userName
+
 		if err == nil {
-			userSecrets[userName], err = r.generatePostgresUserSecret(cluster, user, secret)
+			rotated, due := rotatePostgresUserPassword(user, secret, &status, now)
+			if user.Password != nil && user.Password.SecretRef != nil {
+				rotated, err = r.externalPostgresUserPassword(ctx, cluster, user, secret)
+			}
+			if err == nil {
+				userSecrets[userName], err = r.generatePostgresUserSecret(cluster, user, rotated)
+			}
+
+			if due > 0 && (next == 0 || due < next) {
+				next = due
+			}
+		}
+		if err == nil && user.Certificate != nil {
+			err = generatePostgresUserCertificate(root, user, secret, userSecrets[userName])
 		}
 		if err == nil {
 			err = errors.WithStack(r.apply(ctx, userSecrets[userName]))
 		}
+		if status.RotatedAt != nil {
+			passwords = append(passwords, status)
+		}
+	}
+
+	// Record password rotations only after every Secret has been written.
+	// Otherwise, a rotation that never made it to its Secret would not be
+	// attempted again until its next interval.
+	if err == nil {
+		sort.Slice(passwords, func(i, j int) bool {
+			return passwords[i].Name < passwords[j].Name
+		})
+		cluster.Status.UserPasswords = nil
+		if len(passwords) > 0 {
+			cluster.Status.UserPasswords = passwords
+		}
 	}
 
-	return specUsers, userSecrets, err
+	return specUsers, userSecrets, next, err
 }
 
 // reconcilePostgresUsersInPostgreSQL creates users inside of PostgreSQL and
@@ -461,13 +1059,12 @@ func (r *Reconciler) reconcilePostgresUsersInPostgreSQL(
 
 	// Calculate a hash of the SQL that should be executed in PostgreSQL.
 
-	verifiers := make(map[string]string, len(userSecrets))
-	for userName := range userSecrets {
-		verifiers[userName] = string(userSecrets[userName].Data["verifier"])
-	}
+	verifiers, alternates := postgresUserLogins(specUsers, userSecrets,
+		cluster.Status.UserPasswords)
 
 	write := func(ctx context.Context, exec postgres.Executor) error {
-		return postgres.WriteUsersInPostgreSQL(ctx, exec, specUsers, verifiers)
+		return postgres.WriteUsersInPostgreSQL(ctx, exec, specUsers, verifiers,
+			cluster.Spec.UserManagement, alternates)
 	}
 
 	revision, err := safeHash32(func(hasher io.Writer) error {
@@ -676,12 +1273,14 @@ func (r *Reconciler) reconcileDatabaseInitSQL(ctx context.Context,
 		// If database init sql is not requested, we will always expect the
 		// status to be nil
 		cluster.Status.DatabaseInitSQL = nil
+		cluster.Status.DatabaseInitSQLMigrations = nil
 		return nil
 	}
 
-	// Spec is defined but status is already set, return
-	if cluster.Status.DatabaseInitSQL != nil {
-		return nil
+	// Spec is defined but status is already set or there is no key, move on
+	// to any migrations
+	if cluster.Status.DatabaseInitSQL != nil || cluster.Spec.DatabaseInitSQL.Key == "" {
+		return r.reconcileDatabaseInitSQLMigrations(ctx, cluster, instances)
 	}
 
 	// Based on the previous checks, the user wants to run sql in the database.
@@ -738,7 +1337,16 @@ func (r *Reconciler) reconcileDatabaseInitSQL(ctx context.Context,
 	// A writable pod executor has been found and we have the sql provided by
 	// the user. Setup a write function to execute the sql using the podExecutor
 	write := func(ctx context.Context, exec postgres.Executor) error {
-		stdout, stderr, err := exec.Exec(ctx, strings.NewReader(data), map[string]string{})
+		var stdout, stderr string
+		var err error
+
+		if database := cluster.Spec.DatabaseInitSQL.Database; database != "" {
+			stdout, stderr, err = exec.ExecInDatabasesFromQuery(ctx,
+				`SELECT :'database'`, data,
+				map[string]string{"database": string(database)})
+		} else {
+			stdout, stderr, err = exec.Exec(ctx, strings.NewReader(data), map[string]string{})
+		}
 		log.V(1).Info("applied init SQL", "stdout", stdout, "stderr", stderr)
 		return err
 	}
@@ -760,6 +1368,134 @@ func (r *Reconciler) reconcileDatabaseInitSQL(ctx context.Context,
 		status := cluster.Spec.DatabaseInitSQL.Name
 		cluster.Status.DatabaseInitSQL = &status
 	}
+	if err == nil {
+		err = r.reconcileDatabaseInitSQLMigrations(ctx, cluster, instances)
+	}
 
 	return err
 }
+
+// reconcileDatabaseInitSQLMigrations runs the migrations of DatabaseInitSQL in
+// order and records them in cluster.Status. It stops at the first migration
+// that fails or whose SQL has changed since it ran, and reports either in the
+// DatabaseInitSQLMigrated condition.
+func (r *Reconciler) reconcileDatabaseInitSQLMigrations(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, instances *observedInstances) error {
+	log := logging.FromContext(ctx)
+	spec := cluster.Spec.DatabaseInitSQL
+
+	if len(spec.Migrations) == 0 {
+		cluster.Status.DatabaseInitSQLMigrations = nil
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, v1beta1.DatabaseInitSQLMigrated)
+		return nil
+	}
+
+	// Emit an event only when the condition changes so that a problem is not
+	// reported on every reconcile.
+	setMigrated := func(status metav1.ConditionStatus, reason, message string) {
+		r.setStatusCondition(cluster, metav1.Condition{
+			Type:               v1beta1.DatabaseInitSQLMigrated,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: cluster.Generation,
+		})
+	}
+
+	applied := make(map[string]v1beta1.DatabaseInitSQLMigrationStatus)
+	for _, status := range cluster.Status.DatabaseInitSQLMigrations {
+		applied[status.Version] = status
+	}
+
+	var podExecutor postgres.Executor
+	configmaps := make(map[string]*corev1.ConfigMap)
+
+	for _, migration := range spec.Migrations {
+		name := migration.ConfigMap
+		if name == "" {
+			name = spec.Name
+		}
+		database := string(migration.Database)
+		if database == "" {
+			database = string(spec.Database)
+		}
+		if database == "" {
+			database = "postgres"
+		}
+
+		// Read each ConfigMap once.
+		cm, ok := configmaps[name]
+		if !ok {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: cluster.Namespace,
+			}}
+			if err := errors.WithStack(
+				r.Client.Get(ctx, client.ObjectKeyFromObject(cm), cm),
+			); err != nil {
+				log.Error(err, "Could not get data from ConfigMap",
+					"ConfigMap", name, "Key", migration.Key)
+				return err
+			}
+			configmaps[name] = cm
+		}
+
+		data, ok := cm.Data[migration.Key]
+		if !ok {
+			err := errors.Errorf("ConfigMap did not contain expected key: %s", migration.Key)
+			log.Error(err, "Could not get data from ConfigMap",
+				"ConfigMap", name, "Key", migration.Key)
+			return err
+		}
+		checksum := postgres.MigrationChecksum(data)
+
+		// Migrations that have run cannot change. Nothing else can run until
+		// the change is reverted, but the rest of the cluster is unaffected.
+		if status, ok := applied[migration.Version]; ok {
+			if status.Checksum != checksum || status.Database != database {
+				setMigrated(metav1.ConditionFalse, "DatabaseInitSQLChanged", fmt.Sprintf(
+					"Migration %q has changed since it ran in database %q; no more migrations will run",
+					migration.Version, status.Database))
+				return nil
+			}
+			continue
+		}
+
+		// Find a writable pod once there is a migration to run.
+		if podExecutor == nil {
+			pod, _ := instances.writablePod(naming.ContainerDatabase)
+			if pod == nil {
+				log.V(1).Info("Could not find a pod with a writable database container.")
+				return nil
+			}
+			podExecutor = func(
+				_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+			) error {
+				return r.PodExec(pod.Namespace, pod.Name, naming.ContainerDatabase, stdin, stdout, stderr, command...)
+			}
+		}
+
+		log := log.WithValues("version", migration.Version, "database", database)
+		err := errors.WithStack(postgres.ApplyMigrationInPostgreSQL(
+			logging.NewContext(ctx, log), podExecutor,
+			database, migration.Version, checksum, data))
+
+		if err != nil {
+			setMigrated(metav1.ConditionFalse, "DatabaseInitSQLFailed", fmt.Sprintf(
+				"Migration %q failed in database %q: %v", migration.Version, database, err))
+			return err
+		}
+
+		cluster.Status.DatabaseInitSQLMigrations = append(
+			cluster.Status.DatabaseInitSQLMigrations,
+			v1beta1.DatabaseInitSQLMigrationStatus{
+				Version:   migration.Version,
+				Database:  database,
+				Checksum:  checksum,
+				AppliedAt: &metav1.Time{Time: time.Now()},
+			})
+	}
+
+	setMigrated(metav1.ConditionTrue, "DatabaseInitSQLMigrated",
+		fmt.Sprintf("%d migrations have run", len(spec.Migrations)))
+	return nil
+}
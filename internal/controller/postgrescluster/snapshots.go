@@ -0,0 +1,772 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crunchydata/postgres-operator/internal/config"
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/internal/pgbackrest"
+	"github.com/crunchydata/postgres-operator/internal/pki"
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// defaultVolumeSnapshotRetention is the number of successful snapshots
+	// kept when the spec does not say.
+	defaultVolumeSnapshotRetention = 3
+
+	// volumeSnapshotTimeout is how long PostgreSQL stays in backup mode while
+	// waiting for VolumeSnapshots to be taken.
+	volumeSnapshotTimeout = 30 * time.Minute
+
+	// volumeSnapshotPollInterval is how often a snapshot in progress is checked.
+	// VolumeSnapshots are not watched because their API may not be installed.
+	volumeSnapshotPollInterval = 10 * time.Second
+
+	// ConditionVolumeSnapshotsAvailable is the type used in a condition to
+	// indicate whether or not volume snapshots can be taken
+	ConditionVolumeSnapshotsAvailable = "VolumeSnapshotsAvailable"
+)
+
+// The steps of a volume snapshot as reported in its status.
+const (
+	volumeSnapshotStarting     = "Starting"
+	volumeSnapshotSnapshotting = "Snapshotting"
+	volumeSnapshotStopping     = "Stopping"
+	volumeSnapshotSucceeded    = "Succeeded"
+	volumeSnapshotFailed       = "Failed"
+)
+
+// volumeSnapshotGVK is the kind of the CSI snapshot API.
+// - https://github.com/kubernetes-csi/external-snapshotter
+var volumeSnapshotGVK = schema.GroupVersionKind{
+	Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot",
+}
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=list
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=list
+
+// reconcileVolumeSnapshots takes VolumeSnapshots of the volumes of an instance
+// whenever the volume snapshot CronJob of cluster creates a Job. PostgreSQL is
+// kept in backup mode while the snapshots are taken, and the "backup_label" it
+// reports afterward is stored on the VolumeSnapshot of the data volume. Each
+// step happens in a separate reconcile.
+func (r *Reconciler) reconcileVolumeSnapshots(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	instances *observedInstances, clusterVolumes []corev1.PersistentVolumeClaim,
+) (reconcile.Result, error) {
+	spec := cluster.Spec.Backups.VolumeSnapshots
+
+	err := r.reconcileVolumeSnapshotCronJob(ctx, cluster)
+	if err != nil || spec == nil {
+		if spec == nil {
+			cluster.Status.VolumeSnapshots = nil
+			meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionVolumeSnapshotsAvailable)
+		}
+		return reconcile.Result{}, err
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := r.Client.List(ctx, jobs,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels(naming.VolumeSnapshotJobLabels(cluster.Name)),
+	); err != nil {
+		return reconcile.Result{}, errors.WithStack(err)
+	}
+
+	snapshots := &unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(volumeSnapshotGVK.GroupVersion().WithKind("VolumeSnapshotList"))
+	if err := r.Client.List(ctx, snapshots,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{naming.LabelCluster: cluster.Name},
+		client.HasLabels{naming.LabelVolumeSnapshot},
+	); err != nil {
+		if meta.IsNoMatchError(err) {
+			r.setStatusCondition(cluster, metav1.Condition{
+				Type:    ConditionVolumeSnapshotsAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  "VolumeSnapshotsUnavailable",
+				Message: "The snapshot.storage.k8s.io/v1 API is not installed",
+
+				ObservedGeneration: cluster.GetGeneration(),
+			})
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, errors.WithStack(err)
+	}
+	meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionVolumeSnapshotsAvailable)
+
+	if cluster.Status.VolumeSnapshots == nil {
+		cluster.Status.VolumeSnapshots = &v1beta1.VolumeSnapshotsStatus{}
+	}
+	status := cluster.Status.VolumeSnapshots
+
+	// Start a snapshot when a Job appears after the latest one started.
+	if status.Latest == nil || status.Latest.CompletionTime != nil {
+		if job := nextVolumeSnapshotJob(jobs.Items, status.Latest); job != nil {
+			err = r.startVolumeSnapshot(ctx, cluster, instances, job.Name)
+		}
+	}
+
+	if err == nil && status.Latest != nil && status.Latest.CompletionTime == nil {
+		err = r.progressVolumeSnapshot(ctx, cluster, instances, clusterVolumes, snapshots.Items)
+	}
+
+	// The latest snapshot is never deleted here. The list above may not reflect
+	// what happened to it during this reconcile.
+	retention := defaultVolumeSnapshotRetention
+	if spec.Retention != nil {
+		retention = int(*spec.Retention)
+	}
+	var latest string
+	if status.Latest != nil {
+		latest = status.Latest.Name
+	}
+	for _, snapshot := range volumeSnapshotsToDelete(snapshots.Items, retention, latest) {
+		if err == nil {
+			err = errors.WithStack(client.IgnoreNotFound(r.Client.Delete(ctx, snapshot)))
+		}
+	}
+
+	var result reconcile.Result
+	if status.Latest != nil && status.Latest.CompletionTime == nil {
+		result.RequeueAfter = volumeSnapshotPollInterval
+	}
+	return result, err
+}
+
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=create;patch;delete
+
+// reconcileVolumeSnapshotCronJob writes the CronJob that triggers volume
+// snapshots on a schedule. Its Jobs do nothing but signal the operator.
+func (r *Reconciler) reconcileVolumeSnapshotCronJob(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+) error {
+	cronJob := &batchv1.CronJob{ObjectMeta: naming.VolumeSnapshotCronJob(cluster)}
+	cronJob.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("CronJob"))
+
+	spec := cluster.Spec.Backups.VolumeSnapshots
+	if spec == nil || spec.Schedule == nil {
+		// Delete the CronJob, if it exists. Check the client cache first using Get.
+		err := errors.WithStack(r.Client.Get(ctx, client.ObjectKeyFromObject(cronJob), cronJob))
+		if err == nil {
+			err = errors.WithStack(r.deleteControlled(ctx, cluster, cronJob))
+		}
+		return client.IgnoreNotFound(err)
+	}
+
+	labels := naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		naming.VolumeSnapshotJobLabels(cluster.Name))
+
+	cronJob.Annotations = naming.Merge(cluster.Spec.Metadata.GetAnnotationsOrNil())
+	cronJob.Labels = labels
+
+	cronJob.Spec = batchv1.CronJobSpec{
+		Schedule:          *spec.Schedule,
+		ConcurrencyPolicy: batchv1.ForbidConcurrent,
+		JobTemplate: batchv1.JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:            naming.ContainerVolumeSnapshotTrigger,
+							Command:         []string{"true"},
+							Image:           config.PostgresContainerImage(cluster),
+							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							SecurityContext: initialize.RestrictedSecurityContext(),
+						}},
+						ImagePullSecrets: cluster.Spec.ImagePullSecrets,
+						RestartPolicy:    corev1.RestartPolicyNever,
+						SecurityContext:  postgres.PodSecurityContext(cluster),
+
+						// These Jobs don't make Kubernetes API calls, so we can just
+						// use the default ServiceAccount and not mount its credentials.
+						AutomountServiceAccountToken: initialize.Bool(false),
+						EnableServiceLinks:           initialize.Bool(false),
+					},
+				},
+			},
+		},
+	}
+
+	err := errors.WithStack(r.setControllerReference(cluster, cronJob))
+	if err == nil {
+		err = errors.WithStack(r.apply(ctx, cronJob))
+	}
+	return err
+}
+
+// nextVolumeSnapshotJob returns the newest Job in jobs that was created after
+// latest started, or nil when there is none.
+func nextVolumeSnapshotJob(jobs []batchv1.Job, latest *v1beta1.VolumeSnapshotSetStatus) *batchv1.Job {
+	var next *batchv1.Job
+	for i := range jobs {
+		job := &jobs[i]
+		if latest != nil && (job.Name == latest.Name ||
+			!latest.StartTime.Before(&job.CreationTimestamp)) {
+			continue
+		}
+		if next == nil || next.CreationTimestamp.Before(&job.CreationTimestamp) {
+			next = job
+		}
+	}
+	return next
+}
+
+// volumeSnapshotInstance returns the instance to snapshot. It prefers a ready
+// replica and falls back to the primary. It returns nil when neither is running.
+func volumeSnapshotInstance(instances *observedInstances) *Instance {
+	var primary *Instance
+	for _, instance := range instances.forCluster {
+		if terminating, known := instance.IsTerminating(); terminating || !known {
+			continue
+		}
+		if running, known := instance.IsRunning(naming.ContainerDatabase); !running || !known {
+			continue
+		}
+		if ready, known := instance.IsReady(); !ready || !known {
+			continue
+		}
+		if writable, known := instance.IsWritable(); known && writable {
+			primary = instance
+		} else if known {
+			return instance
+		}
+	}
+	return primary
+}
+
+// volumeSnapshotExecutor returns a postgres.Executor for the database container of pod.
+func (r *Reconciler) volumeSnapshotExecutor(pod *corev1.Pod) postgres.Executor {
+	return func(
+		_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+	) error {
+		return r.PodExec(pod.Namespace, pod.Name, naming.ContainerDatabase,
+			stdin, stdout, stderr, command...)
+	}
+}
+
+// startVolumeSnapshot puts an instance of cluster into backup mode and records
+// the snapshot named name as the latest in its status. Any earlier session on
+// that instance, e.g. one whose status was lost when the operator restarted,
+// is told to leave backup mode.
+func (r *Reconciler) startVolumeSnapshot(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	instances *observedInstances, name string,
+) error {
+	instance := volumeSnapshotInstance(instances)
+	if instance == nil {
+		// Wait for an instance. Jobs are reconciled when they change, and
+		// a snapshot starts when an instance becomes ready.
+		return nil
+	}
+
+	pod := instance.Pods[0]
+	ctx = logging.NewContext(ctx, logging.FromContext(ctx).WithValues("pod", pod.Name))
+
+	now := metav1.Now()
+	status := cluster.Status.VolumeSnapshots
+	status.Latest = &v1beta1.VolumeSnapshotSetStatus{
+		Name:      name,
+		Pod:       pod.Name,
+		Phase:     volumeSnapshotStarting,
+		StartTime: &now,
+	}
+
+	err := r.volumeSnapshotExecutor(pod).StartBackupMode(ctx,
+		cluster.Spec.PostgresVersion, name, volumeSnapshotTimeout)
+	if err != nil {
+		logging.FromContext(ctx).Error(err, "unable to start backup mode")
+		r.failVolumeSnapshot(ctx, cluster, nil, "Unable to start backup mode")
+	}
+	return nil
+}
+
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=create;patch
+
+// progressVolumeSnapshot takes the next step of the latest snapshot of cluster.
+// Snapshots is every VolumeSnapshot of cluster.
+func (r *Reconciler) progressVolumeSnapshot(
+	ctx context.Context, cluster *v1beta1.PostgresCluster, instances *observedInstances,
+	clusterVolumes []corev1.PersistentVolumeClaim, snapshots []unstructured.Unstructured,
+) error {
+	latest := cluster.Status.VolumeSnapshots.Latest
+	ctx = logging.NewContext(ctx, logging.FromContext(ctx).WithValues("pod", latest.Pod))
+
+	var instance *Instance
+	for _, observed := range instances.forCluster {
+		if len(observed.Pods) == 1 && observed.Pods[0].Name == latest.Pod {
+			instance = observed
+		}
+	}
+	if instance == nil || instance.Pods[0].CreationTimestamp.After(latest.StartTime.Time) {
+		r.failVolumeSnapshot(ctx, cluster, snapshots,
+			fmt.Sprintf("Pod %q stopped during the snapshot", latest.Pod))
+		return nil
+	}
+	if time.Since(latest.StartTime.Time) > volumeSnapshotTimeout {
+		r.failVolumeSnapshot(ctx, cluster, snapshots, "The snapshot did not finish in time")
+		return nil
+	}
+
+	exec := r.volumeSnapshotExecutor(instance.Pods[0])
+	state, label, err := exec.BackupMode(ctx, latest.Name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if state == postgres.BackupModeFailed {
+		r.failVolumeSnapshot(ctx, cluster, snapshots, "PostgreSQL was unable to enter backup mode")
+		return nil
+	}
+	if state == "" {
+		// The session is gone, e.g. because the container restarted. Its
+		// snapshots cannot be used.
+		r.failVolumeSnapshot(ctx, cluster, snapshots,
+			fmt.Sprintf("The backup mode session on pod %q is gone", latest.Pod))
+		return nil
+	}
+
+	var taken []unstructured.Unstructured
+	for i := range snapshots {
+		if snapshots[i].GetLabels()[naming.LabelVolumeSnapshot] == latest.Name {
+			taken = append(taken, snapshots[i])
+		}
+	}
+
+	switch {
+	case latest.Phase == volumeSnapshotStarting && state == postgres.BackupModeStarted:
+		// PostgreSQL is in backup mode. Snapshot every volume of the instance.
+		for i := range clusterVolumes {
+			pvc := &clusterVolumes[i]
+			role := pvc.Labels[naming.LabelRole]
+			if pvc.Labels[naming.LabelInstance] != instance.Name ||
+				(role != naming.RolePostgresData && role != naming.RolePostgresWAL) {
+				continue
+			}
+
+			snapshot := &unstructured.Unstructured{}
+			snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+			snapshot.SetNamespace(cluster.Namespace)
+			snapshot.SetName(latest.Name + "-" + role)
+			snapshot.SetAnnotations(naming.Merge(cluster.Spec.Metadata.GetAnnotationsOrNil()))
+			snapshot.SetLabels(naming.Merge(
+				cluster.Spec.Metadata.GetLabelsOrNil(),
+				naming.VolumeSnapshotLabels(cluster.Name, latest.Name, role)))
+
+			// VolumeSnapshots are backups, so they are not owned by the cluster
+			// and remain when it is deleted.
+			_ = unstructured.SetNestedField(snapshot.Object,
+				cluster.Spec.Backups.VolumeSnapshots.VolumeSnapshotClassName,
+				"spec", "volumeSnapshotClassName")
+			_ = unstructured.SetNestedField(snapshot.Object,
+				pvc.Name, "spec", "source", "persistentVolumeClaimName")
+
+			if err := r.Client.Create(ctx, snapshot); err != nil && !apierrors.IsAlreadyExists(err) {
+				return errors.WithStack(err)
+			}
+		}
+		latest.Phase = volumeSnapshotSnapshotting
+
+	case latest.Phase == volumeSnapshotSnapshotting:
+		// Wait until every volume has been captured. Uploading the snapshot
+		// may continue after this.
+		if len(taken) == 0 {
+			return nil
+		}
+		for i := range taken {
+			created, message := volumeSnapshotCreated(&taken[i])
+			if message != "" {
+				r.failVolumeSnapshot(ctx, cluster, snapshots, message)
+				return nil
+			}
+			if !created {
+				return nil
+			}
+		}
+		if err := exec.FinishBackupMode(ctx, latest.Name); err != nil {
+			return errors.WithStack(err)
+		}
+		latest.Phase = volumeSnapshotStopping
+
+	case latest.Phase == volumeSnapshotStopping && state == postgres.BackupModeStopped:
+		// Store the backup label on the snapshot of the data volume. It is
+		// needed to recover from the snapshot.
+		for i := range taken {
+			snapshot := &taken[i]
+			if snapshot.GetLabels()[naming.LabelRole] != naming.RolePostgresData {
+				continue
+			}
+
+			before := snapshot.DeepCopy()
+			snapshot.SetAnnotations(naming.Merge(snapshot.GetAnnotations(),
+				map[string]string{naming.VolumeSnapshotBackupLabel: label}))
+
+			if err := errors.WithStack(
+				r.patch(ctx, snapshot, client.MergeFrom(before))); err != nil {
+				return err
+			}
+			cluster.Status.VolumeSnapshots.LastSucceeded = snapshot.GetName()
+		}
+
+		now := metav1.Now()
+		latest.Phase = volumeSnapshotSucceeded
+		latest.CompletionTime = &now
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "VolumeSnapshotSucceeded",
+			"Volume snapshot %q succeeded", latest.Name)
+	}
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=delete
+
+// failVolumeSnapshot marks the latest snapshot of cluster as failed and deletes
+// any VolumeSnapshots taken for it. PostgreSQL leaves backup mode on its own
+// when the instance stopped or the timeout passed, but it is also told to
+// leave now.
+func (r *Reconciler) failVolumeSnapshot(
+	ctx context.Context, cluster *v1beta1.PostgresCluster,
+	snapshots []unstructured.Unstructured, message string,
+) {
+	log := logging.FromContext(ctx)
+	latest := cluster.Status.VolumeSnapshots.Latest
+
+	now := metav1.Now()
+	latest.Phase = volumeSnapshotFailed
+	latest.CompletionTime = &now
+	r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "VolumeSnapshotFailed",
+		"Volume snapshot %q failed: %s", latest.Name, message)
+
+	for i := range snapshots {
+		if snapshots[i].GetLabels()[naming.LabelVolumeSnapshot] == latest.Name {
+			if err := client.IgnoreNotFound(r.Client.Delete(ctx, &snapshots[i])); err != nil {
+				log.Error(err, "unable to delete failed VolumeSnapshot")
+			}
+		}
+	}
+
+	pod := &corev1.Pod{}
+	pod.Namespace, pod.Name = cluster.Namespace, latest.Pod
+	if err := r.volumeSnapshotExecutor(pod).FinishBackupMode(ctx, latest.Name); err != nil {
+		log.V(1).Info("unable to leave backup mode", "error", err.Error())
+	}
+}
+
+// volumeSnapshotCreated returns whether or not the storage system has captured
+// snapshot. It also returns any error message reported for snapshot.
+// - https://github.com/kubernetes-csi/external-snapshotter/blob/master/client/apis/volumesnapshot/v1/types.go
+func volumeSnapshotCreated(snapshot *unstructured.Unstructured) (bool, string) {
+	message, _, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	created, _, _ := unstructured.NestedString(snapshot.Object, "status", "creationTime")
+	return created != "", message
+}
+
+// volumeSnapshotsToDelete returns the VolumeSnapshots that are no longer needed.
+// Those are the snapshots of everything but the newest keep successful ones and
+// the one named latest.
+func volumeSnapshotsToDelete(
+	snapshots []unstructured.Unstructured, keep int, latest string,
+) []*unstructured.Unstructured {
+	type set struct {
+		created   time.Time
+		succeeded bool
+		snapshots []*unstructured.Unstructured
+	}
+
+	sets := make(map[string]*set)
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		name := snapshot.GetLabels()[naming.LabelVolumeSnapshot]
+		if name == "" || name == latest {
+			continue
+		}
+		if sets[name] == nil {
+			sets[name] = &set{created: snapshot.GetCreationTimestamp().Time}
+		}
+		if _, ok := snapshot.GetAnnotations()[naming.VolumeSnapshotBackupLabel]; ok {
+			sets[name].succeeded = true
+		}
+		sets[name].snapshots = append(sets[name].snapshots, snapshot)
+	}
+
+	ordered := make([]*set, 0, len(sets))
+	for _, s := range sets {
+		ordered = append(ordered, s)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].created.After(ordered[j].created)
+	})
+
+	var result []*unstructured.Unstructured
+	for _, s := range ordered {
+		if s.succeeded && keep > 0 {
+			keep--
+			continue
+		}
+		result = append(result, s.snapshots...)
+	}
+	return result
+}
+
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=create
+
+// reconcileVolumeSnapshotDataSource populates the data directory of a new cluster
+// from a VolumeSnapshot taken by reconcileVolumeSnapshots. The data volume is
+// provisioned from the snapshot, and a restore Job recovers it using WAL from the
+// pgBackRest repository of the cluster that took the snapshot.
+func (r *Reconciler) reconcileVolumeSnapshotDataSource(ctx context.Context,
+	cluster *v1beta1.PostgresCluster, dataSource *v1beta1.DataSourceVolumeSnapshot,
+	configHash string, clusterVolumes []corev1.PersistentVolumeClaim,
+	rootCA *pki.RootCertificateAuthority) error {
+
+	// Ensure the proper instance and instance set can be identified via the status.  The
+	// StartupInstance and StartupInstanceSet values should be populated when the cluster
+	// is being prepared for a restore, and should therefore always exist at this point.
+	// Therefore, if either are not found it is treated as an error.
+	instanceName := cluster.Status.StartupInstance
+	if instanceName == "" {
+		return errors.WithStack(
+			errors.New("unable to find instance name for volume snapshot restore Job"))
+	}
+	instanceSetName := cluster.Status.StartupInstanceSet
+	if instanceSetName == "" {
+		return errors.WithStack(
+			errors.New("unable to find instance set name for volume snapshot restore Job"))
+	}
+
+	var instanceSet *v1beta1.PostgresInstanceSetSpec
+	for i, set := range cluster.Spec.InstanceSets {
+		if set.Name == instanceSetName {
+			instanceSet = &cluster.Spec.InstanceSets[i]
+			break
+		}
+	}
+	if instanceSet == nil {
+		return errors.WithStack(
+			errors.New("unable to determine the proper instance set for the restore"))
+	}
+
+	// If the cluster is already bootstrapped, then nothing to do. However, also
+	// ensure the "data sources initialized" condition is set.
+	if patroni.ClusterBootstrapped(cluster) {
+		condition := meta.FindStatusCondition(cluster.Status.Conditions,
+			ConditionPostgresDataInitialized)
+		if condition == nil || (condition.Status != metav1.ConditionTrue) {
+			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+				ObservedGeneration: cluster.GetGeneration(),
+				Type:               ConditionPostgresDataInitialized,
+				Status:             metav1.ConditionTrue,
+				Reason:             "ClusterAlreadyBootstrapped",
+				Message:            "The cluster is already bootstrapped",
+			})
+		}
+		return nil
+	}
+
+	if dataSource.Target != nil && dataSource.Target.BackupLabel != "" {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "InvalidDataSource",
+			"A backupLabel target cannot be used with a VolumeSnapshot")
+		return nil
+	}
+
+	// The snapshot must be of a data volume and taken while PostgreSQL was in
+	// backup mode. Only then does it have a backup label.
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: cluster.Namespace, Name: dataSource.Name,
+	}, snapshot)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidDataSource",
+			"VolumeSnapshot %q does not exist", dataSource.Name)
+		return nil
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	label, ok := snapshot.GetAnnotations()[naming.VolumeSnapshotBackupLabel]
+	if !ok || snapshot.GetLabels()[naming.LabelRole] != naming.RolePostgresData {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidDataSource",
+			"VolumeSnapshot %q is not a completed snapshot of a PostgreSQL data volume",
+			dataSource.Name)
+		return nil
+	}
+
+	// WAL is fetched from the pgBackRest repository of the cluster that took
+	// the snapshot. That is either this cluster or another in its namespace.
+	sourceClusterName := snapshot.GetLabels()[naming.LabelCluster]
+	sourceCluster := &v1beta1.PostgresCluster{}
+	if sourceClusterName == cluster.GetName() {
+		sourceCluster = cluster.DeepCopy()
+		instance := &Instance{Name: instanceName}
+		result, err := r.reconcilePGBackRest(ctx, cluster, &observedInstances{
+			forCluster: []*Instance{instance},
+		}, rootCA)
+		if err != nil || result != (reconcile.Result{}) {
+			return fmt.Errorf("unable to reconcile pgBackRest as needed to initialize "+
+				"PostgreSQL data for the cluster: %w", err)
+		}
+	} else {
+		if err := r.Client.Get(ctx, client.ObjectKey{
+			Namespace: cluster.GetNamespace(), Name: sourceClusterName,
+		}, sourceCluster); err != nil {
+			if apierrors.IsNotFound(err) {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidDataSource",
+					"PostgresCluster %q of VolumeSnapshot %q does not exist",
+					sourceClusterName, dataSource.Name)
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+		if err := r.copyRestoreConfiguration(ctx, cluster, sourceCluster); err != nil {
+			return err
+		}
+	}
+
+	var foundRepo bool
+	for _, repo := range sourceCluster.Spec.Backups.PGBackRest.Repos {
+		foundRepo = foundRepo || repo.Name == dataSource.RepoName
+	}
+	if !foundRepo {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "InvalidDataSource",
+			"PostgresCluster %q does not have a repo named %q defined",
+			sourceClusterName, dataSource.RepoName)
+		return nil
+	}
+
+	// Define a fake STS to use when calling the reconcile functions below since when
+	// bootstrapping the cluster it will not exist until after the restore is complete.
+	fakeSTS := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name:      instanceName,
+		Namespace: cluster.GetNamespace(),
+	}}
+
+	// The data source of a PersistentVolumeClaim cannot change, so create the
+	// data volume before it is applied. Applying it afterward does not touch
+	// fields that were set during create.
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: naming.InstancePostgresDataVolume(fakeSTS)}
+	pvc.Labels = naming.Merge(
+		cluster.Spec.Metadata.GetLabelsOrNil(),
+		instanceSet.Metadata.GetLabelsOrNil(),
+		map[string]string{
+			naming.LabelCluster:     cluster.Name,
+			naming.LabelInstanceSet: instanceSet.Name,
+			naming.LabelInstance:    fakeSTS.Name,
+			naming.LabelRole:        naming.RolePostgresData,
+			naming.LabelData:        naming.DataPostgres,
+		})
+	instanceSet.DataVolumeClaimSpec.DeepCopyInto(&pvc.Spec)
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: &volumeSnapshotGVK.Group,
+		Kind:     volumeSnapshotGVK.Kind,
+		Name:     dataSource.Name,
+	}
+
+	err = errors.WithStack(r.setControllerReference(cluster, pvc))
+	if err == nil {
+		err = r.Client.Create(ctx, pvc)
+		if apierrors.IsAlreadyExists(err) {
+			err = nil
+		}
+		err = r.handlePersistentVolumeClaimError(cluster, errors.WithStack(err))
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reconcile the PGDATA and WAL volumes for the restore
+	pgdata, err := r.reconcilePostgresDataVolume(ctx, cluster, instanceSet, fakeSTS, clusterVolumes)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	pgwal, err := r.reconcilePostgresWALVolume(ctx, cluster, instanceSet, fakeSTS, nil, clusterVolumes)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	cmd := pgbackrest.SnapshotRestoreCommand(postgres.DataDirectory(cluster), label,
+		volumeSnapshotRecovery(cluster, dataSource))
+
+	// The restore Job takes its resources and scheduling from the data source.
+	// None are configured for VolumeSnapshots.
+	return errors.WithStack(r.applyRestoreJob(ctx, cluster, sourceCluster, pgdata, pgwal,
+		&v1beta1.PostgresClusterDataSource{RepoName: dataSource.RepoName},
+		instanceName, configHash, cmd))
+}
+
+// volumeSnapshotRecovery returns the PostgreSQL settings that recover a data
+// directory restored from a VolumeSnapshot. WAL is fetched from the pgBackRest
+// repository of dataSource, and recovery stops at its target, if any.
+// - https://www.postgresql.org/docs/current/runtime-config-wal.html#RUNTIME-CONFIG-WAL-RECOVERY-TARGET
+func volumeSnapshotRecovery(
+	cluster *v1beta1.PostgresCluster, dataSource *v1beta1.DataSourceVolumeSnapshot,
+) string {
+	restore := fmt.Sprintf(`pgbackrest --stanza=%s --pg1-path=%s --repo=%s archive-get %%f "%%p"`,
+		pgbackrest.DefaultStanzaName, postgres.DataDirectory(cluster),
+		regexRepoIndex.FindString(dataSource.RepoName))
+
+	// Values in PostgreSQL configuration files are quoted like SQL literals.
+	// - https://www.postgresql.org/docs/current/config-setting.html
+	quote := func(s string) string { return `'` + strings.ReplaceAll(s, `'`, `''`) + `'` }
+
+	settings := []string{"restore_command = " + quote(restore)}
+
+	if target := dataSource.Target; target != nil {
+		switch {
+		case target.Time != nil:
+			settings = append(settings, "recovery_target_time = "+
+				quote(target.Time.UTC().Format("2006-01-02 15:04:05-07")))
+		case target.LSN != "":
+			settings = append(settings, "recovery_target_lsn = "+quote(target.LSN))
+		case target.XID != "":
+			settings = append(settings, "recovery_target_xid = "+quote(target.XID))
+		}
+		if len(settings) > 1 {
+			settings = append(settings, "recovery_target_action = 'promote'")
+		}
+	}
+
+	return strings.Join(settings, "\n")
+}
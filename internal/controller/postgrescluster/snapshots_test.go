@@ -0,0 +1,230 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgrescluster
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crunchydata/postgres-operator/internal/naming"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestNextVolumeSnapshotJob(t *testing.T) {
+	start := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	job := func(name string, age time.Duration) batchv1.Job {
+		var job batchv1.Job
+		job.Name = name
+		job.CreationTimestamp = metav1.NewTime(start.Add(age))
+		return job
+	}
+
+	assert.Assert(t, nextVolumeSnapshotJob(nil, nil) == nil)
+
+	jobs := []batchv1.Job{job("a", 0), job("c", 2*time.Minute), job("b", time.Minute)}
+	assert.Equal(t, nextVolumeSnapshotJob(jobs, nil).Name, "c")
+
+	t.Run("AfterLatest", func(t *testing.T) {
+		latest := &v1beta1.VolumeSnapshotSetStatus{
+			Name: "b", StartTime: &metav1.Time{Time: start.Add(time.Minute)},
+		}
+		assert.Equal(t, nextVolumeSnapshotJob(jobs, latest).Name, "c")
+
+		latest.Name = "c"
+		latest.StartTime = &metav1.Time{Time: start.Add(2 * time.Minute)}
+		assert.Assert(t, nextVolumeSnapshotJob(jobs, latest) == nil)
+	})
+}
+
+func TestVolumeSnapshotCreated(t *testing.T) {
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	created, message := volumeSnapshotCreated(snapshot)
+	assert.Assert(t, !created)
+	assert.Equal(t, message, "")
+
+	assert.NilError(t, unstructured.SetNestedField(snapshot.Object,
+		"2022-06-01T12:00:00Z", "status", "creationTime"))
+	created, _ = volumeSnapshotCreated(snapshot)
+	assert.Assert(t, created)
+
+	assert.NilError(t, unstructured.SetNestedField(snapshot.Object,
+		"storage is full", "status", "error", "message"))
+	_, message = volumeSnapshotCreated(snapshot)
+	assert.Equal(t, message, "storage is full")
+}
+
+func TestVolumeSnapshotsToDelete(t *testing.T) {
+	start := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := func(set, role string, age time.Duration, succeeded bool) unstructured.Unstructured {
+		var u unstructured.Unstructured
+		u.SetName(set + "-" + role)
+		u.SetCreationTimestamp(metav1.NewTime(start.Add(age)))
+		u.SetLabels(map[string]string{naming.LabelVolumeSnapshot: set})
+		if succeeded {
+			u.SetAnnotations(map[string]string{naming.VolumeSnapshotBackupLabel: "label"})
+		}
+		return u
+	}
+	names := func(snapshots []*unstructured.Unstructured) []string {
+		var result []string
+		for _, s := range snapshots {
+			result = append(result, s.GetName())
+		}
+		return result
+	}
+
+	snapshots := []unstructured.Unstructured{
+		snapshot("one", "pgdata", 0, true),
+		snapshot("one", "pgwal", 0, false),
+		snapshot("two", "pgdata", time.Hour, false),
+		snapshot("three", "pgdata", 2*time.Hour, true),
+		snapshot("four", "pgdata", 3*time.Hour, true),
+		snapshot("five", "pgdata", 4*time.Hour, false),
+	}
+
+	assert.DeepEqual(t, names(volumeSnapshotsToDelete(snapshots, 2, "five")),
+		[]string{"two-pgdata", "one-pgdata", "one-pgwal"})
+	assert.DeepEqual(t, names(volumeSnapshotsToDelete(snapshots, 3, "")),
+		[]string{"five-pgdata", "two-pgdata"})
+	assert.Assert(t, volumeSnapshotsToDelete(nil, 1, "") == nil)
+}
+
+func TestVolumeSnapshotRecovery(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Spec.PostgresVersion = 14
+
+	dataSource := &v1beta1.DataSourceVolumeSnapshot{Name: "some", RepoName: "repo2"}
+	assert.Equal(t, volumeSnapshotRecovery(cluster, dataSource),
+		`restore_command = 'pgbackrest --stanza=db --pg1-path=/pgdata/pg14 --repo=2 archive-get %f "%p"'`)
+
+	dataSource.Target = &v1beta1.PGBackRestRestoreTarget{XID: "it's"}
+	assert.Equal(t, volumeSnapshotRecovery(cluster, dataSource), ``+
+		`restore_command = 'pgbackrest --stanza=db --pg1-path=/pgdata/pg14 --repo=2 archive-get %f "%p"'`+"\n"+
+		`recovery_target_xid = 'it''s'`+"\n"+
+		`recovery_target_action = 'promote'`)
+
+	when := metav1.NewTime(time.Date(2022, 6, 1, 12, 30, 5, 0, time.FixedZone("", -4*3600)))
+	dataSource.Target = &v1beta1.PGBackRestRestoreTarget{Time: &when}
+	assert.Equal(t, volumeSnapshotRecovery(cluster, dataSource), ``+
+		`restore_command = 'pgbackrest --stanza=db --pg1-path=/pgdata/pg14 --repo=2 archive-get %f "%p"'`+"\n"+
+		`recovery_target_time = '2022-06-01 16:30:05+00'`+"\n"+
+		`recovery_target_action = 'promote'`)
+}
+
+// noVolumeSnapshotsClient behaves as though the VolumeSnapshot API is not installed.
+type noVolumeSnapshotsClient struct{ client.Client }
+
+func (c noVolumeSnapshotsClient) List(
+	ctx context.Context, list client.ObjectList, opts ...client.ListOption,
+) error {
+	if gvk := list.GetObjectKind().GroupVersionKind(); gvk.Group == volumeSnapshotGVK.Group {
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind()}
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestReconcileVolumeSnapshotsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		Client:   noVolumeSnapshotsClient{fake.NewClientBuilder().Build()},
+		Recorder: recorder,
+	}
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Spec.Backups.VolumeSnapshots = &v1beta1.VolumeSnapshots{}
+
+	for i := 0; i < 2; i++ {
+		_, err := r.reconcileVolumeSnapshots(ctx, cluster, &observedInstances{}, nil)
+		assert.NilError(t, err)
+	}
+
+	condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionVolumeSnapshotsAvailable)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, "VolumeSnapshotsUnavailable")
+	assert.Equal(t, len(recorder.Events), 1, "expected one event across reconciles")
+
+	t.Run("Disabled", func(t *testing.T) {
+		cluster.Spec.Backups.VolumeSnapshots = nil
+
+		_, err := r.reconcileVolumeSnapshots(ctx, cluster, &observedInstances{}, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, meta.FindStatusCondition(
+			cluster.Status.Conditions, ConditionVolumeSnapshotsAvailable) == nil)
+	})
+}
+
+func TestProgressVolumeSnapshotSessionGone(t *testing.T) {
+	ctx := context.Background()
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = "ns1", "hippo"
+	cluster.Status.VolumeSnapshots = &v1beta1.VolumeSnapshotsStatus{
+		Latest: &v1beta1.VolumeSnapshotSetStatus{
+			Name: "snap", Pod: "hippo-pod", Phase: volumeSnapshotSnapshotting, StartTime: &start,
+		},
+	}
+
+	pod := &corev1.Pod{}
+	pod.Namespace, pod.Name = "ns1", "hippo-pod"
+	pod.CreationTimestamp = metav1.NewTime(start.Add(-time.Hour))
+	instances := &observedInstances{forCluster: []*Instance{
+		{Name: "hippo-abcd", Pods: []*corev1.Pod{pod}},
+	}}
+
+	var finished []string
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		Recorder: recorder,
+		PodExec: func(
+			_, _, _ string, _ io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			if command[0] == "touch" {
+				finished = append(finished, command[1])
+			}
+			// There is no state because the session is gone.
+			_, _ = stdout.Write([]byte("\n"))
+			return nil
+		},
+	}
+
+	assert.NilError(t, r.progressVolumeSnapshot(ctx, cluster, instances, nil, nil))
+
+	latest := cluster.Status.VolumeSnapshots.Latest
+	assert.Equal(t, latest.Phase, volumeSnapshotFailed)
+	assert.Assert(t, latest.CompletionTime != nil)
+	assert.DeepEqual(t, finished, []string{"/tmp/pgo-backup-mode/snap/taken"})
+
+	assert.Equal(t, len(recorder.Events), 1)
+	assert.Assert(t, cmp.Contains(<-recorder.Events, `session on pod "hippo-pod" is gone`))
+}
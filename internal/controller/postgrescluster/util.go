@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"io"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -190,7 +191,7 @@ func addNSSWrapper(image string, imagePullPolicy corev1.PullPolicy, template *co
 	for i, c := range template.Spec.Containers {
 		switch c.Name {
 		case naming.ContainerDatabase, naming.PGBackRestRepoContainerName,
-			naming.PGBackRestRestoreContainerName:
+			naming.PGBackRestRestoreContainerName, naming.ContainerPGUpgrade:
 			passwd := fmt.Sprintf(nssWrapperDir, "postgres", "passwd")
 			group := fmt.Sprintf(nssWrapperDir, "postgres", "group")
 			template.Spec.Containers[i].Env = append(template.Spec.Containers[i].Env, []corev1.EnvVar{
@@ -244,7 +245,8 @@ func addNSSWrapper(image string, imagePullPolicy corev1.PullPolicy, template *co
 				container.Resources = template.Spec.Containers[i].Resources
 				break
 			}
-			if c.Name == naming.PGBackRestRestoreContainerName {
+			if c.Name == naming.PGBackRestRestoreContainerName ||
+				c.Name == naming.ContainerPGUpgrade {
 				container.Resources = template.Spec.Containers[i].Resources
 				break
 			}
@@ -286,6 +288,19 @@ func jobCompleted(job *batchv1.Job) bool {
 	return false
 }
 
+// jobFinishedAt returns when the Job provided completed or failed. It returns
+// the zero time when the Job has not finished.
+func jobFinishedAt(job *batchv1.Job) time.Time {
+	conditions := job.Status.Conditions
+	for i := range conditions {
+		if (conditions[i].Type == batchv1.JobComplete || conditions[i].Type == batchv1.JobFailed) &&
+			conditions[i].Status == corev1.ConditionTrue {
+			return conditions[i].LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
 // safeHash32 runs content and returns a short alphanumeric string that
 // represents everything written to w. The string is unlikely to have bad words
 // and is safe to store in the Kubernetes API. This is the same algorithm used
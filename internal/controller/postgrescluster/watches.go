@@ -16,14 +16,19 @@
 package postgrescluster
 
 import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/internal/naming"
 	"github.com/crunchydata/postgres-operator/internal/patroni"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 // watchPods returns a handler.EventHandler for Pods.
@@ -60,3 +65,159 @@ func (*Reconciler) watchPods() handler.Funcs {
 		},
 	}
 }
+
+// indexCloneSource is a field index of PostgresClusters by their data source
+// in another namespace. Each cluster is indexed by the namespace and name of
+// its source cluster, "namespace/name", and by its namespace alone.
+const indexCloneSource = "spec.dataSource.postgresCluster"
+
+// cloneSourceKeys returns the values of [indexCloneSource] for object.
+func cloneSourceKeys(object client.Object) []string {
+	cluster, ok := object.(*v1beta1.PostgresCluster)
+	if !ok || cluster.Spec.DataSource == nil || cluster.Spec.DataSource.PostgresCluster == nil {
+		return nil
+	}
+
+	dataSource := cluster.Spec.DataSource.PostgresCluster
+	if dataSource.ClusterNamespace == "" || dataSource.ClusterNamespace == cluster.Namespace {
+		return nil
+	}
+
+	name := dataSource.ClusterName
+	if name == "" {
+		name = cluster.Name
+	}
+	return []string{dataSource.ClusterNamespace + "/" + name, dataSource.ClusterNamespace}
+}
+
+// cloneRequests returns a request for each PostgresCluster that is not yet
+// bootstrapped and uses a PostgresCluster in namespace as its data source.
+// When name is not empty, only clones of that cluster are returned.
+func (r *Reconciler) cloneRequests(ctx context.Context, namespace, name string) []reconcile.Request {
+	key := namespace
+	if name != "" {
+		key += "/" + name
+	}
+
+	clusters := &v1beta1.PostgresClusterList{}
+	if err := r.Client.List(ctx, clusters,
+		client.MatchingFields{indexCloneSource: key},
+	); err != nil {
+		logging.FromContext(ctx).Error(err, "listing PostgresClusters")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if patroni.ClusterBootstrapped(cluster) {
+			continue
+		}
+		for _, value := range cloneSourceKeys(cluster) {
+			if value == key {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(cluster),
+				})
+			}
+		}
+	}
+	return requests
+}
+
+// watchClusterForClones returns a handler.EventHandler that queues the
+// PostgresClusters in other namespaces that are not yet bootstrapped and use a
+// PostgresCluster as their data source whenever it changes. This keeps their
+// copies of its pgBackRest configuration current.
+func (r *Reconciler) watchClusterForClones() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(source client.Object) []reconcile.Request {
+		return r.cloneRequests(context.Background(), source.GetNamespace(), source.GetName())
+	})
+}
+
+// watchConfigurationForClones returns a handler.EventHandler that queues the
+// PostgresClusters in other namespaces that are not yet bootstrapped and use
+// a PostgresCluster as their data source whenever a Secret or ConfigMap in
+// its pgBackRest configuration changes. This keeps their copies of those
+// current, e.g. when credentials are rotated.
+func (r *Reconciler) watchConfigurationForClones() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+		ctx := context.Background()
+
+		// Most namespaces have no clusters that are cloned elsewhere.
+		if len(r.cloneRequests(ctx, object.GetNamespace(), "")) == 0 {
+			return nil
+		}
+
+		clusters := &v1beta1.PostgresClusterList{}
+		if err := r.Client.List(ctx, clusters,
+			client.InNamespace(object.GetNamespace()),
+		); err != nil {
+			logging.FromContext(ctx).Error(err, "listing PostgresClusters")
+			return nil
+		}
+
+		_, secret := object.(*corev1.Secret)
+		_, configmap := object.(*corev1.ConfigMap)
+
+		var requests []reconcile.Request
+		for i := range clusters.Items {
+			for _, projection := range clusters.Items[i].Spec.Backups.PGBackRest.Configuration {
+				if (secret && projection.Secret != nil &&
+					projection.Secret.Name == object.GetName()) ||
+					(configmap && projection.ConfigMap != nil &&
+						projection.ConfigMap.Name == object.GetName()) {
+					requests = append(requests, r.cloneRequests(ctx,
+						clusters.Items[i].Namespace, clusters.Items[i].Name)...)
+					break
+				}
+			}
+		}
+		return requests
+	})
+}
+
+// watchVolumeSnapshotJobs returns a handler.EventHandler that queues the
+// PostgresCluster of a Job that triggers a volume snapshot. These Jobs are
+// created by a CronJob or by hand, so they are not owned by the cluster.
+func (*Reconciler) watchVolumeSnapshotJobs() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(job client.Object) []reconcile.Request {
+		labels := job.GetLabels()
+		if _, ok := labels[naming.LabelVolumeSnapshot]; ok && labels[naming.LabelCluster] != "" {
+			return []reconcile.Request{{NamespacedName: client.ObjectKey{
+				Namespace: job.GetNamespace(), Name: labels[naming.LabelCluster],
+			}}}
+		}
+		return nil
+	})
+}
+
+// watchPostgresUserPasswordSecrets returns a handler.EventHandler that queues
+// the PostgresClusters in the namespace of a Secret that take the password of
+// a user from it. These Secrets are managed by something other than the
+// operator, so they are not owned by the cluster.
+func (r *Reconciler) watchPostgresUserPasswordSecrets() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(secret client.Object) []reconcile.Request {
+		ctx := context.Background()
+		clusters := &v1beta1.PostgresClusterList{}
+		if err := r.Client.List(ctx, clusters,
+			client.InNamespace(secret.GetNamespace()),
+		); err != nil {
+			logging.FromContext(ctx).Error(err, "listing PostgresClusters")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for i := range clusters.Items {
+			for _, user := range clusters.Items[i].Spec.Users {
+				if user.Password != nil && user.Password.SecretRef != nil &&
+					user.Password.SecretRef.Name == secret.GetName() {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: client.ObjectKeyFromObject(&clusters.Items[i]),
+					})
+					break
+				}
+			}
+		}
+		return requests
+	})
+}
@@ -22,9 +22,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crunchydata/postgres-operator/internal/controller/runtime"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestWatchPodsUpdate(t *testing.T) {
@@ -141,3 +146,150 @@ func TestWatchPodsUpdate(t *testing.T) {
 		queue.Done(item)
 	})
 }
+
+func TestWatchClusterForClones(t *testing.T) {
+	clone := func(namespace, name, sourceNamespace string) *v1beta1.PostgresCluster {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Namespace, cluster.Name = namespace, name
+		cluster.Spec.DataSource = &v1beta1.DataSource{
+			PostgresCluster: &v1beta1.PostgresClusterDataSource{
+				ClusterName: "prod", ClusterNamespace: sourceNamespace, RepoName: "repo2",
+			},
+		}
+		return cluster
+	}
+
+	bootstrapped := clone("dev2", "done", "prod")
+	bootstrapped.Status.Patroni.SystemIdentifier = "6952526174828511264"
+
+	scheme, err := runtime.CreatePostgresOperatorScheme()
+	assert.NilError(t, err)
+
+	reconciler := &Reconciler{Client: fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(
+			clone("dev1", "copy", "prod"),
+			clone("dev1", "other", "staging"),
+			clone("prod", "local", ""),
+			bootstrapped,
+		).Build()}
+
+	queue := controllertest.Queue{Interface: workqueue.New()}
+	source := &v1beta1.PostgresCluster{}
+	source.Namespace, source.Name = "prod", "prod"
+
+	reconciler.watchClusterForClones().Update(event.UpdateEvent{
+		ObjectOld: source, ObjectNew: source,
+	}, queue)
+
+	assert.Equal(t, queue.Len(), 1)
+	item, _ := queue.Get()
+	assert.Equal(t, item, reconcile.Request{NamespacedName: client.ObjectKey{
+		Namespace: "dev1", Name: "copy",
+	}})
+}
+
+func TestWatchPostgresUserPasswordSecrets(t *testing.T) {
+	cluster := func(namespace, name, secret string) *v1beta1.PostgresCluster {
+		cluster := &v1beta1.PostgresCluster{}
+		cluster.Namespace, cluster.Name = namespace, name
+		cluster.Spec.Users = []v1beta1.PostgresUserSpec{
+			{Name: "generated"},
+			{Name: "external", Password: &v1beta1.PostgresPasswordSpec{
+				SecretRef: &v1beta1.PostgresPasswordSecretReference{Name: secret},
+			}},
+		}
+		return cluster
+	}
+
+	scheme, err := runtime.CreatePostgresOperatorScheme()
+	assert.NilError(t, err)
+
+	reconciler := &Reconciler{Client: fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(
+			cluster("ns1", "match", "vault"),
+			cluster("ns1", "other", "elsewhere"),
+			cluster("ns2", "namespace", "vault"),
+		).Build()}
+
+	queue := controllertest.Queue{Interface: workqueue.New()}
+	secret := &corev1.Secret{}
+	secret.Namespace, secret.Name = "ns1", "vault"
+
+	reconciler.watchPostgresUserPasswordSecrets().Update(event.UpdateEvent{
+		ObjectOld: secret, ObjectNew: secret,
+	}, queue)
+
+	assert.Equal(t, queue.Len(), 1)
+	item, _ := queue.Get()
+	assert.Equal(t, item, reconcile.Request{NamespacedName: client.ObjectKey{
+		Namespace: "ns1", Name: "match",
+	}})
+}
+
+func TestWatchConfigurationForClones(t *testing.T) {
+	source := &v1beta1.PostgresCluster{}
+	source.Namespace, source.Name = "prod", "prod"
+	source.Spec.Backups.PGBackRest.Configuration = []corev1.VolumeProjection{
+		{Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "s3-credentials"},
+		}},
+	}
+
+	clone := &v1beta1.PostgresCluster{}
+	clone.Namespace, clone.Name = "dev1", "copy"
+	clone.Spec.DataSource = &v1beta1.DataSource{
+		PostgresCluster: &v1beta1.PostgresClusterDataSource{
+			ClusterName: "prod", ClusterNamespace: "prod", RepoName: "repo2",
+		},
+	}
+
+	scheme, err := runtime.CreatePostgresOperatorScheme()
+	assert.NilError(t, err)
+
+	reconciler := &Reconciler{Client: fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(source, clone).Build()}
+
+	update := func(object client.Object) *controllertest.Queue {
+		queue := &controllertest.Queue{Interface: workqueue.New()}
+		reconciler.watchConfigurationForClones().Update(event.UpdateEvent{
+			ObjectOld: object, ObjectNew: object,
+		}, queue)
+		return queue
+	}
+
+	secret := &corev1.Secret{}
+	secret.Namespace, secret.Name = "prod", "s3-credentials"
+
+	queue := update(secret)
+	assert.Equal(t, queue.Len(), 1)
+	item, _ := queue.Get()
+	assert.Equal(t, item, reconcile.Request{NamespacedName: client.ObjectKey{
+		Namespace: "dev1", Name: "copy",
+	}})
+
+	// A ConfigMap by the same name is not in the configuration.
+	configmap := &corev1.ConfigMap{}
+	configmap.Namespace, configmap.Name = "prod", "s3-credentials"
+	assert.Equal(t, update(configmap).Len(), 0)
+
+	// Secrets of other clusters are ignored.
+	secret.Name = "other"
+	assert.Equal(t, update(secret).Len(), 0)
+}
+
+func TestCloneSourceKeys(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Namespace, cluster.Name = "dev1", "hippo"
+	assert.Assert(t, cloneSourceKeys(cluster) == nil)
+
+	cluster.Spec.DataSource = &v1beta1.DataSource{
+		PostgresCluster: &v1beta1.PostgresClusterDataSource{ClusterNamespace: "dev1"},
+	}
+	assert.Assert(t, cloneSourceKeys(cluster) == nil, "same namespace")
+
+	cluster.Spec.DataSource.PostgresCluster.ClusterNamespace = "prod"
+	assert.DeepEqual(t, cloneSourceKeys(cluster), []string{"prod/hippo", "prod"})
+
+	cluster.Spec.DataSource.PostgresCluster.ClusterName = "rhino"
+	assert.DeepEqual(t, cloneSourceKeys(cluster), []string{"prod/rhino", "prod"})
+}
@@ -51,6 +51,17 @@ const (
 	// of the Job.
 	PGBackRestRestore = annotationPrefix + "pgbackrest-restore"
 
+	// PGBackRestCloneNamespaces is an annotation added to a PostgresCluster to allow it to be
+	// used as the data source of PostgresClusters in other namespaces. The value is a
+	// comma-separated list of namespaces, or "*" to allow every namespace. When present, PGO
+	// copies the pgBackRest configuration and credentials of the cluster into those namespaces.
+	PGBackRestCloneNamespaces = annotationPrefix + "pgbackrest-clone-namespaces"
+
+	// VolumeSnapshotBackupLabel is an annotation on the VolumeSnapshot of a PostgreSQL data
+	// volume. Its value is the "backup_label" file returned when PostgreSQL left backup mode,
+	// which must be written to the data directory before the snapshot can be recovered.
+	VolumeSnapshotBackupLabel = annotationPrefix + "backup-label"
+
 	// PGBackRestIPVersion is an annotation used to indicate whether an IPv6 wildcard address should be
 	// used for the pgBackRest "tls-server-address" or not. If the user wants to use IPv6, the value
 	// should be "IPv6". As of right now, if the annotation is not present or if the annotation's value
@@ -58,4 +69,10 @@ const (
 	// for this annotation is due to an issue in pgBackRest (#1841) where using a wildcard address to
 	// bind all addresses does not work in certain IPv6 environments.
 	PGBackRestIPVersion = annotationPrefix + "pgbackrest-ip-version"
+
+	// PostgresPasswordRotation is the annotation that is added to the Secret of a PostgreSQL user
+	// to request a new password. The value of the annotation is a unique identifier (e.g. a
+	// timestamp), which is stored in the PostgresCluster status so that each value generates one
+	// password.
+	PostgresPasswordRotation = annotationPrefix + "rotate-password"
 )
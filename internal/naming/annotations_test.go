@@ -30,4 +30,7 @@ func TestAnnotationsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestCurrentConfig))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestRestore))
 	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestIPVersion))
+	assert.Assert(t, nil == validation.IsQualifiedName(PostgresPasswordRotation))
+	assert.Assert(t, nil == validation.IsQualifiedName(PGBackRestCloneNamespaces))
+	assert.Assert(t, nil == validation.IsQualifiedName(VolumeSnapshotBackupLabel))
 }
@@ -91,6 +91,14 @@ const (
 	// LabelStartupInstance is used to indicate the startup instance associated with a resource
 	LabelStartupInstance = labelPrefix + "startup-instance"
 
+	// LabelPGUpgrade is used to indicate that a Job or Pod is for a major
+	// PostgreSQL upgrade. Its value is the identifier of the upgrade.
+	LabelPGUpgrade = labelPrefix + "pgupgrade"
+
+	// LabelVolumeSnapshot is used to identify volume snapshot resources. On a
+	// VolumeSnapshot, its value is the name of the snapshot it belongs to.
+	LabelVolumeSnapshot = labelPrefix + "volume-snapshot"
+
 	RolePrimary = "primary"
 	RoleReplica = "replica"
 
@@ -105,6 +113,10 @@ const (
 	// RolePGBouncer is the LabelRole applied to PgBouncer objects.
 	RolePGBouncer = "pgbouncer"
 
+	// RolePGBouncerReadOnly is the LabelRole applied to objects of PgBouncer
+	// that connects to PostgreSQL replicas.
+	RolePGBouncerReadOnly = "pgbouncer-ro"
+
 	// RolePGAdmin is the LabelRole applied to pgAdmin objects.
 	RolePGAdmin = "pgadmin"
 
@@ -143,6 +155,10 @@ const (
 	// BackupReplicaCreate is the backup type for the backup taken to enable pgBackRest replica
 	// creation
 	BackupReplicaCreate BackupJobType = "replica-create"
+
+	// BackupDeclarative is the backup type for Jobs that perform the backups requested by
+	// PGBackRestBackup objects
+	BackupDeclarative BackupJobType = "declarative"
 )
 
 // Merge takes sets of labels and merges them. The last set
@@ -284,3 +300,31 @@ func PGBackRestRepoVolumeLabels(clusterName, repoName string) labels.Set {
 	}
 	return labels.Merge(repoLabels, repoVolLabels)
 }
+
+// VolumeSnapshotJobLabels provides labels for the CronJob and Jobs that trigger
+// volume snapshots.
+func VolumeSnapshotJobLabels(clusterName string) labels.Set {
+	return map[string]string{
+		LabelCluster:        clusterName,
+		LabelVolumeSnapshot: "",
+	}
+}
+
+// PGUpgradeJobLabels provides labels for the Job that performs a major
+// PostgreSQL upgrade of a cluster.
+func PGUpgradeJobLabels(clusterName, upgradeID string) labels.Set {
+	return map[string]string{
+		LabelCluster:   clusterName,
+		LabelPGUpgrade: upgradeID,
+	}
+}
+
+// VolumeSnapshotLabels provides labels for the VolumeSnapshots of the volume with
+// role taken as part of the snapshot named snapshotName.
+func VolumeSnapshotLabels(clusterName, snapshotName, role string) labels.Set {
+	return map[string]string{
+		LabelCluster:        clusterName,
+		LabelVolumeSnapshot: snapshotName,
+		LabelRole:           role,
+	}
+}
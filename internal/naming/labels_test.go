@@ -43,8 +43,10 @@ func TestLabelsValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGBackRestRestore))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGBackRestRestoreConfig))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGMonitorDiscovery))
+	assert.Assert(t, nil == validation.IsQualifiedName(LabelPGUpgrade))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelPostgresUser))
 	assert.Assert(t, nil == validation.IsQualifiedName(LabelStartupInstance))
+	assert.Assert(t, nil == validation.IsQualifiedName(LabelVolumeSnapshot))
 }
 
 func TestLabelValuesValid(t *testing.T) {
@@ -55,6 +57,7 @@ func TestLabelValuesValid(t *testing.T) {
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePatroniReplica))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePGAdmin))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePGBouncer))
+	assert.Assert(t, nil == validation.IsValidLabelValue(RolePGBouncerReadOnly))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePostgresData))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePostgresUser))
 	assert.Assert(t, nil == validation.IsValidLabelValue(RolePostgresWAL))
@@ -47,6 +47,8 @@ const (
 	ContainerPGBouncer = "pgbouncer"
 	// ContainerPGBouncerConfig is the name of a container supporting PgBouncer.
 	ContainerPGBouncerConfig = "pgbouncer-config"
+	// ContainerPGBouncerExporter is the name of a container exporting PgBouncer metrics.
+	ContainerPGBouncerExporter = "pgbouncer-exporter"
 
 	// ContainerPostgresStartup is the name of the initialization container
 	// that prepares the filesystem for PostgreSQL.
@@ -76,6 +78,16 @@ const (
 	// ContainerJobMovePGBackRestRepoDir is the name of the job container utilized to copy v4
 	// Operator pgBackRest repo directories to the v5 default location
 	ContainerJobMovePGBackRestRepoDir = "repo-move-job"
+
+	// ContainerVolumeSnapshotTrigger is the name of the container in the Jobs
+	// that trigger volume snapshots.
+	ContainerVolumeSnapshotTrigger = "volume-snapshot"
+
+	// ContainerPGUpgrade is the name of the Job container that runs pg_upgrade.
+	ContainerPGUpgrade = "pgupgrade"
+	// ContainerPGUpgradeBinaries is the name of the init container that copies
+	// the PostgreSQL binaries of the current major version for pg_upgrade.
+	ContainerPGUpgradeBinaries = "pgupgrade-binaries"
 )
 
 const (
@@ -220,8 +232,8 @@ func ClusterPGAdmin(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 }
 
 // ClusterPGBouncer returns the ObjectMeta necessary to lookup the ConfigMap,
-// Deployment, Secret, PodDisruptionBudget or Service that is cluster's
-// PgBouncer proxy.
+// Deployment, HorizontalPodAutoscaler, Secret, PodDisruptionBudget or Service
+// that is cluster's PgBouncer proxy.
 func ClusterPGBouncer(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
 		Namespace: cluster.Namespace,
@@ -229,6 +241,25 @@ func ClusterPGBouncer(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	}
 }
 
+// ClusterPGBouncerAdmin returns the ObjectMeta necessary to lookup the Secret
+// with credentials for the admin console of cluster's PgBouncer proxy.
+func ClusterPGBouncerAdmin(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name + "-pgbouncer-admin",
+	}
+}
+
+// ClusterPGBouncerReadOnly returns the ObjectMeta necessary to lookup the
+// Deployment, PodDisruptionBudget or Service of cluster's PgBouncer proxy to
+// PostgreSQL replicas.
+func ClusterPGBouncerReadOnly(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name + "-pgbouncer-ro",
+	}
+}
+
 // ClusterPodService returns the ObjectMeta necessary to lookup the Service
 // that is responsible for the network identity of Pods.
 func ClusterPodService(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
@@ -397,6 +428,16 @@ func PatroniScope(cluster *v1beta1.PostgresCluster) string {
 	return cluster.Name + "-ha"
 }
 
+// PatroniSync returns the ObjectMeta necessary to lookup the ConfigMap or
+// Endpoints Patroni creates for cluster to record its synchronous standbys.
+// See Patroni DCS "sync_path".
+func PatroniSync(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.Namespace,
+		Name:      PatroniScope(cluster) + "-sync",
+	}
+}
+
 // PatroniTrigger returns the ObjectMeta necessary to lookup the ConfigMap or
 // Endpoints Patroni creates for cluster to initiate a controlled change of the
 // leader. See Patroni DCS "failover_path".
@@ -424,6 +465,15 @@ func PGBackRestBackupJob(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	}
 }
 
+// PGBackRestBackupObjectJob returns the ObjectMeta for the pgBackRest backup Job
+// of a PGBackRestBackup
+func PGBackRestBackupObjectJob(backup *v1beta1.PGBackRestBackup) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      backup.GetName() + "-backup",
+		Namespace: backup.GetNamespace(),
+	}
+}
+
 // PGBackRestCronJob returns the ObjectMeta for a pgBackRest CronJob
 func PGBackRestCronJob(cluster *v1beta1.PostgresCluster, backuptype, repoName string) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
@@ -432,6 +482,15 @@ func PGBackRestCronJob(cluster *v1beta1.PostgresCluster, backuptype, repoName st
 	}
 }
 
+// VolumeSnapshotCronJob returns the ObjectMeta for the CronJob that triggers
+// volume snapshots of cluster.
+func VolumeSnapshotCronJob(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.GetNamespace(),
+		Name:      cluster.Name + "-volume-snapshot",
+	}
+}
+
 // PGBackRestRestoreJob returns the ObjectMeta for a pgBackRest restore Job
 func PGBackRestRestoreJob(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
@@ -440,6 +499,15 @@ func PGBackRestRestoreJob(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
 	}
 }
 
+// PGUpgradeJob returns the ObjectMeta for the Job that performs a major
+// PostgreSQL upgrade of cluster.
+func PGUpgradeJob(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace: cluster.GetNamespace(),
+		Name:      cluster.Name + "-pgupgrade",
+	}
+}
+
 // PGBackRestRBAC returns the ObjectMeta necessary to lookup the ServiceAccount, Role, and
 // RoleBinding for pgBackRest Jobs
 func PGBackRestRBAC(cluster *v1beta1.PostgresCluster) metav1.ObjectMeta {
@@ -51,6 +51,7 @@ func TestContainerNamesUniqueAndValid(t *testing.T) {
 		ContainerPGBackRestLogDirInit,
 		ContainerPGBouncer,
 		ContainerPGBouncerConfig,
+		ContainerPGBouncerExporter,
 		ContainerPostgresStartup,
 		ContainerPGMonitorExporter,
 	} {
@@ -97,6 +98,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 			{"ClusterPGBouncer", ClusterPGBouncer(cluster)},
 			{"PatroniDistributedConfiguration", PatroniDistributedConfiguration(cluster)},
 			{"PatroniLeaderConfigMap", PatroniLeaderConfigMap(cluster)},
+			{"PatroniSync", PatroniSync(cluster)},
 			{"PatroniTrigger", PatroniTrigger(cluster)},
 			{"PGBackRestConfig", PGBackRestConfig(cluster)},
 			{"PGBackRestSSHConfig", PGBackRestSSHConfig(cluster)},
@@ -109,19 +111,26 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 			{"PGBackRestCronJon", PGBackRestCronJob(cluster, "incr", "repo2")},
 			{"PGBackRestCronJon", PGBackRestCronJob(cluster, "diff", "repo3")},
 			{"PGBackRestCronJon", PGBackRestCronJob(cluster, "full", "repo4")},
+			{"VolumeSnapshotCronJob", VolumeSnapshotCronJob(cluster)},
 		})
 	})
 
 	t.Run("Deployments", func(t *testing.T) {
 		testUniqueAndValid(t, []test{
 			{"ClusterPGBouncer", ClusterPGBouncer(cluster)},
+			{"ClusterPGBouncerReadOnly", ClusterPGBouncerReadOnly(cluster)},
 		})
 	})
 
 	t.Run("Jobs", func(t *testing.T) {
+		// A PGBackRestBackup may have the same name as its cluster.
+		backup := &v1beta1.PGBackRestBackup{ObjectMeta: cluster.ObjectMeta}
+
 		testUniqueAndValid(t, []test{
 			{"PGBackRestBackupJob", PGBackRestBackupJob(cluster)},
+			{"PGBackRestBackupObjectJob", PGBackRestBackupObjectJob(backup)},
 			{"PGBackRestRestoreJob", PGBackRestRestoreJob(cluster)},
+			{"PGUpgradeJob", PGUpgradeJob(cluster)},
 		})
 	})
 
@@ -129,6 +138,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 		testUniqueAndValid(t, []test{
 			{"InstanceSetPDB", InstanceSet(cluster, instanceSet)},
 			{"PGBouncerPDB", ClusterPGBouncer(cluster)},
+			{"PGBouncerReadOnlyPDB", ClusterPGBouncerReadOnly(cluster)},
 		})
 	})
 
@@ -149,6 +159,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 	t.Run("Secrets", func(t *testing.T) {
 		names := testUniqueAndValid(t, []test{
 			{"ClusterPGBouncer", ClusterPGBouncer(cluster)},
+			{"ClusterPGBouncerAdmin", ClusterPGBouncerAdmin(cluster)},
 			{"DeprecatedPostgresUserSecret", DeprecatedPostgresUserSecret(cluster)},
 			{"PostgresTLSSecret", PostgresTLSSecret(cluster)},
 			{"ReplicationClientCertSecret", ReplicationClientCertSecret(cluster)},
@@ -179,6 +190,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 	t.Run("Services", func(t *testing.T) {
 		testUniqueAndValid(t, []test{
 			{"ClusterPGBouncer", ClusterPGBouncer(cluster)},
+			{"ClusterPGBouncerReadOnly", ClusterPGBouncerReadOnly(cluster)},
 			{"ClusterPGAdmin", ClusterPGAdmin(cluster)},
 			{"ClusterPodService", ClusterPodService(cluster)},
 			{"ClusterPrimaryService", ClusterPrimaryService(cluster)},
@@ -186,6 +198,7 @@ func TestClusterNamesUniqueAndValid(t *testing.T) {
 			// Patroni can use Endpoints which relate directly to a Service.
 			{"PatroniDistributedConfiguration", PatroniDistributedConfiguration(cluster)},
 			{"PatroniLeaderEndpoints", PatroniLeaderEndpoints(cluster)},
+			{"PatroniSync", PatroniSync(cluster)},
 			{"PatroniTrigger", PatroniTrigger(cluster)},
 		})
 	})
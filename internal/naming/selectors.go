@@ -125,6 +125,17 @@ func ClusterPGBouncerSelector(cluster *v1beta1.PostgresCluster) metav1.LabelSele
 	}
 }
 
+// ClusterPGBouncerReadOnlySelector selects things labeled for the read-only
+// PgBouncer in cluster.
+func ClusterPGBouncerReadOnlySelector(cluster *v1beta1.PostgresCluster) metav1.LabelSelector {
+	return metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			LabelCluster: cluster.Name,
+			LabelRole:    RolePGBouncerReadOnly,
+		},
+	}
+}
+
 // ClusterPostgresUsers selects things labeled for PostgreSQL users in cluster.
 func ClusterPostgresUsers(cluster string) metav1.LabelSelector {
 	return metav1.LabelSelector{
@@ -135,6 +135,18 @@ func TestClusterPGBouncerSelector(t *testing.T) {
 	assert.ErrorContains(t, err, "Invalid")
 }
 
+func TestClusterPGBouncerReadOnlySelector(t *testing.T) {
+	cluster := &v1beta1.PostgresCluster{}
+	cluster.Name = "something"
+
+	s, err := AsSelector(ClusterPGBouncerReadOnlySelector(cluster))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, s.String(), strings.Join([]string{
+		"postgres-operator.crunchydata.com/cluster=something",
+		"postgres-operator.crunchydata.com/role=pgbouncer-ro",
+	}, ","))
+}
+
 func TestClusterPostgresUsers(t *testing.T) {
 	s, err := AsSelector(ClusterPostgresUsers("something"))
 	assert.NilError(t, err)
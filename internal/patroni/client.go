@@ -0,0 +1,365 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package patroni
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+)
+
+// clientTimeout limits each call to the Patroni REST API. Changing the primary
+// waits up to two "loop_wait" and at least twenty seconds.
+// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/api.py#L461-L477
+const clientTimeout = 2 * time.Minute
+
+// Client implements API by calling the Patroni REST API of one member over
+// mutual TLS.
+// - https://patroni.readthedocs.io/en/latest/rest_api.html
+type Client struct {
+	// URL is the scheme, host, and port of the REST API of one member.
+	URL string
+
+	// HTTP sends requests to URL and other members of the cluster.
+	HTTP *http.Client
+}
+
+// Client implements API.
+var _ API = (*Client)(nil)
+
+// transports holds one http.Transport for each Secret of instance certificates
+// so that calls to Patroni reuse connections rather than open new ones.
+var transports = struct {
+	sync.Mutex
+	cache map[string]cachedTransport
+}{cache: map[string]cachedTransport{}}
+
+type cachedTransport struct {
+	checksum  [sha256.Size]byte
+	transport *http.Transport
+}
+
+// NewClient returns a Client that calls the REST API at rawURL. It trusts and
+// authenticates with the certificates in instanceCertificates, the Secret
+// populated by [InstanceCertificates]. Clients of the same Secret share
+// connections until its certificates change.
+func NewClient(rawURL string, instanceCertificates *corev1.Secret) (*Client, error) {
+	authority := instanceCertificates.Data[certAuthorityFileKey]
+	combined := instanceCertificates.Data[certServerFileKey]
+	checksum := sha256.Sum256(append(append([]byte{}, authority...), combined...))
+	key := instanceCertificates.Namespace + "/" + instanceCertificates.Name
+
+	transports.Lock()
+	defer transports.Unlock()
+
+	cached, ok := transports.cache[key]
+	if !ok || cached.checksum != checksum {
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(authority) {
+			return nil, fmt.Errorf("missing or invalid %q", certAuthorityFileKey)
+		}
+
+		// The combined file has the private key and the certificate in PEM format.
+		// Patroni verifies clients against the same certificate authority.
+		certificate, err := tls.X509KeyPair(combined, combined)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q: %w", certServerFileKey, err)
+		}
+
+		// Connections made with the old certificates are no longer needed.
+		if ok {
+			cached.transport.CloseIdleConnections()
+		}
+
+		// Patroni is reached directly at the DNS name of its Pod; there is no
+		// proxy in between. Idle connections are closed after a minute so that
+		// the transports of deleted instances do not hold any.
+		cached = cachedTransport{checksum: checksum, transport: &http.Transport{
+			IdleConnTimeout: time.Minute,
+			TLSClientConfig: &tls.Config{
+				Certificates: []tls.Certificate{certificate},
+				MinVersion:   tls.VersionTLS12,
+				RootCAs:      roots,
+			},
+		}}
+		transports.cache[key] = cached
+	}
+
+	return &Client{
+		URL: strings.TrimSuffix(rawURL, "/"),
+		HTTP: &http.Client{
+			Timeout:   clientTimeout,
+			Transport: cached.transport,
+		},
+	}, nil
+}
+
+// clusterMember is one member of the cluster as reported by "GET /cluster".
+type clusterMember struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	State    string `json:"state"`
+	Host     string `json:"host"`
+	Port     int32  `json:"port"`
+	APIURL   string `json:"api_url"`
+	Timeline int64  `json:"timeline"`
+
+	// Lag is the number of bytes that a replica is behind the leader. It is
+	// nil on the leader and when Patroni cannot determine it.
+	Lag *int64 `json:"-"`
+
+	// PendingRestart indicates that PostgreSQL must restart to apply changed
+	// parameters.
+	PendingRestart bool `json:"pending_restart"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Patroni reports "lag" as either
+// a number or the string "unknown".
+func (m *clusterMember) UnmarshalJSON(data []byte) error {
+	type member clusterMember
+	var raw struct {
+		member
+		Lag json.RawMessage `json:"lag"`
+	}
+
+	err := json.Unmarshal(data, &raw)
+	if err == nil {
+		*m = clusterMember(raw.member)
+
+		var lag int64
+		if json.Unmarshal(raw.Lag, &lag) == nil {
+			m.Lag = &lag
+		}
+	}
+	return err
+}
+
+// IsLeader returns true when m holds the leader lock of its cluster.
+func (m clusterMember) IsLeader() bool {
+	return m.Role == "leader" || m.Role == "master" || m.Role == "standby_leader"
+}
+
+// do sends a request with body encoded as JSON to endpoint and returns the
+// status code and body of the response.
+func (c *Client) do(
+	ctx context.Context, method, endpoint string, body interface{},
+) (int, []byte, error) {
+	var content io.Reader
+	if body != nil {
+		var buffer bytes.Buffer
+		if err := json.NewEncoder(&buffer).Encode(body); err != nil {
+			return 0, nil, err
+		}
+		content = &buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, content)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.HTTP.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	result, err := io.ReadAll(response.Body)
+	return response.StatusCode, result, err
+}
+
+// changePrimary calls endpoint and returns true when Patroni reports that the
+// leader changed to the candidate. Patroni responds "200 OK" even when
+// a different member became leader, so check for the text that indicates
+// success, like [Executor] does.
+// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/api.py#L461-L477
+func (c *Client) changePrimary(
+	ctx context.Context, endpoint, current, next, success string,
+) (bool, error) {
+	body := map[string]string{}
+	if current != "" {
+		body["leader"] = current
+	}
+	if next != "" {
+		body["candidate"] = next
+	}
+
+	status, response, err := c.do(ctx, http.MethodPost, c.URL+endpoint, body)
+
+	log := logging.FromContext(ctx)
+	log.V(1).Info("changed primary",
+		"status", status,
+		"response", string(response),
+	)
+
+	return err == nil && status == http.StatusOK &&
+		strings.Contains(string(response), success), err
+}
+
+// ChangePrimaryAndWait tries to demote the current Patroni leader by calling
+// "POST /switchover". It returns true when an election completes successfully.
+// When Patroni is paused, next cannot be blank.
+func (c *Client) ChangePrimaryAndWait(
+	ctx context.Context, current, next string,
+) (bool, error) {
+	return c.changePrimary(ctx, "/switchover", current, next, "switched over")
+}
+
+// SwitchoverAndWait tries to change the current Patroni leader to target by
+// calling "POST /switchover". It returns true when an election completes
+// successfully. When target is blank, Patroni chooses the best candidate.
+func (c *Client) SwitchoverAndWait(ctx context.Context, target string) (bool, error) {
+	// The switchover endpoint requires the name of the current leader.
+	members, err := c.getCluster(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var current string
+	for _, member := range members {
+		if member.IsLeader() {
+			current = member.Name
+		}
+	}
+
+	return c.changePrimary(ctx, "/switchover", current, target, "switched over")
+}
+
+// FailoverAndWait tries to change the current Patroni leader to target by
+// calling "POST /failover". It returns true when an election completes
+// successfully. Unlike a switchover, the current leader need not be healthy.
+func (c *Client) FailoverAndWait(ctx context.Context, target string) (bool, error) {
+	return c.changePrimary(ctx, "/failover", "", target, "failed over")
+}
+
+// ReplaceConfiguration replaces Patroni's entire dynamic configuration by
+// calling "PUT /config".
+func (c *Client) ReplaceConfiguration(
+	ctx context.Context, configuration map[string]interface{},
+) error {
+	status, response, err := c.do(ctx, http.MethodPut, c.URL+"/config", configuration)
+
+	log := logging.FromContext(ctx)
+	log.V(1).Info("replaced configuration",
+		"status", status,
+		"response", string(response),
+	)
+
+	if err == nil && status != http.StatusOK {
+		err = fmt.Errorf("unexpected response %d: %s", status, response)
+	}
+	return err
+}
+
+// getCluster returns the members of the cluster by calling "GET /cluster".
+func (c *Client) getCluster(ctx context.Context) ([]clusterMember, error) {
+	status, response, err := c.do(ctx, http.MethodGet, c.URL+"/cluster", nil)
+	if err == nil && status != http.StatusOK {
+		err = fmt.Errorf("unexpected response %d: %s", status, response)
+	}
+
+	var cluster struct {
+		Members []clusterMember `json:"members"`
+	}
+	if err == nil {
+		err = json.Unmarshal(response, &cluster)
+	}
+	return cluster.Members, err
+}
+
+// GetTimeline returns the timeline of the running leader. It returns zero when
+// there is no running leader.
+func (c *Client) GetTimeline(ctx context.Context) (int64, error) {
+	members, err := c.getCluster(ctx)
+
+	for _, member := range members {
+		if member.IsLeader() && member.State == "running" {
+			return member.Timeline, err
+		}
+	}
+	return 0, err
+}
+
+// RestartPendingMembers looks up Patroni members with role ("master" or
+// "replica") and calls "POST /restart" on those that have a pending restart.
+// Members are reached through the host of c.URL, replacing its first label
+// with the name of each member. Patroni serves only its own cluster, so scope
+// is not used.
+func (c *Client) RestartPendingMembers(ctx context.Context, role, _ string) error {
+	members, err := c.getCluster(ctx)
+	if err != nil {
+		return err
+	}
+
+	base, err := url.Parse(c.URL)
+	if err != nil {
+		return err
+	}
+	_, domain, _ := strings.Cut(base.Hostname(), ".")
+
+	log := logging.FromContext(ctx)
+	for _, member := range members {
+		if !member.PendingRestart || member.IsLeader() != (role == "master") {
+			continue
+		}
+
+		endpoint := *base
+		endpoint.Host = member.Name + "." + domain
+		if port := base.Port(); port != "" {
+			endpoint.Host += ":" + port
+		}
+		endpoint.Path = "/restart"
+
+		status, response, err := c.do(ctx, http.MethodPost, endpoint.String(),
+			map[string]interface{}{"restart_pending": true})
+
+		log.V(1).Info("restarted member",
+			"member", member.Name,
+			"status", status,
+			"response", string(response),
+		)
+
+		// Patroni responds "503 Service Unavailable" when the restart
+		// conditions are no longer satisfied, meaning the member has
+		// already restarted.
+		// - https://github.com/zalando/patroni/blob/v2.1.1/patroni/api.py#L414-L438
+		if err == nil && status != http.StatusOK && status != http.StatusServiceUnavailable {
+			err = fmt.Errorf("unexpected response %d from %q: %s", status, member.Name, response)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,354 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package patroni
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/pki"
+)
+
+// testClient returns a Client that sends every request to handler over plain
+// HTTP, regardless of the host in the URL.
+func testClient(t *testing.T, rawURL string, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &Client{
+		URL: rawURL,
+		HTTP: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return new(net.Dialer).DialContext(ctx, network, server.Listener.Addr().String())
+			},
+		}},
+	}
+}
+
+const clusterJSON = `{"members": [
+	{"name": "one-0", "role": "leader", "state": "running", "api_url": "https://one-0.pods:8008/patroni", "host": "one-0.pods", "port": 5432, "timeline": 4},
+	{"name": "two-0", "role": "replica", "state": "running", "api_url": "https://two-0.pods:8008/patroni", "host": "two-0.pods", "port": 5432, "timeline": 4, "lag": 16, "pending_restart": true},
+	{"name": "three-0", "role": "sync_standby", "state": "stopped", "host": "three-0.pods", "port": 5432, "lag": "unknown", "pending_restart": true}
+], "scope": "hippo-ha"}`
+
+func TestNewClient(t *testing.T) {
+	ctx := context.Background()
+	root, err := pki.NewRootCertificateAuthority()
+	assert.NilError(t, err)
+
+	leaf, err := root.GenerateLeafCertificate("server", []string{"localhost"})
+	assert.NilError(t, err)
+
+	certificates := new(corev1.Secret)
+	assert.NilError(t, InstanceCertificates(ctx,
+		root.Certificate, leaf.Certificate, leaf.PrivateKey, certificates))
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewClient("https://localhost", new(corev1.Secret))
+		assert.ErrorContains(t, err, "patroni.ca-roots")
+
+		_, err = NewClient("https://localhost", &corev1.Secret{Data: map[string][]byte{
+			"patroni.ca-roots": certificates.Data["patroni.ca-roots"],
+		}})
+		assert.ErrorContains(t, err, "patroni.crt-combined")
+	})
+
+	t.Run("SharedTransport", func(t *testing.T) {
+		secret := certificates.DeepCopy()
+		secret.Namespace, secret.Name = "ns", "shared"
+
+		first, err := NewClient("https://one", secret)
+		assert.NilError(t, err)
+		second, err := NewClient("https://two", secret)
+		assert.NilError(t, err)
+		assert.Assert(t, first.HTTP.Transport == second.HTTP.Transport)
+
+		transport := first.HTTP.Transport.(*http.Transport)
+		assert.Assert(t, transport.Proxy == nil)
+		assert.Assert(t, transport.IdleConnTimeout > 0)
+
+		// Other certificates get another transport.
+		other, err := root.GenerateLeafCertificate("other", []string{"localhost"})
+		assert.NilError(t, err)
+		assert.NilError(t, InstanceCertificates(ctx,
+			root.Certificate, other.Certificate, other.PrivateKey, secret))
+
+		third, err := NewClient("https://one", secret)
+		assert.NilError(t, err)
+		assert.Assert(t, third.HTTP.Transport != first.HTTP.Transport)
+	})
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		// Serve with the same certificate and require clients to present
+		// one signed by the same root, like Patroni with "verify_client".
+		serverCert, err := tls.X509KeyPair(
+			certificates.Data[certServerFileKey], certificates.Data[certServerFileKey])
+		assert.NilError(t, err)
+
+		roots := x509.NewCertPool()
+		assert.Assert(t, roots.AppendCertsFromPEM(certificates.Data[certAuthorityFileKey]))
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, len(r.TLS.PeerCertificates), 1)
+				assert.Equal(t, r.TLS.PeerCertificates[0].Subject.CommonName, "server")
+				_, _ = io.WriteString(w, clusterJSON)
+			}))
+		server.TLS = &tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    roots,
+		}
+		server.StartTLS()
+		t.Cleanup(server.Close)
+
+		_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		client, err := NewClient("https://localhost:"+port+"/", certificates)
+		assert.NilError(t, err)
+		assert.Equal(t, client.URL, "https://localhost:"+port)
+
+		members, err := client.getCluster(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, len(members), 3)
+	})
+}
+
+func TestClientGetCluster(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Members", func(t *testing.T) {
+		client := testClient(t, "http://one-0.pods.ns.svc:8008",
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method, http.MethodGet)
+				assert.Equal(t, r.URL.Path, "/cluster")
+				_, _ = io.WriteString(w, clusterJSON)
+			})
+
+		members, err := client.getCluster(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, len(members), 3)
+
+		assert.Equal(t, members[0].Name, "one-0")
+		assert.Assert(t, members[0].IsLeader())
+		assert.Assert(t, members[0].Lag == nil)
+		assert.Equal(t, members[0].Timeline, int64(4))
+
+		assert.Equal(t, members[1].APIURL, "https://two-0.pods:8008/patroni")
+		assert.Assert(t, !members[1].IsLeader())
+		assert.Assert(t, members[1].Lag != nil)
+		assert.Equal(t, *members[1].Lag, int64(16))
+		assert.Assert(t, members[1].PendingRestart)
+
+		assert.Assert(t, members[2].Lag == nil, "expected unknown lag")
+
+		timeline, err := client.GetTimeline(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, timeline, int64(4))
+	})
+
+	t.Run("NoLeader", func(t *testing.T) {
+		client := testClient(t, "http://one-0.pods.ns.svc:8008",
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, `{"members": [{"name": "two-0", "role": "replica", "state": "running", "timeline": 4}]}`)
+			})
+
+		timeline, err := client.GetTimeline(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, timeline, int64(0))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		client := testClient(t, "http://one-0.pods.ns.svc:8008",
+			func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "nope", http.StatusForbidden)
+			})
+
+		_, err := client.getCluster(ctx)
+		assert.ErrorContains(t, err, "403")
+
+		_, err = client.GetTimeline(ctx)
+		assert.ErrorContains(t, err, "403")
+	})
+}
+
+func TestClientChangePrimary(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name     string
+		call     func(*Client) (bool, error)
+		path     string
+		body     string
+		response string
+		success  bool
+	}{
+		{
+			name: "ChangePrimaryAndWait",
+			call: func(c *Client) (bool, error) { return c.ChangePrimaryAndWait(ctx, "old", "new") },
+			path: "/switchover", body: `{"candidate":"new","leader":"old"}`,
+			response: `Successfully switched over to "new"`, success: true,
+		},
+		{
+			name: "ChangePrimaryAndWaitOther",
+			call: func(c *Client) (bool, error) { return c.ChangePrimaryAndWait(ctx, "old", "new") },
+			path: "/switchover", body: `{"candidate":"new","leader":"old"}`,
+			response: `Switched over to "other" instead of "new"`, success: false,
+		},
+		{
+			name: "SwitchoverAndWait",
+			call: func(c *Client) (bool, error) { return c.SwitchoverAndWait(ctx, "two-0") },
+			path: "/switchover", body: `{"candidate":"two-0","leader":"one-0"}`,
+			response: `Successfully switched over to "two-0"`, success: true,
+		},
+		{
+			name: "SwitchoverAndWaitAny",
+			call: func(c *Client) (bool, error) { return c.SwitchoverAndWait(ctx, "") },
+			path: "/switchover", body: `{"leader":"one-0"}`,
+			response: `Successfully switched over to "two-0"`, success: true,
+		},
+		{
+			name: "FailoverAndWait",
+			call: func(c *Client) (bool, error) { return c.FailoverAndWait(ctx, "two-0") },
+			path: "/failover", body: `{"candidate":"two-0"}`,
+			response: `Successfully failed over to "two-0"`, success: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			client := testClient(t, "http://one-0.pods.ns.svc:8008",
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/cluster" {
+						_, _ = io.WriteString(w, clusterJSON)
+						return
+					}
+
+					called = true
+					assert.Equal(t, r.Method, http.MethodPost)
+					assert.Equal(t, r.URL.Path, tt.path)
+
+					b, _ := io.ReadAll(r.Body)
+					assert.Equal(t, strings.TrimSpace(string(b)), tt.body)
+					_, _ = io.WriteString(w, tt.response)
+				})
+
+			success, err := tt.call(client)
+			assert.NilError(t, err)
+			assert.Assert(t, called)
+			assert.Equal(t, success, tt.success)
+		})
+	}
+
+	t.Run("Failed", func(t *testing.T) {
+		client := testClient(t, "http://one-0.pods.ns.svc:8008",
+			func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Switchover failed", http.StatusServiceUnavailable)
+			})
+
+		success, err := client.ChangePrimaryAndWait(ctx, "old", "new")
+		assert.NilError(t, err)
+		assert.Assert(t, !success)
+	})
+}
+
+func TestClientReplaceConfiguration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		called := false
+		client := testClient(t, "http://one-0.pods.ns.svc:8008",
+			func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				assert.Equal(t, r.Method, http.MethodPut)
+				assert.Equal(t, r.URL.Path, "/config")
+
+				var body map[string]interface{}
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.DeepEqual(t, body, map[string]interface{}{"some": "values"})
+				_, _ = io.WriteString(w, `{"some": "values"}`)
+			})
+
+		assert.NilError(t, client.ReplaceConfiguration(ctx,
+			map[string]interface{}{"some": "values"}))
+		assert.Assert(t, called)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		client := testClient(t, "http://one-0.pods.ns.svc:8008",
+			func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "bad config", http.StatusBadRequest)
+			})
+
+		err := client.ReplaceConfiguration(ctx, map[string]interface{}{})
+		assert.ErrorContains(t, err, "400")
+		assert.ErrorContains(t, err, "bad config")
+	})
+}
+
+func TestClientRestartPendingMembers(t *testing.T) {
+	ctx := context.Background()
+
+	var restarted []string
+	client := testClient(t, "http://one-0.pods.ns.svc:8008",
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/cluster":
+				body := strings.Replace(clusterJSON,
+					`"role": "leader", "state": "running",`,
+					`"role": "leader", "state": "running", "pending_restart": true,`, 1)
+				_, _ = io.WriteString(w, body)
+			case "/restart":
+				assert.Equal(t, r.Method, http.MethodPost)
+				b, _ := io.ReadAll(r.Body)
+				assert.Equal(t, strings.TrimSpace(string(b)), `{"restart_pending":true}`)
+
+				restarted = append(restarted, r.Host)
+				if strings.HasPrefix(r.Host, "three-0.") {
+					http.Error(w, "restart conditions are not satisfied", http.StatusServiceUnavailable)
+				}
+			default:
+				t.Errorf("unexpected request: %v", r.URL)
+			}
+		})
+
+	t.Run("Replicas", func(t *testing.T) {
+		restarted = nil
+		assert.NilError(t, client.RestartPendingMembers(ctx, "replica", "hippo-ha"))
+
+		sort.Strings(restarted)
+		assert.DeepEqual(t, restarted, []string{
+			"three-0.pods.ns.svc:8008",
+			"two-0.pods.ns.svc:8008",
+		})
+	})
+
+	t.Run("Leader", func(t *testing.T) {
+		restarted = nil
+		assert.NilError(t, client.RestartPendingMembers(ctx, "master", "hippo-ha"))
+		assert.DeepEqual(t, restarted, []string{"one-0.pods.ns.svc:8008"})
+	})
+}
@@ -200,6 +200,17 @@ func DynamicConfiguration(
 	root["ttl"] = *cluster.Spec.Patroni.LeaderLeaseDurationSeconds
 	root["loop_wait"] = *cluster.Spec.Patroni.SyncPeriodSeconds
 
+	// Typed synchronous replication settings override any in configuration.
+	// - https://github.com/zalando/patroni/blob/v2.1.3/docs/replication_modes.rst
+	if sync := cluster.Spec.Patroni.Synchronous; sync != nil {
+		root["synchronous_mode"] = true
+		root["synchronous_mode_strict"] = sync.Mode == "Strict"
+		root["synchronous_node_count"] = int32(1)
+		if sync.StandbyCount != nil {
+			root["synchronous_node_count"] = *sync.StandbyCount
+		}
+	}
+
 	// Copy the "postgresql" section before making any changes.
 	postgresql := map[string]interface{}{
 		// TODO(cbandy): explain this. requires an archive, perhaps.
@@ -224,6 +235,12 @@ func DynamicConfiguration(
 			parameters[k] = v
 		}
 	}
+	// Override the above with parameters specified in the cluster spec.
+	if pgParameters.Specified != nil {
+		for k, v := range pgParameters.Specified.AsMap() {
+			parameters[k] = v
+		}
+	}
 	// Override the above with mandatory parameters.
 	if pgParameters.Mandatory != nil {
 		for k, v := range pgParameters.Mandatory.AsMap() {
@@ -242,11 +259,15 @@ func DynamicConfiguration(
 	}
 	postgresql["parameters"] = parameters
 
-	// Copy the "postgresql.pg_hba" section after any mandatory values.
-	hba := make([]string, 0, len(pgHBAs.Mandatory))
+	// Copy the "postgresql.pg_hba" section after any mandatory values and any
+	// rules specified in the cluster spec.
+	hba := make([]string, 0, len(pgHBAs.Mandatory)+len(pgHBAs.Specified))
 	for i := range pgHBAs.Mandatory {
 		hba = append(hba, pgHBAs.Mandatory[i].String())
 	}
+	for i := range pgHBAs.Specified {
+		hba = append(hba, pgHBAs.Specified[i].String())
+	}
 	if section, ok := postgresql["pg_hba"].([]interface{}); ok {
 		for i := range section {
 			// any pg_hba values that are not strings will be skipped
@@ -496,10 +517,23 @@ func instanceYAML(
 
 		"tags": map[string]interface{}{
 			// TODO(cbandy): "nofailover"
-			// TODO(cbandy): "nosync"
 		},
 	}
 
+	// Replicas of sets that are not listed never become synchronous standbys.
+	// - https://github.com/zalando/patroni/blob/v2.1.3/docs/SETTINGS.rst#tags
+	if cluster.Spec.Patroni != nil && cluster.Spec.Patroni.Synchronous != nil {
+		if sets := cluster.Spec.Patroni.Synchronous.InstanceSets; len(sets) > 0 {
+			eligible := false
+			for _, name := range sets {
+				eligible = eligible || name == instance.Name
+			}
+			if !eligible {
+				root["tags"].(map[string]interface{})["nosync"] = true
+			}
+		}
+	}
+
 	postgresql := map[string]interface{}{
 		// TODO(cbandy): "bin_dir"
 
@@ -573,10 +607,11 @@ func instanceYAML(
 		isDataSource := (cluster.Spec.DataSource != nil && cluster.Spec.DataSource.Volumes != nil &&
 			cluster.Spec.DataSource.Volumes.PGDataVolume != nil &&
 			cluster.Spec.DataSource.Volumes.PGDataVolume.Directory != "")
+		isUpgrade := (cluster.Status.PostgresUpgrade != nil && !cluster.Status.PostgresUpgrade.Finished)
 		// If the cluster is being bootstrapped using existing volumes, or if the cluster is being
-		// bootstrapped following a restore, then use the "existing"
+		// bootstrapped following a restore or major upgrade, then use the "existing"
 		// bootstrap method.  Otherwise use "initdb".
-		if isRestore || isDataSource {
+		if isRestore || isDataSource || isUpgrade {
 			data_dir := postgres.DataDirectory(cluster)
 			root["bootstrap"] = map[string]interface{}{
 				"method": "existing",
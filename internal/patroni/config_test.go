@@ -228,6 +228,60 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "synchronous: spec overrides input",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{
+						Synchronous: &v1beta1.PatroniSynchronousReplication{
+							Mode:         "Strict",
+							StandbyCount: newInt32(2),
+						},
+					},
+				},
+			},
+			input: map[string]interface{}{
+				"synchronous_mode":        false,
+				"synchronous_mode_strict": false,
+				"synchronous_node_count":  5,
+			},
+			expected: map[string]interface{}{
+				"loop_wait":               int32(10),
+				"ttl":                     int32(30),
+				"synchronous_mode":        true,
+				"synchronous_mode_strict": true,
+				"synchronous_node_count":  int32(2),
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "synchronous: defaults",
+			cluster: &v1beta1.PostgresCluster{
+				Spec: v1beta1.PostgresClusterSpec{
+					Patroni: &v1beta1.PatroniSpec{
+						Synchronous: &v1beta1.PatroniSynchronousReplication{},
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"loop_wait":               int32(10),
+				"ttl":                     int32(30),
+				"synchronous_mode":        true,
+				"synchronous_mode_strict": false,
+				"synchronous_node_count":  int32(1),
+				"postgresql": map[string]interface{}{
+					"parameters":    map[string]interface{}{},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
 		{
 			name: "postgresql: wrong-type is ignored",
 			input: map[string]interface{}{
@@ -336,6 +390,35 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "postgresql.parameters: specified overrides input",
+			input: map[string]interface{}{
+				"postgresql": map[string]interface{}{
+					"parameters": map[string]interface{}{
+						"something": "str",
+						"another":   5,
+					},
+				},
+			},
+			params: postgres.Parameters{
+				Specified: parameters(map[string]string{
+					"something": "overrides",
+				}),
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters": map[string]interface{}{
+						"something": "overrides",
+						"another":   5,
+					},
+					"pg_hba":        []string{},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
 		{
 			name: "postgresql.parameters: mandatory overrides input",
 			input: map[string]interface{}{
@@ -515,6 +598,62 @@ func TestDynamicConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "postgresql.pg_hba: specified after mandatory, before input",
+			input: map[string]interface{}{
+				"postgresql": map[string]interface{}{
+					"pg_hba": []interface{}{"custom"},
+				},
+			},
+			hbas: postgres.HBAs{
+				Mandatory: []postgres.HostBasedAuthentication{
+					*postgres.NewHBA().Local().Method("peer"),
+				},
+				Default: []postgres.HostBasedAuthentication{
+					*postgres.NewHBA().TLS().Method("md5"),
+				},
+				Specified: []postgres.HostBasedAuthentication{
+					*postgres.NewHBA().TLS().Users("app").Method("cert"),
+				},
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters": map[string]interface{}{},
+					"pg_hba": []string{
+						"local all all peer",
+						`hostssl all "app" all cert`,
+						"custom",
+					},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
+		{
+			name: "postgresql.pg_hba: no default when specified",
+			hbas: postgres.HBAs{
+				Default: []postgres.HostBasedAuthentication{
+					*postgres.NewHBA().TLS().Method("md5"),
+				},
+				Specified: []postgres.HostBasedAuthentication{
+					*postgres.NewHBA().TLS().Method("scram-sha-256"),
+				},
+			},
+			expected: map[string]interface{}{
+				"loop_wait": int32(10),
+				"ttl":       int32(30),
+				"postgresql": map[string]interface{}{
+					"parameters": map[string]interface{}{},
+					"pg_hba": []string{
+						"hostssl all all all scram-sha-256",
+					},
+					"use_pg_rewind": true,
+					"use_slots":     false,
+				},
+			},
+		},
 		{
 			name: "postgresql.pg_hba: ignore non-string types",
 			input: map[string]interface{}{
@@ -889,6 +1028,28 @@ postgresql:
 restapi: {}
 tags: {}
 	`, "\t\n")+"\n")
+
+	t.Run("Synchronous", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Patroni = &v1beta1.PatroniSpec{
+			Synchronous: &v1beta1.PatroniSynchronousReplication{},
+		}
+
+		data, err := instanceYAML(cluster, &v1beta1.PostgresInstanceSetSpec{Name: "a"}, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.HasSuffix(data, "\ntags: {}\n"),
+			"expected every set to be eligible by default")
+
+		cluster.Spec.Patroni.Synchronous.InstanceSets = []string{"a"}
+
+		data, err = instanceYAML(cluster, &v1beta1.PostgresInstanceSetSpec{Name: "a"}, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.HasSuffix(data, "\ntags: {}\n"))
+
+		data, err = instanceYAML(cluster, &v1beta1.PostgresInstanceSetSpec{Name: "b"}, nil)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.HasSuffix(data, "\ntags:\n  nosync: true\n"))
+	})
 }
 
 func TestPGBackRestCreateReplicaCommand(t *testing.T) {
@@ -34,11 +34,12 @@ type Executor func(
 
 // WriteUsersInPGAdmin uses exec and "python" to create users in pgAdmin and
 // update their passwords when they already exist. A blank password for a user
-// blocks that user from logging in to pgAdmin. The pgAdmin configuration
-// database must exist before calling this.
+// blocks that user from logging in to pgAdmin. The server connection of each
+// user logs into PostgreSQL as the role in logins, which defaults to the user.
+// The pgAdmin configuration database must exist before calling this.
 func WriteUsersInPGAdmin(
 	ctx context.Context, cluster *v1beta1.PostgresCluster, exec Executor,
-	users []v1beta1.PostgresUserSpec, passwords map[string]string,
+	users []v1beta1.PostgresUserSpec, passwords, logins map[string]string,
 ) error {
 	primary := naming.ClusterPrimaryService(cluster)
 
@@ -212,7 +213,7 @@ with create_app().app_context():`,
 		// pgAdmin v4.21.
 		// - https://github.com/pgadmin-org/pgadmin4/blob/REL-4_30/web/pgadmin/model/__init__.py#L108
 		`
-        server.username = data['username']
+        server.username = data.get('login', data['username'])
         server.password = encrypt(data['password'], data['password'])
         server.save_password = int(bool(data['password']))`,
 
@@ -247,11 +248,16 @@ with create_app().app_context():`,
 	for i := range users {
 		spec := users[i]
 
+		data := map[string]interface{}{
+			"username": spec.Name,
+			"password": passwords[string(spec.Name)],
+		}
+		if login := logins[string(spec.Name)]; login != "" && login != string(spec.Name) {
+			data["login"] = login
+		}
+
 		if err == nil {
-			err = encoder.Encode(map[string]interface{}{
-				"username": spec.Name,
-				"password": passwords[string(spec.Name)],
-			})
+			err = encoder.Encode(data)
 		}
 	}
 
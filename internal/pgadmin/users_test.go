@@ -147,7 +147,7 @@ with create_app().app_context():
         server.maintenance_db = "postgres"
         server.ssl_mode = "prefer"
 
-        server.username = data['username']
+        server.username = data.get('login', data['username'])
         server.password = encrypt(data['password'], data['password'])
         server.save_password = int(bool(data['password']))
 
@@ -167,7 +167,7 @@ with create_app().app_context():
 			return expected
 		}
 
-		assert.Equal(t, expected, WriteUsersInPGAdmin(ctx, cluster, exec, nil, nil))
+		assert.Equal(t, expected, WriteUsersInPGAdmin(ctx, cluster, exec, nil, nil, nil))
 	})
 
 	t.Run("Flake8", func(t *testing.T) {
@@ -198,7 +198,7 @@ with create_app().app_context():
 			return nil
 		}
 
-		_ = WriteUsersInPGAdmin(ctx, cluster, exec, nil, nil)
+		_ = WriteUsersInPGAdmin(ctx, cluster, exec, nil, nil, nil)
 		assert.Assert(t, called)
 	})
 
@@ -215,13 +215,13 @@ with create_app().app_context():
 			return nil
 		}
 
-		assert.NilError(t, WriteUsersInPGAdmin(ctx, cluster, exec, nil, nil))
+		assert.NilError(t, WriteUsersInPGAdmin(ctx, cluster, exec, nil, nil, nil))
 		assert.Equal(t, calls, 1)
 
-		assert.NilError(t, WriteUsersInPGAdmin(ctx, cluster, exec, []v1beta1.PostgresUserSpec{}, nil))
+		assert.NilError(t, WriteUsersInPGAdmin(ctx, cluster, exec, []v1beta1.PostgresUserSpec{}, nil, nil))
 		assert.Equal(t, calls, 2)
 
-		assert.NilError(t, WriteUsersInPGAdmin(ctx, cluster, exec, nil, map[string]string{}))
+		assert.NilError(t, WriteUsersInPGAdmin(ctx, cluster, exec, nil, map[string]string{}, nil))
 		assert.Equal(t, calls, 3)
 	})
 
@@ -237,7 +237,7 @@ with create_app().app_context():
 			assert.DeepEqual(t, string(b), strings.TrimLeft(`
 {"password":"","username":"user-no-options"}
 {"password":"","username":"user-no-databases"}
-{"password":"some$pass!word","username":"user-with-password"}
+{"login":"user-with-password_alt","password":"some$pass!word","username":"user-with-password"}
 `, "\n"))
 			return nil
 		}
@@ -260,6 +260,10 @@ with create_app().app_context():
 				"no-user":            "ignored",
 				"user-with-password": "some$pass!word",
 			},
+			map[string]string{
+				"user-no-options":    "user-no-options",
+				"user-with-password": "user-with-password_alt",
+			},
 		))
 		assert.Equal(t, calls, 1)
 	})
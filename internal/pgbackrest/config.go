@@ -178,38 +178,47 @@ func MakePGBackrestLogDir(template *corev1.PodTemplateSpec,
 //     This ensures compatibility with the "existing" bootstrap method that is included in the
 //     Patroni config when bootstrapping a cluster using an existing data directory.
 func RestoreCommand(pgdata string, args ...string) []string {
+	return append([]string{"bash", "-ceu", "--", restoreScript, "-", pgdata}, args...)
+}
 
-	// After pgBackRest restores files, PostgreSQL starts in recovery to finish
-	// replaying WAL files. "hot_standby" is "on" (by default) so we can detect
-	// when recovery has finished. In that mode, some parameters cannot be
-	// smaller than they were when PostgreSQL was backed up. Configure them to
-	// match the values reported by "pg_controldata". Those parameters are also
-	// written to WAL files and may change during recovery. When they increase,
-	// PostgreSQL exits and we reconfigure and restart it.
-	// For PG14, when some parameters from WAL require a restart, the behavior is
-	// to pause unless a restart is requested. For this edge case, we run a CASE
-	// query to check
-	// (a) if the instance is in recovery;
-	// (b) if so, if the WAL replay is paused;
-	// (c) if so, to unpause WAL replay, allowing our expected behavior to resume.
-	// A note on the PostgreSQL code: we cast `pg_catalog.pg_wal_replay_resume()` as text
-	// because that method returns a void (which is a non-NULL but empty result). When
-	// that void is cast as a string, it is an ''
-	// - https://www.postgresql.org/docs/current/hot-standby.html
-	// - https://www.postgresql.org/docs/current/app-pgcontroldata.html
-
-	// The postmaster.pid file is removed, if it exists, before attempting a restore.
-	// This allows the restore to be tried more than once without the causing an
-	// error due to the presence of the file in subsequent attempts.
-
-	// The 'pg_ctl' timeout is set to a very large value (1 year) to ensure there
-	// are no timeouts when starting or stopping Postgres.
-
-	const restoreScript = `declare -r pgdata="$1" opts="$2"
+// After pgBackRest restores files, PostgreSQL starts in recovery to finish
+// replaying WAL files. "hot_standby" is "on" (by default) so we can detect
+// when recovery has finished. In that mode, some parameters cannot be
+// smaller than they were when PostgreSQL was backed up. Configure them to
+// match the values reported by "pg_controldata". Those parameters are also
+// written to WAL files and may change during recovery. When they increase,
+// PostgreSQL exits and we reconfigure and restart it.
+// For PG14, when some parameters from WAL require a restart, the behavior is
+// to pause unless a restart is requested. For this edge case, we run a CASE
+// query to check
+// (a) if the instance is in recovery;
+// (b) if so, if the WAL replay is paused;
+// (c) if so, to unpause WAL replay, allowing our expected behavior to resume.
+// A note on the PostgreSQL code: we cast `pg_catalog.pg_wal_replay_resume()` as text
+// because that method returns a void (which is a non-NULL but empty result). When
+// that void is cast as a string, it is an ''
+// - https://www.postgresql.org/docs/current/hot-standby.html
+// - https://www.postgresql.org/docs/current/app-pgcontroldata.html
+
+// The postmaster.pid file is removed, if it exists, before attempting a restore.
+// This allows the restore to be tried more than once without the causing an
+// error due to the presence of the file in subsequent attempts.
+
+// The 'pg_ctl' timeout is set to a very large value (1 year) to ensure there
+// are no timeouts when starting or stopping Postgres.
+
+// restoreScript is the script run by RestoreCommand. It expects the data directory
+// and pgBackRest options as its first and second arguments.
+const restoreScript = `declare -r pgdata="$1" opts="$2"
 install --directory --mode=0700 "${pgdata}"
 rm -f "${pgdata}/postmaster.pid"
 bash -xc "pgbackrest restore ${opts}"
-rm -f "${pgdata}/patroni.dynamic.json"
+` + recoveryScript
+
+// recoveryScript is the part of restoreScript and snapshotRestoreScript that
+// starts PostgreSQL on restored files and waits for recovery to finish. It
+// expects "pgdata" to be declared already.
+const recoveryScript = `rm -f "${pgdata}/patroni.dynamic.json"
 export PGDATA="${pgdata}" PGHOST='/tmp'
 
 until [ "${recovery=}" = 'f' ]; do
@@ -248,7 +257,58 @@ done
 pg_ctl stop --silent --wait --timeout=31536000
 mv "${pgdata}" "${pgdata}_bootstrap"`
 
-	return append([]string{"bash", "-ceu", "--", restoreScript, "-", pgdata}, args...)
+// SnapshotRestoreCommand returns the command for preparing a data directory that
+// was restored from a VolumeSnapshot. The snapshot was taken while PostgreSQL was in
+// backup mode, so the script writes label to the "backup_label" file, discards any
+// WAL files that were captured with the data directory, and writes recovery, the
+// recovery settings, where PostgreSQL expects them. It then performs the same
+// recovery and renaming as RestoreCommand.
+func SnapshotRestoreCommand(pgdata, label, recovery string) []string {
+	const snapshotRestoreScript = `declare -r pgdata="$1" label="$2" recovery="$3"
+rm -f "${pgdata}/postmaster.pid" "${pgdata}/backup_label" "${pgdata}/recovery.conf" \
+  "${pgdata}/recovery.signal" "${pgdata}/standby.signal"
+printf '%s' "${label}" > "${pgdata}/backup_label"
+rm -rf "${pgdata}/pg_wal"
+install --directory --mode=0700 "${pgdata}/pg_wal"
+if [ "$(< "${pgdata}/PG_VERSION")" -ge 12 ]; then
+echo "${recovery}" >> "${pgdata}/postgresql.auto.conf"
+touch "${pgdata}/recovery.signal"
+else
+echo "${recovery}" > "${pgdata}/recovery.conf"
+fi
+`
+
+	return []string{"bash", "-ceu", "--", snapshotRestoreScript + recoveryScript,
+		"-", pgdata, label, recovery}
+}
+
+// VerifyCommand returns the command for checking that a pgBackRest backup can be
+// restored. It performs the restore of RestoreCommand, starts the restored database
+// again, and then:
+//   - Runs check, when it is not empty, in the "postgres" database. The command fails
+//     when check fails or when any row it returns is exactly "f".
+//   - Otherwise runs pg_amcheck against all databases on PostgreSQL 14 and newer, or
+//     connects to the "postgres" database on older versions.
+func VerifyCommand(pgdata, opts, check string) []string {
+	const verifyScript = `
+declare -r check="$3"
+pg_ctl start --silent --timeout=31536000 --wait --pgdata="${pgdata}_bootstrap" \
+  --options='--config-file=/tmp/postgres.restore.conf'
+
+if [ -n "${check}" ]; then
+result=$(psql -X --set=ON_ERROR_STOP=1 --no-align --tuples-only --dbname=postgres --command="${check}")
+echo "${result}"
+if grep -qx 'f' <<< "${result}"; then echo 'check returned false'; exit 1; fi
+elif [ "$(< "${pgdata}_bootstrap/PG_VERSION")" -ge 14 ]; then
+pg_amcheck --all --install-missing
+else
+psql -X --set=ON_ERROR_STOP=1 --dbname=postgres --command='SELECT 1'
+fi
+
+pg_ctl stop --silent --wait --timeout=31536000 --pgdata="${pgdata}_bootstrap"`
+
+	return []string{"bash", "-ceu", "--", restoreScript + "\n" + verifyScript,
+		"-", pgdata, opts, check}
 }
 
 // populatePGInstanceConfigurationMap returns options representing the pgBackRest configuration for
@@ -282,6 +342,10 @@ func populatePGInstanceConfigurationMap(
 			}
 		}
 
+		for option, val := range getRepoRetentionConfigs(repo) {
+			global.Set(option, val)
+		}
+
 		// Only "volume" (i.e. PVC-based) repos should ever have a repo host configured.  This
 		// means cloud-based repos (S3, GCS or Azure) should not have a repo host configured.
 		if repoHostName != "" && repo.Volume != nil {
@@ -334,6 +398,10 @@ func populateRepoHostConfigurationMap(
 			}
 		}
 
+		for option, val := range getRepoRetentionConfigs(repo) {
+			global.Set(option, val)
+		}
+
 		if !pgBackRestLogPathSet && repo.Volume != nil {
 			// pgBackRest will log to the first configured repo volume when commands
 			// are run on the pgBackRest repo host. With our previous check in
@@ -394,6 +462,33 @@ func getExternalRepoConfigs(repo v1beta1.PGBackRestRepo) map[string]string {
 	return repoConfigs
 }
 
+// getRepoRetentionConfigs returns a map containing the retention settings for a pgBackRest
+// repository as defined in the PostgresCluster spec
+func getRepoRetentionConfigs(repo v1beta1.PGBackRestRepo) map[string]string {
+
+	repoConfigs := make(map[string]string)
+
+	if retention := repo.Retention; retention != nil {
+		if retention.Full != nil {
+			repoConfigs[repo.Name+"-retention-full"] = fmt.Sprint(*retention.Full)
+		}
+		if retention.FullType != "" {
+			repoConfigs[repo.Name+"-retention-full-type"] = retention.FullType
+		}
+		if retention.Differential != nil {
+			repoConfigs[repo.Name+"-retention-diff"] = fmt.Sprint(*retention.Differential)
+		}
+		if retention.Archive != nil {
+			repoConfigs[repo.Name+"-retention-archive"] = fmt.Sprint(*retention.Archive)
+		}
+		if retention.ArchiveType != "" {
+			repoConfigs[repo.Name+"-retention-archive-type"] = retention.ArchiveType
+		}
+	}
+
+	return repoConfigs
+}
+
 // reloadCommand returns an entrypoint that convinces the pgBackRest TLS server
 // to reload its options and certificate files when they change. The process
 // will appear as name in `ps` and `top`.
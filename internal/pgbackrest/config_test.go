@@ -159,6 +159,47 @@ pg1-socket-path = /tmp/postgres
 		`, "\t\n")+"\n")
 	})
 
+	t.Run("Retention", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backups.PGBackRest.Global = map[string]string{
+			"repo2-retention-diff": "5",
+		}
+		cluster.Spec.Backups.PGBackRest.Repos = []v1beta1.PGBackRestRepo{
+			{
+				Name:   "repo1",
+				Volume: &v1beta1.RepoPVC{},
+				Retention: &v1beta1.PGBackRestRetention{
+					Full: initialize.Int32(14), FullType: "time",
+					Archive: initialize.Int32(2), ArchiveType: "diff",
+				},
+			},
+			{
+				Name: "repo2",
+				GCS:  &v1beta1.RepoGCS{Bucket: "g-bucket"},
+				Retention: &v1beta1.PGBackRestRetention{
+					Full: initialize.Int32(3), Differential: initialize.Int32(2),
+				},
+			},
+		}
+
+		configmap := CreatePGBackRestConfigMapIntent(cluster,
+			"repo-hostname", "abcde12345", "pod-service-name", "test-ns",
+			[]string{"some-instance"})
+
+		for _, key := range []string{"pgbackrest_repo.conf", "pgbackrest_instance.conf"} {
+			config := configmap.Data[key]
+			assert.Assert(t, strings.Contains(config, "\nrepo1-retention-archive = 2\n"), key)
+			assert.Assert(t, strings.Contains(config, "\nrepo1-retention-archive-type = diff\n"), key)
+			assert.Assert(t, strings.Contains(config, "\nrepo1-retention-full = 14\n"), key)
+			assert.Assert(t, strings.Contains(config, "\nrepo1-retention-full-type = time\n"), key)
+			assert.Assert(t, strings.Contains(config, "\nrepo2-retention-full = 3\n"), key)
+
+			// options in global take precedence
+			assert.Assert(t, strings.Contains(config, "\nrepo2-retention-diff = 5\n"), key)
+			assert.Assert(t, !strings.Contains(config, "repo2-retention-diff = 2"), key)
+		}
+	})
+
 	t.Run("CustomMetadata", func(t *testing.T) {
 		cluster := cluster.DeepCopy()
 		cluster.Spec.Metadata = &v1beta1.Metadata{
@@ -318,6 +359,46 @@ func TestRestoreCommandPrettyYAML(t *testing.T) {
 		"expected literal block scalar, got:\n%s", b)
 }
 
+func TestSnapshotRestoreCommand(t *testing.T) {
+	shellcheck := require.ShellCheck(t)
+
+	pgdata := "/pgdata/pg14"
+	label := "START WAL LOCATION: 0/2000028 (file 000000010000000000000002)\n"
+	recovery := "restore_command = 'pgbackrest --stanza=db archive-get %f \"%p\"'"
+	command := SnapshotRestoreCommand(pgdata, label, recovery)
+
+	assert.DeepEqual(t, command[:3], []string{"bash", "-ceu", "--"})
+	assert.DeepEqual(t, command[4:], []string{"-", pgdata, label, recovery})
+	assert.Assert(t, strings.HasSuffix(command[3], recoveryScript))
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "script.bash")
+	assert.NilError(t, os.WriteFile(file, []byte(command[3]), 0o600))
+
+	cmd := exec.Command(shellcheck, "--enable=all", file)
+	output, err := cmd.CombinedOutput()
+	assert.NilError(t, err, "%q\n%s", cmd.Args, output)
+}
+
+func TestVerifyCommand(t *testing.T) {
+	shellcheck := require.ShellCheck(t)
+
+	pgdata := "/pgdata/pg14"
+	command := VerifyCommand(pgdata, "--stanza=db --repo=1", "SELECT true")
+
+	assert.DeepEqual(t, command[:3], []string{"bash", "-ceu", "--"})
+	assert.DeepEqual(t, command[4:], []string{"-", pgdata, "--stanza=db --repo=1", "SELECT true"})
+	assert.Assert(t, strings.HasPrefix(command[3], restoreScript))
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "script.bash")
+	assert.NilError(t, os.WriteFile(file, []byte(command[3]), 0o600))
+
+	cmd := exec.Command(shellcheck, "--enable=all", file)
+	output, err := cmd.CombinedOutput()
+	assert.NilError(t, err, "%q\n%s", cmd.Args, output)
+}
+
 func TestServerConfig(t *testing.T) {
 	cluster := &v1beta1.PostgresCluster{}
 	cluster.UID = "shoe"
@@ -18,9 +18,13 @@ package pgbackrest
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -98,3 +102,245 @@ fi
 
 	return false, nil
 }
+
+// InfoOutput is the JSON output of the pgBackRest "info" command, one entry per
+// stanza.
+// - https://pgbackrest.org/command.html#command-info
+type InfoOutput []InfoStanza
+
+// InfoStanza describes the archives and backups of one stanza in a repository.
+type InfoStanza struct {
+	Name    string        `json:"name"`
+	Archive []InfoArchive `json:"archive"`
+	Backup  []InfoBackup  `json:"backup"`
+	Status  InfoStatus    `json:"status"`
+}
+
+// InfoArchive describes the range of WAL archived for one database.
+type InfoArchive struct {
+	ID  string `json:"id"`
+	Max string `json:"max"`
+	Min string `json:"min"`
+}
+
+// InfoBackup describes one backup in a repository.
+type InfoBackup struct {
+	Label string `json:"label"`
+	Type  string `json:"type"`
+
+	Archive struct {
+		Start string `json:"start"`
+		Stop  string `json:"stop"`
+	} `json:"archive"`
+
+	Info struct {
+		// Size is the size of the database in bytes.
+		Size int64 `json:"size"`
+
+		// Delta is the amount of the database in bytes that was copied.
+		Delta int64 `json:"delta"`
+
+		Repository struct {
+			// Size is the size of the backup in the repository in bytes.
+			Size int64 `json:"size"`
+
+			// Delta is the amount of the repository in bytes used by this
+			// backup alone.
+			Delta int64 `json:"delta"`
+		} `json:"repository"`
+	} `json:"info"`
+
+	LSN struct {
+		// Start and Stop are write-ahead log locations, e.g. "0/3000028".
+		Start string `json:"start"`
+		Stop  string `json:"stop"`
+	} `json:"lsn"`
+
+	Timestamp struct {
+		// Start and Stop are seconds since the Unix epoch.
+		Start int64 `json:"start"`
+		Stop  int64 `json:"stop"`
+	} `json:"timestamp"`
+}
+
+// ArchiveEnd returns the write-ahead log location just past the newest WAL
+// segment archived for stanza. It returns false when there is no archive.
+func (stanza InfoStanza) ArchiveEnd() (uint64, bool) {
+	size := stanza.walSegmentSize()
+
+	var end uint64
+	for _, archive := range stanza.Archive {
+		if log, seg, ok := walSegmentPosition(archive.Max); ok {
+			if position := log<<32 + (seg+1)*size; position > end {
+				end = position
+			}
+		}
+	}
+	return end, end > 0
+}
+
+// ArchiveMax returns the newest WAL segment archived for stanza and the ID of
+// the archive that holds it. WAL file names sort in the order they are written.
+func (stanza InfoStanza) ArchiveMax() (archiveID, wal string) {
+	for _, archive := range stanza.Archive {
+		if archive.Max > wal {
+			archiveID, wal = archive.ID, archive.Max
+		}
+	}
+	return
+}
+
+// walSegmentSize returns the size of the WAL segments in stanza. It is not
+// reported by pgBackRest, so this finds the size that places the end of every
+// backup in its last archived segment. PostgreSQL allows powers of two from
+// 1MiB to 1GiB, and the default is 16MiB.
+// - https://www.postgresql.org/docs/current/app-initdb.html
+func (stanza InfoStanza) walSegmentSize() uint64 {
+	const defaultSize = 16 << 20
+
+	var sizes []uint64
+	for size := uint64(1 << 20); size <= 1<<30; size <<= 1 {
+		consistent := true
+		for _, backup := range stanza.Backup {
+			lsn, err := ParseLSN(backup.LSN.Stop)
+			log, seg, ok := walSegmentPosition(backup.Archive.Stop)
+			if err == nil && ok && (lsn>>32 != log || (lsn&0xFFFFFFFF)/size != seg) {
+				consistent = false
+			}
+		}
+		if consistent && size == defaultSize {
+			return size
+		}
+		if consistent {
+			sizes = append(sizes, size)
+		}
+	}
+	if len(sizes) == 0 {
+		return defaultSize
+	}
+	return sizes[0]
+}
+
+// walSegmentPosition returns the log and segment numbers in the name of a WAL
+// segment file, e.g. "000000010000000A000000FF". The first eight digits are
+// the timeline and do not affect the position.
+func walSegmentPosition(name string) (log, seg uint64, ok bool) {
+	if len(name) != 24 {
+		return 0, 0, false
+	}
+	log, err1 := strconv.ParseUint(name[8:16], 16, 32)
+	seg, err2 := strconv.ParseUint(name[16:24], 16, 32)
+	return log, seg, err1 == nil && err2 == nil
+}
+
+// ParseLSN returns the numeric value of a PostgreSQL write-ahead log location
+// in its text form, e.g. "16/B374D848".
+func ParseLSN(lsn string) (uint64, error) {
+	parts := strings.SplitN(lsn, "/", 2)
+	if len(parts) == 2 {
+		high, err1 := strconv.ParseUint(parts[0], 16, 32)
+		low, err2 := strconv.ParseUint(parts[1], 16, 32)
+		if err1 == nil && err2 == nil {
+			return high<<32 | low, nil
+		}
+	}
+	return 0, errors.Errorf("invalid LSN %q", lsn)
+}
+
+// FormatLSN returns the text form of a PostgreSQL write-ahead log location.
+func FormatLSN(lsn uint64) string {
+	return fmt.Sprintf("%X/%X", lsn>>32, lsn&0xFFFFFFFF)
+}
+
+// InfoStatus is the health of a stanza as reported by pgBackRest.
+type InfoStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Info runs the pgBackRest "info" command against the repo at repoIndex and
+// returns its parsed output.
+func (exec Executor) Info(ctx context.Context, repoIndex string) (InfoOutput, error) {
+	var stdout, stderr bytes.Buffer
+	var output InfoOutput
+
+	err := exec(ctx, nil, &stdout, &stderr, "pgbackrest", "info",
+		"--output=json", "--stanza="+DefaultStanzaName, "--repo="+repoIndex)
+
+	if err != nil {
+		return nil, errors.WithStack(fmt.Errorf("%w: %v", err, stderr.String()))
+	}
+	if err = json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return output, nil
+}
+
+// ArchiveTime runs the pgBackRest "repo-ls" command against the repo at
+// repoIndex and returns when the WAL segment named wal was archived in the
+// archive identified by archiveID. It returns the zero time when the segment
+// is not in the repo.
+// - https://pgbackrest.org/command.html#command-repo-ls
+func (exec Executor) ArchiveTime(
+	ctx context.Context, repoIndex, archiveID, wal string,
+) (time.Time, error) {
+	var stdout, stderr bytes.Buffer
+	var output map[string]struct {
+		Type string `json:"type"`
+		Time int64  `json:"time"`
+	}
+
+	if _, _, ok := walSegmentPosition(wal); !ok {
+		return time.Time{}, errors.Errorf("invalid WAL segment %q", wal)
+	}
+
+	// Archived segments are in directories named by their first sixteen
+	// digits. Their file names also have a checksum and compression suffix.
+	err := exec(ctx, nil, &stdout, &stderr, "pgbackrest", "repo-ls",
+		"--output=json", "--repo="+repoIndex, "--filter=^"+wal,
+		path.Join("archive", DefaultStanzaName, archiveID, wal[:16]))
+
+	if err != nil {
+		return time.Time{}, errors.WithStack(fmt.Errorf("%w: %v", err, stderr.String()))
+	}
+	if err = json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return time.Time{}, errors.WithStack(err)
+	}
+
+	var archived time.Time
+	for _, file := range output {
+		if t := time.Unix(file.Time, 0); file.Type == "file" && t.After(archived) {
+			archived = t
+		}
+	}
+	return archived, nil
+}
+
+// RepoVolumeUsage reports the capacity, in bytes, of the filesystem holding the
+// repo named repoName and how much of it is used. It must run where the repo
+// volume is mounted, e.g. the dedicated repository host.
+func (exec Executor) RepoVolumeUsage(
+	ctx context.Context, repoName string,
+) (used, capacity int64, err error) {
+	var stdout, stderr bytes.Buffer
+
+	// The POSIX output format is a header followed by one line per filesystem:
+	// name, total 1024-byte blocks, used blocks, available blocks, capacity,
+	// and mount point.
+	// - https://pubs.opengroup.org/onlinepubs/9699919799/utilities/df.html
+	err = exec(ctx, nil, &stdout, &stderr, "df", "-P", "-k", defaultRepo1Path+repoName)
+	if err != nil {
+		return 0, 0, errors.WithStack(fmt.Errorf("%w: %v", err, stderr.String()))
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) < 6 {
+		return 0, 0, errors.Errorf("unexpected output from df: %q", stdout.String())
+	}
+
+	if capacity, err = strconv.ParseInt(fields[1], 10, 64); err == nil {
+		used, err = strconv.ParseInt(fields[2], 10, 64)
+	}
+	return used * 1024, capacity * 1024, errors.WithStack(err)
+}
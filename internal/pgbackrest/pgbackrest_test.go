@@ -17,6 +17,7 @@ package pgbackrest
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
 	"os/exec"
@@ -72,3 +73,234 @@ fi
 	output, err := cmd.CombinedOutput()
 	assert.NilError(t, err, "%q\n%s", cmd.Args, output)
 }
+
+func TestInfo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdin == nil)
+			assert.DeepEqual(t, command, []string{
+				"pgbackrest", "info", "--output=json", "--stanza=db", "--repo=2",
+			})
+			_, _ = stderr.Write([]byte("some message"))
+			return expected
+		}
+
+		_, err := Executor(exec).Info(ctx, "2")
+		assert.ErrorIs(t, err, expected)
+		assert.ErrorContains(t, err, "some message")
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`[{
+				"archive": [{"id": "14-1", "max": "000000010000000000000006", "min": "000000010000000000000001"}],
+				"backup": [{
+					"archive": {"start": "000000010000000000000003", "stop": "000000010000000000000003"},
+					"info": {"delta": 25310976, "repository": {"delta": 3164393, "size": 3164393}, "size": 25310976},
+					"label": "20220601-161839F",
+					"lsn": {"start": "0/3000028", "stop": "0/3000100"},
+					"timestamp": {"start": 1654100319, "stop": 1654100325},
+					"type": "full"
+				}],
+				"name": "db",
+				"status": {"code": 0, "message": "ok"}
+			}]`))
+			return nil
+		}
+
+		output, err := Executor(exec).Info(ctx, "1")
+		assert.NilError(t, err)
+		assert.Equal(t, len(output), 1)
+
+		stanza := output[0]
+		assert.Equal(t, stanza.Name, "db")
+		assert.Equal(t, stanza.Status.Message, "ok")
+		assert.Equal(t, len(stanza.Archive), 1)
+		assert.Equal(t, stanza.Archive[0].Min, "000000010000000000000001")
+		assert.Equal(t, stanza.Archive[0].Max, "000000010000000000000006")
+		assert.Equal(t, len(stanza.Backup), 1)
+
+		backup := stanza.Backup[0]
+		assert.Equal(t, backup.Label, "20220601-161839F")
+		assert.Equal(t, backup.Type, "full")
+		assert.Equal(t, backup.Archive.Start, "000000010000000000000003")
+		assert.Equal(t, backup.Info.Size, int64(25310976))
+		assert.Equal(t, backup.Info.Repository.Size, int64(3164393))
+		assert.Equal(t, backup.LSN.Stop, "0/3000100")
+		assert.Equal(t, backup.Timestamp.Stop, int64(1654100325))
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("not json"))
+			return nil
+		}
+
+		_, err := Executor(exec).Info(ctx, "1")
+		assert.Assert(t, err != nil)
+	})
+}
+
+func TestInfoStanzaArchiveEnd(t *testing.T) {
+	var stanza InfoStanza
+	_, ok := stanza.ArchiveEnd()
+	assert.Assert(t, !ok)
+
+	stanza.Archive = []InfoArchive{
+		{ID: "13-1", Max: "00000003000000010000002F"},
+		{ID: "14-2", Max: "000000010000000100000030"},
+	}
+	end, ok := stanza.ArchiveEnd()
+	assert.Assert(t, ok)
+	assert.Equal(t, FormatLSN(end), "1/31000000", "default segment size")
+
+	// The segment size comes from where backups stop.
+	stanza.Backup = make([]InfoBackup, 1)
+	stanza.Backup[0].LSN.Stop = "1/6000028"
+	stanza.Backup[0].Archive.Stop = "000000010000000100000003"
+	end, ok = stanza.ArchiveEnd()
+	assert.Assert(t, ok)
+	assert.Equal(t, FormatLSN(end), "1/62000000", "32MiB segments")
+}
+
+func TestInfoStanzaArchiveMax(t *testing.T) {
+	id, wal := InfoStanza{}.ArchiveMax()
+	assert.Equal(t, id, "")
+	assert.Equal(t, wal, "")
+
+	id, wal = InfoStanza{Archive: []InfoArchive{
+		{ID: "13-1", Max: "000000010000000000000009"},
+		{ID: "14-2", Max: "00000002000000000000000C"},
+		{ID: "13-3", Max: "000000010000000000000005"},
+	}}.ArchiveMax()
+	assert.Equal(t, id, "14-2")
+	assert.Equal(t, wal, "00000002000000000000000C")
+}
+
+func TestParseLSN(t *testing.T) {
+	lsn, err := ParseLSN("16/B374D848")
+	assert.NilError(t, err)
+	assert.Equal(t, lsn, uint64(0x16B374D848))
+	assert.Equal(t, FormatLSN(lsn), "16/B374D848")
+
+	for _, invalid := range []string{"", "0", "x/1", "1/2/3", "100000000/0"} {
+		_, err := ParseLSN(invalid)
+		assert.ErrorContains(t, err, "invalid LSN", "%q", invalid)
+	}
+}
+
+func TestArchiveTime(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdin == nil)
+			assert.DeepEqual(t, command, []string{
+				"pgbackrest", "repo-ls", "--output=json", "--repo=2",
+				"--filter=^00000002000000010000000C", "archive/db/14-2/0000000200000001",
+			})
+			_, _ = stderr.Write([]byte("some message"))
+			return expected
+		}
+
+		_, err := Executor(exec).ArchiveTime(ctx, "2", "14-2", "00000002000000010000000C")
+		assert.ErrorIs(t, err, expected)
+		assert.ErrorContains(t, err, "some message")
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`{
+				".": {"type": "path"},
+				"00000001000000000000000C-0f1e2d3c4b5a69788796a5b4c3d2e1f0f1e2d3c4.gz": {
+					"type": "file", "size": 1234, "time": 1654101500
+				}
+			}`))
+			return nil
+		}
+
+		archived, err := Executor(exec).ArchiveTime(ctx, "1", "14-1", "00000001000000000000000C")
+		assert.NilError(t, err)
+		assert.Equal(t, archived.Unix(), int64(1654101500))
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(`{".": {"type": "path"}}`))
+			return nil
+		}
+
+		archived, err := Executor(exec).ArchiveTime(ctx, "1", "14-1", "00000001000000000000000C")
+		assert.NilError(t, err)
+		assert.Assert(t, archived.IsZero())
+	})
+
+	t.Run("InvalidSegment", func(t *testing.T) {
+		_, err := Executor(nil).ArchiveTime(ctx, "1", "14-1", "nope")
+		assert.ErrorContains(t, err, "invalid WAL segment")
+	})
+}
+
+func TestRepoVolumeUsage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdin == nil)
+			assert.DeepEqual(t, command, []string{"df", "-P", "-k", "/pgbackrest/repo2"})
+			_, _ = stderr.Write([]byte("some message"))
+			return expected
+		}
+
+		_, _, err := Executor(exec).RepoVolumeUsage(ctx, "repo2")
+		assert.ErrorIs(t, err, expected)
+		assert.ErrorContains(t, err, "some message")
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte(
+				"Filesystem     1024-blocks    Used Available Capacity Mounted on\n" +
+					"/dev/nvme1n1       1024000  768000    256000      75% /pgbackrest/repo1\n"))
+			return nil
+		}
+
+		used, capacity, err := Executor(exec).RepoVolumeUsage(ctx, "repo1")
+		assert.NilError(t, err)
+		assert.Equal(t, used, int64(786432000))
+		assert.Equal(t, capacity, int64(1048576000))
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, stdout, _ io.Writer, _ ...string,
+		) error {
+			_, _ = stdout.Write([]byte("nope"))
+			return nil
+		}
+
+		_, _, err := Executor(exec).RepoVolumeUsage(ctx, "repo1")
+		assert.ErrorContains(t, err, "unexpected output")
+	})
+}
@@ -0,0 +1,112 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// Executor provides methods for calling "psql" in a PgBouncer container.
+type Executor func(
+	ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+) error
+
+// console uses "psql" to send commands to the PgBouncer admin console as the
+// admin user. The password in inSecret is passed via stdin so that it does not
+// appear in the process list. When stopOnError is false, failed commands are
+// logged but do not cause an error. When timeout is positive, "psql" is
+// interrupted after that long.
+// - https://www.pgbouncer.org/usage.html#admin-console
+func (exec Executor) console(
+	ctx context.Context, inCluster *v1beta1.PostgresCluster, inSecret *corev1.Secret,
+	timeout time.Duration, stopOnError bool, commands ...string,
+) error {
+	// Read the password from the first line of stdin then execute `psql`
+	// without reading config files nor prompting for a password. PgBouncer
+	// requires TLS, but its certificate does not include "localhost".
+	const script = `IFS= read -r -t 5 PGPASSWORD && export PGPASSWORD && exec "$@"`
+
+	connection := fmt.Sprintf("host=localhost port=%d dbname=%s user=%s sslmode=require",
+		*inCluster.Spec.Proxy.PGBouncer.Port, adminDatabase, adminUser)
+
+	command := []string{"bash", "-ceu", "--", script, "-"}
+	if timeout > 0 {
+		// Coreutils `timeout` exits with status 124 when the time runs out.
+		command = append(command, "timeout", fmt.Sprintf("%.3fs", timeout.Seconds()))
+	}
+	command = append(command, "psql", "-Xw", "--file=-")
+	if stopOnError {
+		command = append(command, "--set=ON_ERROR_STOP=1")
+	}
+	command = append(command, connection)
+
+	var stdin strings.Builder
+	stdin.WriteString(string(inSecret.Data[adminPasswordSecretKey]) + "\n")
+	for _, c := range commands {
+		stdin.WriteString(c + ";\n")
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := exec(ctx, strings.NewReader(stdin.String()), &stdout, &stderr, command...)
+
+	log := logging.FromContext(ctx)
+	log.V(1).Info("sent commands to PgBouncer",
+		"commands", commands, "stdout", stdout.String(), "stderr", stderr.String())
+
+	return err
+}
+
+// Pause calls "PAUSE" in the PgBouncer admin console. It returns when every
+// server connection has been released to its pool, which may be much later in
+// "session" pooling mode, or returns an error after timeout. Clients wait
+// until [Executor.Resume] is called, even after an error.
+// - https://www.pgbouncer.org/usage.html#pause-db
+func (exec Executor) Pause(
+	ctx context.Context, inCluster *v1beta1.PostgresCluster, inSecret *corev1.Secret,
+	timeout time.Duration,
+) error {
+	return exec.console(ctx, inCluster, inSecret, timeout, true, "PAUSE")
+}
+
+// Reconnect calls "RECONNECT" in the PgBouncer admin console so that server
+// connections close as soon as they are released. New server connections
+// resolve the Service of the PostgreSQL primary again.
+// - https://www.pgbouncer.org/usage.html#reconnect-db
+func (exec Executor) Reconnect(
+	ctx context.Context, inCluster *v1beta1.PostgresCluster, inSecret *corev1.Secret,
+) error {
+	return exec.console(ctx, inCluster, inSecret, 0, true, "RECONNECT")
+}
+
+// Resume calls "RECONNECT" and "RESUME" in the PgBouncer admin console so that
+// clients continue using new server connections. PgBouncer reports an error
+// when it is not paused, so the commands do not stop on errors.
+// - https://www.pgbouncer.org/usage.html#resume-db
+func (exec Executor) Resume(
+	ctx context.Context, inCluster *v1beta1.PostgresCluster, inSecret *corev1.Secret,
+) error {
+	return exec.console(ctx, inCluster, inSecret, 0, false, "RECONNECT", "RESUME")
+}
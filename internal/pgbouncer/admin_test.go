@@ -0,0 +1,114 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestExecutorConsole(t *testing.T) {
+	ctx := context.Background()
+	expected := errors.New("pass-through")
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.Proxy = new(v1beta1.PostgresProxySpec)
+	cluster.Spec.Proxy.PGBouncer = new(v1beta1.PGBouncerPodSpec)
+	cluster.Default()
+
+	secret := &corev1.Secret{Data: map[string][]byte{
+		"pgbouncer-admin-password": []byte("some-password"),
+	}}
+
+	// record returns an Executor that stores its command and stdin.
+	record := func(command *[]string, stdin *string) Executor {
+		return func(
+			_ context.Context, in io.Reader, stdout, stderr io.Writer, args ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+
+			b, err := io.ReadAll(in)
+			assert.NilError(t, err)
+
+			*command, *stdin = args, string(b)
+			return expected
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		call    func(Executor) error
+		stdin   string
+		stopped bool
+	}{
+		{
+			name:    "Pause",
+			call:    func(exec Executor) error { return exec.Pause(ctx, cluster, secret, 1500*time.Millisecond) },
+			stdin:   "some-password\nPAUSE;\n",
+			stopped: true,
+		},
+		{
+			name:    "Reconnect",
+			call:    func(exec Executor) error { return exec.Reconnect(ctx, cluster, secret) },
+			stdin:   "some-password\nRECONNECT;\n",
+			stopped: true,
+		},
+		{
+			name:  "Resume",
+			call:  func(exec Executor) error { return exec.Resume(ctx, cluster, secret) },
+			stdin: "some-password\nRECONNECT;\nRESUME;\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var command []string
+			var stdin string
+
+			assert.Equal(t, expected, tt.call(record(&command, &stdin)))
+			assert.Equal(t, stdin, tt.stdin)
+
+			// The password is not an argument.
+			joined := strings.Join(command, " ")
+			assert.Assert(t, !strings.Contains(joined, "some-password"))
+
+			assert.Assert(t, strings.Contains(joined, "psql -Xw --file=-"), joined)
+			assert.Equal(t, command[len(command)-1],
+				"host=localhost port=5432 dbname=pgbouncer user=pgbouncer_admin sslmode=require")
+			assert.Equal(t, strings.Contains(joined, "--set=ON_ERROR_STOP=1"), tt.stopped)
+		})
+	}
+
+	t.Run("Timeout", func(t *testing.T) {
+		var command []string
+		var stdin string
+
+		_ = record(&command, &stdin).Pause(ctx, cluster, secret, 1500*time.Millisecond)
+		assert.Assert(t, strings.Contains(strings.Join(command, " "),
+			" timeout 1.500s psql "), "got %q", command)
+
+		_ = record(&command, &stdin).Resume(ctx, cluster, secret)
+		assert.Assert(t, !strings.Contains(strings.Join(command, " "), "timeout"))
+	})
+}
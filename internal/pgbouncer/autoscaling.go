@@ -0,0 +1,84 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+const (
+	// The exporter reports the "cl_active" and "cl_waiting" columns of
+	// SHOW POOLS as these gauges, one series per pool.
+	// - https://github.com/prometheus-community/pgbouncer_exporter
+	activeClientsMetric  = "pgbouncer_pools_client_active_connections"
+	waitingClientsMetric = "pgbouncer_pools_client_waiting_connections"
+)
+
+// AutoscalerMetrics returns the per-pod metrics that scale the PgBouncer pods
+// of inCluster, one for each target in its autoscaling spec.
+func AutoscalerMetrics(inCluster *v1beta1.PostgresCluster) []autoscalingv2.MetricSpec {
+	spec := inCluster.Spec.Proxy.PGBouncer.Autoscaling
+
+	metric := func(name string, target int32) autoscalingv2.MetricSpec {
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: name},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(int64(target), resource.DecimalSI),
+				},
+			},
+		}
+	}
+
+	var metrics []autoscalingv2.MetricSpec
+	if spec.TargetClientConnections != nil {
+		metrics = append(metrics, metric(activeClientsMetric, *spec.TargetClientConnections))
+	}
+	if spec.TargetWaitingClients != nil {
+		metrics = append(metrics, metric(waitingClientsMetric, *spec.TargetWaitingClients))
+	}
+	return metrics
+}
+
+// ValidateAutoscaling returns an error describing why the autoscaling spec of
+// cluster cannot be used. It returns nil when autoscaling is not specified.
+func ValidateAutoscaling(cluster *v1beta1.PostgresCluster) error {
+	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil ||
+		cluster.Spec.Proxy.PGBouncer.Autoscaling == nil {
+		return nil
+	}
+
+	spec := cluster.Spec.Proxy.PGBouncer.Autoscaling
+
+	switch {
+	case spec.TargetClientConnections == nil && spec.TargetWaitingClients == nil:
+		return errors.New("at least one target is required")
+	case spec.MinReplicas != nil && *spec.MinReplicas > spec.MaxReplicas:
+		return fmt.Errorf("minReplicas (%d) is greater than maxReplicas (%d)",
+			*spec.MinReplicas, spec.MaxReplicas)
+	case !ExporterEnabled(cluster):
+		return errors.New("the exporter is required; set monitoring.exporter")
+	}
+	return nil
+}
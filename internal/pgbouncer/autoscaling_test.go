@@ -0,0 +1,87 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/initialize"
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestAutoscalerMetrics(t *testing.T) {
+	t.Parallel()
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.Proxy = new(v1beta1.PostgresProxySpec)
+	cluster.Spec.Proxy.PGBouncer = new(v1beta1.PGBouncerPodSpec)
+	cluster.Spec.Proxy.PGBouncer.Autoscaling = &v1beta1.PGBouncerAutoscalingSpec{
+		MaxReplicas: 5,
+	}
+
+	assert.Assert(t, AutoscalerMetrics(cluster) == nil)
+
+	cluster.Spec.Proxy.PGBouncer.Autoscaling.TargetClientConnections = initialize.Int32(100)
+	cluster.Spec.Proxy.PGBouncer.Autoscaling.TargetWaitingClients = initialize.Int32(2)
+
+	assert.Assert(t, cmp.MarshalMatches(AutoscalerMetrics(cluster), `
+- pods:
+    metric:
+      name: pgbouncer_pools_client_active_connections
+    target:
+      averageValue: "100"
+      type: AverageValue
+  type: Pods
+- pods:
+    metric:
+      name: pgbouncer_pools_client_waiting_connections
+    target:
+      averageValue: "2"
+      type: AverageValue
+  type: Pods
+	`))
+}
+
+func TestValidateAutoscaling(t *testing.T) {
+	t.Parallel()
+
+	cluster := new(v1beta1.PostgresCluster)
+	assert.NilError(t, ValidateAutoscaling(cluster))
+
+	cluster.Spec.Proxy = new(v1beta1.PostgresProxySpec)
+	cluster.Spec.Proxy.PGBouncer = new(v1beta1.PGBouncerPodSpec)
+	assert.NilError(t, ValidateAutoscaling(cluster))
+
+	cluster.Spec.Proxy.PGBouncer.Autoscaling = &v1beta1.PGBouncerAutoscalingSpec{
+		MinReplicas: initialize.Int32(3),
+		MaxReplicas: 2,
+	}
+	assert.ErrorContains(t, ValidateAutoscaling(cluster), "at least one target")
+
+	cluster.Spec.Proxy.PGBouncer.Autoscaling.TargetWaitingClients = initialize.Int32(1)
+	assert.ErrorContains(t, ValidateAutoscaling(cluster), "minReplicas (3) is greater")
+
+	cluster.Spec.Proxy.PGBouncer.Autoscaling.MaxReplicas = 3
+	assert.ErrorContains(t, ValidateAutoscaling(cluster), "exporter is required")
+
+	cluster.Spec.Proxy.PGBouncer.Monitoring = &v1beta1.PGBouncerMonitoringSpec{
+		Exporter: &v1beta1.PGBouncerExporterSpec{},
+	}
+	assert.NilError(t, ValidateAutoscaling(cluster))
+}
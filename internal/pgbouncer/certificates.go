@@ -38,6 +38,9 @@ const (
 	certFrontendAuthoritySecretKey  = "pgbouncer-frontend.ca-roots"
 	certFrontendPrivateKeySecretKey = "pgbouncer-frontend.key"
 	certFrontendSecretKey           = "pgbouncer-frontend.crt"
+
+	certFrontendReadOnlyPrivateKeySecretKey = "pgbouncer-frontend-ro.key"
+	certFrontendReadOnlySecretKey           = "pgbouncer-frontend-ro.crt"
 )
 
 // backendAuthority creates a volume projection of the PostgreSQL server
@@ -66,10 +69,16 @@ func backendAuthority(postgres *corev1.SecretProjection) corev1.VolumeProjection
 }
 
 // frontendCertificate creates a volume projection of the PgBouncer certificate.
+// When readOnly is true, the generated certificate is the one for replicas.
 func frontendCertificate(
-	custom *corev1.SecretProjection, secret *corev1.Secret,
+	custom *corev1.SecretProjection, secret *corev1.Secret, readOnly bool,
 ) corev1.VolumeProjection {
 	if custom == nil {
+		privateKeyKey, certificateKey := certFrontendPrivateKeySecretKey, certFrontendSecretKey
+		if readOnly {
+			privateKeyKey, certificateKey = certFrontendReadOnlyPrivateKeySecretKey, certFrontendReadOnlySecretKey
+		}
+
 		return corev1.VolumeProjection{Secret: &corev1.SecretProjection{
 			LocalObjectReference: corev1.LocalObjectReference{
 				Name: secret.Name,
@@ -80,11 +89,11 @@ func frontendCertificate(
 					Path: certFrontendAuthorityProjectionPath,
 				},
 				{
-					Key:  certFrontendPrivateKeySecretKey,
+					Key:  privateKeyKey,
 					Path: certFrontendPrivateKeyProjectionPath,
 				},
 				{
-					Key:  certFrontendSecretKey,
+					Key:  certificateKey,
 					Path: certFrontendProjectionPath,
 				},
 			},
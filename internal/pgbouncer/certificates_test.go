@@ -54,7 +54,7 @@ func TestFrontendCertificate(t *testing.T) {
 	secret.Name = "op-secret"
 
 	t.Run("Generated", func(t *testing.T) {
-		assert.Assert(t, marshalMatches(frontendCertificate(nil, secret), `
+		assert.Assert(t, marshalMatches(frontendCertificate(nil, secret, false), `
 secret:
   items:
   - key: pgbouncer-frontend.ca-roots
@@ -67,12 +67,26 @@ secret:
 		`))
 	})
 
+	t.Run("GeneratedReadOnly", func(t *testing.T) {
+		assert.Assert(t, marshalMatches(frontendCertificate(nil, secret, true), `
+secret:
+  items:
+  - key: pgbouncer-frontend.ca-roots
+    path: ~postgres-operator/frontend-ca.crt
+  - key: pgbouncer-frontend-ro.key
+    path: ~postgres-operator/frontend-tls.key
+  - key: pgbouncer-frontend-ro.crt
+    path: ~postgres-operator/frontend-tls.crt
+  name: op-secret
+		`))
+	})
+
 	t.Run("Custom", func(t *testing.T) {
 		custom := new(corev1.SecretProjection)
 		custom.Name = "some-other"
 
 		// No items; assume Key matches Path.
-		assert.Assert(t, marshalMatches(frontendCertificate(custom, secret), `
+		assert.Assert(t, marshalMatches(frontendCertificate(custom, secret, false), `
 secret:
   items:
   - key: ca.crt
@@ -91,7 +105,7 @@ secret:
 			{Key: "some-cert-key", Path: "tls.crt"},
 			{Key: "some-key-key", Path: "tls.key"},
 		}
-		assert.Assert(t, marshalMatches(frontendCertificate(custom, secret), `
+		assert.Assert(t, marshalMatches(frontendCertificate(custom, secret, false), `
 secret:
   items:
   - key: some-ca-key
@@ -31,17 +31,40 @@ const (
 
 	authFileAbsolutePath  = configDirectory + "/" + authFileProjectionPath
 	emptyFileAbsolutePath = configDirectory + "/" + emptyFileProjectionPath
+	hbaFileAbsolutePath   = configDirectory + "/" + hbaFileProjectionPath
 	iniFileAbsolutePath   = configDirectory + "/" + iniFileProjectionPath
 
 	authFileProjectionPath  = "~postgres-operator/users.txt"
 	emptyFileProjectionPath = "pgbouncer.ini"
+	hbaFileProjectionPath   = "~postgres-operator/hba.conf"
 	iniFileProjectionPath   = "~postgres-operator.ini"
 
-	authFileSecretKey   = "pgbouncer-users.txt" // #nosec G101 this is a name, not a credential
-	passwordSecretKey   = "pgbouncer-password"  // #nosec G101 this is a name, not a credential
-	verifierSecretKey   = "pgbouncer-verifier"  // #nosec G101 this is a name, not a credential
-	emptyConfigMapKey   = "pgbouncer-empty"
-	iniFileConfigMapKey = "pgbouncer.ini"
+	authFileSecretKey           = "pgbouncer-users.txt"      // #nosec G101 this is a name, not a credential
+	passwordSecretKey           = "pgbouncer-password"       // #nosec G101 this is a name, not a credential
+	verifierSecretKey           = "pgbouncer-verifier"       // #nosec G101 this is a name, not a credential
+	adminPasswordSecretKey      = "pgbouncer-admin-password" // #nosec G101 this is a name, not a credential
+	adminVerifierSecretKey      = "pgbouncer-admin-verifier" // #nosec G101 this is a name, not a credential
+	statsPasswordSecretKey      = "pgbouncer-stats-password" // #nosec G101 this is a name, not a credential
+	statsVerifierSecretKey      = "pgbouncer-stats-verifier" // #nosec G101 this is a name, not a credential
+	emptyConfigMapKey           = "pgbouncer-empty"
+	hbaFileConfigMapKey         = "pgbouncer-hba.conf"
+	iniFileConfigMapKey         = "pgbouncer.ini"
+	iniFileReadOnlyConfigMapKey = "pgbouncer-ro.ini"
+)
+
+const (
+	// adminUser and statsUser connect to the PgBouncer admin console. They
+	// exist only in PgBouncer's authentication file, not in PostgreSQL.
+	// - https://www.pgbouncer.org/usage.html#admin-console
+	adminUser = "pgbouncer_admin"
+	statsUser = "pgbouncer_stats"
+
+	// adminDatabase is the name of the PgBouncer admin console.
+	adminDatabase = "pgbouncer"
+
+	// exporterPort is the default port of the PgBouncer exporter.
+	// - https://github.com/prometheus/prometheus/wiki/Default-port-allocations
+	exporterPort = 9127
 )
 
 const (
@@ -71,8 +94,10 @@ func (vs iniValueSet) String() string {
 	return b.String()
 }
 
-// authFileContents returns a PgBouncer user database.
-func authFileContents(password string) []byte {
+// authFileContents returns a PgBouncer user database. It contains the
+// plaintext password of the PostgreSQL user and the SCRAM verifiers of the
+// admin console users.
+func authFileContents(password, adminVerifier, statsVerifier string) []byte {
 	// > There should be at least 2 fields, surrounded by double quotes.
 	// > Double quotes in a field value can be escaped by writing two double quotes.
 	// - https://www.pgbouncer.org/config.html#authentication-file-format
@@ -81,15 +106,53 @@ func authFileContents(password string) []byte {
 	}
 
 	user1 := quote(postgresqlUser) + " " + quote(password) + "\n"
+	user2 := quote(adminUser) + " " + quote(adminVerifier) + "\n"
+	user3 := quote(statsUser) + " " + quote(statsVerifier) + "\n"
 
-	return []byte(user1)
+	return []byte(user1 + user2 + user3)
 }
 
 func clusterINI(cluster *v1beta1.PostgresCluster) string {
-	var (
-		pgBouncerPort = *cluster.Spec.Proxy.PGBouncer.Port
-		postgresPort  = *cluster.Spec.Port
-	)
+	postgresPort := *cluster.Spec.Port
+
+	// Use a wildcard to automatically create connection pools based on database
+	// names. These pools connect to cluster's primary service. The service name
+	// is an RFC 1123 DNS label so it does not need to be quoted nor escaped.
+	// - https://www.pgbouncer.org/config.html#section-databases
+	//
+	// NOTE(cbandy): PgBouncer only accepts connections to items in this section
+	// and the database "pgbouncer", which is the admin console. For connections
+	// to the wildcard, PgBouncer first checks for the database in PostgreSQL.
+	// When that database does not exist, the client will experience timeouts
+	// or errors that sound like PgBouncer misconfiguration.
+	// - https://github.com/pgbouncer/pgbouncer/issues/352
+	databases := iniValueSet{
+		"*": fmt.Sprintf("host=%s port=%d",
+			naming.ClusterPrimaryService(cluster).Name, postgresPort),
+	}
+
+	// Replace the above with any specified databases.
+	if len(cluster.Spec.Proxy.PGBouncer.Config.Databases) > 0 {
+		databases = iniValueSet(cluster.Spec.Proxy.PGBouncer.Config.Databases)
+	}
+
+	return iniContents(cluster, databases)
+}
+
+// readOnlyINI returns the configuration of PgBouncer that connects to the
+// replicas of cluster. Its wildcard pool connects to the replica service, and
+// the specified databases do not apply.
+func readOnlyINI(cluster *v1beta1.PostgresCluster) string {
+	return iniContents(cluster, iniValueSet{
+		"*": fmt.Sprintf("host=%s port=%d",
+			naming.ClusterReplicaService(cluster).Name, *cluster.Spec.Port),
+	})
+}
+
+// iniContents returns the main configuration file of PgBouncer with the
+// global and user settings of cluster and the pool definitions in databases.
+func iniContents(cluster *v1beta1.PostgresCluster, databases iniValueSet) string {
+	pgBouncerPort := *cluster.Spec.Proxy.PGBouncer.Port
 
 	global := iniValueSet{
 		// Prior to PostgreSQL v12, the default setting for "extra_float_digits"
@@ -110,12 +173,13 @@ func clusterINI(cluster *v1beta1.PostgresCluster) string {
 		"auth_query": "SELECT username, password from pgbouncer.get_auth($1)",
 		"auth_user":  postgresqlUser,
 
-		// TODO(cbandy): Use an HBA file to control authentication of PgBouncer
-		// accounts; e.g. "admin_users" below.
+		// Use an HBA file to control authentication of PgBouncer accounts.
+		// The admin console users authenticate using "auth_file" only.
 		// - https://www.pgbouncer.org/config.html#hba-file-format
-		//"auth_hba_file": "",
-		//"auth_type":     "hba",
-		//"admin_users": "pgbouncer",
+		"auth_hba_file": hbaFileAbsolutePath,
+		"auth_type":     "hba",
+		"admin_users":   adminUser,
+		"stats_users":   statsUser,
 
 		// Require TLS encryption on client connections.
 		"client_tls_sslmode":   "require",
@@ -143,27 +207,6 @@ func clusterINI(cluster *v1beta1.PostgresCluster) string {
 	// Prevent the user from bypassing the main configuration file.
 	global["conffile"] = iniFileAbsolutePath
 
-	// Use a wildcard to automatically create connection pools based on database
-	// names. These pools connect to cluster's primary service. The service name
-	// is an RFC 1123 DNS label so it does not need to be quoted nor escaped.
-	// - https://www.pgbouncer.org/config.html#section-databases
-	//
-	// NOTE(cbandy): PgBouncer only accepts connections to items in this section
-	// and the database "pgbouncer", which is the admin console. For connections
-	// to the wildcard, PgBouncer first checks for the database in PostgreSQL.
-	// When that database does not exist, the client will experience timeouts
-	// or errors that sound like PgBouncer misconfiguration.
-	// - https://github.com/pgbouncer/pgbouncer/issues/352
-	databases := iniValueSet{
-		"*": fmt.Sprintf("host=%s port=%d",
-			naming.ClusterPrimaryService(cluster).Name, postgresPort),
-	}
-
-	// Replace the above with any specified databases.
-	if len(cluster.Spec.Proxy.PGBouncer.Config.Databases) > 0 {
-		databases = iniValueSet(cluster.Spec.Proxy.PGBouncer.Config.Databases)
-	}
-
 	users := iniValueSet(cluster.Spec.Proxy.PGBouncer.Config.Users)
 
 	// Include any custom configuration file, then apply global settings, then
@@ -182,11 +225,17 @@ func clusterINI(cluster *v1beta1.PostgresCluster) string {
 }
 
 // podConfigFiles returns projections of PgBouncer's configuration files to
-// include in the configuration volume.
+// include in the configuration volume. When readOnly is true, the main
+// configuration file is the one that connects to replicas.
 func podConfigFiles(
 	config v1beta1.PGBouncerConfiguration,
-	configmap *corev1.ConfigMap, secret *corev1.Secret,
+	configmap *corev1.ConfigMap, secret *corev1.Secret, readOnly bool,
 ) []corev1.VolumeProjection {
+	iniKey := iniFileConfigMapKey
+	if readOnly {
+		iniKey = iniFileReadOnlyConfigMapKey
+	}
+
 	// Start with an empty file at /etc/pgbouncer/pgbouncer.ini. This file can
 	// be overridden by the user, but it must exist because our configuration
 	// file refers to it.
@@ -215,10 +264,16 @@ func podConfigFiles(
 				LocalObjectReference: corev1.LocalObjectReference{
 					Name: configmap.Name,
 				},
-				Items: []corev1.KeyToPath{{
-					Key:  iniFileConfigMapKey,
-					Path: iniFileProjectionPath,
-				}},
+				Items: []corev1.KeyToPath{
+					{
+						Key:  iniKey,
+						Path: iniFileProjectionPath,
+					},
+					{
+						Key:  hbaFileConfigMapKey,
+						Path: hbaFileProjectionPath,
+					},
+				},
 			},
 		},
 		{
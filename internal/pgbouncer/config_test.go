@@ -45,8 +45,11 @@ func TestAuthFileContents(t *testing.T) {
 	t.Parallel()
 
 	password := `very"random`
-	data := authFileContents(password)
-	assert.Equal(t, string(data), `"_crunchypgbouncer" "very""random"`+"\n")
+	data := authFileContents(password, "SCRAM-admin", "SCRAM-stats")
+	assert.Equal(t, string(data), ``+
+		`"_crunchypgbouncer" "very""random"`+"\n"+
+		`"pgbouncer_admin" "SCRAM-admin"`+"\n"+
+		`"pgbouncer_stats" "SCRAM-stats"`+"\n")
 }
 
 func TestClusterINI(t *testing.T) {
@@ -72,8 +75,11 @@ func TestClusterINI(t *testing.T) {
 %include /etc/pgbouncer/pgbouncer.ini
 
 [pgbouncer]
+admin_users = pgbouncer_admin
 auth_file = /etc/pgbouncer/~postgres-operator/users.txt
+auth_hba_file = /etc/pgbouncer/~postgres-operator/hba.conf
 auth_query = SELECT username, password from pgbouncer.get_auth($1)
+auth_type = hba
 auth_user = _crunchypgbouncer
 client_tls_ca_file = /etc/pgbouncer/~postgres-operator/frontend-ca.crt
 client_tls_cert_file = /etc/pgbouncer/~postgres-operator/frontend-tls.crt
@@ -85,6 +91,7 @@ listen_addr = *
 listen_port = 8888
 server_tls_ca_file = /etc/pgbouncer/~postgres-operator/backend-ca.crt
 server_tls_sslmode = verify-full
+stats_users = pgbouncer_stats
 unix_socket_dir =
 
 [databases]
@@ -112,8 +119,11 @@ unix_socket_dir =
 %include /etc/pgbouncer/pgbouncer.ini
 
 [pgbouncer]
+admin_users = pgbouncer_admin
 auth_file = /etc/pgbouncer/~postgres-operator/users.txt
+auth_hba_file = /etc/pgbouncer/~postgres-operator/hba.conf
 auth_query = SELECT username, password from pgbouncer.get_auth($1)
+auth_type = hba
 auth_user = _crunchypgbouncer
 client_tls_ca_file = /etc/pgbouncer/~postgres-operator/frontend-ca.crt
 client_tls_cert_file = /etc/pgbouncer/~postgres-operator/frontend-tls.crt
@@ -125,6 +135,7 @@ listen_addr = *
 listen_port = 8888
 server_tls_ca_file = /etc/pgbouncer/~postgres-operator/backend-ca.crt
 server_tls_sslmode = verify-full
+stats_users = pgbouncer_stats
 unix_socket_dir =
 verbose = whomp
 
@@ -139,6 +150,17 @@ app = mode=rad
 		cluster.Spec.Proxy.PGBouncer.Config.Global["conffile"] = "too-far"
 		assert.Assert(t, !strings.Contains(clusterINI(cluster), "too-far"))
 	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		data := readOnlyINI(cluster)
+
+		// Global and user settings apply; specified databases do not.
+		assert.Assert(t, strings.Contains(data, "\nverbose = whomp\n"))
+		assert.Assert(t, strings.Contains(data, "\n[users]\napp = mode=rad\n"))
+		assert.Assert(t, strings.Contains(data,
+			"\n[databases]\n* = host=foo-baz-replicas port=9999\n"), "got:\n%s", data)
+		assert.Assert(t, !strings.Contains(data, "appdb"))
+	})
 }
 
 func TestPodConfigFiles(t *testing.T) {
@@ -149,7 +171,7 @@ func TestPodConfigFiles(t *testing.T) {
 	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "some-shh"}}
 
 	t.Run("Default", func(t *testing.T) {
-		projections := podConfigFiles(config, configmap, secret)
+		projections := podConfigFiles(config, configmap, secret, false)
 		assert.Assert(t, marshalMatches(projections, `
 - configMap:
     items:
@@ -160,6 +182,8 @@ func TestPodConfigFiles(t *testing.T) {
     items:
     - key: pgbouncer.ini
       path: ~postgres-operator.ini
+    - key: pgbouncer-hba.conf
+      path: ~postgres-operator/hba.conf
     name: some-cm
 - secret:
     items:
@@ -169,6 +193,19 @@ func TestPodConfigFiles(t *testing.T) {
 		`))
 	})
 
+	t.Run("ReadOnly", func(t *testing.T) {
+		projections := podConfigFiles(config, configmap, secret, true)
+		assert.Assert(t, marshalMatches(projections[1], `
+configMap:
+  items:
+  - key: pgbouncer-ro.ini
+    path: ~postgres-operator.ini
+  - key: pgbouncer-hba.conf
+    path: ~postgres-operator/hba.conf
+  name: some-cm
+		`))
+	})
+
 	t.Run("CustomFiles", func(t *testing.T) {
 		config.Files = []corev1.VolumeProjection{
 			{Secret: &corev1.SecretProjection{
@@ -182,7 +219,7 @@ func TestPodConfigFiles(t *testing.T) {
 			}},
 		}
 
-		projections := podConfigFiles(config, configmap, secret)
+		projections := podConfigFiles(config, configmap, secret, false)
 		assert.Assert(t, marshalMatches(projections, `
 - configMap:
     items:
@@ -200,6 +237,8 @@ func TestPodConfigFiles(t *testing.T) {
     items:
     - key: pgbouncer.ini
       path: ~postgres-operator.ini
+    - key: pgbouncer-hba.conf
+      path: ~postgres-operator/hba.conf
     name: some-cm
 - secret:
     items:
@@ -0,0 +1,190 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crunchydata/postgres-operator/internal/postgres"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// hbaRecord is one line of PgBouncer's HBA file. The format is that of
+// pg_hba.conf with fewer features; e.g. addresses cannot be quoted and there
+// are no groups.
+// - https://www.pgbouncer.org/config.html#hba-file-format
+type hbaRecord struct {
+	connection, databases, users, address, method string
+}
+
+// String returns r formatted for the HBA file without a newline.
+func (r hbaRecord) String() string {
+	return fmt.Sprintf("%s %s %s %s %s",
+		r.connection, r.databases, r.users, r.address, r.method)
+}
+
+// hbaNames returns names quoted and separated by commas. It returns "all"
+// when there are no names.
+func hbaNames(names ...string) string {
+	if len(names) == 0 {
+		return "all"
+	}
+
+	quoted := make([]string, len(names))
+	for i := range names {
+		quoted[i] = `"` + strings.ReplaceAll(names[i], `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, ",")
+}
+
+// mandatoryHBAs returns the records that PgBouncer compares first.
+func mandatoryHBAs() []hbaRecord {
+	consoleUsers := hbaNames(adminUser, statsUser)
+
+	return []hbaRecord{
+		// The admin console users must connect over TLS using their SCRAM
+		// passwords. They cannot connect to other databases.
+		{"hostssl", hbaNames(adminDatabase), consoleUsers, "all", "scram-sha-256"},
+		{"host", "all", consoleUsers, "all", "reject"},
+
+		// The PostgreSQL user of PgBouncer is only for connecting to PostgreSQL.
+		{"host", "all", hbaNames(postgresqlUser), "all", "reject"},
+	}
+}
+
+// defaultHBAs returns the records that PgBouncer uses when none are specified.
+func defaultHBAs() []hbaRecord {
+	// Allow TLS connections to any database using passwords. The "md5"
+	// authentication method automatically switches to SCRAM-SHA-256 when that
+	// is how the password is stored.
+	// - https://www.pgbouncer.org/config.html#auth_type
+	return []hbaRecord{
+		{"hostssl", "all", "all", "all", "md5"},
+	}
+}
+
+// hbasFromRule returns the records that implement rule, one for each of its
+// networks.
+func hbasFromRule(rule v1beta1.PGBouncerHBARuleSpec) ([]hbaRecord, error) {
+	var databases, users []string
+	for _, name := range rule.Databases {
+		databases = append(databases, string(name))
+	}
+	for _, name := range rule.Users {
+		users = append(users, string(name))
+	}
+
+	// PgBouncer listens only for TCP connections.
+	if rule.Connection == "local" {
+		return nil, fmt.Errorf("unknown connection %q", rule.Connection)
+	}
+	if err := postgres.ValidateHBARule(rule.Connection, rule.Method, rule.Networks,
+		append(databases, users...)...); err != nil {
+		return nil, err
+	}
+
+	switch rule.Method {
+	case "cert", "md5", "password", "reject", "scram-sha-256", "trust":
+	default:
+		return nil, fmt.Errorf("unknown method %q", rule.Method)
+	}
+
+	record := hbaRecord{
+		connection: rule.Connection,
+		databases:  hbaNames(databases...),
+		users:      hbaNames(users...),
+		address:    "all",
+		method:     rule.Method,
+	}
+	if record.connection == "" {
+		record.connection = "hostssl"
+	}
+
+	if len(rule.Networks) == 0 {
+		return []hbaRecord{record}, nil
+	}
+
+	records := make([]hbaRecord, 0, len(rule.Networks))
+	for _, network := range rule.Networks {
+		// Format the parsed block so that nothing from the spec is written
+		// to the file verbatim. The network is already known to be valid.
+		_, block, _ := net.ParseCIDR(network)
+		record.address = block.String()
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// specifiedHBAs returns the records that implement the authentication rules in
+// the spec of cluster. The rules are a single policy, so it returns an error
+// describing every invalid rule when any is invalid.
+func specifiedHBAs(cluster *v1beta1.PostgresCluster) ([]hbaRecord, error) {
+	if cluster.Spec.Proxy.PGBouncer.Authentication == nil {
+		return nil, nil
+	}
+
+	var invalid []string
+	var records []hbaRecord
+	for i, rule := range cluster.Spec.Proxy.PGBouncer.Authentication.Rules {
+		specified, err := hbasFromRule(rule)
+		if err == nil {
+			records = append(records, specified...)
+		} else {
+			invalid = append(invalid, fmt.Sprintf("rules[%d]: %v", i, err))
+		}
+	}
+
+	if len(invalid) > 0 {
+		return nil, errors.New(strings.Join(invalid, "; "))
+	}
+	return records, nil
+}
+
+// hbaFileContents returns the HBA file of PgBouncer. Mandatory records come
+// first, followed by any specified in the cluster spec. Default records are
+// used when nothing valid is specified.
+func hbaFileContents(cluster *v1beta1.PostgresCluster) string {
+	records := mandatoryHBAs()
+
+	if specified, err := specifiedHBAs(cluster); err == nil && len(specified) > 0 {
+		records = append(records, specified...)
+	} else {
+		records = append(records, defaultHBAs()...)
+	}
+
+	var b strings.Builder
+	b.WriteString(iniGeneratedWarning + "\n")
+	for _, record := range records {
+		b.WriteString(record.String() + "\n")
+	}
+	return b.String()
+}
+
+// ValidateAuthentication returns an error describing any authentication rules
+// in the PgBouncer spec of cluster that cannot be used. PgBouncer ignores all
+// specified rules when any is invalid.
+func ValidateAuthentication(cluster *v1beta1.PostgresCluster) error {
+	if cluster.Spec.Proxy == nil || cluster.Spec.Proxy.PGBouncer == nil {
+		return nil
+	}
+
+	_, err := specifiedHBAs(cluster)
+	return err
+}
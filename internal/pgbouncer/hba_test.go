@@ -0,0 +1,157 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func TestHBAsFromRule(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		rule     v1beta1.PGBouncerHBARuleSpec
+		expected []string
+	}{
+		{
+			rule:     v1beta1.PGBouncerHBARuleSpec{Method: "md5"},
+			expected: []string{`hostssl all all all md5`},
+		},
+		{
+			rule: v1beta1.PGBouncerHBARuleSpec{
+				Connection: "host",
+				Databases:  []v1beta1.PostgresIdentifier{"app", `odd"name`},
+				Users:      []v1beta1.PostgresIdentifier{"reports"},
+				Method:     "scram-sha-256",
+			},
+			expected: []string{`host "app","odd""name" "reports" all scram-sha-256`},
+		},
+		{
+			rule: v1beta1.PGBouncerHBARuleSpec{
+				Networks: []string{"10.0.0.1/8", "fd00::/8"},
+				Method:   "reject",
+			},
+			expected: []string{
+				`hostssl all all 10.0.0.0/8 reject`,
+				`hostssl all all fd00::/8 reject`,
+			},
+		},
+	} {
+		records, err := hbasFromRule(tt.rule)
+		assert.NilError(t, err)
+
+		var lines []string
+		for _, record := range records {
+			lines = append(lines, record.String())
+		}
+		assert.DeepEqual(t, lines, tt.expected)
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, tt := range []struct {
+			rule    v1beta1.PGBouncerHBARuleSpec
+			message string
+		}{
+			{
+				rule:    v1beta1.PGBouncerHBARuleSpec{Connection: "local", Method: "md5"},
+				message: `unknown connection "local"`,
+			},
+			{
+				rule:    v1beta1.PGBouncerHBARuleSpec{Method: "peer"},
+				message: `unknown method "peer"`,
+			},
+			{
+				rule:    v1beta1.PGBouncerHBARuleSpec{Connection: "host", Method: "cert"},
+				message: `the "cert" method requires a hostssl connection`,
+			},
+			{
+				rule: v1beta1.PGBouncerHBARuleSpec{
+					Users: []v1beta1.PostgresIdentifier{"a\nhost"}, Method: "trust",
+				},
+				message: "names cannot contain line breaks",
+			},
+			{
+				rule: v1beta1.PGBouncerHBARuleSpec{
+					Networks: []string{"samenet"}, Method: "trust",
+				},
+				message: `invalid network "samenet"`,
+			},
+		} {
+			_, err := hbasFromRule(tt.rule)
+			assert.ErrorContains(t, err, tt.message)
+		}
+	})
+}
+
+func TestHBAFileContents(t *testing.T) {
+	t.Parallel()
+
+	cluster := new(v1beta1.PostgresCluster)
+	cluster.Spec.Proxy = new(v1beta1.PostgresProxySpec)
+	cluster.Spec.Proxy.PGBouncer = new(v1beta1.PGBouncerPodSpec)
+
+	mandatory := strings.Trim(`
+hostssl "pgbouncer" "pgbouncer_admin","pgbouncer_stats" all scram-sha-256
+host all "pgbouncer_admin","pgbouncer_stats" all reject
+host all "_crunchypgbouncer" all reject
+	`, "\t\n") + "\n"
+
+	t.Run("Default", func(t *testing.T) {
+		assert.Equal(t, hbaFileContents(cluster), ""+
+			"# Generated by postgres-operator. DO NOT EDIT.\n"+
+			"# Your changes will not be saved.\n\n"+
+			mandatory+
+			"hostssl all all all md5\n")
+		assert.NilError(t, ValidateAuthentication(cluster))
+	})
+
+	t.Run("Specified", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Proxy.PGBouncer.Authentication = &v1beta1.PGBouncerAuthenticationSpec{
+			Rules: []v1beta1.PGBouncerHBARuleSpec{
+				{Users: []v1beta1.PostgresIdentifier{"app"}, Method: "scram-sha-256"},
+				{Method: "reject"},
+			},
+		}
+
+		assert.Assert(t, strings.HasSuffix(hbaFileContents(cluster), "\n"+
+			mandatory+
+			`hostssl all "app" all scram-sha-256`+"\n"+
+			"hostssl all all all reject\n"))
+		assert.NilError(t, ValidateAuthentication(cluster))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Proxy.PGBouncer.Authentication = &v1beta1.PGBouncerAuthenticationSpec{
+			Rules: []v1beta1.PGBouncerHBARuleSpec{
+				{Method: "reject"},
+				{Method: "peer"},
+			},
+		}
+
+		// None of the specified rules are used.
+		assert.Assert(t, strings.HasSuffix(hbaFileContents(cluster), "\n"+
+			mandatory+
+			"hostssl all all all md5\n"))
+		assert.Error(t, ValidateAuthentication(cluster), `rules[1]: unknown method "peer"`)
+	})
+}
@@ -17,6 +17,9 @@ package pgbouncer
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/url"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -44,15 +47,22 @@ func ConfigMap(
 	initialize.StringMap(&outConfigMap.Data)
 
 	outConfigMap.Data[emptyConfigMapKey] = ""
+	outConfigMap.Data[hbaFileConfigMapKey] = hbaFileContents(inCluster)
 	outConfigMap.Data[iniFileConfigMapKey] = clusterINI(inCluster)
+
+	if inCluster.Spec.Proxy.PGBouncer.ReadOnly != nil {
+		outConfigMap.Data[iniFileReadOnlyConfigMapKey] = readOnlyINI(inCluster)
+	}
 }
 
-// Secret populates the PgBouncer Secret.
+// Secret populates the PgBouncer Secret. The read-only Service is nil when
+// there is no PgBouncer for replicas.
 func Secret(ctx context.Context,
 	inCluster *v1beta1.PostgresCluster,
 	inRoot *pki.RootCertificateAuthority,
 	inSecret *corev1.Secret,
 	inService *corev1.Service,
+	inReadOnlyService *corev1.Service,
 	outSecret *corev1.Secret,
 ) error {
 	if inCluster.Spec.Proxy == nil || inCluster.Spec.Proxy.PGBouncer == nil {
@@ -63,36 +73,51 @@ func Secret(ctx context.Context,
 	var err error
 	initialize.ByteMap(&outSecret.Data)
 
-	// Use the existing password and verifier. Generate both when either is missing.
+	// credentials returns the existing password and verifier stored in the
+	// passwordKey and verifierKey of the Secret. It generates both when either
+	// is missing.
 	// NOTE(cbandy): We don't have a function to compare a plaintext password
 	// to a SCRAM verifier.
-	password := string(inSecret.Data[passwordSecretKey])
-	verifier := string(inSecret.Data[verifierSecretKey])
+	credentials := func(passwordKey, verifierKey string) (string, string) {
+		password := string(inSecret.Data[passwordKey])
+		verifier := string(inSecret.Data[verifierKey])
+
+		if err == nil && (len(password) == 0 || len(verifier) == 0) {
+			password, verifier, err = generatePassword()
+			err = errors.WithStack(err)
+		}
 
-	if err == nil && (len(password) == 0 || len(verifier) == 0) {
-		password, verifier, err = generatePassword()
-		err = errors.WithStack(err)
+		if err == nil {
+			// Store the SCRAM verifier alongside the plaintext password so that
+			// later reconciles don't generate it repeatedly.
+			outSecret.Data[passwordKey] = []byte(password)
+			outSecret.Data[verifierKey] = []byte(verifier)
+		}
+		return password, verifier
 	}
 
+	password, _ := credentials(passwordSecretKey, verifierSecretKey)
+	_, adminVerifier := credentials(adminPasswordSecretKey, adminVerifierSecretKey)
+	_, statsVerifier := credentials(statsPasswordSecretKey, statsVerifierSecretKey)
+
 	if err == nil {
-		// Store the SCRAM verifier alongside the plaintext password so that
-		// later reconciles don't generate it repeatedly.
-		outSecret.Data[authFileSecretKey] = authFileContents(password)
-		outSecret.Data[passwordSecretKey] = []byte(password)
-		outSecret.Data[verifierSecretKey] = []byte(verifier)
+		outSecret.Data[authFileSecretKey] =
+			authFileContents(password, adminVerifier, statsVerifier)
 	}
 
-	if inCluster.Spec.Proxy.PGBouncer.CustomTLSSecret == nil {
+	// frontend stores a certificate for service in the certificateKey and
+	// privateKeyKey of the Secret.
+	frontend := func(service *corev1.Service, certificateKey, privateKeyKey string) {
 		leaf := &pki.LeafCertificate{}
-		dnsNames := naming.ServiceDNSNames(ctx, inService)
+		dnsNames := naming.ServiceDNSNames(ctx, service)
 		dnsFQDN := dnsNames[0]
 
 		if err == nil {
 			// Unmarshal and validate the stored leaf. These first errors can
 			// be ignored because they result in an invalid leaf which is then
 			// correctly regenerated.
-			_ = leaf.Certificate.UnmarshalText(inSecret.Data[certFrontendSecretKey])
-			_ = leaf.PrivateKey.UnmarshalText(inSecret.Data[certFrontendPrivateKeySecretKey])
+			_ = leaf.Certificate.UnmarshalText(inSecret.Data[certificateKey])
+			_ = leaf.PrivateKey.UnmarshalText(inSecret.Data[privateKeyKey])
 
 			leaf, err = inRoot.RegenerateLeafWhenNecessary(leaf, dnsFQDN, dnsNames)
 			err = errors.WithStack(err)
@@ -102,16 +127,65 @@ func Secret(ctx context.Context,
 			outSecret.Data[certFrontendAuthoritySecretKey], err = inRoot.Certificate.MarshalText()
 		}
 		if err == nil {
-			outSecret.Data[certFrontendPrivateKeySecretKey], err = leaf.PrivateKey.MarshalText()
+			outSecret.Data[privateKeyKey], err = leaf.PrivateKey.MarshalText()
 		}
 		if err == nil {
-			outSecret.Data[certFrontendSecretKey], err = leaf.Certificate.MarshalText()
+			outSecret.Data[certificateKey], err = leaf.Certificate.MarshalText()
 		}
 	}
 
+	if inCluster.Spec.Proxy.PGBouncer.CustomTLSSecret == nil {
+		frontend(inService, certFrontendSecretKey, certFrontendPrivateKeySecretKey)
+	}
+
+	if readOnly := inCluster.Spec.Proxy.PGBouncer.ReadOnly; readOnly != nil &&
+		readOnly.CustomTLSSecret == nil && inReadOnlyService != nil {
+		frontend(inReadOnlyService,
+			certFrontendReadOnlySecretKey, certFrontendReadOnlyPrivateKeySecretKey)
+	}
+
 	return err
 }
 
+// AdminSecret populates the Secret with credentials for the PgBouncer admin
+// console. The passwords come from inSecret, which is populated by [Secret].
+func AdminSecret(
+	inCluster *v1beta1.PostgresCluster,
+	inSecret *corev1.Secret,
+	inService *corev1.Service,
+	outSecret *corev1.Secret,
+) {
+	if inCluster.Spec.Proxy == nil || inCluster.Spec.Proxy.PGBouncer == nil {
+		// PgBouncer is disabled; there is nothing to do.
+		return
+	}
+
+	initialize.ByteMap(&outSecret.Data)
+
+	hostname := inService.Name + "." + inService.Namespace + ".svc"
+	port := fmt.Sprint(*inCluster.Spec.Proxy.PGBouncer.Port)
+
+	outSecret.Data["dbname"] = []byte(adminDatabase)
+	outSecret.Data["host"] = []byte(hostname)
+	outSecret.Data["port"] = []byte(port)
+
+	for _, user := range []struct{ prefix, name, passwordKey string }{
+		{"admin", adminUser, adminPasswordSecretKey},
+		{"stats", statsUser, statsPasswordSecretKey},
+	} {
+		password := inSecret.Data[user.passwordKey]
+
+		outSecret.Data[user.prefix+"-user"] = []byte(user.name)
+		outSecret.Data[user.prefix+"-password"] = password
+		outSecret.Data[user.prefix+"-uri"] = []byte((&url.URL{
+			Scheme: "postgresql",
+			User:   url.UserPassword(user.name, string(password)),
+			Host:   net.JoinHostPort(hostname, port),
+			Path:   adminDatabase,
+		}).String())
+	}
+}
+
 // Pod populates a PodSpec with the container and volumes needed to run PgBouncer.
 func Pod(
 	inCluster *v1beta1.PostgresCluster,
@@ -125,14 +199,48 @@ func Pod(
 		return
 	}
 
+	pod(inCluster, inConfigMap, inPostgreSQLCertificate, inSecret, false, outPod)
+}
+
+// ReadOnlyPod populates a PodSpec with the container and volumes needed to run
+// PgBouncer that connects to PostgreSQL replicas.
+func ReadOnlyPod(
+	inCluster *v1beta1.PostgresCluster,
+	inConfigMap *corev1.ConfigMap,
+	inPostgreSQLCertificate *corev1.SecretProjection,
+	inSecret *corev1.Secret,
+	outPod *corev1.PodSpec,
+) {
+	if inCluster.Spec.Proxy == nil || inCluster.Spec.Proxy.PGBouncer == nil ||
+		inCluster.Spec.Proxy.PGBouncer.ReadOnly == nil {
+		// Read-only PgBouncer is disabled; there is nothing to do.
+		return
+	}
+
+	pod(inCluster, inConfigMap, inPostgreSQLCertificate, inSecret, true, outPod)
+}
+
+func pod(
+	inCluster *v1beta1.PostgresCluster,
+	inConfigMap *corev1.ConfigMap,
+	inPostgreSQLCertificate *corev1.SecretProjection,
+	inSecret *corev1.Secret,
+	readOnly bool,
+	outPod *corev1.PodSpec,
+) {
+	customTLS := inCluster.Spec.Proxy.PGBouncer.CustomTLSSecret
+	if readOnly {
+		customTLS = inCluster.Spec.Proxy.PGBouncer.ReadOnly.CustomTLSSecret
+	}
+
 	configVolumeMount := corev1.VolumeMount{
 		Name: "pgbouncer-config", MountPath: configDirectory, ReadOnly: true,
 	}
 	configVolume := corev1.Volume{Name: configVolumeMount.Name}
 	configVolume.Projected = &corev1.ProjectedVolumeSource{
 		Sources: append(append([]corev1.VolumeProjection{},
-			podConfigFiles(inCluster.Spec.Proxy.PGBouncer.Config, inConfigMap, inSecret)...),
-			frontendCertificate(inCluster.Spec.Proxy.PGBouncer.CustomTLSSecret, inSecret),
+			podConfigFiles(inCluster.Spec.Proxy.PGBouncer.Config, inConfigMap, inSecret, readOnly)...),
+			frontendCertificate(customTLS, inSecret, readOnly),
 			backendAuthority(inPostgreSQLCertificate),
 		),
 	}
@@ -196,9 +304,76 @@ func Pod(
 		outPod.Containers = append(outPod.Containers, inCluster.Spec.Proxy.PGBouncer.Containers...)
 	}
 
+	if ExporterEnabled(inCluster) {
+		outPod.Containers = append(outPod.Containers, exporterContainer(inCluster, inSecret))
+	}
+
 	outPod.Volumes = []corev1.Volume{configVolume}
 }
 
+// ExporterEnabled returns true when the PgBouncer pods of cluster should run
+// a metrics exporter.
+func ExporterEnabled(cluster *v1beta1.PostgresCluster) bool {
+	return cluster.Spec.Proxy != nil &&
+		cluster.Spec.Proxy.PGBouncer != nil &&
+		cluster.Spec.Proxy.PGBouncer.Monitoring != nil &&
+		cluster.Spec.Proxy.PGBouncer.Monitoring.Exporter != nil
+}
+
+// exporterContainer returns a container that exports the statistics of the
+// PgBouncer in the same pod to Prometheus. It connects to the admin console as
+// the stats user whose password is in inSecret.
+// - https://github.com/prometheus-community/pgbouncer_exporter
+func exporterContainer(
+	inCluster *v1beta1.PostgresCluster, inSecret *corev1.Secret,
+) corev1.Container {
+	// PgBouncer requires TLS from every client, but the certificate does not
+	// include "localhost". The password is read from the environment by the
+	// PostgreSQL driver.
+	// - https://www.postgresql.org/docs/current/libpq-envars.html
+	connectionString := (&url.URL{
+		Scheme:   "postgres",
+		User:     url.User(statsUser),
+		Host:     net.JoinHostPort("localhost", fmt.Sprint(*inCluster.Spec.Proxy.PGBouncer.Port)),
+		Path:     adminDatabase,
+		RawQuery: "sslmode=require",
+	}).String()
+
+	return corev1.Container{
+		Name: naming.ContainerPGBouncerExporter,
+
+		Command: []string{
+			"pgbouncer_exporter", fmt.Sprintf("--web.listen-address=:%d", exporterPort),
+		},
+		Image:           config.PGBouncerExporterContainerImage(inCluster),
+		ImagePullPolicy: inCluster.Spec.ImagePullPolicy,
+		Resources:       inCluster.Spec.Proxy.PGBouncer.Monitoring.Exporter.Resources,
+		SecurityContext: initialize.RestrictedSecurityContext(),
+
+		Env: []corev1.EnvVar{
+			{Name: "PGBOUNCER_EXPORTER_CONNECTION_STRING", Value: connectionString},
+			{Name: "PGPASSWORD", ValueFrom: &corev1.EnvVarSource{
+				// Environment variables are not updated after a secret update,
+				// but this password is generated once and never changes.
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: inSecret.Name,
+					},
+					Key: statsPasswordSecretKey,
+				},
+			}},
+		},
+
+		// ContainerPort is needed to support proper target discovery by
+		// Prometheus for pgMonitor integration.
+		Ports: []corev1.ContainerPort{{
+			Name:          naming.PortExporter,
+			ContainerPort: exporterPort,
+			Protocol:      corev1.ProtocolTCP,
+		}},
+	}
+}
+
 // PostgreSQL populates outHBAs with any records needed to run PgBouncer.
 func PostgreSQL(
 	inCluster *v1beta1.PostgresCluster,
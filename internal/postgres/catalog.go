@@ -0,0 +1,606 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParameterDefinition describes a PostgreSQL parameter the way the server
+// does in its "pg_settings" view.
+// - https://www.postgresql.org/docs/current/view-pg-settings.html
+type ParameterDefinition struct {
+	// The type of the parameter: "bool", "enum", "integer", "real", or "string".
+	Type string
+
+	// The implicit unit of a numeric parameter, if any, e.g. "kB" or "ms".
+	Unit string
+
+	// The range of values allowed for a numeric parameter.
+	Min, Max float64
+
+	// The values allowed for an enum parameter.
+	Values []string
+
+	// When a change to the parameter takes effect. Changes to "postmaster"
+	// parameters require a restart.
+	// - https://www.postgresql.org/docs/current/view-pg-settings.html#VIEW-PG-SETTINGS
+	Context string
+
+	// The first and last major versions of PostgreSQL to have this definition.
+	// Zero means unbounded.
+	since, until int
+}
+
+const (
+	contextPostmaster       = "postmaster"
+	contextSighup           = "sighup"
+	contextSuperuser        = "superuser"
+	contextSuperuserBackend = "superuser-backend"
+	contextUser             = "user"
+)
+
+// RequiresRestart returns whether or not PostgreSQL must restart for a change
+// to the parameter to take effect.
+func (def ParameterDefinition) RequiresRestart() bool {
+	return def.Context == contextPostmaster
+}
+
+func (def ParameterDefinition) between(since, until int) ParameterDefinition {
+	def.since, def.until = since, until
+	return def
+}
+
+func boolParameter(context string) ParameterDefinition {
+	return ParameterDefinition{Type: "bool", Context: context}
+}
+
+func enumParameter(context string, values ...string) ParameterDefinition {
+	return ParameterDefinition{Type: "enum", Context: context, Values: values}
+}
+
+func integerParameter(context, unit string, min, max float64) ParameterDefinition {
+	return ParameterDefinition{Type: "integer", Context: context, Unit: unit, Min: min, Max: max}
+}
+
+func realParameter(context, unit string, min, max float64) ParameterDefinition {
+	return ParameterDefinition{Type: "real", Context: context, Unit: unit, Min: min, Max: max}
+}
+
+func stringParameter(context string) ParameterDefinition {
+	return ParameterDefinition{Type: "string", Context: context}
+}
+
+const (
+	intMax  = math.MaxInt32
+	realMax = math.MaxFloat64
+)
+
+var (
+	logLevels = []string{
+		"debug5", "debug4", "debug3", "debug2", "debug1",
+		"info", "notice", "warning", "error", "log", "fatal", "panic",
+	}
+	tlsVersions = []string{"TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"}
+)
+
+// parameterCatalog lists the parameters that can be set in a configuration
+// file of each supported major version of PostgreSQL. Parameters that only
+// report build options and developer options are omitted. A name can appear
+// more than once when its definition changed between versions.
+// - https://www.postgresql.org/docs/current/runtime-config.html
+var parameterCatalog = map[string][]ParameterDefinition{
+	// File Locations
+	"config_file":       {stringParameter(contextPostmaster)},
+	"data_directory":    {stringParameter(contextPostmaster)},
+	"external_pid_file": {stringParameter(contextPostmaster)},
+	"hba_file":          {stringParameter(contextPostmaster)},
+	"ident_file":        {stringParameter(contextPostmaster)},
+
+	// Connections and Authentication
+	"authentication_timeout":           {integerParameter(contextSighup, "s", 1, 600)},
+	"bonjour":                          {boolParameter(contextPostmaster)},
+	"bonjour_name":                     {stringParameter(contextPostmaster)},
+	"client_connection_check_interval": {integerParameter(contextUser, "ms", 0, intMax).between(14, 0)},
+	"db_user_namespace":                {boolParameter(contextSighup)},
+	"krb_caseins_users":                {boolParameter(contextSighup)},
+	"krb_server_keyfile":               {stringParameter(contextSighup)},
+	"listen_addresses":                 {stringParameter(contextPostmaster)},
+	"max_connections":                  {integerParameter(contextPostmaster, "", 1, 262143)},
+	"password_encryption": {
+		enumParameter(contextUser, "md5", "scram-sha-256", "on", "off").between(0, 13),
+		enumParameter(contextUser, "md5", "scram-sha-256").between(14, 0),
+	},
+	"port":                                   {integerParameter(contextPostmaster, "", 1, 65535)},
+	"ssl":                                    {boolParameter(contextSighup)},
+	"ssl_ca_file":                            {stringParameter(contextSighup)},
+	"ssl_cert_file":                          {stringParameter(contextSighup)},
+	"ssl_ciphers":                            {stringParameter(contextSighup)},
+	"ssl_crl_dir":                            {stringParameter(contextSighup).between(14, 0)},
+	"ssl_crl_file":                           {stringParameter(contextSighup)},
+	"ssl_dh_params_file":                     {stringParameter(contextSighup)},
+	"ssl_ecdh_curve":                         {stringParameter(contextSighup)},
+	"ssl_key_file":                           {stringParameter(contextSighup)},
+	"ssl_max_protocol_version":               {enumParameter(contextSighup, append([]string{""}, tlsVersions...)...).between(12, 0)},
+	"ssl_min_protocol_version":               {enumParameter(contextSighup, tlsVersions...).between(12, 0)},
+	"ssl_passphrase_command":                 {stringParameter(contextSighup).between(11, 0)},
+	"ssl_passphrase_command_supports_reload": {boolParameter(contextSighup).between(11, 0)},
+	"ssl_prefer_server_ciphers":              {boolParameter(contextSighup)},
+	"superuser_reserved_connections":         {integerParameter(contextPostmaster, "", 0, 262143)},
+	"tcp_keepalives_count":                   {integerParameter(contextUser, "", 0, intMax)},
+	"tcp_keepalives_idle":                    {integerParameter(contextUser, "s", 0, intMax)},
+	"tcp_keepalives_interval":                {integerParameter(contextUser, "s", 0, intMax)},
+	"tcp_user_timeout":                       {integerParameter(contextUser, "ms", 0, intMax).between(12, 0)},
+	"unix_socket_directories":                {stringParameter(contextPostmaster)},
+	"unix_socket_group":                      {stringParameter(contextPostmaster)},
+	"unix_socket_permissions":                {integerParameter(contextPostmaster, "", 0, 0777)},
+
+	// Resource Consumption
+	"autovacuum_work_mem":              {integerParameter(contextSighup, "kB", -1, intMax)},
+	"backend_flush_after":              {integerParameter(contextUser, "8kB", 0, 256)},
+	"bgwriter_delay":                   {integerParameter(contextSighup, "ms", 10, 10000)},
+	"bgwriter_flush_after":             {integerParameter(contextSighup, "8kB", 0, 256)},
+	"bgwriter_lru_maxpages":            {integerParameter(contextSighup, "", 0, 1073741823)},
+	"bgwriter_lru_multiplier":          {realParameter(contextSighup, "", 0, 10)},
+	"dynamic_shared_memory_type":       {enumParameter(contextPostmaster, "posix", "sysv", "mmap")},
+	"effective_io_concurrency":         {integerParameter(contextUser, "", 0, 1000)},
+	"hash_mem_multiplier":              {realParameter(contextUser, "", 1, 1000).between(13, 0)},
+	"huge_page_size":                   {integerParameter(contextPostmaster, "kB", 0, intMax).between(14, 0)},
+	"huge_pages":                       {enumParameter(contextPostmaster, "off", "on", "try")},
+	"logical_decoding_work_mem":        {integerParameter(contextUser, "kB", 64, intMax).between(13, 0)},
+	"maintenance_io_concurrency":       {integerParameter(contextUser, "", 0, 1000).between(13, 0)},
+	"maintenance_work_mem":             {integerParameter(contextUser, "kB", 1024, intMax)},
+	"max_files_per_process":            {integerParameter(contextPostmaster, "", 25, intMax)},
+	"max_parallel_maintenance_workers": {integerParameter(contextUser, "", 0, 1024).between(11, 0)},
+	"max_parallel_workers":             {integerParameter(contextUser, "", 0, 1024)},
+	"max_parallel_workers_per_gather":  {integerParameter(contextUser, "", 0, 1024)},
+	"max_prepared_transactions":        {integerParameter(contextPostmaster, "", 0, 262143)},
+	"max_stack_depth":                  {integerParameter(contextSuperuser, "kB", 100, intMax)},
+	"max_worker_processes":             {integerParameter(contextPostmaster, "", 0, 262143)},
+	"min_dynamic_shared_memory":        {integerParameter(contextPostmaster, "MB", 0, intMax).between(14, 0)},
+	"old_snapshot_threshold":           {integerParameter(contextPostmaster, "min", -1, 86400)},
+	"parallel_leader_participation":    {boolParameter(contextUser).between(11, 0)},
+	"shared_buffers":                   {integerParameter(contextPostmaster, "8kB", 16, 1073741823)},
+	"shared_memory_type":               {enumParameter(contextPostmaster, "mmap", "sysv").between(12, 0)},
+	"temp_buffers":                     {integerParameter(contextUser, "8kB", 100, 1073741823)},
+	"temp_file_limit":                  {integerParameter(contextSuperuser, "kB", -1, intMax)},
+	"vacuum_cost_delay": {
+		integerParameter(contextUser, "ms", 0, 100).between(0, 11),
+		realParameter(contextUser, "ms", 0, 100).between(12, 0),
+	},
+	"vacuum_cost_limit":      {integerParameter(contextUser, "", 1, 10000)},
+	"vacuum_cost_page_dirty": {integerParameter(contextUser, "", 0, 10000)},
+	"vacuum_cost_page_hit":   {integerParameter(contextUser, "", 0, 10000)},
+	"vacuum_cost_page_miss":  {integerParameter(contextUser, "", 0, 10000)},
+	"work_mem":               {integerParameter(contextUser, "kB", 64, intMax)},
+
+	// Write Ahead Log
+	"archive_cleanup_command":      {stringParameter(contextSighup).between(12, 0)},
+	"archive_command":              {stringParameter(contextSighup)},
+	"archive_mode":                 {enumParameter(contextPostmaster, "always", "on", "off")},
+	"archive_timeout":              {integerParameter(contextSighup, "s", 0, 1073741823)},
+	"checkpoint_completion_target": {realParameter(contextSighup, "", 0, 1)},
+	"checkpoint_flush_after":       {integerParameter(contextSighup, "8kB", 0, 256)},
+	"checkpoint_timeout":           {integerParameter(contextSighup, "s", 30, 86400)},
+	"checkpoint_warning":           {integerParameter(contextSighup, "s", 0, intMax)},
+	"commit_delay":                 {integerParameter(contextSuperuser, "", 0, 100000)},
+	"commit_siblings":              {integerParameter(contextUser, "", 0, 1000)},
+	"fsync":                        {boolParameter(contextSighup)},
+	"full_page_writes":             {boolParameter(contextSighup)},
+	"max_wal_size":                 {integerParameter(contextSighup, "MB", 2, intMax)},
+	"min_wal_size":                 {integerParameter(contextSighup, "MB", 2, intMax)},
+	"recovery_end_command":         {stringParameter(contextSighup).between(12, 0)},
+	"recovery_init_sync_method":    {enumParameter(contextSighup, "fsync", "syncfs").between(14, 0)},
+	"recovery_target":              {enumParameter(contextPostmaster, "", "immediate").between(12, 0)},
+	"recovery_target_action":       {enumParameter(contextPostmaster, "pause", "promote", "shutdown").between(12, 0)},
+	"recovery_target_inclusive":    {boolParameter(contextPostmaster).between(12, 0)},
+	"recovery_target_lsn":          {stringParameter(contextPostmaster).between(12, 0)},
+	"recovery_target_name":         {stringParameter(contextPostmaster).between(12, 0)},
+	"recovery_target_time":         {stringParameter(contextPostmaster).between(12, 0)},
+	"recovery_target_timeline":     {stringParameter(contextPostmaster).between(12, 0)},
+	"recovery_target_xid":          {stringParameter(contextPostmaster).between(12, 0)},
+	"restore_command": {
+		stringParameter(contextPostmaster).between(12, 13),
+		stringParameter(contextSighup).between(14, 0),
+	},
+	"synchronous_commit": {enumParameter(contextUser,
+		"local", "remote_write", "remote_apply", "on", "off")},
+	"wal_buffers":            {integerParameter(contextPostmaster, "8kB", -1, 262143)},
+	"wal_compression":        {boolParameter(contextSuperuser)},
+	"wal_init_zero":          {boolParameter(contextSuperuser).between(12, 0)},
+	"wal_level":              {enumParameter(contextPostmaster, "minimal", "replica", "logical")},
+	"wal_log_hints":          {boolParameter(contextPostmaster)},
+	"wal_recycle":            {boolParameter(contextSuperuser).between(12, 0)},
+	"wal_skip_threshold":     {integerParameter(contextUser, "kB", 0, intMax).between(13, 0)},
+	"wal_sync_method":        {enumParameter(contextSighup, "fsync", "fdatasync", "open_sync", "open_datasync")},
+	"wal_writer_delay":       {integerParameter(contextSighup, "ms", 1, 10000)},
+	"wal_writer_flush_after": {integerParameter(contextSighup, "8kB", 0, intMax)},
+
+	// Replication
+	"hot_standby":                       {boolParameter(contextPostmaster)},
+	"hot_standby_feedback":              {boolParameter(contextSighup)},
+	"max_logical_replication_workers":   {integerParameter(contextPostmaster, "", 0, 262143)},
+	"max_replication_slots":             {integerParameter(contextPostmaster, "", 0, 262143)},
+	"max_slot_wal_keep_size":            {integerParameter(contextSighup, "MB", -1, intMax).between(13, 0)},
+	"max_standby_archive_delay":         {integerParameter(contextSighup, "ms", -1, intMax)},
+	"max_standby_streaming_delay":       {integerParameter(contextSighup, "ms", -1, intMax)},
+	"max_sync_workers_per_subscription": {integerParameter(contextSighup, "", 0, 262143)},
+	"max_wal_senders":                   {integerParameter(contextPostmaster, "", 0, 262143)},
+	"primary_conninfo": {
+		stringParameter(contextPostmaster).between(12, 12),
+		stringParameter(contextSighup).between(13, 0),
+	},
+	"primary_slot_name": {
+		stringParameter(contextPostmaster).between(12, 12),
+		stringParameter(contextSighup).between(13, 0),
+	},
+	"promote_trigger_file":          {stringParameter(contextSighup).between(12, 0)},
+	"recovery_min_apply_delay":      {integerParameter(contextSighup, "ms", 0, intMax).between(12, 0)},
+	"synchronous_standby_names":     {stringParameter(contextSighup)},
+	"track_commit_timestamp":        {boolParameter(contextPostmaster)},
+	"vacuum_defer_cleanup_age":      {integerParameter(contextSighup, "", 0, 1000000)},
+	"wal_keep_segments":             {integerParameter(contextSighup, "", 0, intMax).between(0, 12)},
+	"wal_keep_size":                 {integerParameter(contextSighup, "MB", 0, intMax).between(13, 0)},
+	"wal_receiver_create_temp_slot": {boolParameter(contextSighup).between(13, 0)},
+	"wal_receiver_status_interval":  {integerParameter(contextSighup, "s", 0, intMax/1000)},
+	"wal_receiver_timeout":          {integerParameter(contextSighup, "ms", 0, intMax)},
+	"wal_retrieve_retry_interval":   {integerParameter(contextSighup, "ms", 1, intMax)},
+	"wal_sender_timeout":            {integerParameter(contextUser, "ms", 0, intMax)},
+
+	// Query Planning
+	"constraint_exclusion":           {enumParameter(contextUser, "partition", "on", "off")},
+	"cpu_index_tuple_cost":           {realParameter(contextUser, "", 0, realMax)},
+	"cpu_operator_cost":              {realParameter(contextUser, "", 0, realMax)},
+	"cpu_tuple_cost":                 {realParameter(contextUser, "", 0, realMax)},
+	"cursor_tuple_fraction":          {realParameter(contextUser, "", 0, 1)},
+	"default_statistics_target":      {integerParameter(contextUser, "", 1, 10000)},
+	"effective_cache_size":           {integerParameter(contextUser, "8kB", 1, intMax)},
+	"enable_async_append":            {boolParameter(contextUser).between(14, 0)},
+	"enable_bitmapscan":              {boolParameter(contextUser)},
+	"enable_gathermerge":             {boolParameter(contextUser)},
+	"enable_hashagg":                 {boolParameter(contextUser)},
+	"enable_hashjoin":                {boolParameter(contextUser)},
+	"enable_incremental_sort":        {boolParameter(contextUser).between(13, 0)},
+	"enable_indexonlyscan":           {boolParameter(contextUser)},
+	"enable_indexscan":               {boolParameter(contextUser)},
+	"enable_material":                {boolParameter(contextUser)},
+	"enable_memoize":                 {boolParameter(contextUser).between(14, 0)},
+	"enable_mergejoin":               {boolParameter(contextUser)},
+	"enable_nestloop":                {boolParameter(contextUser)},
+	"enable_parallel_append":         {boolParameter(contextUser).between(11, 0)},
+	"enable_parallel_hash":           {boolParameter(contextUser).between(11, 0)},
+	"enable_partition_pruning":       {boolParameter(contextUser).between(11, 0)},
+	"enable_partitionwise_aggregate": {boolParameter(contextUser).between(11, 0)},
+	"enable_partitionwise_join":      {boolParameter(contextUser).between(11, 0)},
+	"enable_seqscan":                 {boolParameter(contextUser)},
+	"enable_sort":                    {boolParameter(contextUser)},
+	"enable_tidscan":                 {boolParameter(contextUser)},
+	"force_parallel_mode":            {enumParameter(contextUser, "off", "on", "regress")},
+	"from_collapse_limit":            {integerParameter(contextUser, "", 1, intMax)},
+	"geqo":                           {boolParameter(contextUser)},
+	"geqo_effort":                    {integerParameter(contextUser, "", 1, 10)},
+	"geqo_generations":               {integerParameter(contextUser, "", 0, intMax)},
+	"geqo_pool_size":                 {integerParameter(contextUser, "", 0, intMax)},
+	"geqo_seed":                      {realParameter(contextUser, "", 0, 1)},
+	"geqo_selection_bias":            {realParameter(contextUser, "", 1.5, 2)},
+	"geqo_threshold":                 {integerParameter(contextUser, "", 2, intMax)},
+	"jit":                            {boolParameter(contextUser).between(11, 0)},
+	"jit_above_cost":                 {realParameter(contextUser, "", -1, realMax).between(11, 0)},
+	"jit_inline_above_cost":          {realParameter(contextUser, "", -1, realMax).between(11, 0)},
+	"jit_optimize_above_cost":        {realParameter(contextUser, "", -1, realMax).between(11, 0)},
+	"join_collapse_limit":            {integerParameter(contextUser, "", 1, intMax)},
+	"min_parallel_index_scan_size":   {integerParameter(contextUser, "8kB", 0, 715827882)},
+	"min_parallel_table_scan_size":   {integerParameter(contextUser, "8kB", 0, 715827882)},
+	"parallel_setup_cost":            {realParameter(contextUser, "", 0, realMax)},
+	"parallel_tuple_cost":            {realParameter(contextUser, "", 0, realMax)},
+	"plan_cache_mode": {enumParameter(contextUser,
+		"auto", "force_generic_plan", "force_custom_plan").between(12, 0)},
+	"random_page_cost": {realParameter(contextUser, "", 0, realMax)},
+	"seq_page_cost":    {realParameter(contextUser, "", 0, realMax)},
+
+	// Error Reporting and Logging
+	"application_name":                  {stringParameter(contextUser)},
+	"client_min_messages":               {enumParameter(contextUser, "debug5", "debug4", "debug3", "debug2", "debug1", "log", "notice", "warning", "error")},
+	"cluster_name":                      {stringParameter(contextPostmaster)},
+	"debug_pretty_print":                {boolParameter(contextUser)},
+	"debug_print_parse":                 {boolParameter(contextUser)},
+	"debug_print_plan":                  {boolParameter(contextUser)},
+	"debug_print_rewritten":             {boolParameter(contextUser)},
+	"event_source":                      {stringParameter(contextPostmaster)},
+	"log_autovacuum_min_duration":       {integerParameter(contextSighup, "ms", -1, intMax)},
+	"log_checkpoints":                   {boolParameter(contextSighup)},
+	"log_connections":                   {boolParameter(contextSuperuserBackend)},
+	"log_destination":                   {stringParameter(contextSighup)},
+	"log_directory":                     {stringParameter(contextSighup)},
+	"log_disconnections":                {boolParameter(contextSuperuserBackend)},
+	"log_duration":                      {boolParameter(contextSuperuser)},
+	"log_error_verbosity":               {enumParameter(contextSuperuser, "terse", "default", "verbose")},
+	"log_executor_stats":                {boolParameter(contextSuperuser)},
+	"log_file_mode":                     {integerParameter(contextSighup, "", 0, 0777)},
+	"log_filename":                      {stringParameter(contextSighup)},
+	"log_hostname":                      {boolParameter(contextSighup)},
+	"log_line_prefix":                   {stringParameter(contextSighup)},
+	"log_lock_waits":                    {boolParameter(contextSuperuser)},
+	"log_min_duration_sample":           {integerParameter(contextSuperuser, "ms", -1, intMax).between(13, 0)},
+	"log_min_duration_statement":        {integerParameter(contextSuperuser, "ms", -1, intMax)},
+	"log_min_error_statement":           {enumParameter(contextSuperuser, logLevels...)},
+	"log_min_messages":                  {enumParameter(contextSuperuser, logLevels...)},
+	"log_parameter_max_length":          {integerParameter(contextSuperuser, "B", -1, 1073741823).between(13, 0)},
+	"log_parameter_max_length_on_error": {integerParameter(contextUser, "B", -1, 1073741823).between(13, 0)},
+	"log_parser_stats":                  {boolParameter(contextSuperuser)},
+	"log_planner_stats":                 {boolParameter(contextSuperuser)},
+	"log_recovery_conflict_waits":       {boolParameter(contextSighup).between(14, 0)},
+	"log_replication_commands":          {boolParameter(contextSuperuser)},
+	"log_rotation_age":                  {integerParameter(contextSighup, "min", 0, intMax/60)},
+	"log_rotation_size":                 {integerParameter(contextSighup, "kB", 0, intMax/1024)},
+	"log_statement":                     {enumParameter(contextSuperuser, "none", "ddl", "mod", "all")},
+	"log_statement_sample_rate":         {realParameter(contextSuperuser, "", 0, 1).between(13, 0)},
+	"log_statement_stats":               {boolParameter(contextSuperuser)},
+	"log_temp_files":                    {integerParameter(contextSuperuser, "kB", -1, intMax)},
+	"log_timezone":                      {stringParameter(contextSighup)},
+	"log_transaction_sample_rate":       {realParameter(contextSuperuser, "", 0, 1).between(12, 0)},
+	"log_truncate_on_rotation":          {boolParameter(contextSighup)},
+	"logging_collector":                 {boolParameter(contextPostmaster)},
+	"syslog_facility": {enumParameter(contextSighup,
+		"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7")},
+	"syslog_ident":            {stringParameter(contextSighup)},
+	"syslog_sequence_numbers": {boolParameter(contextSighup)},
+	"syslog_split_messages":   {boolParameter(contextSighup)},
+	"update_process_title":    {boolParameter(contextSuperuser)},
+
+	// Run-time Statistics
+	"compute_query_id":          {enumParameter(contextSuperuser, "auto", "on", "off", "regress").between(14, 0)},
+	"stats_temp_directory":      {stringParameter(contextSighup)},
+	"track_activities":          {boolParameter(contextSuperuser)},
+	"track_activity_query_size": {integerParameter(contextPostmaster, "B", 100, 1048576)},
+	"track_counts":              {boolParameter(contextSuperuser)},
+	"track_functions":           {enumParameter(contextSuperuser, "none", "pl", "all")},
+	"track_io_timing":           {boolParameter(contextSuperuser)},
+	"track_wal_io_timing":       {boolParameter(contextSuperuser).between(14, 0)},
+
+	// Automatic Vacuuming
+	"autovacuum":                          {boolParameter(contextSighup)},
+	"autovacuum_analyze_scale_factor":     {realParameter(contextSighup, "", 0, 100)},
+	"autovacuum_analyze_threshold":        {integerParameter(contextSighup, "", 0, intMax)},
+	"autovacuum_freeze_max_age":           {integerParameter(contextPostmaster, "", 100000, 2000000000)},
+	"autovacuum_max_workers":              {integerParameter(contextPostmaster, "", 1, 262143)},
+	"autovacuum_multixact_freeze_max_age": {integerParameter(contextPostmaster, "", 10000, 2000000000)},
+	"autovacuum_naptime":                  {integerParameter(contextSighup, "s", 1, intMax/1000)},
+	"autovacuum_vacuum_cost_delay": {
+		integerParameter(contextSighup, "ms", -1, 100).between(0, 11),
+		realParameter(contextSighup, "ms", -1, 100).between(12, 0),
+	},
+	"autovacuum_vacuum_cost_limit":          {integerParameter(contextSighup, "", -1, 10000)},
+	"autovacuum_vacuum_insert_scale_factor": {realParameter(contextSighup, "", 0, 100).between(13, 0)},
+	"autovacuum_vacuum_insert_threshold":    {integerParameter(contextSighup, "", -1, intMax).between(13, 0)},
+	"autovacuum_vacuum_scale_factor":        {realParameter(contextSighup, "", 0, 100)},
+	"autovacuum_vacuum_threshold":           {integerParameter(contextSighup, "", 0, intMax)},
+
+	// Client Connection Defaults
+	"bytea_output":                        {enumParameter(contextUser, "escape", "hex")},
+	"check_function_bodies":               {boolParameter(contextUser)},
+	"client_encoding":                     {stringParameter(contextUser)},
+	"datestyle":                           {stringParameter(contextUser)},
+	"default_table_access_method":         {stringParameter(contextUser).between(12, 0)},
+	"default_tablespace":                  {stringParameter(contextUser)},
+	"default_text_search_config":          {stringParameter(contextUser)},
+	"default_toast_compression":           {enumParameter(contextUser, "pglz", "lz4").between(14, 0)},
+	"default_transaction_deferrable":      {boolParameter(contextUser)},
+	"default_transaction_isolation":       {enumParameter(contextUser, "serializable", "repeatable read", "read committed", "read uncommitted")},
+	"default_transaction_read_only":       {boolParameter(contextUser)},
+	"default_with_oids":                   {boolParameter(contextUser).between(0, 11)},
+	"dynamic_library_path":                {stringParameter(contextSuperuser)},
+	"extra_float_digits":                  {integerParameter(contextUser, "", -15, 3)},
+	"gin_fuzzy_search_limit":              {integerParameter(contextUser, "", 0, intMax)},
+	"gin_pending_list_limit":              {integerParameter(contextUser, "kB", 64, intMax)},
+	"idle_in_transaction_session_timeout": {integerParameter(contextUser, "ms", 0, intMax)},
+	"idle_session_timeout":                {integerParameter(contextUser, "ms", 0, intMax).between(14, 0)},
+	"intervalstyle":                       {enumParameter(contextUser, "postgres", "postgres_verbose", "sql_standard", "iso_8601")},
+	"jit_provider":                        {stringParameter(contextPostmaster).between(11, 0)},
+	"lc_messages":                         {stringParameter(contextSuperuser)},
+	"lc_monetary":                         {stringParameter(contextUser)},
+	"lc_numeric":                          {stringParameter(contextUser)},
+	"lc_time":                             {stringParameter(contextUser)},
+	"local_preload_libraries":             {stringParameter(contextUser)},
+	"lock_timeout":                        {integerParameter(contextUser, "ms", 0, intMax)},
+	"row_security":                        {boolParameter(contextUser)},
+	"search_path":                         {stringParameter(contextUser)},
+	"session_preload_libraries":           {stringParameter(contextSuperuser)},
+	"session_replication_role":            {enumParameter(contextSuperuser, "origin", "replica", "local")},
+	"shared_preload_libraries":            {stringParameter(contextPostmaster)},
+	"statement_timeout":                   {integerParameter(contextUser, "ms", 0, intMax)},
+	"temp_tablespaces":                    {stringParameter(contextUser)},
+	"timezone":                            {stringParameter(contextUser)},
+	"timezone_abbreviations":              {stringParameter(contextUser)},
+	"vacuum_cleanup_index_scale_factor":   {realParameter(contextUser, "", 0, 1e10).between(11, 13)},
+	"vacuum_failsafe_age":                 {integerParameter(contextUser, "", 0, 2100000000).between(14, 0)},
+	"vacuum_freeze_min_age":               {integerParameter(contextUser, "", 0, 1000000000)},
+	"vacuum_freeze_table_age":             {integerParameter(contextUser, "", 0, 2000000000)},
+	"vacuum_multixact_failsafe_age":       {integerParameter(contextUser, "", 0, 2100000000).between(14, 0)},
+	"vacuum_multixact_freeze_min_age":     {integerParameter(contextUser, "", 0, 1000000000)},
+	"vacuum_multixact_freeze_table_age":   {integerParameter(contextUser, "", 0, 2000000000)},
+	"xmlbinary":                           {enumParameter(contextUser, "base64", "hex")},
+	"xmloption":                           {enumParameter(contextUser, "content", "document")},
+
+	// Lock Management
+	"deadlock_timeout":               {integerParameter(contextSuperuser, "ms", 1, intMax)},
+	"max_locks_per_transaction":      {integerParameter(contextPostmaster, "", 10, intMax)},
+	"max_pred_locks_per_page":        {integerParameter(contextSighup, "", 0, intMax)},
+	"max_pred_locks_per_relation":    {integerParameter(contextSighup, "", -intMax, intMax)},
+	"max_pred_locks_per_transaction": {integerParameter(contextPostmaster, "", 10, intMax)},
+
+	// Version and Platform Compatibility
+	"array_nulls":                 {boolParameter(contextUser)},
+	"backslash_quote":             {enumParameter(contextUser, "safe_encoding", "on", "off")},
+	"escape_string_warning":       {boolParameter(contextUser)},
+	"lo_compat_privileges":        {boolParameter(contextSuperuser)},
+	"operator_precedence_warning": {boolParameter(contextUser).between(0, 13)},
+	"quote_all_identifiers":       {boolParameter(contextUser)},
+	"standard_conforming_strings": {boolParameter(contextUser)},
+	"synchronize_seqscans":        {boolParameter(contextUser)},
+	"transform_null_equals":       {boolParameter(contextUser)},
+
+	// Error Handling
+	"data_sync_retry":     {boolParameter(contextPostmaster)},
+	"exit_on_error":       {boolParameter(contextUser)},
+	"restart_after_crash": {boolParameter(contextSighup)},
+
+	// Just-in-Time Compilation
+	"jit_debugging_support": {boolParameter(contextSuperuserBackend).between(11, 0)},
+	"jit_dump_bitcode":      {boolParameter(contextSuperuser).between(11, 0)},
+	"jit_expressions":       {boolParameter(contextUser).between(11, 0)},
+	"jit_profiling_support": {boolParameter(contextSuperuserBackend).between(11, 0)},
+	"jit_tuple_deforming":   {boolParameter(contextUser).between(11, 0)},
+}
+
+// LookupParameter returns the definition of parameter name in PostgreSQL
+// version and whether or not there is one.
+func LookupParameter(version int, name string) (ParameterDefinition, bool) {
+	for _, def := range parameterCatalog[strings.ToLower(name)] {
+		if (def.since == 0 || version >= def.since) && (def.until == 0 || version <= def.until) {
+			return def, true
+		}
+	}
+	return ParameterDefinition{}, false
+}
+
+// ValidateParameter returns an error when value cannot be assigned to
+// parameter name in PostgreSQL version. Names that contain a period belong to
+// extensions and are not validated.
+// - https://www.postgresql.org/docs/current/runtime-config-custom.html
+func ValidateParameter(version int, name, value string) error {
+	if strings.Contains(name, ".") {
+		return nil
+	}
+
+	def, ok := LookupParameter(version, name)
+	if !ok {
+		return fmt.Errorf("unrecognized configuration parameter %q in PostgreSQL %d", name, version)
+	}
+	if err := def.validate(value); err != nil {
+		return fmt.Errorf("invalid value for parameter %q: %w", name, err)
+	}
+	return nil
+}
+
+// validate returns an error when value is not valid for def. It follows the
+// rules PostgreSQL uses to parse values in configuration files.
+// - https://www.postgresql.org/docs/current/config-setting.html
+func (def ParameterDefinition) validate(value string) error {
+	switch def.Type {
+	case "bool":
+		if !parseBool(value) {
+			return fmt.Errorf("%q requires a Boolean value", value)
+		}
+
+	case "enum":
+		for _, allowed := range def.Values {
+			if strings.EqualFold(strings.TrimSpace(value), allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %q", value, def.Values)
+
+	case "integer", "real":
+		number, err := def.parseNumber(value)
+		if err != nil {
+			return err
+		}
+		if def.Type == "integer" {
+			number = math.Round(number)
+		}
+		if number < def.Min || number > def.Max {
+			return fmt.Errorf("%q is outside the valid range (%v .. %v)", value, def.Min, def.Max)
+		}
+	}
+	return nil
+}
+
+// parseBool returns whether or not value is one of the ways PostgreSQL spells
+// a Boolean: on, off, true, false, yes, no, 1, 0, or an unambiguous prefix of
+// those.
+func parseBool(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "":
+		return false
+	case value == "1", value == "0":
+		return true
+	case strings.HasPrefix("true", value), strings.HasPrefix("false", value),
+		strings.HasPrefix("yes", value), strings.HasPrefix("no", value):
+		return true
+	case len(value) >= 2 && (strings.HasPrefix("on", value) || strings.HasPrefix("off", value)):
+		return true
+	}
+	return false
+}
+
+// memoryUnits are the memory units PostgreSQL accepts in multiples of bytes.
+var memoryUnits = map[string]float64{
+	"B": 1, "kB": 1 << 10, "8kB": 8 << 10, "MB": 1 << 20, "GB": 1 << 30, "TB": 1 << 40,
+}
+
+// timeUnits are the time units PostgreSQL accepts in multiples of milliseconds.
+var timeUnits = map[string]float64{
+	"us": 0.001, "ms": 1, "s": 1000, "min": 60 * 1000, "h": 60 * 60 * 1000, "d": 24 * 60 * 60 * 1000,
+}
+
+// parseNumber converts value to a number in the unit of def.
+func (def ParameterDefinition) parseNumber(value string) (float64, error) {
+	trimmed := strings.TrimSpace(value)
+
+	// Integers can also be written in octal or hexadecimal, without a unit.
+	if def.Type == "integer" {
+		if integer, err := strconv.ParseInt(trimmed, 0, 64); err == nil {
+			return float64(integer), nil
+		}
+	}
+
+	split := strings.IndexFunc(trimmed, func(r rune) bool {
+		return unicode.IsLetter(r) && r != 'e' && r != 'E'
+	})
+	if split < 0 {
+		split = len(trimmed)
+	}
+	digits, unit := strings.TrimSpace(trimmed[:split]), strings.TrimSpace(trimmed[split:])
+
+	number, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if unit == "" {
+		return number, nil
+	}
+
+	if from, ok := memoryUnits[unit]; ok && unit != "8kB" {
+		if to, ok := memoryUnits[def.Unit]; ok {
+			return number * from / to, nil
+		}
+	}
+	if from, ok := timeUnits[unit]; ok {
+		if to, ok := timeUnits[def.Unit]; ok {
+			return number * from / to, nil
+		}
+	}
+	if def.Unit == "" {
+		return 0, fmt.Errorf("%q has a unit, but the parameter has none", value)
+	}
+	return 0, fmt.Errorf("%q has an invalid unit; the parameter unit is %q", value, def.Unit)
+}
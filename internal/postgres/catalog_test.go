@@ -0,0 +1,136 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParameterCatalog(t *testing.T) {
+	for name, definitions := range parameterCatalog {
+		assert.Equal(t, name, strings.ToLower(name), "expected lowercase names")
+		assert.Assert(t, len(definitions) > 0, "expected %q to have a definition", name)
+
+		for _, def := range definitions {
+			switch def.Type {
+			case "bool", "string":
+			case "enum":
+				assert.Assert(t, len(def.Values) > 0, "expected %q to have values", name)
+			case "integer", "real":
+				assert.Assert(t, def.Min <= def.Max, "expected %q to have a range", name)
+			default:
+				t.Errorf("unexpected type of %q: %q", name, def.Type)
+			}
+		}
+	}
+
+	// Every mandatory and default parameter should be in the catalog.
+	// NOTE: PostgreSQL 10 has no "jit" parameter.
+	parameters := NewParameters()
+	for _, set := range []*ParameterSet{parameters.Mandatory, parameters.Default} {
+		for name, value := range set.AsMap() {
+			for version := 11; version <= 14; version++ {
+				assert.NilError(t, ValidateParameter(version, name, value))
+			}
+		}
+	}
+}
+
+func TestLookupParameter(t *testing.T) {
+	def, ok := LookupParameter(14, "Shared_Buffers")
+	assert.Assert(t, ok, "expected names to be case-insensitive")
+	assert.Assert(t, def.RequiresRestart())
+
+	def, ok = LookupParameter(14, "work_mem")
+	assert.Assert(t, ok)
+	assert.Assert(t, !def.RequiresRestart())
+
+	_, ok = LookupParameter(12, "wal_keep_segments")
+	assert.Assert(t, ok)
+	_, ok = LookupParameter(13, "wal_keep_segments")
+	assert.Assert(t, !ok, "expected removed parameters to be missing")
+
+	_, ok = LookupParameter(12, "wal_keep_size")
+	assert.Assert(t, !ok, "expected new parameters to be missing")
+
+	def, ok = LookupParameter(11, "vacuum_cost_delay")
+	assert.Assert(t, ok)
+	assert.Equal(t, def.Type, "integer")
+
+	def, ok = LookupParameter(12, "vacuum_cost_delay")
+	assert.Assert(t, ok)
+	assert.Equal(t, def.Type, "real")
+}
+
+func TestValidateParameter(t *testing.T) {
+	for _, tt := range []struct {
+		version     int
+		name, value string
+		err         string
+	}{
+		{14, "pg_stat_statements.track", "anything", ""},
+		{14, "nope", "on", `unrecognized configuration parameter "nope"`},
+		{12, "idle_session_timeout", "1min", `unrecognized`},
+
+		// bool
+		{14, "autovacuum", "on", ""},
+		{14, "autovacuum", "OFF", ""},
+		{14, "autovacuum", "t", ""},
+		{14, "autovacuum", "1", ""},
+		{14, "autovacuum", "o", "requires a Boolean value"},
+		{14, "autovacuum", "maybe", "requires a Boolean value"},
+
+		// enum
+		{14, "log_statement", "DDL", ""},
+		{14, "log_statement", "some", `is not one of`},
+		{13, "password_encryption", "on", ""},
+		{14, "password_encryption", "on", `is not one of`},
+
+		// integer
+		{14, "max_connections", "100", ""},
+		{14, "max_connections", "0", "outside the valid range"},
+		{14, "max_connections", "100MB", "has a unit, but the parameter has none"},
+		{14, "shared_buffers", "128MB", ""},
+		{14, "shared_buffers", "16", ""},
+		{14, "shared_buffers", "64kB", "outside the valid range"},
+		{14, "shared_buffers", "10min", "invalid unit"},
+		{14, "statement_timeout", "1.5s", ""},
+		{14, "statement_timeout", "30 min", ""},
+		{14, "statement_timeout", "-1", "outside the valid range"},
+		{14, "statement_timeout", "soon", "is not a number"},
+		{14, "unix_socket_permissions", "0777", ""},
+		{14, "unix_socket_permissions", "0x1FF", ""},
+
+		// real
+		{14, "random_page_cost", "1.1", ""},
+		{14, "checkpoint_completion_target", "1.5", "outside the valid range"},
+		{14, "vacuum_cost_delay", "2ms", ""},
+		{14, "vacuum_cost_delay", "0.5", ""},
+
+		// string
+		{14, "search_path", `"$user", public`, ""},
+	} {
+		err := ValidateParameter(tt.version, tt.name, tt.value)
+		if tt.err == "" {
+			assert.NilError(t, err, "%v", tt)
+		} else {
+			assert.ErrorContains(t, err, tt.err, "%v", tt)
+		}
+	}
+}
@@ -19,10 +19,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/crunchydata/postgres-operator/internal/logging"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
+// managedDatabaseComment marks the databases that WriteDatabasesInPostgreSQL
+// manages. Only databases with this comment are ever dropped.
+const managedDatabaseComment = "managed by postgres-operator"
+
 // CreateDatabasesInPostgreSQL calls exec to create databases that do not exist
 // in PostgreSQL.
 func CreateDatabasesInPostgreSQL(
@@ -81,3 +87,313 @@ SELECT pg_catalog.format('CREATE DATABASE %I',
 
 	return err
 }
+
+// WriteDatabasesInPostgreSQL calls exec to create databases along with their
+// owners, extensions, and schemas. Owners that do not exist are created, and
+// those that are also in users can login. When management says so, databases
+// that were once written this way but are no longer in databases are dropped,
+// except those that users have access to.
+func WriteDatabasesInPostgreSQL(
+	ctx context.Context, exec Executor,
+	databases []v1beta1.PostgresDatabaseSpec, users []v1beta1.PostgresUserSpec,
+	management *v1beta1.PostgresDatabaseManagementSpec,
+) error {
+	log := logging.FromContext(ctx)
+
+	drop := management != nil &&
+		management.RemovedDatabases == v1beta1.PostgresRemovedDatabasesDrop
+
+	// Nothing is written when there is nothing to manage so that the SQL of
+	// existing clusters does not change.
+	if len(databases) == 0 && !drop {
+		return nil
+	}
+
+	logins := make(map[v1beta1.PostgresIdentifier]bool, len(users))
+	keep := []v1beta1.PostgresIdentifier{}
+	for _, user := range users {
+		logins[user.Name] = true
+		keep = append(keep, user.Databases...)
+	}
+	keepJSON, err := json.Marshal(keep)
+	if err != nil {
+		return err
+	}
+
+	var sql bytes.Buffer
+
+	// Prevent unexpected dereferences by emptying "search_path". The "pg_catalog"
+	// schema is still searched, and only temporary objects can be created.
+	// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+	_, _ = sql.WriteString(`SET search_path TO '';`)
+
+	// Fill a temporary table with the JSON of the database specifications.
+	// "\copy" reads from subsequent lines until the special line "\.".
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMANDS-COPY
+	_, _ = sql.WriteString(`
+CREATE TEMPORARY TABLE input (id serial, data json);
+\copy input (data) from stdin with (format text)
+`)
+
+	encoder := json.NewEncoder(&sql)
+	encoder.SetEscapeHTML(false)
+
+	type database struct {
+		Database string `json:"database"`
+		Owner    string `json:"owner"`
+		Login    bool   `json:"login,omitempty"`
+		Encoding string `json:"encoding,omitempty"`
+		Locale   string `json:"locale,omitempty"`
+		Template string `json:"template,omitempty"`
+	}
+	var objects bool
+	for _, spec := range databases {
+		owner := databaseOwner(spec)
+
+		if err == nil {
+			err = encoder.Encode(database{
+				Database: string(spec.Name),
+				Owner:    string(owner),
+				Login:    logins[owner],
+				Encoding: spec.Encoding,
+				Locale:   spec.Locale,
+				Template: string(spec.Template),
+			})
+		}
+		objects = objects || len(spec.Extensions) > 0 || len(spec.Schemas) > 0
+	}
+	_, _ = sql.WriteString(`\.` + "\n")
+
+	// Create owners that do not already exist. Those that are also users can
+	// login, like the users that WriteUsersInPostgreSQL creates.
+	// - https://www.postgresql.org/docs/current/sql-createrole.html
+	_, _ = sql.WriteString(`
+SELECT DISTINCT pg_catalog.format('CREATE ROLE %I WITH %s',
+       pg_catalog.json_extract_path_text(input.data, 'owner'),
+       CASE pg_catalog.json_extract_path_text(input.data, 'login')
+       WHEN 'true' THEN 'LOGIN' ELSE 'NOLOGIN' END)
+  FROM input
+ WHERE NOT EXISTS (
+       SELECT 1 FROM pg_catalog.pg_roles
+       WHERE rolname = pg_catalog.json_extract_path_text(input.data, 'owner'))
+\gexec
+`)
+
+	// Create databases that do not already exist. Options that are missing
+	// from the JSON are NULL, and so are the clauses they are in.
+	// - https://www.postgresql.org/docs/current/sql-createdatabase.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('CREATE DATABASE %I OWNER %I',
+       pg_catalog.json_extract_path_text(input.data, 'database'),
+       pg_catalog.json_extract_path_text(input.data, 'owner'))
+    || COALESCE(' TEMPLATE ' || pg_catalog.quote_ident(
+       pg_catalog.json_extract_path_text(input.data, 'template')), '')
+    || COALESCE(' ENCODING ' || pg_catalog.quote_literal(
+       pg_catalog.json_extract_path_text(input.data, 'encoding')), '')
+    || COALESCE(' LC_COLLATE ' || pg_catalog.quote_literal(
+       pg_catalog.json_extract_path_text(input.data, 'locale')) || ' LC_CTYPE ' ||
+       pg_catalog.quote_literal(
+       pg_catalog.json_extract_path_text(input.data, 'locale')), '')
+  FROM input
+ WHERE NOT EXISTS (
+       SELECT 1 FROM pg_catalog.pg_database
+       WHERE datname = pg_catalog.json_extract_path_text(input.data, 'database'))
+ ORDER BY input.id
+\gexec
+`)
+
+	// Give existing databases to their specified owner and mark them as managed.
+	// - https://www.postgresql.org/docs/current/sql-alterdatabase.html
+	// - https://www.postgresql.org/docs/current/sql-comment.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('ALTER DATABASE %I OWNER TO %I', d.datname,
+       pg_catalog.json_extract_path_text(input.data, 'owner'))
+  FROM input, pg_catalog.pg_database d, pg_catalog.pg_roles r
+ WHERE d.datname = pg_catalog.json_extract_path_text(input.data, 'database')
+   AND r.oid = d.datdba
+   AND r.rolname <> pg_catalog.json_extract_path_text(input.data, 'owner')
+ ORDER BY input.id
+\gexec
+
+SELECT pg_catalog.format('COMMENT ON DATABASE %I IS %L',
+       pg_catalog.json_extract_path_text(input.data, 'database'),
+       '` + managedDatabaseComment + `')
+  FROM input ORDER BY input.id
+\gexec
+`)
+
+	// Drop managed databases that are no longer specified. Prevent new
+	// connections and end existing ones first; a database cannot be dropped
+	// while anyone is connected to it.
+	// - https://www.postgresql.org/docs/current/sql-dropdatabase.html
+	if drop {
+		_, _ = sql.WriteString(`
+SELECT pg_catalog.format('ALTER DATABASE %I WITH ALLOW_CONNECTIONS false', datname),
+       pg_catalog.format('SELECT pg_catalog.pg_terminate_backend(pid) FROM pg_catalog.pg_stat_activity WHERE datname = %L', datname),
+       pg_catalog.format('DROP DATABASE %I', datname)
+  FROM pg_catalog.pg_database
+ WHERE pg_catalog.shobj_description(oid, 'pg_database') = '` + managedDatabaseComment + `'
+   AND datname NOT IN ('postgres', 'template0', 'template1')
+   AND datname NOT IN (
+       SELECT pg_catalog.json_extract_path_text(input.data, 'database') FROM input)
+   AND datname NOT IN (SELECT pg_catalog.json_array_elements_text(:'keep'::json))
+ ORDER BY datname
+\gexec
+`)
+	}
+
+	stdout, stderr, err := exec.Exec(ctx, &sql,
+		map[string]string{
+			"keep": string(keepJSON),
+
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("wrote PostgreSQL databases", "stdout", stdout, "stderr", stderr)
+
+	if err == nil && objects {
+		err = writeDatabaseObjectsInPostgreSQL(ctx, exec, databases)
+	}
+
+	return err
+}
+
+// databaseOwner returns the role that should own the database of spec.
+func databaseOwner(spec v1beta1.PostgresDatabaseSpec) v1beta1.PostgresIdentifier {
+	if spec.Owner == "" {
+		return "postgres"
+	}
+	return spec.Owner
+}
+
+// writeDatabaseObjectsInPostgreSQL calls exec to create the schemas and
+// extensions of databases. Extensions that exist are updated to their specified
+// version or, when there is none, to the default version of the installed
+// packages.
+func writeDatabaseObjectsInPostgreSQL(
+	ctx context.Context, exec Executor, databases []v1beta1.PostgresDatabaseSpec,
+) error {
+	log := logging.FromContext(ctx)
+
+	type extension struct {
+		Database string `json:"database"`
+		Name     string `json:"name"`
+		Version  string `json:"version,omitempty"`
+	}
+	type schema struct {
+		Database string `json:"database"`
+		Name     string `json:"name"`
+		Owner    string `json:"owner"`
+	}
+
+	names := []string{}
+	extensions := []extension{}
+	schemas := []schema{}
+	for _, spec := range databases {
+		if len(spec.Extensions) > 0 || len(spec.Schemas) > 0 {
+			names = append(names, string(spec.Name))
+		}
+		for _, e := range spec.Extensions {
+			extensions = append(extensions, extension{
+				string(spec.Name), string(e.Name), e.Version,
+			})
+		}
+		for _, name := range spec.Schemas {
+			schemas = append(schemas, schema{
+				string(spec.Name), string(name), string(databaseOwner(spec)),
+			})
+		}
+	}
+
+	databasesJSON, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	extensionsJSON, err := json.Marshal(extensions)
+	if err != nil {
+		return err
+	}
+	schemasJSON, err := json.Marshal(schemas)
+	if err != nil {
+		return err
+	}
+
+	const (
+		extensionsInDatabase = `
+  FROM pg_catalog.json_to_recordset(:'extensions'::json)
+       AS e (database text, name text, version text)`
+		schemasInDatabase = `
+  FROM pg_catalog.json_to_recordset(:'schemas'::json)
+       AS s (database text, name text, owner text)`
+	)
+
+	stdout, stderr, err := exec.ExecInDatabasesFromQuery(ctx,
+		// Prevent unexpected dereferences by emptying "search_path".
+		// The "pg_catalog" schema is still searched.
+		// - https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-SEARCH-PATH
+		`SET search_path = '';`+
+			`SELECT datname FROM pg_catalog.pg_database WHERE datallowconn`+
+			` AND datname IN (SELECT pg_catalog.json_array_elements_text(:'databases'::json))`,
+		strings.Join([]string{
+			// Quiet NOTICE messages from IF NOT EXISTS statements.
+			// - https://www.postgresql.org/docs/current/runtime-config-client.html
+			`SET client_min_messages = WARNING;`,
+
+			// Create the following objects in a transaction.
+			`BEGIN;`,
+
+			// Create schemas owned by the database owner, and give existing
+			// schemas to them.
+			// - https://www.postgresql.org/docs/current/sql-createschema.html
+			strings.TrimSpace(`
+SELECT pg_catalog.format('CREATE SCHEMA IF NOT EXISTS %I AUTHORIZATION %I', s.name, s.owner)` +
+				schemasInDatabase + `
+ WHERE s.database = pg_catalog.current_database()
+ ORDER BY s.name
+\gexec`),
+			strings.TrimSpace(`
+SELECT pg_catalog.format('ALTER SCHEMA %I OWNER TO %I', n.nspname, s.owner)` +
+				schemasInDatabase + `, pg_catalog.pg_namespace n, pg_catalog.pg_roles r
+ WHERE s.database = pg_catalog.current_database()
+   AND n.nspname = s.name AND r.oid = n.nspowner AND r.rolname <> s.owner
+ ORDER BY s.name
+\gexec`),
+
+			// Install extensions along with any they depend on, then bring
+			// those that exist to their expected version.
+			// - https://www.postgresql.org/docs/current/sql-createextension.html
+			// - https://www.postgresql.org/docs/current/sql-alterextension.html
+			strings.TrimSpace(`
+SELECT pg_catalog.format('CREATE EXTENSION IF NOT EXISTS %I', e.name)
+    || COALESCE(' VERSION ' || pg_catalog.quote_literal(e.version), '') || ' CASCADE'` +
+				extensionsInDatabase + `
+ WHERE e.database = pg_catalog.current_database()
+ ORDER BY e.name
+\gexec`),
+			strings.TrimSpace(`
+SELECT pg_catalog.format('ALTER EXTENSION %I UPDATE', x.extname)
+    || COALESCE(' TO ' || pg_catalog.quote_literal(e.version), '')` +
+				extensionsInDatabase + `, pg_catalog.pg_extension x, pg_catalog.pg_available_extensions a
+ WHERE e.database = pg_catalog.current_database()
+   AND x.extname = e.name AND a.name = e.name
+   AND x.extversion <> COALESCE(e.version, a.default_version)
+ ORDER BY e.name
+\gexec`),
+
+			// Commit (finish) the transaction.
+			`COMMIT;`,
+		}, "\n"),
+		map[string]string{
+			"databases":  string(databasesJSON),
+			"extensions": string(extensionsJSON),
+			"schemas":    string(schemasJSON),
+
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("wrote PostgreSQL schemas and extensions", "stdout", stdout, "stderr", stderr)
+
+	return err
+}
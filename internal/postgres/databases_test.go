@@ -25,6 +25,7 @@ import (
 	"gotest.tools/v3/assert"
 
 	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
 func TestCreateDatabasesInPostgreSQL(t *testing.T) {
@@ -101,3 +102,139 @@ SELECT pg_catalog.format('CREATE DATABASE %I',
 		assert.Equal(t, calls, 1)
 	})
 }
+
+func TestWriteDatabasesInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			t.Fatal("expected no SQL")
+			return nil
+		}
+
+		assert.NilError(t, WriteDatabasesInPostgreSQL(ctx, exec, nil, nil, nil))
+		assert.NilError(t, WriteDatabasesInPostgreSQL(ctx, exec, nil, nil,
+			&v1beta1.PostgresDatabaseManagementSpec{
+				RemovedDatabases: v1beta1.PostgresRemovedDatabasesKeep,
+			}))
+	})
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+
+		assert.Equal(t, expected, WriteDatabasesInPostgreSQL(ctx, exec,
+			[]v1beta1.PostgresDatabaseSpec{{Name: "app"}}, nil, nil))
+	})
+
+	t.Run("Databases", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `
+\copy input (data) from stdin with (format text)
+{"database":"app","owner":"postgres"}
+{"database":"white space","owner":"someone","login":true,"encoding":"UTF8","locale":"C","template":"template0"}
+{"database":"other","owner":"nobody"}
+\.
+`))
+			assert.Assert(t, cmp.Contains(string(b), `CREATE ROLE %I WITH %s`))
+			assert.Assert(t, cmp.Contains(string(b), `COMMENT ON DATABASE %I IS %L`))
+			assert.Assert(t, !strings.Contains(string(b), `DROP DATABASE`))
+			assert.Assert(t, cmp.Contains(strings.Join(command, "\n"), `--set=keep=["db1"]`))
+			return nil
+		}
+
+		assert.NilError(t, WriteDatabasesInPostgreSQL(ctx, exec,
+			[]v1beta1.PostgresDatabaseSpec{
+				{Name: "app"},
+				{
+					Name: "white space", Owner: "someone",
+					Encoding: "UTF8", Locale: "C", Template: "template0",
+				},
+				{Name: "other", Owner: "nobody"},
+			},
+			[]v1beta1.PostgresUserSpec{
+				{Name: "someone", Databases: []v1beta1.PostgresIdentifier{"db1"}},
+			},
+			nil))
+		assert.Equal(t, calls, 1, "expected no schemas nor extensions")
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `
+SELECT pg_catalog.format('ALTER DATABASE %I WITH ALLOW_CONNECTIONS false', datname),`))
+			assert.Assert(t, cmp.Contains(string(b), `
+       pg_catalog.format('DROP DATABASE %I', datname)
+  FROM pg_catalog.pg_database
+ WHERE pg_catalog.shobj_description(oid, 'pg_database') = 'managed by postgres-operator'`))
+			return nil
+		}
+
+		assert.NilError(t, WriteDatabasesInPostgreSQL(ctx, exec, nil, nil,
+			&v1beta1.PostgresDatabaseManagementSpec{
+				RemovedDatabases: v1beta1.PostgresRemovedDatabasesDrop,
+			}))
+		assert.Equal(t, calls, 1)
+	})
+
+	t.Run("Objects", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+			if calls == 1 {
+				return nil
+			}
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `CREATE SCHEMA IF NOT EXISTS %I AUTHORIZATION %I`))
+			assert.Assert(t, cmp.Contains(string(b), `CREATE EXTENSION IF NOT EXISTS %I`))
+			assert.Assert(t, cmp.Contains(string(b), `ALTER EXTENSION %I UPDATE`))
+
+			assert.Assert(t, cmp.Contains(strings.Join(command, "\n"),
+				`--set=databases=["app"]`))
+			assert.Assert(t, cmp.Contains(strings.Join(command, "\n"),
+				`--set=extensions=[{"database":"app","name":"pg_trgm"},{"database":"app","name":"postgis","version":"3.2.1"}]`))
+			assert.Assert(t, cmp.Contains(strings.Join(command, "\n"),
+				`--set=schemas=[{"database":"app","name":"reports","owner":"someone"}]`))
+			return nil
+		}
+
+		assert.NilError(t, WriteDatabasesInPostgreSQL(ctx, exec,
+			[]v1beta1.PostgresDatabaseSpec{
+				{Name: "plain"},
+				{
+					Name: "app", Owner: "someone",
+					Extensions: []v1beta1.PostgresExtensionSpec{
+						{Name: "pg_trgm"}, {Name: "postgis", Version: "3.2.1"},
+					},
+					Schemas: []v1beta1.PostgresIdentifier{"reports"},
+				},
+			}, nil, nil))
+		assert.Equal(t, calls, 2)
+	})
+}
@@ -17,7 +17,11 @@ package postgres
 
 import (
 	"fmt"
+	"net"
+	"sort"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // NewHBAs returns HostBasedAuthentication records required by this package.
@@ -46,8 +50,10 @@ func NewHBAs() HBAs {
 	}
 }
 
-// HBAs is a pairing of HostBasedAuthentication records.
-type HBAs struct{ Mandatory, Default []HostBasedAuthentication }
+// HBAs is a grouping of HostBasedAuthentication records. Mandatory records
+// come first, followed by any Specified in the cluster spec. Default records
+// are used only when nothing else is configured.
+type HBAs struct{ Mandatory, Default, Specified []HostBasedAuthentication }
 
 // HostBasedAuthentication represents a single record for pg_hba.conf.
 // - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
@@ -88,6 +94,16 @@ func (hba *HostBasedAuthentication) Database(name string) *HostBasedAuthenticati
 	return hba
 }
 
+// Databases makes hba match connections made to any of the named databases.
+func (hba *HostBasedAuthentication) Databases(names ...string) *HostBasedAuthentication {
+	quoted := make([]string, len(names))
+	for i := range names {
+		quoted[i] = hba.quote(names[i])
+	}
+	hba.database = strings.Join(quoted, ",")
+	return hba
+}
+
 // Local makes hba match connection attempts using Unix-domain sockets.
 func (hba *HostBasedAuthentication) Local() *HostBasedAuthentication {
 	hba.origin = "local"
@@ -114,9 +130,15 @@ func (hba *HostBasedAuthentication) NoSSL() *HostBasedAuthentication {
 
 // Options specifies any options for the authentication method.
 func (hba *HostBasedAuthentication) Options(opts map[string]string) *HostBasedAuthentication {
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	hba.options = ""
-	for k, v := range opts {
-		hba.options = fmt.Sprintf("%s %s=%s", hba.options, k, hba.quote(v))
+	for _, k := range keys {
+		hba.options = fmt.Sprintf("%s %s=%s", hba.options, k, hba.quote(opts[k]))
 	}
 	return hba
 }
@@ -133,6 +155,15 @@ func (hba *HostBasedAuthentication) Role(name string) *HostBasedAuthentication {
 	return hba
 }
 
+// Roles makes hba match connections by users that are members of any of the
+// named roles. It can be combined with Users to match either.
+func (hba *HostBasedAuthentication) Roles(names ...string) *HostBasedAuthentication {
+	for i := range names {
+		hba.addUser("+" + hba.quote(names[i]))
+	}
+	return hba
+}
+
 // SameNetwork makes hba match connection attempts from IP addresses in any
 // subnet to which the server is directly connected.
 func (hba *HostBasedAuthentication) SameNetwork() *HostBasedAuthentication {
@@ -158,6 +189,23 @@ func (hba *HostBasedAuthentication) User(name string) *HostBasedAuthentication {
 	return hba
 }
 
+// Users makes hba match connections by any of the named users. It can be
+// combined with Roles to match either.
+func (hba *HostBasedAuthentication) Users(names ...string) *HostBasedAuthentication {
+	for i := range names {
+		hba.addUser(hba.quote(names[i]))
+	}
+	return hba
+}
+
+func (hba *HostBasedAuthentication) addUser(value string) {
+	if hba.user == "all" || hba.user == "" {
+		hba.user = value
+	} else {
+		hba.user += "," + value
+	}
+}
+
 // String returns hba formatted for the pg_hba.conf file without a newline.
 func (hba HostBasedAuthentication) String() string {
 	if hba.origin == "local" {
@@ -168,3 +216,34 @@ func (hba HostBasedAuthentication) String() string {
 	return strings.TrimSpace(fmt.Sprintf("%s %s %s %s %s %s",
 		hba.origin, hba.database, hba.user, hba.address, hba.method, hba.options))
 }
+
+// ValidateHBARule returns an error when the parts of an authentication rule
+// that PostgreSQL and PgBouncer have in common cannot be written to an HBA
+// file. An empty connection means "hostssl". Each record is one line of the
+// file, so names cannot contain line breaks.
+func ValidateHBARule(connection, method string, networks []string, names ...string) error {
+	switch connection {
+	case "local", "host", "hostnossl", "hostssl", "":
+	default:
+		return fmt.Errorf("unknown connection %q", connection)
+	}
+
+	switch {
+	case method == "cert" && !(connection == "hostssl" || connection == ""):
+		return errors.New(`the "cert" method requires a hostssl connection`)
+	case connection == "local" && len(networks) > 0:
+		return errors.New("networks are not allowed for local connections")
+	}
+
+	for _, name := range names {
+		if strings.ContainsAny(name, "\r\n") {
+			return errors.New("names cannot contain line breaks")
+		}
+	}
+	for _, network := range networks {
+		if _, _, err := net.ParseCIDR(network); err != nil {
+			return fmt.Errorf("invalid network %q: expected CIDR notation", network)
+		}
+	}
+	return nil
+}
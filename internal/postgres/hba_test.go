@@ -70,4 +70,37 @@ func TestHostBasedAuthentication(t *testing.T) {
 
 	assert.Equal(t, `hostnossl all all all reject`,
 		NewHBA().NoSSL().Method("reject").String())
+
+	assert.Equal(t, `hostssl "one","two" "alice",+"staff" "10.0.0.0/8" cert  clientname="DN" map="certs"`,
+		NewHBA().TLS().Databases("one", "two").Users("alice").Roles("staff").
+			Network("10.0.0.0/8").Method("cert").
+			Options(map[string]string{"map": "certs", "clientname": "DN"}).
+			String())
+
+	assert.Equal(t, `host all +"a",+"b" all md5`,
+		NewHBA().TCP().Roles("a", "b").Method("md5").String())
+}
+
+func TestValidateHBARule(t *testing.T) {
+	assert.NilError(t, ValidateHBARule("", "cert", nil, "app"))
+	assert.NilError(t, ValidateHBARule("host", "md5", []string{"10.0.0.0/8", "fd00::/8"}))
+	assert.NilError(t, ValidateHBARule("local", "peer", nil))
+
+	for _, tt := range []struct {
+		connection, method string
+		networks, names    []string
+		message            string
+	}{
+		{connection: "hostgssenc", method: "md5", message: `unknown connection "hostgssenc"`},
+		{connection: "host", method: "cert", message: `the "cert" method requires a hostssl connection`},
+		{
+			connection: "local", method: "trust", networks: []string{"10.0.0.0/8"},
+			message: "networks are not allowed for local connections",
+		},
+		{method: "md5", names: []string{"a\nhost all all all trust"}, message: "names cannot contain line breaks"},
+		{method: "md5", networks: []string{"samenet"}, message: `invalid network "samenet"`},
+	} {
+		err := ValidateHBARule(tt.connection, tt.method, tt.networks, tt.names...)
+		assert.ErrorContains(t, err, tt.message)
+	}
 }
@@ -0,0 +1,133 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/crunchydata/postgres-operator/internal/logging"
+)
+
+// MigrationChecksum returns the SHA-256 checksum of sql in hexadecimal.
+func MigrationChecksum(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:])
+}
+
+// ApplyMigrationInPostgreSQL calls exec to run sql in database unless it has
+// already run there. The SQL and a record of its version and checksum are
+// committed in one transaction, so sql cannot contain statements that do not
+// run in a transaction block nor those that end it. It is an error when the
+// version has run with a different checksum.
+//
+// The SQL is sent to PostgreSQL as-is: psql meta-commands and variables in it
+// are not interpreted.
+func ApplyMigrationInPostgreSQL(
+	ctx context.Context, exec Executor, database, version, checksum, sql string,
+) error {
+	log := logging.FromContext(ctx)
+
+	// Quote sql with a tag that it does not contain. psql does not interpret
+	// anything inside a dollar-quoted string.
+	// - https://www.postgresql.org/docs/current/sql-syntax-lexical.html#SQL-SYNTAX-DOLLAR-QUOTING
+	tag := "$migration$"
+	for i := 0; strings.Contains(sql, tag); i++ {
+		tag = fmt.Sprintf("$migration%d$", i)
+	}
+
+	stdout, stderr, err := exec.ExecInDatabasesFromQuery(ctx,
+		`SELECT :'database'`,
+		strings.Join([]string{
+			// Quiet NOTICE messages from IF NOT EXISTS statements.
+			// - https://www.postgresql.org/docs/current/runtime-config-client.html
+			`SET client_min_messages = WARNING;`,
+			`CREATE SCHEMA IF NOT EXISTS postgres_operator;`,
+			strings.TrimSpace(`
+CREATE TABLE IF NOT EXISTS postgres_operator.migrations (
+  version text PRIMARY KEY,
+  checksum text NOT NULL,
+  applied_at timestamp with time zone NOT NULL DEFAULT pg_catalog.now()
+);`),
+			`RESET client_min_messages;`,
+
+			// Run the migration and record it in a transaction. Lock the table
+			// so that only one session runs the migration.
+			`BEGIN;`,
+			`LOCK TABLE postgres_operator.migrations IN SHARE ROW EXCLUSIVE MODE;`,
+
+			// Fail when the migration has run with different SQL. Variables are
+			// not interpolated in function bodies, so pass them as settings.
+			// - https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-ADMIN-SET
+			strings.TrimSpace(`
+SELECT pg_catalog.set_config('postgres_operator.version', :'version', true),
+       pg_catalog.set_config('postgres_operator.checksum', :'checksum', true)
+\gset`),
+			strings.TrimSpace(`
+DO $$ BEGIN
+  IF EXISTS (
+    SELECT 1 FROM postgres_operator.migrations
+     WHERE version = pg_catalog.current_setting('postgres_operator.version')
+       AND checksum <> pg_catalog.current_setting('postgres_operator.checksum'))
+  THEN
+    RAISE EXCEPTION 'migration % has changed since it ran',
+      pg_catalog.current_setting('postgres_operator.version');
+  END IF;
+END $$;`),
+
+			// Run the migration when it has not run.
+			// - https://www.postgresql.org/docs/current/app-psql.html#PSQL-METACOMMAND-IF
+			strings.TrimSpace(`
+SELECT NOT EXISTS (
+       SELECT 1 FROM postgres_operator.migrations WHERE version = :'version'
+       ) AS pending
+\gset`),
+			// Run the quoted SQL with "\gexec" and check that the transaction
+			// did not end along the way; the settings above are local to it.
+			// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMANDS-GEXEC
+			`\if :pending`,
+			`SELECT ` + tag + sql + tag + ` \gexec`,
+			strings.TrimSpace(`
+DO $$ BEGIN
+  IF COALESCE(pg_catalog.current_setting('postgres_operator.version', true), '') = '' THEN
+    RAISE EXCEPTION 'migration must not end its transaction';
+  END IF;
+END $$;`),
+			`INSERT INTO postgres_operator.migrations (version, checksum) VALUES (:'version', :'checksum');`,
+			`\endif`,
+
+			// Commit (finish) the transaction.
+			`COMMIT;`,
+		}, "\n"),
+		map[string]string{
+			"database": database,
+			"checksum": checksum,
+			"version":  version,
+
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("applied PostgreSQL migration", "stdout", stdout, "stderr", stderr)
+
+	if err != nil && strings.TrimSpace(stderr) != "" {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/testing/cmp"
+)
+
+func TestMigrationChecksum(t *testing.T) {
+	assert.Equal(t, MigrationChecksum(""),
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	assert.Assert(t, MigrationChecksum("SELECT 1;") != MigrationChecksum("SELECT 2;"))
+}
+
+func TestApplyMigrationInPostgreSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Arguments", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			_, _ = stderr.Write([]byte("ERROR:  migration 001 has changed since it ran\n"))
+			return expected
+		}
+
+		err := ApplyMigrationInPostgreSQL(ctx, exec, "db", "001", "abc", "")
+		assert.Assert(t, errors.Is(err, expected))
+		assert.ErrorContains(t, err, "migration 001 has changed")
+	})
+
+	t.Run("Script", func(t *testing.T) {
+		calls := 0
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			calls++
+
+			// The database query is the first argument after the script.
+			assert.Equal(t, command[4], `-`)
+			assert.Equal(t, command[5], `SELECT :'database'`)
+			assert.DeepEqual(t, command[6:], []string{
+				`--set=ON_ERROR_STOP=on`,
+				`--set=QUIET=on`,
+				`--set=checksum=abc`,
+				`--set=database=white space`,
+				`--set=version=001`,
+			})
+
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.Contains(string(b), `
+BEGIN;
+LOCK TABLE postgres_operator.migrations IN SHARE ROW EXCLUSIVE MODE;`))
+			assert.Assert(t, cmp.Contains(string(b), `
+\if :pending
+SELECT $migration$CREATE TABLE things ();$migration$ \gexec
+DO $$ BEGIN`))
+			assert.Assert(t, cmp.Contains(string(b), `
+INSERT INTO postgres_operator.migrations (version, checksum) VALUES (:'version', :'checksum');
+\endif
+COMMIT;`))
+			assert.Assert(t, strings.HasSuffix(string(b), "COMMIT;"))
+			return nil
+		}
+
+		assert.NilError(t, ApplyMigrationInPostgreSQL(ctx, exec,
+			"white space", "001", "abc", `CREATE TABLE things ();`))
+		assert.Equal(t, calls, 1)
+	})
+
+	t.Run("Quoting", func(t *testing.T) {
+		var script string
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, _ ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			script = string(b)
+			return err
+		}
+
+		// Meta-commands, variables, and the default tag stay inside the quotes.
+		sql := "SELECT $migration$ :'version' $migration$;\n\\! rm -rf /"
+		assert.NilError(t, ApplyMigrationInPostgreSQL(ctx, exec, "db", "001", "abc", sql))
+		assert.Assert(t, cmp.Contains(script,
+			"\nSELECT $migration0$"+sql+"$migration0$ \\gexec\n"))
+	})
+}
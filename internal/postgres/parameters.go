@@ -16,6 +16,8 @@
 package postgres
 
 import (
+	"context"
+	"fmt"
 	"strings"
 )
 
@@ -24,6 +26,7 @@ func NewParameters() Parameters {
 	parameters := Parameters{
 		Mandatory: NewParameterSet(),
 		Default:   NewParameterSet(),
+		Specified: NewParameterSet(),
 	}
 
 	// Use UNIX domain sockets for local connections.
@@ -59,8 +62,9 @@ func NewParameters() Parameters {
 	return parameters
 }
 
-// Parameters is a pairing of ParameterSets.
-type Parameters struct{ Mandatory, Default *ParameterSet }
+// Parameters is a grouping of ParameterSets. Specified parameters override
+// Default ones, and Mandatory parameters override both.
+type Parameters struct{ Mandatory, Default, Specified *ParameterSet }
 
 // ParameterSet is a collection of PostgreSQL parameters.
 // - https://www.postgresql.org/docs/current/config-setting.html
@@ -119,3 +123,24 @@ func (ps ParameterSet) Value(name string) string {
 	value, _ := ps.Get(name)
 	return value
 }
+
+// PendingRestart returns the names of parameters that have changed in the
+// configuration files but cannot take effect until PostgreSQL restarts.
+// - https://www.postgresql.org/docs/current/view-pg-settings.html
+func (exec Executor) PendingRestart(ctx context.Context) ([]string, error) {
+	const sql = `
+\pset format unaligned
+\pset tuples_only on
+SELECT name FROM pg_catalog.pg_settings WHERE pending_restart ORDER BY name;
+`
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(sql),
+		map[string]string{
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, stderr)
+	}
+
+	return strings.Fields(stdout), nil
+}
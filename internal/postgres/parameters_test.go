@@ -16,6 +16,10 @@
 package postgres
 
 import (
+	"context"
+	"errors"
+	"io"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -39,6 +43,7 @@ func TestNewParameters(t *testing.T) {
 
 		"password_encryption": "scram-sha-256",
 	})
+	assert.DeepEqual(t, parameters.Specified.AsMap(), map[string]string{})
 }
 
 func TestParameterSet(t *testing.T) {
@@ -68,3 +73,37 @@ func TestParameterSet(t *testing.T) {
 	ps2.Add("x", "n")
 	assert.Assert(t, ps2.Value("x") != ps.Value("x"))
 }
+
+func TestExecutorPendingRestart(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command[:3], []string{"psql", "-Xw", "--file=-"})
+
+			b, _ := io.ReadAll(stdin)
+			assert.Assert(t, strings.Contains(string(b), "pending_restart"))
+
+			_, _ = stdout.Write([]byte("max_connections\nshared_buffers\n"))
+			return nil
+		}
+
+		names, err := Executor(exec).PendingRestart(ctx)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, names, []string{"max_connections", "shared_buffers"})
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, _, stderr io.Writer, _ ...string,
+		) error {
+			_, _ = stderr.Write([]byte("boom"))
+			return errors.New("exit 2")
+		}
+
+		_, err := Executor(exec).PendingRestart(ctx)
+		assert.ErrorContains(t, err, "exit 2: boom")
+	})
+}
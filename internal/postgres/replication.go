@@ -0,0 +1,66 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReplicationLag returns how many bytes of write-ahead log each streaming
+// replica has yet to replay, keyed by its "application_name". Patroni sets that
+// to the name of the replica's Pod. It should be called on the primary or, in a
+// standby cluster, on the standby leader. Replicas that have not reported a
+// replay position are omitted.
+// - https://www.postgresql.org/docs/current/monitoring-stats.html#MONITORING-PG-STAT-REPLICATION-VIEW
+func (exec Executor) ReplicationLag(ctx context.Context) (map[string]int64, error) {
+	const sql = `
+\pset format unaligned
+\pset tuples_only on
+SELECT application_name, pg_catalog.pg_wal_lsn_diff(CASE
+  WHEN pg_catalog.pg_is_in_recovery() THEN pg_catalog.pg_last_wal_replay_lsn()
+  ELSE pg_catalog.pg_current_wal_lsn() END, replay_lsn)::bigint
+  FROM pg_catalog.pg_stat_replication WHERE replay_lsn IS NOT NULL;
+`
+	stdout, stderr, err := exec.Exec(ctx, strings.NewReader(sql),
+		map[string]string{
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, stderr)
+	}
+
+	lag := make(map[string]int64)
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		name, bytes, found := strings.Cut(line, "|")
+		if !found {
+			continue
+		}
+		value, err := strconv.ParseInt(bytes, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected replication lag %q: %w", line, err)
+		}
+		// A replica can be ahead of a standby leader for a moment.
+		if value < 0 {
+			value = 0
+		}
+		lag[name] = value
+	}
+	return lag, nil
+}
@@ -0,0 +1,75 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestExecutorReplicationLag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Output", func(t *testing.T) {
+		exec := func(
+			_ context.Context, stdin io.Reader, stdout, _ io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command[:3], []string{"psql", "-Xw", "--file=-"})
+			assert.Assert(t, strings.Contains(strings.Join(command, " "), "--set=ON_ERROR_STOP=on"))
+
+			b, _ := io.ReadAll(stdin)
+			assert.Assert(t, strings.Contains(string(b), "pg_stat_replication"))
+
+			_, _ = stdout.Write([]byte("hippo-00-abcd-0|0\nhippo-00-wxyz-0|16384\nahead|-8\n"))
+			return nil
+		}
+
+		lag, err := Executor(exec).ReplicationLag(ctx)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, lag, map[string]int64{
+			"hippo-00-abcd-0": 0,
+			"hippo-00-wxyz-0": 16384,
+			"ahead":           0,
+		})
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		exec := func(context.Context, io.Reader, io.Writer, io.Writer, ...string) error {
+			return nil
+		}
+
+		lag, err := Executor(exec).ReplicationLag(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, len(lag), 0)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		exec := func(
+			_ context.Context, _ io.Reader, _, stderr io.Writer, _ ...string,
+		) error {
+			_, _ = stderr.Write([]byte("boom"))
+			return errors.New("exit 2")
+		}
+
+		_, err := Executor(exec).ReplicationLag(ctx)
+		assert.ErrorContains(t, err, "exit 2: boom")
+	})
+}
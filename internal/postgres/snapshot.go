@@ -0,0 +1,144 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backupModeDirectory is where the files of backup mode sessions are written.
+// Each session has a directory named for its label.
+const backupModeDirectory = "/tmp/pgo-backup-mode"
+
+// States of a backup mode session as reported by [Executor.BackupMode].
+const (
+	BackupModeStarting = "starting"
+	BackupModeStarted  = "started"
+	BackupModeStopped  = "stopped"
+	BackupModeFailed   = "failed"
+)
+
+// StartBackupMode starts a "psql" session in the background that puts PostgreSQL
+// into non-exclusive backup mode using label. The session waits until
+// [Executor.FinishBackupMode] is called or timeout elapses before it leaves
+// backup mode. It waits for the WAL of the backup to be archived. Any previous
+// sessions are told to leave backup mode, and those that have are removed, so
+// that one left behind by an interrupted snapshot does not hold backup mode
+// until its timeout.
+// - https://www.postgresql.org/docs/current/continuous-archiving.html#BACKUP-LOWLEVEL-BASE-BACKUP
+func (exec Executor) StartBackupMode(
+	ctx context.Context, version int, label string, timeout time.Duration,
+) error {
+	// PostgreSQL 15 renamed the functions and removed exclusive backups.
+	start := `SELECT pg_catalog.pg_start_backup(:'label', true, false);`
+	stop := `SELECT labelfile FROM pg_catalog.pg_stop_backup(false, true);`
+	if version >= 15 {
+		start = `SELECT pg_catalog.pg_backup_start(:'label', true);`
+		stop = `SELECT labelfile FROM pg_catalog.pg_backup_stop(true);`
+	}
+
+	// Meta-commands in psql cannot span lines, so the wait is one line. The
+	// "\!" meta-command does not interpolate psql variables, but it runs a
+	// shell that sees the exported environment of psql. The session runs in
+	// its directory, so relative paths are written there.
+	// - https://www.postgresql.org/docs/current/app-psql.html#APP-PSQL-META-COMMANDS
+	session := strings.Join([]string{
+		`\set QUIET on`,
+		`\pset format unaligned`,
+		`\pset tuples_only on`,
+		start,
+		`\! echo ` + BackupModeStarted + ` > state`,
+		`\! for _ in $(seq "${timeout}"); do [ ! -e taken ] || break; sleep 1; done`,
+		`\o backup_label`,
+		stop,
+		`\o`,
+		`\! echo ` + BackupModeStopped + ` > state`,
+	}, "\n") + "\n"
+
+	// Write the session to a file then run it in the background with its
+	// output redirected so that this command returns immediately.
+	const script = `
+declare -r sessions="$1" directory="$1/$2"
+declare -rx timeout="$3"
+shift 3
+for previous in "${sessions}"/*/; do
+  [ -d "${previous}" ] || continue
+  case "$(cat "${previous}state" 2> /dev/null || true)" in
+    ` + BackupModeStopped + `|` + BackupModeFailed + `) rm -rf "${previous}" ;;
+    *) touch "${previous}taken" ;;
+  esac
+done
+rm -rf "${directory}"
+install --directory --mode=0700 "${sessions}" "${directory}"
+cat > "${directory}/session.sql"
+echo ` + BackupModeStarting + ` > "${directory}/state"
+cd "${directory}"
+nohup bash -c 'psql "$@" || echo ` + BackupModeFailed + ` > state' - "$@" \
+  --file=session.sql > log 2>&1 < /dev/null &
+`
+	var stdout, stderr bytes.Buffer
+	err := exec(ctx, strings.NewReader(session), &stdout, &stderr,
+		"bash", "-ceu", "--", script, "-", backupModeDirectory, label,
+		strconv.Itoa(int(timeout.Seconds())),
+		"-Xw", "--set=ON_ERROR_STOP=1", "--set=label="+label)
+
+	if err != nil {
+		err = fmt.Errorf("%w: %s", err, stderr.String())
+	}
+	return err
+}
+
+// BackupMode returns the state of the session started by StartBackupMode using
+// label. The state is empty when there is no such session, e.g. because the
+// container restarted. When the state is BackupModeStopped, it also returns the
+// contents of the "backup_label" file reported by PostgreSQL.
+func (exec Executor) BackupMode(ctx context.Context, label string) (string, string, error) {
+	const script = `
+state=$(cat "$1/state" 2> /dev/null || true)
+echo "${state}"
+[ "${state}" != ` + BackupModeStopped + ` ] || cat "$1/backup_label"
+`
+	var stdout, stderr bytes.Buffer
+	err := exec(ctx, nil, &stdout, &stderr,
+		"bash", "-ceu", "--", script, "-", backupModeDirectory+"/"+label)
+
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s", err, stderr.String())
+	}
+
+	// The first line is the state. The label file ends with a newline, and
+	// psql adds another after it.
+	state, file, _ := strings.Cut(stdout.String(), "\n")
+	return state, strings.TrimSuffix(file, "\n"), nil
+}
+
+// FinishBackupMode tells the session started by StartBackupMode using label to
+// leave backup mode.
+func (exec Executor) FinishBackupMode(ctx context.Context, label string) error {
+	var stdout, stderr bytes.Buffer
+	err := exec(ctx, nil, &stdout, &stderr,
+		"touch", backupModeDirectory+"/"+label+"/taken")
+
+	if err != nil {
+		err = fmt.Errorf("%w: %s", err, stderr.String())
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/crunchydata/postgres-operator/internal/testing/require"
+)
+
+func TestExecutorStartBackupMode(t *testing.T) {
+	var session string
+	var command []string
+	executor := Executor(func(
+		_ context.Context, stdin io.Reader, _, _ io.Writer, args ...string,
+	) error {
+		b, err := io.ReadAll(stdin)
+		session, command = string(b), args
+		return err
+	})
+
+	assert.NilError(t, executor.StartBackupMode(context.Background(), 14, "snap", 10*time.Minute))
+	assert.DeepEqual(t, command[:3], []string{"bash", "-ceu", "--"})
+	assert.DeepEqual(t, command[4:], []string{
+		"-", "/tmp/pgo-backup-mode", "snap", "600",
+		"-Xw", "--set=ON_ERROR_STOP=1", "--set=label=snap",
+	})
+	assert.Assert(t, strings.Contains(session, "pg_start_backup(:'label', true, false)"))
+	assert.Assert(t, strings.Contains(session, "pg_stop_backup(false, true)"))
+
+	assert.NilError(t, executor.StartBackupMode(context.Background(), 15, "snap", time.Minute))
+	assert.Assert(t, strings.Contains(session, "pg_backup_start(:'label', true)"))
+	assert.Assert(t, strings.Contains(session, "pg_backup_stop(true)"))
+
+	// Every meta-command is on its own line.
+	for _, line := range strings.Split(strings.TrimSpace(session), "\n") {
+		assert.Assert(t, strings.HasPrefix(line, `\`) || strings.HasPrefix(line, "SELECT"), "%q", line)
+	}
+
+	t.Run("ShellCheck", func(t *testing.T) {
+		shellcheck := require.ShellCheck(t)
+
+		file := filepath.Join(t.TempDir(), "script.bash")
+		assert.NilError(t, os.WriteFile(file, []byte(command[3]), 0o600))
+
+		// Expect shellcheck to be happy.
+		cmd := exec.Command(shellcheck, "--enable=all", "--shell=bash", file)
+		output, err := cmd.CombinedOutput()
+		assert.NilError(t, err, "%q\n%s", cmd.Args, output)
+	})
+}
+
+func TestExecutorBackupMode(t *testing.T) {
+	var stdout string
+	exec := Executor(func(
+		_ context.Context, _ io.Reader, out, _ io.Writer, args ...string,
+	) error {
+		assert.Equal(t, args[len(args)-1], "/tmp/pgo-backup-mode/snap")
+		_, err := io.WriteString(out, stdout)
+		return err
+	})
+
+	stdout = "\n"
+	state, label, err := exec.BackupMode(context.Background(), "snap")
+	assert.NilError(t, err)
+	assert.Equal(t, state, "")
+	assert.Equal(t, label, "")
+
+	stdout = "started\n"
+	state, _, err = exec.BackupMode(context.Background(), "snap")
+	assert.NilError(t, err)
+	assert.Equal(t, state, BackupModeStarted)
+
+	stdout = "stopped\nSTART WAL LOCATION: 0/2000028\nLABEL: snap\n\n"
+	state, label, err = exec.BackupMode(context.Background(), "snap")
+	assert.NilError(t, err)
+	assert.Equal(t, state, BackupModeStopped)
+	assert.Equal(t, label, "START WAL LOCATION: 0/2000028\nLABEL: snap\n")
+
+	t.Run("Error", func(t *testing.T) {
+		expected := errors.New("boom")
+		exec := Executor(func(
+			_ context.Context, _ io.Reader, _, stderr io.Writer, _ ...string,
+		) error {
+			_, _ = io.WriteString(stderr, "oops")
+			return expected
+		})
+
+		_, _, err := exec.BackupMode(context.Background(), "snap")
+		assert.ErrorIs(t, err, expected)
+		assert.ErrorContains(t, err, "oops")
+	})
+}
+
+func TestExecutorFinishBackupMode(t *testing.T) {
+	var command []string
+	exec := Executor(func(
+		_ context.Context, _ io.Reader, _, _ io.Writer, args ...string,
+	) error {
+		command = args
+		return nil
+	})
+
+	assert.NilError(t, exec.FinishBackupMode(context.Background(), "snap"))
+	assert.DeepEqual(t, command, []string{"touch", "/tmp/pgo-backup-mode/snap/taken"})
+}
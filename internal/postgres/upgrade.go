@@ -0,0 +1,105 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import "fmt"
+
+// UpgradeBinariesCommand returns the command that copies the PostgreSQL
+// installation of version into directory. The upgrade runs in an image of the
+// new version, so the old binaries come from the current image this way.
+func UpgradeBinariesCommand(version int, directory string) []string {
+	const script = `cp --archive --no-target-directory "/usr/pgsql-$1" "$2"`
+
+	return []string{"bash", "-ceu", "--", script, "-", fmt.Sprint(version), directory}
+}
+
+// UpgradeBinariesDirectory returns where the upgrade expects the PostgreSQL
+// installation of version.
+func UpgradeBinariesDirectory(version int) string {
+	return fmt.Sprintf("/usr/pgsql-%d", version)
+}
+
+// UpgradeCommand returns the command that upgrades the data directory of
+// oldVersion to newVersion using "pg_upgrade --link". The new data directory
+// is created next to the old one with the "_bootstrap" suffix so that Patroni
+// can bootstrap from it using its "existing" method. The script:
+//   - Starts and stops the old cluster so that it is shut down cleanly, and
+//     reads its encoding, locale, and preloaded libraries along the way.
+//   - Creates the new cluster with the same encoding, locale, and checksums.
+//   - Checks then upgrades the old cluster, linking rather than copying files.
+//
+// When any step fails before pg_upgrade finishes, the old cluster is put back
+// the way it was and the new directory is removed. pg_upgrade renames the old
+// "pg_control" file when it begins linking; that is undone, too.
+// - https://www.postgresql.org/docs/current/pgupgrade.html
+func UpgradeCommand(oldVersion, newVersion int) []string {
+	const script = `declare -r old_version="$1" new_version="$2"
+declare -r old_data="` + dataMountPath + `/pg${old_version}"
+declare -r new_data="` + dataMountPath + `/pg${new_version}_bootstrap"
+declare -r old_bin="/usr/pgsql-${old_version}/bin" new_bin="/usr/pgsql-${new_version}/bin"
+echo "Upgrading PostgreSQL ${old_version} in ${old_data} to PostgreSQL ${new_version}"
+
+restore_old() {
+  if [ -f "${old_data}/global/pg_control.old" ] && [ ! -f "${old_data}/global/pg_control" ]; then
+    mv "${old_data}/global/pg_control.old" "${old_data}/global/pg_control"
+  fi
+  rm -rf "${new_data}"
+}
+restore_old
+trap restore_old EXIT
+
+# pg_upgrade writes its logs and sockets in the working directory.
+cd /tmp
+install --directory --mode=0700 "${old_data}"
+rm -f "${old_data}/postmaster.pid"
+
+echo > /tmp/pg_hba.upgrade.conf 'local all "postgres" peer'
+declare -r options="-c archive_mode=off -c hba_file=/tmp/pg_hba.upgrade.conf -c ssl=off"
+export PGHOST='/tmp' PGUSER='postgres'
+
+"${old_bin}/pg_ctl" start --silent --timeout=31536000 --wait --pgdata="${old_data}" \
+  --options="${options} -c listen_addresses= -c unix_socket_directories=/tmp"
+IFS=$'\t' read -r encoding collate ctype <<< "$("${old_bin}/psql" -X --no-align --tuples-only \
+  --field-separator=$'\t' --dbname=postgres --command="SELECT pg_catalog.pg_encoding_to_char(encoding),
+  datcollate, datctype FROM pg_catalog.pg_database WHERE datname = 'template0'")"
+libraries=$("${old_bin}/psql" -X --no-align --tuples-only --dbname=postgres \
+  --command='SHOW shared_preload_libraries')
+"${old_bin}/pg_ctl" stop --silent --timeout=31536000 --wait --pgdata="${old_data}"
+
+control=$("${old_bin}/pg_controldata" "${old_data}")
+read -r checksums <<< "${control##*Data page checksum version:}"
+initdb_options=(--encoding="${encoding}" --lc-collate="${collate}" --lc-ctype="${ctype}")
+if [ "${checksums}" != '0' ]; then initdb_options+=(--data-checksums); fi
+
+"${new_bin}/initdb" --pgdata="${new_data}" --username=postgres "${initdb_options[@]}"
+
+upgrade_options=(
+  --old-bindir="${old_bin}" --new-bindir="${new_bin}"
+  --old-datadir="${old_data}" --new-datadir="${new_data}"
+  --old-options="${options}"
+  --new-options="${options} -c shared_preload_libraries='${libraries}'"
+  --username=postgres
+)
+"${new_bin}/pg_upgrade" --check "${upgrade_options[@]}"
+"${new_bin}/pg_upgrade" --link "${upgrade_options[@]}"
+
+trap - EXIT
+echo "Upgraded PostgreSQL ${old_version} to ${new_version}."
+echo "The files of PostgreSQL ${old_version} remain in ${old_data} and can be removed once the upgrade is verified."`
+
+	return []string{"bash", "-ceu", "--", script, "-",
+		fmt.Sprint(oldVersion), fmt.Sprint(newVersion)}
+}
@@ -0,0 +1,67 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator/internal/testing/require"
+)
+
+func TestUpgradeBinariesCommand(t *testing.T) {
+	command := UpgradeBinariesCommand(13, "/binaries")
+
+	assert.DeepEqual(t, command[:3], []string{"bash", "-ceu", "--"})
+	assert.DeepEqual(t, command[4:], []string{"-", "13", "/binaries"})
+	assert.Equal(t, UpgradeBinariesDirectory(13), "/usr/pgsql-13")
+}
+
+func TestUpgradeCommand(t *testing.T) {
+	shellcheck := require.ShellCheck(t)
+
+	command := UpgradeCommand(13, 14)
+
+	assert.DeepEqual(t, command[:3], []string{"bash", "-ceu", "--"})
+	assert.DeepEqual(t, command[4:], []string{"-", "13", "14"})
+
+	script := command[3]
+	assert.Assert(t, strings.Contains(script, `"${new_bin}/pg_upgrade" --check`))
+	assert.Assert(t, strings.Contains(script, `"${new_bin}/pg_upgrade" --link`))
+	assert.Assert(t, strings.Contains(script, `new_data="/pgdata/pg${new_version}_bootstrap"`),
+		"expected the new cluster where Patroni bootstraps from it")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "script.bash")
+	assert.NilError(t, os.WriteFile(file, []byte(script), 0o600))
+
+	cmd := exec.Command(shellcheck, "--enable=all", file)
+	output, err := cmd.CombinedOutput()
+	assert.NilError(t, err, "%q\n%s", cmd.Args, output)
+}
+
+func TestUpgradeCommandPrettyYAML(t *testing.T) {
+	b, err := yaml.Marshal(UpgradeCommand(10, 14))
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(b), "\n- |"),
+		"expected literal block scalar, got:\n%s", b)
+}
@@ -19,23 +19,61 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/crunchydata/postgres-operator/internal/logging"
 	"github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 )
 
+// managedUserComment marks the users that WriteUsersInPostgreSQL manages. Only
+// these are disabled or dropped once they are removed from the spec.
+const managedUserComment = "managed by postgres-operator"
+
+// AlternateLogin is a second role through which a user logs in. The user and
+// its alternate take turns holding the current password while the other keeps
+// the previous password until it expires.
+type AlternateLogin struct {
+	// Name of the role. It is a member of the user and acts as the user.
+	Name string
+
+	// The role, either the user or its alternate, with the current password.
+	Current string
+
+	// When the password of the role that is not Current stops working.
+	Expires time.Time
+}
+
+// validUntil returns the VALID UNTIL value of role, the user or its alternate.
+// - https://www.postgresql.org/docs/current/sql-alterrole.html
+func (login AlternateLogin) validUntil(role string) string {
+	switch {
+	case role == login.Current:
+		return "infinity"
+	case login.Expires.IsZero():
+		return "-infinity"
+	}
+	return login.Expires.UTC().Format(time.RFC3339)
+}
+
 // WriteUsersInPostgreSQL calls exec to create users that do not exist in
 // PostgreSQL. Once they exist, it updates their options and passwords and
-// grants them access to their specified databases. The databases must already
-// exist.
+// grants them access to their specified databases, roles, and schemas. The
+// databases must already exist. Users in alternates also get a second role
+// that logs in with its own password; verifiers are keyed by role name. When
+// management is not nil, it also revokes access that is not specified and
+// disables or drops users that it managed before but are no longer specified.
 func WriteUsersInPostgreSQL(
 	ctx context.Context, exec Executor,
 	users []v1beta1.PostgresUserSpec, verifiers map[string]string,
+	management *v1beta1.PostgresUserManagementSpec,
+	alternates map[string]AlternateLogin,
 ) error {
 	log := logging.FromContext(ctx)
 
 	var err error
 	var sql bytes.Buffer
+	var roles, privileges, logins bool
 
 	// Prevent unexpected dereferences by emptying "search_path". The "pg_catalog"
 	// schema is still searched, and only temporary objects can be created.
@@ -65,13 +103,50 @@ CREATE TEMPORARY TABLE input (id serial, data json);
 			options = `LOGIN SUPERUSER`
 		}
 
+		data := map[string]interface{}{
+			"databases": databases,
+			"options":   options,
+			"username":  spec.Name,
+			"verifier":  verifiers[string(spec.Name)],
+		}
+
+		// Include memberships and other databases only when they are
+		// specified so that the SQL of existing clusters does not change.
+		if spec.Name != "postgres" && len(spec.Roles) > 0 {
+			data["roles"] = spec.Roles
+			roles = true
+		}
+		if spec.Name != "postgres" && len(spec.Privileges) > 0 {
+			connect := make([]v1beta1.PostgresIdentifier, len(spec.Privileges))
+			for i := range spec.Privileges {
+				connect[i] = spec.Privileges[i].Database
+			}
+			data["connect"] = connect
+			privileges = true
+		}
+
+		// The alternate of a user has the same options and acts as the user
+		// once it logs in. Only one of them has a password that does not expire.
+		if login, ok := alternates[string(spec.Name)]; ok {
+			data["valid_until"] = login.validUntil(string(spec.Name))
+
+			if err == nil {
+				err = encoder.Encode(data)
+			}
+			data = map[string]interface{}{
+				"acts_as":     spec.Name,
+				"databases":   nil,
+				"options":     options,
+				"roles":       []v1beta1.PostgresIdentifier{spec.Name},
+				"username":    login.Name,
+				"valid_until": login.validUntil(login.Name),
+				"verifier":    verifiers[login.Name],
+			}
+			roles, logins = true, true
+		}
+
 		if err == nil {
-			err = encoder.Encode(map[string]interface{}{
-				"databases": databases,
-				"options":   options,
-				"username":  spec.Name,
-				"verifier":  verifiers[string(spec.Name)],
-			})
+			err = encoder.Encode(data)
 		}
 	}
 	_, _ = sql.WriteString(`\.` + "\n")
@@ -95,18 +170,53 @@ SELECT pg_catalog.format('CREATE USER %I',
 \gexec
 `)
 
+	// Managed users may have been disabled when they were removed from the
+	// spec. Allow them to login again before setting any options from the spec.
+	if management != nil {
+		_, _ = sql.WriteString(`
+SELECT pg_catalog.format('ALTER ROLE %I WITH LOGIN',
+       pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input ORDER BY input.id
+\gexec
+`)
+	}
+
 	// Set any options from the specification. Validation ensures that the value
-	// does not contain semicolons.
+	// does not contain semicolons. Users without a verifier have no password.
 	// - https://www.postgresql.org/docs/current/sql-alterrole.html
 	_, _ = sql.WriteString(`
 SELECT pg_catalog.format('ALTER ROLE %I WITH %s PASSWORD %L',
        pg_catalog.json_extract_path_text(input.data, 'username'),
        pg_catalog.json_extract_path_text(input.data, 'options'),
-       pg_catalog.json_extract_path_text(input.data, 'verifier'))
+       NULLIF(pg_catalog.json_extract_path_text(input.data, 'verifier'), ''))
   FROM input ORDER BY input.id
 \gexec
 `)
 
+	// Passwords of alternate logins expire, and the sessions of those roles act
+	// as their users so that objects they create belong to the users.
+	// - https://www.postgresql.org/docs/current/sql-alterrole.html
+	// - https://www.postgresql.org/docs/current/sql-set-role.html
+	if logins {
+		_, _ = sql.WriteString(`
+SELECT pg_catalog.format('ALTER ROLE %I VALID UNTIL %L',
+       pg_catalog.json_extract_path_text(input.data, 'username'),
+       pg_catalog.json_extract_path_text(input.data, 'valid_until'))
+  FROM input
+ WHERE pg_catalog.json_extract_path_text(input.data, 'valid_until') IS NOT NULL
+ ORDER BY input.id
+\gexec
+
+SELECT pg_catalog.format('ALTER ROLE %I SET role TO %I',
+       pg_catalog.json_extract_path_text(input.data, 'username'),
+       pg_catalog.json_extract_path_text(input.data, 'acts_as'))
+  FROM input
+ WHERE pg_catalog.json_extract_path_text(input.data, 'acts_as') IS NOT NULL
+ ORDER BY input.id
+\gexec
+`)
+	}
+
 	// Grant access to any specified databases.
 	// - https://www.postgresql.org/docs/current/sql-grant.html
 	_, _ = sql.WriteString(`
@@ -119,6 +229,46 @@ SELECT pg_catalog.format('GRANT ALL PRIVILEGES ON DATABASE %I TO %I',
 \gexec
 `)
 
+	// Allow connections to databases in which the user has privileges. Those
+	// databases that do not exist are skipped.
+	if privileges {
+		_, _ = sql.WriteString(`
+SELECT pg_catalog.format('GRANT CONNECT ON DATABASE %I TO %I',
+       d.datname, pg_catalog.json_extract_path_text(input.data, 'username'))
+  FROM input,
+       pg_catalog.json_array_elements_text(
+       pg_catalog.json_extract_path(input.data, 'connect')) AS c (name),
+       pg_catalog.pg_database d
+ WHERE d.datname = c.name
+ ORDER BY input.id
+\gexec
+`)
+	}
+
+	// Grant membership in any specified roles that exist. Memberships that
+	// already exist are skipped.
+	// - https://www.postgresql.org/docs/current/role-membership.html
+	if roles {
+		_, _ = sql.WriteString(`
+SELECT pg_catalog.format('GRANT %I TO %I', r.rolname, u.rolname)
+  FROM input,
+       pg_catalog.json_array_elements_text(
+       pg_catalog.json_extract_path(input.data, 'roles')) AS g (name),
+       pg_catalog.pg_roles r, pg_catalog.pg_roles u
+ WHERE r.rolname = g.name
+   AND u.rolname = pg_catalog.json_extract_path_text(input.data, 'username')
+   AND NOT EXISTS (
+       SELECT 1 FROM pg_catalog.pg_auth_members m
+       WHERE m.roleid = r.oid AND m.member = u.oid)
+ ORDER BY input.id
+\gexec
+`)
+	}
+
+	if management != nil {
+		writeManagedUsersSQL(&sql)
+	}
+
 	// Commit (finish) the transaction.
 	_, _ = sql.WriteString(`COMMIT;`)
 
@@ -130,5 +280,287 @@ SELECT pg_catalog.format('GRANT ALL PRIVILEGES ON DATABASE %I TO %I',
 
 	log.V(1).Info("wrote PostgreSQL users", "stdout", stdout, "stderr", stderr)
 
+	if err == nil && privileges {
+		err = writeUserPrivilegesInPostgreSQL(ctx, exec, users, management != nil)
+	}
+	if err == nil && management != nil &&
+		management.RemovedUsers == v1beta1.PostgresRemovedUsersDrop {
+		err = dropRemovedUsersInPostgreSQL(ctx, exec, users, management, alternates)
+	}
+
+	return err
+}
+
+// writeManagedUsersSQL writes the statements that mark the users in the
+// "input" table as managed, revoke any access they have that is not specified,
+// and disable managed users that are not specified.
+func writeManagedUsersSQL(sql *bytes.Buffer) {
+	// Mark the specified users as managed. The "postgres" user is never
+	// disabled nor dropped.
+	// - https://www.postgresql.org/docs/current/sql-comment.html
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('COMMENT ON ROLE %I IS %L',
+       pg_catalog.json_extract_path_text(input.data, 'username'),
+       '` + managedUserComment + `')
+  FROM input
+ WHERE pg_catalog.json_extract_path_text(input.data, 'username') <> 'postgres'
+ ORDER BY input.id
+\gexec
+`)
+
+	// Revoke memberships in roles that are not specified.
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('REVOKE %I FROM %I', r.rolname, u.rolname)
+  FROM input, pg_catalog.pg_roles u, pg_catalog.pg_auth_members m, pg_catalog.pg_roles r
+ WHERE u.rolname = pg_catalog.json_extract_path_text(input.data, 'username')
+   AND u.rolname <> 'postgres'
+   AND m.member = u.oid AND r.oid = m.roleid
+   AND r.rolname NOT IN (
+       SELECT pg_catalog.json_array_elements_text(
+              pg_catalog.json_extract_path(
+              pg_catalog.json_strip_nulls(input.data), 'roles')))
+ ORDER BY input.id, r.rolname
+\gexec
+`)
+
+	// Revoke privileges on databases that are not specified and not owned by
+	// the user. This includes CONNECT, but any that PUBLIC has still apply.
+	// - https://www.postgresql.org/docs/current/ddl-priv.html
+	_, _ = sql.WriteString(`
+SELECT DISTINCT pg_catalog.format('REVOKE ALL PRIVILEGES ON DATABASE %I FROM %I', d.datname, u.rolname)
+  FROM input, pg_catalog.pg_roles u, pg_catalog.pg_database d,
+       pg_catalog.aclexplode(d.datacl) AS acl
+ WHERE u.rolname = pg_catalog.json_extract_path_text(input.data, 'username')
+   AND u.rolname <> 'postgres'
+   AND acl.grantee = u.oid AND d.datdba <> u.oid
+   AND d.datname NOT IN (
+       SELECT pg_catalog.json_array_elements_text(
+              pg_catalog.json_extract_path(
+              pg_catalog.json_strip_nulls(input.data), 'databases'))
+       UNION ALL
+       SELECT pg_catalog.json_array_elements_text(
+              pg_catalog.json_extract_path(
+              pg_catalog.json_strip_nulls(input.data), 'connect')))
+\gexec
+`)
+
+	// Disable managed users that are not specified.
+	_, _ = sql.WriteString(`
+SELECT pg_catalog.format('ALTER ROLE %I WITH NOLOGIN', rolname)
+  FROM pg_catalog.pg_roles
+ WHERE rolcanlogin
+   AND pg_catalog.shobj_description(oid, 'pg_authid') = '` + managedUserComment + `'
+   AND rolname NOT IN (
+       SELECT pg_catalog.json_extract_path_text(input.data, 'username') FROM input)
+ ORDER BY rolname
+\gexec
+`)
+}
+
+// writeUserPrivilegesInPostgreSQL calls exec to grant users access to the
+// schemas in their privileges, in every database. Access to tables and
+// sequences created later by users that own the database is granted, too.
+// When managed is true, it also revokes write access from read-only users.
+func writeUserPrivilegesInPostgreSQL(
+	ctx context.Context, exec Executor,
+	users []v1beta1.PostgresUserSpec, managed bool,
+) error {
+	log := logging.FromContext(ctx)
+
+	type privilege struct {
+		Username string `json:"username"`
+		Database string `json:"database"`
+		Schema   string `json:"schema"`
+		Access   string `json:"access"`
+	}
+	type owner struct {
+		Username string `json:"username"`
+		Database string `json:"database"`
+	}
+
+	privileges := []privilege{}
+	owners := []owner{}
+	for _, spec := range users {
+		if spec.Name == "postgres" {
+			continue
+		}
+		for _, database := range spec.Databases {
+			owners = append(owners, owner{string(spec.Name), string(database)})
+		}
+		for _, p := range spec.Privileges {
+			schemas := p.Schemas
+			if len(schemas) == 0 {
+				schemas = []v1beta1.PostgresIdentifier{"public"}
+			}
+			for _, schema := range schemas {
+				privileges = append(privileges, privilege{
+					string(spec.Name), string(p.Database), string(schema), p.Access,
+				})
+			}
+		}
+	}
+
+	privilegesJSON, err := json.Marshal(privileges)
+	if err != nil {
+		return err
+	}
+	ownersJSON, err := json.Marshal(owners)
+	if err != nil {
+		return err
+	}
+
+	// Choose privileges according to the access of each schema in the current
+	// database. Schemas and users that do not exist are skipped.
+	// - https://www.postgresql.org/docs/current/ddl-priv.html
+	const privilegesInDatabase = `
+WITH privileges AS (
+SELECT p.username, n.nspname,
+       CASE p.access WHEN 'ReadWrite' THEN 'SELECT, INSERT, UPDATE, DELETE' ELSE 'SELECT' END AS tables,
+       CASE p.access WHEN 'ReadWrite' THEN 'SELECT, USAGE, UPDATE' ELSE 'SELECT' END AS sequences,
+       p.access
+  FROM pg_catalog.json_to_recordset(:'privileges'::json)
+       AS p (username text, database text, schema text, access text),
+       pg_catalog.pg_namespace n, pg_catalog.pg_roles r
+ WHERE p.database = pg_catalog.current_database()
+   AND n.nspname = p.schema AND r.rolname = p.username
+), owners AS (
+SELECT o.username
+  FROM pg_catalog.json_to_recordset(:'owners'::json) AS o (username text, database text),
+       pg_catalog.pg_roles r
+ WHERE o.database = pg_catalog.current_database() AND r.rolname = o.username
+)`
+
+	statements := []string{
+		// Quiet NOTICE messages from repeated grants.
+		// - https://www.postgresql.org/docs/current/runtime-config-client.html
+		`SET client_min_messages = WARNING;`,
+
+		// Grant the following privileges in a transaction.
+		`BEGIN;`,
+
+		// Grant access to the existing objects in each schema.
+		strings.TrimSpace(privilegesInDatabase + `
+SELECT pg_catalog.format('GRANT USAGE ON SCHEMA %I TO %I', nspname, username),
+       pg_catalog.format('GRANT %s ON ALL TABLES IN SCHEMA %I TO %I', tables, nspname, username),
+       pg_catalog.format('GRANT %s ON ALL SEQUENCES IN SCHEMA %I TO %I', sequences, nspname, username)
+  FROM privileges ORDER BY username, nspname
+\gexec`),
+
+		// Grant access to objects created later by the owners of the database.
+		// - https://www.postgresql.org/docs/current/sql-alterdefaultprivileges.html
+		strings.TrimSpace(privilegesInDatabase + `
+SELECT pg_catalog.format('ALTER DEFAULT PRIVILEGES FOR ROLE %I IN SCHEMA %I GRANT %s ON TABLES TO %I',
+       owners.username, nspname, tables, privileges.username),
+       pg_catalog.format('ALTER DEFAULT PRIVILEGES FOR ROLE %I IN SCHEMA %I GRANT %s ON SEQUENCES TO %I',
+       owners.username, nspname, sequences, privileges.username)
+  FROM privileges, owners WHERE owners.username <> privileges.username
+ ORDER BY privileges.username, nspname, owners.username
+\gexec`),
+	}
+
+	if managed {
+		// Revoke write access from users that should only read.
+		statements = append(statements, strings.TrimSpace(privilegesInDatabase+`
+SELECT pg_catalog.format('REVOKE INSERT, UPDATE, DELETE, TRUNCATE ON ALL TABLES IN SCHEMA %I FROM %I', nspname, username),
+       pg_catalog.format('REVOKE USAGE, UPDATE ON ALL SEQUENCES IN SCHEMA %I FROM %I', nspname, username)
+  FROM privileges WHERE access = 'ReadOnly' ORDER BY username, nspname
+\gexec`), strings.TrimSpace(privilegesInDatabase+`
+SELECT pg_catalog.format('ALTER DEFAULT PRIVILEGES FOR ROLE %I IN SCHEMA %I REVOKE INSERT, UPDATE, DELETE, TRUNCATE ON TABLES FROM %I',
+       owners.username, nspname, privileges.username),
+       pg_catalog.format('ALTER DEFAULT PRIVILEGES FOR ROLE %I IN SCHEMA %I REVOKE USAGE, UPDATE ON SEQUENCES FROM %I',
+       owners.username, nspname, privileges.username)
+  FROM privileges, owners
+ WHERE owners.username <> privileges.username AND access = 'ReadOnly'
+ ORDER BY privileges.username, nspname, owners.username
+\gexec`))
+	}
+
+	// Commit (finish) the transaction.
+	statements = append(statements, `COMMIT;`)
+
+	stdout, stderr, err := exec.ExecInAllDatabases(ctx,
+		strings.Join(statements, "\n"),
+		map[string]string{
+			"owners":     string(ownersJSON),
+			"privileges": string(privilegesJSON),
+
+			"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+			"QUIET":         "on", // Do not print successful statements to stdout.
+		})
+
+	log.V(1).Info("wrote PostgreSQL user privileges", "stdout", stdout, "stderr", stderr)
+
+	return err
+}
+
+// dropRemovedUsersInPostgreSQL calls exec to drop the managed users that are
+// not in users nor alternates. First, their objects in every database are
+// given to the role in management and any privileges they have are revoked.
+func dropRemovedUsersInPostgreSQL(
+	ctx context.Context, exec Executor,
+	users []v1beta1.PostgresUserSpec, management *v1beta1.PostgresUserManagementSpec,
+	alternates map[string]AlternateLogin,
+) error {
+	log := logging.FromContext(ctx)
+
+	names := make([]string, len(users))
+	for i := range users {
+		names[i] = string(users[i].Name)
+	}
+	for i := range users {
+		if login, ok := alternates[string(users[i].Name)]; ok {
+			names = append(names, login.Name)
+		}
+	}
+	namesJSON, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	reassign := string(management.ReassignOwnedTo)
+	if reassign == "" {
+		reassign = "postgres"
+	}
+
+	const removed = `
+  FROM pg_catalog.pg_roles
+ WHERE pg_catalog.shobj_description(oid, 'pg_authid') = '` + managedUserComment + `'
+   AND rolname NOT IN (SELECT pg_catalog.json_array_elements_text(:'users'::json))
+ ORDER BY rolname
+\gexec`
+
+	variables := map[string]string{
+		"reassign": reassign,
+		"users":    string(namesJSON),
+
+		"ON_ERROR_STOP": "on", // Abort when any one statement fails.
+		"QUIET":         "on", // Do not print successful statements to stdout.
+	}
+
+	// First, give away objects and revoke privileges in all databases and
+	// database templates. Any privileges on shared objects are also revoked.
+	// - https://www.postgresql.org/docs/current/role-removal.html
+	stdout, stderr, err := exec.ExecInAllDatabases(ctx,
+		strings.Join([]string{
+			`BEGIN;`,
+			strings.TrimSpace(`
+SELECT pg_catalog.format('REASSIGN OWNED BY %I TO %I', rolname, :'reassign'),
+       pg_catalog.format('DROP OWNED BY %I', rolname)` + removed),
+			`COMMIT;`,
+		}, "\n"),
+		variables)
+
+	log.V(1).Info("removed objects of PostgreSQL users", "stdout", stdout, "stderr", stderr)
+
+	if err == nil {
+		// Drop the users now that their objects and privileges are gone.
+		stdout, stderr, err = exec.ExecInDatabasesFromQuery(ctx,
+			`SELECT pg_catalog.current_database()`,
+			strings.TrimSpace(`SELECT pg_catalog.format('DROP ROLE %I', rolname)`+removed),
+			variables)
+
+		log.V(1).Info("dropped PostgreSQL users", "stdout", stdout, "stderr", stderr)
+	}
+
 	return err
 }
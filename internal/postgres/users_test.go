@@ -21,6 +21,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -41,7 +42,7 @@ func TestWriteUsersInPostgreSQL(t *testing.T) {
 			return expected
 		}
 
-		assert.Equal(t, expected, WriteUsersInPostgreSQL(ctx, exec, nil, nil))
+		assert.Equal(t, expected, WriteUsersInPostgreSQL(ctx, exec, nil, nil, nil, nil))
 	})
 
 	t.Run("Empty", func(t *testing.T) {
@@ -71,7 +72,7 @@ SELECT pg_catalog.format('CREATE USER %I',
 SELECT pg_catalog.format('ALTER ROLE %I WITH %s PASSWORD %L',
        pg_catalog.json_extract_path_text(input.data, 'username'),
        pg_catalog.json_extract_path_text(input.data, 'options'),
-       pg_catalog.json_extract_path_text(input.data, 'verifier'))
+       NULLIF(pg_catalog.json_extract_path_text(input.data, 'verifier'), ''))
   FROM input ORDER BY input.id
 \gexec
 
@@ -86,13 +87,13 @@ COMMIT;`))
 			return nil
 		}
 
-		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, nil, nil))
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, nil, nil, nil, nil))
 		assert.Equal(t, calls, 1)
 
-		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, []v1beta1.PostgresUserSpec{}, nil))
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, []v1beta1.PostgresUserSpec{}, nil, nil, nil))
 		assert.Equal(t, calls, 2)
 
-		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, nil, map[string]string{}))
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, nil, map[string]string{}, nil, nil))
 		assert.Equal(t, calls, 3)
 	})
 
@@ -133,6 +134,7 @@ COMMIT;`))
 				"no-user":            "ignored",
 				"user-with-verifier": "some$verifier",
 			},
+			nil, nil,
 		))
 		assert.Equal(t, calls, 1)
 	})
@@ -165,7 +167,178 @@ COMMIT;`))
 			map[string]string{
 				"postgres": "allowed",
 			},
+			nil, nil,
 		))
 		assert.Equal(t, calls, 1)
 	})
+
+	t.Run("RolesAndPrivileges", func(t *testing.T) {
+		var calls [][]string
+		var scripts []string
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			calls = append(calls, command)
+			scripts = append(scripts, string(b))
+			return nil
+		}
+
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec,
+			[]v1beta1.PostgresUserSpec{
+				{
+					Name:      "owner",
+					Databases: []v1beta1.PostgresIdentifier{"db1"},
+				},
+				{
+					Name:  "reader",
+					Roles: []v1beta1.PostgresIdentifier{"analysts"},
+					Privileges: []v1beta1.PostgresUserPrivilegesSpec{
+						{Database: "db1", Access: "ReadOnly"},
+						{Database: "db2", Schemas: []v1beta1.PostgresIdentifier{"s1", "s2"}, Access: "ReadWrite"},
+					},
+				},
+				{
+					Name:  "postgres",
+					Roles: []v1beta1.PostgresIdentifier{"ignored"},
+				},
+			},
+			nil, nil, nil,
+		))
+
+		assert.Equal(t, len(calls), 2)
+		assert.Assert(t, cmp.Contains(scripts[0], `
+{"databases":["db1"],"options":"","username":"owner","verifier":""}
+{"connect":["db1","db2"],"databases":null,"options":"","roles":["analysts"],"username":"reader","verifier":""}
+{"databases":["postgres"],"options":"LOGIN SUPERUSER","username":"postgres","verifier":""}
+\.
+`))
+		assert.Assert(t, cmp.Contains(scripts[0], `GRANT CONNECT ON DATABASE`))
+		assert.Assert(t, cmp.Contains(scripts[0], `'GRANT %I TO %I'`))
+		assert.Assert(t, !strings.Contains(scripts[0], `COMMENT ON ROLE`),
+			"expected nothing managed")
+
+		// Privileges are granted in every database.
+		assert.Assert(t, cmp.Contains(strings.Join(calls[1], " "), "bash"))
+		assert.Assert(t, cmp.Contains(calls[1], `--set=owners=[{"username":"owner","database":"db1"}]`))
+		assert.Assert(t, cmp.Contains(calls[1], `--set=privileges=[`+
+			`{"username":"reader","database":"db1","schema":"public","access":"ReadOnly"},`+
+			`{"username":"reader","database":"db2","schema":"s1","access":"ReadWrite"},`+
+			`{"username":"reader","database":"db2","schema":"s2","access":"ReadWrite"}]`))
+		assert.Assert(t, cmp.Contains(scripts[1], `ALTER DEFAULT PRIVILEGES FOR ROLE`))
+		assert.Assert(t, !strings.Contains(scripts[1], `REVOKE`),
+			"expected nothing managed")
+	})
+
+	t.Run("Managed", func(t *testing.T) {
+		var calls [][]string
+		var scripts []string
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			calls = append(calls, command)
+			scripts = append(scripts, string(b))
+			return nil
+		}
+
+		users := []v1beta1.PostgresUserSpec{{Name: "app"}}
+		management := &v1beta1.PostgresUserManagementSpec{RemovedUsers: "Disable"}
+
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, users, nil, management, nil))
+		assert.Equal(t, len(calls), 1)
+		assert.Assert(t, cmp.Contains(scripts[0], `'ALTER ROLE %I WITH LOGIN'`))
+		assert.Assert(t, cmp.Contains(scripts[0], `'COMMENT ON ROLE %I IS %L'`))
+		assert.Assert(t, cmp.Contains(scripts[0], `'REVOKE %I FROM %I'`))
+		assert.Assert(t, cmp.Contains(scripts[0], `'REVOKE ALL PRIVILEGES ON DATABASE %I FROM %I'`))
+		assert.Assert(t, cmp.Contains(scripts[0], `'ALTER ROLE %I WITH NOLOGIN'`))
+
+		t.Run("Drop", func(t *testing.T) {
+			calls, scripts = nil, nil
+			management := &v1beta1.PostgresUserManagementSpec{
+				RemovedUsers: "Drop", ReassignOwnedTo: "keeper",
+			}
+
+			assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, users, nil, management, nil))
+			assert.Equal(t, len(calls), 3)
+
+			// Objects are reassigned in every database.
+			assert.Assert(t, cmp.Contains(calls[1], `--set=reassign=keeper`))
+			assert.Assert(t, cmp.Contains(calls[1], `--set=users=["app"]`))
+			assert.Assert(t, cmp.Contains(scripts[1], `REASSIGN OWNED BY %I TO %I`))
+			assert.Assert(t, cmp.Contains(scripts[1], `DROP OWNED BY %I`))
+
+			// Roles are dropped in one database.
+			assert.Assert(t, cmp.Contains(calls[2], `SELECT pg_catalog.current_database()`))
+			assert.Assert(t, cmp.Contains(scripts[2], `DROP ROLE %I`))
+		})
+
+		t.Run("DropAlternate", func(t *testing.T) {
+			calls, scripts = nil, nil
+			management := &v1beta1.PostgresUserManagementSpec{RemovedUsers: "Drop"}
+
+			assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, users, nil, management,
+				map[string]AlternateLogin{"app": {Name: "app_alt", Current: "app"}}))
+			assert.Assert(t, cmp.Contains(calls[1], `--set=users=["app","app_alt"]`),
+				"expected the alternate to stay")
+		})
+
+		t.Run("DropDefaultOwner", func(t *testing.T) {
+			calls, scripts = nil, nil
+			management := &v1beta1.PostgresUserManagementSpec{RemovedUsers: "Drop"}
+
+			assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec, users, nil, management, nil))
+			assert.Assert(t, cmp.Contains(calls[1], `--set=reassign=postgres`))
+		})
+	})
+
+	t.Run("AlternateLogins", func(t *testing.T) {
+		var script string
+		exec := func(
+			_ context.Context, stdin io.Reader, _, _ io.Writer, command ...string,
+		) error {
+			b, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			script = string(b)
+			return nil
+		}
+
+		expires := time.Date(2022, time.June, 1, 12, 30, 0, 0, time.UTC)
+		assert.NilError(t, WriteUsersInPostgreSQL(ctx, exec,
+			[]v1beta1.PostgresUserSpec{
+				{
+					Name:      "app",
+					Databases: []v1beta1.PostgresIdentifier{"db1"},
+					Options:   "CREATEDB",
+				},
+				{Name: "other"},
+			},
+			map[string]string{
+				"app":     "previous$verifier",
+				"app_alt": "current$verifier",
+			},
+			nil,
+			map[string]AlternateLogin{
+				"app": {Name: "app_alt", Current: "app_alt", Expires: expires},
+			},
+		))
+
+		assert.Assert(t, cmp.Contains(script, `
+{"databases":["db1"],"options":"CREATEDB","username":"app","valid_until":"2022-06-01T12:30:00Z","verifier":"previous$verifier"}
+{"acts_as":"app","databases":null,"options":"CREATEDB","roles":["app"],"username":"app_alt","valid_until":"infinity","verifier":"current$verifier"}
+{"databases":null,"options":"","username":"other","verifier":""}
+\.
+`))
+		assert.Assert(t, cmp.Contains(script, `'ALTER ROLE %I VALID UNTIL %L'`))
+		assert.Assert(t, cmp.Contains(script, `'ALTER ROLE %I SET role TO %I'`))
+		assert.Assert(t, cmp.Contains(script, `'GRANT %I TO %I'`))
+
+		t.Run("NoPreviousPassword", func(t *testing.T) {
+			login := AlternateLogin{Name: "app_alt", Current: "app"}
+			assert.Equal(t, login.validUntil("app"), "infinity")
+			assert.Equal(t, login.validUntil("app_alt"), "-infinity")
+		})
+	})
 }
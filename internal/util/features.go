@@ -37,6 +37,9 @@ const (
 	//
 	// Enables support of custom sidecars for pgBouncer Pods
 	PGBouncerSidecars featuregate.Feature = "PGBouncerSidecars"
+	//
+	// Enables calls to the Patroni REST API rather than "patronictl"
+	PatroniRESTAPI featuregate.Feature = "PatroniRESTAPI"
 )
 
 // pgoFeatures consists of all known PGO feature keys.
@@ -49,6 +52,7 @@ const (
 var pgoFeatures = map[featuregate.Feature]featuregate.FeatureSpec{
 	InstanceSidecars:  {Default: false, PreRelease: featuregate.Alpha},
 	PGBouncerSidecars: {Default: false, PreRelease: featuregate.Alpha},
+	PatroniRESTAPI:    {Default: false, PreRelease: featuregate.Alpha},
 }
 
 // DefaultMutableFeatureGate is a mutable, shared global FeatureGate.
@@ -53,12 +53,42 @@ type PatroniSpec struct {
 	// +optional
 	Switchover *PatroniSwitchover `json:"switchover,omitempty"`
 
+	// Synchronous replication settings. When set, these take precedence over
+	// any synchronous settings in dynamicConfiguration.
+	// More info: https://patroni.readthedocs.io/en/latest/replication_modes.html
+	// +optional
+	Synchronous *PatroniSynchronousReplication `json:"synchronous,omitempty"`
+
 	// TODO(cbandy): Add UseConfigMaps bool, default false.
 	// TODO(cbandy): Allow other DCS: etcd, raft, etc?
 	// N.B. changing this will cause downtime.
 	// - https://patroni.readthedocs.io/en/latest/kubernetes.html
 }
 
+type PatroniSynchronousReplication struct {
+
+	// How strictly the primary waits for synchronous standbys. "On" allows
+	// writes to continue without synchronous replication when no standby is
+	// available. "Strict" stops writes until a synchronous standby is
+	// available.
+	// +optional
+	// +kubebuilder:default=On
+	// +kubebuilder:validation:Enum={On,Strict}
+	Mode string `json:"mode,omitempty"`
+
+	// The number of replicas that must confirm each commit.
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	StandbyCount *int32 `json:"standbyCount,omitempty"`
+
+	// The names of the instance sets whose replicas can become synchronous
+	// standbys. When empty, replicas of every instance set can.
+	// +listType=set
+	// +optional
+	InstanceSets []string `json:"instanceSets,omitempty"`
+}
+
 type PatroniSwitchover struct {
 
 	// Whether or not the operator should allow switchovers in a PostgresCluster
@@ -125,4 +155,8 @@ type PatroniStatus struct {
 	// Tracks the current timeline during switchovers
 	// +optional
 	SwitchoverTimeline *int64 `json:"switchoverTimeline,omitempty"`
+
+	// The pods currently acting as synchronous standbys, as reported by Patroni.
+	// +optional
+	SynchronousStandbys []string `json:"synchronousStandbys,omitempty"`
 }
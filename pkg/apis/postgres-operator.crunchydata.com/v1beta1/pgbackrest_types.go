@@ -17,6 +17,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -147,6 +148,31 @@ type PGBackRestArchive struct {
 	// Configuration for pgBackRest sidecar containers
 	// +optional
 	Sidecars *PGBackRestSidecars `json:"sidecars,omitempty"`
+
+	// Defines how backups are checked by "verify" schedules
+	// +optional
+	Verification *PGBackRestVerification `json:"verification,omitempty"`
+}
+
+// PGBackRestVerification defines how backups are test-restored and checked by the
+// "verify" schedule of a repository.
+type PGBackRestVerification struct {
+
+	// SQL to run in the "postgres" database once the restored backup is consistent.
+	// Verification fails when the SQL fails or when any row it returns is exactly "f".
+	// When omitted, pg_amcheck checks every database on PostgreSQL 14 and newer.
+	// +optional
+	SQL string `json:"sql,omitempty"`
+
+	// Defines a PersistentVolumeClaim spec for a volume that exists only while a
+	// backup is being verified. When omitted, the backup is restored into an
+	// emptyDir volume.
+	// +optional
+	VolumeClaimSpec *corev1.PersistentVolumeClaimSpec `json:"volumeClaimSpec,omitempty"`
+
+	// Resource requirements for the verification container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // PGBackRestSidecars defines the configuration for pgBackRest sidecar containers
@@ -271,6 +297,66 @@ type PGBackRestBackupSchedules struct {
 	// +optional
 	// +kubebuilder:validation:MinLength=6
 	Incremental *string `json:"incremental,omitempty"`
+
+	// Defines the Cron schedule for expiring backups and archived WAL according
+	// to the retention of the repository. pgBackRest also expires after each
+	// successful backup:
+	// https://pgbackrest.org/command.html#command-expire
+	// Follows the standard Cron schedule syntax:
+	// https://k8s.io/docs/concepts/workloads/controllers/cron-jobs/#cron-schedule-syntax
+	// +optional
+	// +kubebuilder:validation:MinLength=6
+	Expire *string `json:"expire,omitempty"`
+
+	// Defines the Cron schedule for verifying the latest backup by restoring it
+	// into a temporary volume, starting PostgreSQL, and checking the data. See
+	// also "verification".
+	// Follows the standard Cron schedule syntax:
+	// https://k8s.io/docs/concepts/workloads/controllers/cron-jobs/#cron-schedule-syntax
+	// +optional
+	// +kubebuilder:validation:MinLength=6
+	Verify *string `json:"verify,omitempty"`
+}
+
+// PGBackRestRetention defines how long pgBackRest keeps backups and archived WAL
+// in a repository:
+// https://pgbackrest.org/configuration.html#section-repository
+type PGBackRestRetention struct {
+
+	// The number of full backups to keep or, when fullType is "time", the number
+	// of days to keep them. Differential and incremental backups expire along with
+	// the full backup they depend on.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9999999
+	Full *int32 `json:"full,omitempty"`
+
+	// Whether full is a number of backups ("count") or a number of days ("time").
+	// Defaults to "count".
+	// +optional
+	// +kubebuilder:validation:Enum={count,time}
+	FullType string `json:"fullType,omitempty"`
+
+	// The number of differential backups to keep. Incremental backups expire along
+	// with the differential backup they depend on.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9999999
+	Differential *int32 `json:"differential,omitempty"`
+
+	// The number of backups, of archiveType, for which to keep archived WAL.
+	// WAL required to make the remaining backups consistent is always kept.
+	// Defaults to keeping WAL for every backup.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9999999
+	Archive *int32 `json:"archive,omitempty"`
+
+	// The type of backup counted by archive: "full", "diff" or "incr".
+	// Defaults to "full".
+	// +optional
+	// +kubebuilder:validation:Enum={full,diff,incr}
+	ArchiveType string `json:"archiveType,omitempty"`
 }
 
 // PGBackRestStatus defines the status of pgBackRest within a PostgresCluster
@@ -297,6 +383,12 @@ type PGBackRestStatus struct {
 	// Status information for in-place restores
 	// +optional
 	Restore *PGBackRestJobStatus `json:"restore,omitempty"`
+
+	// The value of the restore annotation when the in-place restore of a
+	// PGBackRestRestoreRequest began. That value of the annotation does not
+	// request another restore.
+	// +optional
+	RestoreAnnotation string `json:"restoreAnnotation,omitempty"`
 }
 
 // PGBackRestRepo represents a pgBackRest repository.  Only one of its members may be specified.
@@ -316,6 +408,11 @@ type PGBackRestRepo struct {
 	// +optional
 	BackupSchedules *PGBackRestBackupSchedules `json:"schedules,omitempty"`
 
+	// Defines how long backups and archived WAL are kept in the repository.
+	// Equivalent options in "global" take precedence.
+	// +optional
+	Retention *PGBackRestRetention `json:"retention,omitempty"`
+
 	// Represents a pgBackRest repository that is created using Azure storage
 	// +optional
 	Azure *RepoAzure `json:"azure,omitempty"`
@@ -349,6 +446,13 @@ type RepoPVC struct {
 	// Defines a PersistentVolumeClaim spec used to create and/or bind a volume
 	// +kubebuilder:validation:Required
 	VolumeClaimSpec corev1.PersistentVolumeClaimSpec `json:"volumeClaimSpec"`
+
+	// The percentage of the volume that may be used before the cluster reports
+	// that the repository is running out of space. Defaults to 80.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	UsageThreshold *int32 `json:"usageThreshold,omitempty"`
 }
 
 // RepoAzure represents a pgBackRest repository that is created using Azure storage
@@ -412,6 +516,89 @@ type RepoStatus struct {
 	// commands accordingly.
 	// +optional
 	RepoOptionsHash string `json:"repoOptionsHash,omitempty"`
+
+	// The backups and archived WAL in the repository, as periodically reported
+	// by pgBackRest.
+	// +optional
+	Inventory *RepoInventory `json:"inventory,omitempty"`
+
+	// The most recent test restore of the repository by its "verify" schedule.
+	// +optional
+	Verification *RepoVerificationStatus `json:"verification,omitempty"`
+}
+
+// RepoVerificationStatus describes the most recent test restore of a pgBackRest repository
+type RepoVerificationStatus struct {
+
+	// The name of the Job that performed the verification.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// Represents the time the verification Job was acknowledged by the Job controller.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Represents the time the verification Job finished, whether or not it passed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Specifies whether or not the verification is finished.
+	// +optional
+	Finished bool `json:"finished,omitempty"`
+
+	// Specifies whether or not the backup was restored and checked successfully.
+	// +optional
+	Passed bool `json:"passed,omitempty"`
+
+	// Represents the time the most recent successful verification finished.
+	// +optional
+	LastPassedTime *metav1.Time `json:"lastPassedTime,omitempty"`
+}
+
+// RepoInventory describes the contents of a pgBackRest repository
+type RepoInventory struct {
+
+	// When pgBackRest last reported the contents of the repository.
+	// +optional
+	UpdateTime *metav1.Time `json:"updateTime,omitempty"`
+
+	// The most recent backups in the repository, oldest first. At most ten are listed.
+	// +optional
+	Backups []PGBackRestBackupInfo `json:"backups,omitempty"`
+
+	// The oldest WAL file archived in the repository.
+	// +optional
+	WALMin string `json:"walMin,omitempty"`
+
+	// The newest WAL file archived in the repository.
+	// +optional
+	WALMax string `json:"walMax,omitempty"`
+
+	// The range of time to which PostgreSQL can be recovered using the repository.
+	// +optional
+	RecoveryWindow *RepoRecoveryWindow `json:"recoveryWindow,omitempty"`
+
+	// The capacity of the repository volume, if any.
+	// +optional
+	VolumeCapacity *resource.Quantity `json:"volumeCapacity,omitempty"`
+
+	// The space used on the repository volume, if any.
+	// +optional
+	VolumeUsed *resource.Quantity `json:"volumeUsed,omitempty"`
+}
+
+// RepoRecoveryWindow is the range of time to which PostgreSQL can be recovered
+// using a pgBackRest repository
+type RepoRecoveryWindow struct {
+
+	// The earliest time that can be recovered. This is when the oldest backup finished.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The latest time known to be recoverable. This is when walMax was archived
+	// or, when that is unknown, when the newest backup finished.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
 }
 
 // PGBackRestDataSource defines a pgBackRest configuration specifically for restoring from cloud-based data source
@@ -0,0 +1,180 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PGBackRestBackupSucceeded is the type of the condition that indicates
+	// whether or not the backup of a PGBackRestBackup completed successfully.
+	// It is "Unknown" until the backup Job finishes.
+	PGBackRestBackupSucceeded = "Succeeded"
+)
+
+// PGBackRestBackupSpec defines the desired state of PGBackRestBackup
+type PGBackRestBackupSpec struct {
+
+	// The name of the PostgresCluster to back up. The cluster must be in the
+	// same namespace as this PGBackRestBackup.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	PostgresClusterName string `json:"postgresClusterName"`
+
+	// The name of the pgBackRest repo to run the backup command against.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^repo[1-4]
+	RepoName string `json:"repoName"`
+
+	// The type of pgBackRest backup to take. When empty, pgBackRest takes an
+	// incremental backup or a full backup when there is no prior backup.
+	// https://pgbackrest.org/command.html#command-backup/category-command/option-type
+	// +optional
+	// +kubebuilder:validation:Enum={full,diff,incr}
+	Type string `json:"type,omitempty"`
+
+	// Command line options to include when running the pgBackRest backup command.
+	// https://pgbackrest.org/command.html#command-backup
+	// +optional
+	Options []string `json:"options,omitempty"`
+}
+
+// PGBackRestBackupStatus defines the observed state of PGBackRestBackup
+type PGBackRestBackupStatus struct {
+
+	// observedGeneration represents the .metadata.generation on which the status was based.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// conditions represent the observations of the backup's current state.
+	// Known .status.conditions.type are: "Succeeded"
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The name of the Job running the backup command.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// Specifies whether or not the Job is finished executing (does not indicate success or
+	// failure).
+	// +optional
+	Finished bool `json:"finished,omitempty"`
+
+	// Represents the time the backup Job was acknowledged by the Job controller.
+	// It is represented in RFC3339 form and is in UTC.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Represents the time the backup Job was determined by the Job controller
+	// to be completed.  This field is only set if the backup completed successfully.
+	// Additionally, it is represented in RFC3339 form and is in UTC.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// The number of actively running backup Pods.
+	// +optional
+	Active int32 `json:"active,omitempty"`
+
+	// The number of Pods for the backup Job that reached the "Succeeded" phase.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// The number of Pods for the backup Job that reached the "Failed" phase.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// Details of the backup as reported by pgBackRest once it completed.
+	// +optional
+	Backup *PGBackRestBackupInfo `json:"backup,omitempty"`
+}
+
+// PGBackRestBackupInfo describes one backup in a pgBackRest repository.
+type PGBackRestBackupInfo struct {
+
+	// The pgBackRest label that identifies the backup, e.g. "20220601-161839F".
+	// +kubebuilder:validation:Required
+	Label string `json:"label"`
+
+	// The type of backup: "full", "diff" or "incr".
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// The time pgBackRest started the backup.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time pgBackRest finished the backup.
+	// +optional
+	StopTime *metav1.Time `json:"stopTime,omitempty"`
+
+	// How long pgBackRest took to perform the backup.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The size of the database as backed up.
+	// +optional
+	DatabaseSize *resource.Quantity `json:"databaseSize,omitempty"`
+
+	// The size of the backup in the repository, after compression.
+	// +optional
+	RepoSize *resource.Quantity `json:"repoSize,omitempty"`
+
+	// The first WAL file required to restore the backup.
+	// +optional
+	WALStart string `json:"walStart,omitempty"`
+
+	// The last WAL file required to restore the backup.
+	// +optional
+	WALStop string `json:"walStop,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.postgresClusterName`
+// +kubebuilder:printcolumn:name="Repo",type=string,JSONPath=`.spec.repoName`
+// +kubebuilder:printcolumn:name="Label",type=string,JSONPath=`.status.backup.label`
+// +kubebuilder:printcolumn:name="Succeeded",type=string,JSONPath=`.status.conditions[?(@.type=="Succeeded")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +operator-sdk:csv:customresourcedefinitions:resources={{Job,v1}}
+
+// PGBackRestBackup is the Schema for the pgbackrestbackups API. Each one runs
+// a single pgBackRest backup of a PostgresCluster.
+type PGBackRestBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PGBackRestBackupSpec   `json:"spec,omitempty"`
+	Status PGBackRestBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PGBackRestBackupList contains a list of PGBackRestBackup
+type PGBackRestBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PGBackRestBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PGBackRestBackup{}, &PGBackRestBackupList{})
+}
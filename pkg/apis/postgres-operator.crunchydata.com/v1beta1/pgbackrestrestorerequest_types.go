@@ -0,0 +1,155 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PGBackRestRestoreValidated is the type of the condition that indicates
+	// whether or not the target of a PGBackRestRestoreRequest can be recovered from
+	// its repository. A restore does not begin until this is "True".
+	PGBackRestRestoreValidated = "Validated"
+
+	// PGBackRestRestoreSucceeded is the type of the condition that indicates
+	// whether or not the restore of a PGBackRestRestoreRequest completed successfully.
+	PGBackRestRestoreSucceeded = "Succeeded"
+)
+
+// PGBackRestRestoreRequestSpec defines the desired state of PGBackRestRestoreRequest
+type PGBackRestRestoreRequestSpec struct {
+
+	// The name of the PostgresCluster to restore in-place. The cluster must be
+	// in the same namespace as this PGBackRestRestoreRequest.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	PostgresClusterName string `json:"postgresClusterName"`
+
+	// The name of the pgBackRest repo that contains the backups to restore.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^repo[1-4]
+	RepoName string `json:"repoName"`
+
+	// The point to which PostgreSQL should be recovered. When omitted, all
+	// archived WAL is replayed.
+	// +optional
+	Target *PGBackRestRestoreTarget `json:"target,omitempty"`
+
+	// Command line options to include when running the pgBackRest restore command.
+	// https://pgbackrest.org/command.html#command-restore
+	// +optional
+	Options []string `json:"options,omitempty"`
+
+	// Whether or not to only validate the target. When true, the cluster is
+	// never modified.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// PGBackRestRestoreTarget is the point to which PostgreSQL is recovered. At
+// most one of time, lsn, and xid may be set.
+// - https://www.postgresql.org/docs/current/runtime-config-wal.html#RUNTIME-CONFIG-WAL-RECOVERY-TARGET
+type PGBackRestRestoreTarget struct {
+
+	// Recover to this time.
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+
+	// Recover to this write-ahead log location, e.g. "0/3000000".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8}$`
+	LSN string `json:"lsn,omitempty"`
+
+	// Recover to this transaction ID.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+$`
+	XID string `json:"xid,omitempty"`
+
+	// Restore the backup with this pgBackRest label, e.g. "20220601-161839F".
+	// When no other target is set, recovery stops as soon as the backup is
+	// consistent.
+	// +optional
+	BackupLabel string `json:"backupLabel,omitempty"`
+}
+
+// PGBackRestRestoreRequestStatus defines the observed state of PGBackRestRestoreRequest
+type PGBackRestRestoreRequestStatus struct {
+
+	// observedGeneration represents the .metadata.generation on which the status was based.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// conditions represent the observations of the restore's current state.
+	// Known .status.conditions.type are: "Succeeded", "Validated"
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The backup that pgBackRest is expected to restore, as found during validation.
+	// +optional
+	Backup *PGBackRestBackupInfo `json:"backup,omitempty"`
+
+	// Specifies whether or not the restore is finished executing (does not indicate
+	// success or failure).
+	// +optional
+	Finished bool `json:"finished,omitempty"`
+
+	// Represents the time the restore Job was acknowledged by the Job controller.
+	// It is represented in RFC3339 form and is in UTC.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Represents the time the restore Job was determined by the Job controller
+	// to be completed.  This field is only set if the restore completed successfully.
+	// Additionally, it is represented in RFC3339 form and is in UTC.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.postgresClusterName`
+// +kubebuilder:printcolumn:name="Repo",type=string,JSONPath=`.spec.repoName`
+// +kubebuilder:printcolumn:name="Validated",type=string,JSONPath=`.status.conditions[?(@.type=="Validated")].status`
+// +kubebuilder:printcolumn:name="Succeeded",type=string,JSONPath=`.status.conditions[?(@.type=="Succeeded")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PGBackRestRestoreRequest is the Schema for the pgbackrestrestorerequests API. Each one
+// validates and then performs a single in-place restore of a PostgresCluster.
+type PGBackRestRestoreRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PGBackRestRestoreRequestSpec   `json:"spec,omitempty"`
+	Status PGBackRestRestoreRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PGBackRestRestoreRequestList contains a list of PGBackRestRestoreRequest
+type PGBackRestRestoreRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PGBackRestRestoreRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PGBackRestRestoreRequest{}, &PGBackRestRestoreRequestList{})
+}
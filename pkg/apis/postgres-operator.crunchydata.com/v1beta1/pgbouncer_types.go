@@ -17,6 +17,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -53,6 +54,116 @@ type PGBouncerConfiguration struct {
 	// More info: https://www.pgbouncer.org/config.html#section-users
 	// +optional
 	Users map[string]string `json:"users,omitempty"`
+
+	// How long to wait for PgBouncer to pause before planned changes of the
+	// PostgreSQL primary, such as switchovers and rolling restarts. Clients
+	// wait while PgBouncer is paused rather than fail, and PgBouncer resumes
+	// when the change is done. When PgBouncer cannot pause in time, the change
+	// happens without pausing. When omitted, PgBouncer is not paused.
+	// More info: https://www.pgbouncer.org/usage.html#pause-db
+	// +optional
+	PauseTimeout *metav1.Duration `json:"pauseTimeout,omitempty"`
+}
+
+type PGBouncerAuthenticationSpec struct {
+	// PgBouncer compares every new client connection to these rules in the
+	// order they are defined. The first rule that matches determines if and how
+	// the connection must then authenticate. Rules required by the operator are
+	// always compared first. When any rule is invalid, none are used.
+	// More info: https://www.pgbouncer.org/config.html#hba-file-format
+	// +listType=atomic
+	// +optional
+	Rules []PGBouncerHBARuleSpec `json:"rules,omitempty"`
+}
+
+type PGBouncerHBARuleSpec struct {
+	// The connection transport this rule matches. Defaults to hostssl.
+	// "host" matches connections using TCP/IP, with or without TLS.
+	// "hostssl" matches connections using TCP/IP with TLS.
+	// "hostnossl" matches connections using TCP/IP without TLS.
+	// +kubebuilder:default=hostssl
+	// +kubebuilder:validation:Enum={host,hostssl,hostnossl}
+	// +optional
+	Connection string `json:"connection,omitempty"`
+
+	// The databases this rule matches. When omitted, the rule matches all
+	// databases.
+	// +listType=set
+	// +optional
+	Databases []PostgresIdentifier `json:"databases,omitempty"`
+
+	// The users this rule matches. When omitted, the rule matches all users.
+	// +listType=set
+	// +optional
+	Users []PostgresIdentifier `json:"users,omitempty"`
+
+	// The client IP addresses this rule matches, in CIDR notation. When
+	// omitted, the rule matches all addresses.
+	// +listType=set
+	// +optional
+	Networks []string `json:"networks,omitempty"`
+
+	// The authentication method to use when a connection matches this rule.
+	// The "cert" method requires a hostssl connection.
+	// More info: https://www.pgbouncer.org/config.html#auth_type
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum={cert,md5,password,reject,scram-sha-256,trust}
+	Method string `json:"method"`
+}
+
+// PGBouncerAutoscalingSpec defines a HorizontalPodAutoscaler of PgBouncer pods.
+// It scales on metrics of the PgBouncer exporter which must be served by the
+// Kubernetes custom metrics API, e.g. by Prometheus Adapter. At least one
+// target is required.
+// More info: https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/
+type PGBouncerAutoscalingSpec struct {
+
+	// Fewest PgBouncer pods to scale down to.
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Most PgBouncer pods to scale up to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Average number of active client connections per pod to scale toward.
+	// These are the "cl_active" clients of SHOW POOLS.
+	// More info: https://www.pgbouncer.org/usage.html#show-pools
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetClientConnections *int32 `json:"targetClientConnections,omitempty"`
+
+	// Average number of clients per pod waiting for a server connection to
+	// scale toward. These are the "cl_waiting" clients of SHOW POOLS.
+	// More info: https://www.pgbouncer.org/usage.html#show-pools
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetWaitingClients *int32 `json:"targetWaitingClients,omitempty"`
+}
+
+type PGBouncerMonitoringSpec struct {
+	// Runs a Prometheus exporter of PgBouncer pool, client, and server
+	// statistics in every PgBouncer pod.
+	// +optional
+	Exporter *PGBouncerExporterSpec `json:"exporter,omitempty"`
+}
+
+type PGBouncerExporterSpec struct {
+	// Name of a container image that can run the PgBouncer exporter. Changing
+	// this value causes PgBouncer to restart. The image may also be set using
+	// the RELATED_IMAGE_PGBOUNCER_EXPORTER environment variable.
+	// More info: https://github.com/prometheus-community/pgbouncer_exporter
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Compute resources of the exporter container. Changing this value causes
+	// PgBouncer to restart.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // PGBouncerPodSpec defines the desired state of a PgBouncer connection pooler.
@@ -66,6 +177,16 @@ type PGBouncerPodSpec struct {
 	// +optional
 	Affinity *corev1.Affinity `json:"affinity,omitempty"`
 
+	// Authentication settings for clients of PgBouncer.
+	// +optional
+	Authentication *PGBouncerAuthenticationSpec `json:"authentication,omitempty"`
+
+	// Scales PgBouncer pods between a minimum and maximum using pool metrics
+	// from the exporter. The replicas field is ignored unless these settings
+	// are invalid.
+	// +optional
+	Autoscaling *PGBouncerAutoscalingSpec `json:"autoscaling,omitempty"`
+
 	// Configuration settings for the PgBouncer process. Changes to any of these
 	// values will be automatically reloaded without validation. Be careful, as
 	// you may put PgBouncer into an unusable state.
@@ -106,6 +227,11 @@ type PGBouncerPodSpec struct {
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
 
+	// Defines a second set of PgBouncer pods whose pools connect to PostgreSQL
+	// replicas rather than the primary.
+	// +optional
+	ReadOnly *PGBouncerReadOnlySpec `json:"readOnly,omitempty"`
+
 	// Number of desired PgBouncer pods.
 	// +optional
 	// +kubebuilder:default=1
@@ -117,6 +243,10 @@ type PGBouncerPodSpec struct {
 	// +optional
 	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
 
+	// Metrics collection for PgBouncer pods.
+	// +optional
+	Monitoring *PGBouncerMonitoringSpec `json:"monitoring,omitempty"`
+
 	// Compute resources of a PgBouncer container. Changing this value causes
 	// PgBouncer to restart.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers
@@ -144,6 +274,35 @@ type PGBouncerPodSpec struct {
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 }
 
+// PGBouncerReadOnlySpec defines PgBouncer pods that connect to PostgreSQL
+// replicas. These pods use the same image, configuration, resources, and
+// scheduling constraints as the PgBouncer pods that connect to the primary.
+type PGBouncerReadOnlySpec struct {
+
+	// A secret projection containing a certificate and key with which to encrypt
+	// connections to the read-only PgBouncer. The "tls.crt", "tls.key", and
+	// "ca.crt" paths must be PEM-encoded certificates and keys. Changing this
+	// value causes PgBouncer to restart.
+	// More info: https://kubernetes.io/docs/concepts/configuration/secret/#projection-of-secret-keys-to-specific-paths
+	// +optional
+	CustomTLSSecret *corev1.SecretProjection `json:"customTLSSecret,omitempty"`
+
+	// Number of desired read-only PgBouncer pods.
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Minimum number of read-only pods that should be available at a time.
+	// Defaults to one when the replicas field is greater than one.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// Specification of the service that exposes the read-only PgBouncer.
+	// +optional
+	Service *ServiceSpec `json:"service,omitempty"`
+}
+
 // PGBouncerSidecars defines the configuration for pgBouncer sidecar containers
 type PGBouncerSidecars struct {
 	// Defines the configuration for the pgBouncer config sidecar container
@@ -163,6 +322,11 @@ func (s *PGBouncerPodSpec) Default() {
 		s.Replicas = new(int32)
 		*s.Replicas = 1
 	}
+
+	if s.ReadOnly != nil && s.ReadOnly.Replicas == nil {
+		s.ReadOnly.Replicas = new(int32)
+		*s.ReadOnly.Replicas = 1
+	}
 }
 
 type PGBouncerPodStatus struct {
@@ -176,4 +340,21 @@ type PGBouncerPodStatus struct {
 
 	// Total number of non-terminated pods.
 	Replicas int32 `json:"replicas,omitempty"`
+
+	// Total number of ready read-only pods.
+	// +optional
+	ReadOnlyReadyReplicas int32 `json:"readOnlyReadyReplicas,omitempty"`
+
+	// Total number of non-terminated read-only pods.
+	// +optional
+	ReadOnlyReplicas int32 `json:"readOnlyReplicas,omitempty"`
+
+	// Number of pods most recently calculated by the autoscaler.
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// When PgBouncer is paused for a change of the PostgreSQL primary, the time
+	// by which it should have resumed. PgBouncer is resumed once this passes.
+	// +optional
+	PausedUntil *metav1.Time `json:"pausedUntil,omitempty"`
 }
@@ -0,0 +1,172 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PGUpgradeValidated is the type of the condition that indicates whether
+	// or not a PGUpgrade can be performed on its PostgresCluster. An upgrade
+	// does not begin until this is "True".
+	PGUpgradeValidated = "Validated"
+
+	// PGUpgradeProgressing is the type of the condition that reports the step
+	// of a PGUpgrade that is in progress.
+	PGUpgradeProgressing = "Progressing"
+
+	// PGUpgradeSucceeded is the type of the condition that indicates whether
+	// or not the upgrade of a PGUpgrade completed successfully.
+	PGUpgradeSucceeded = "Succeeded"
+)
+
+// PGUpgradeSpec defines the desired state of PGUpgrade
+type PGUpgradeSpec struct {
+
+	// The name of the PostgresCluster to upgrade. The cluster must be in the
+	// same namespace as this PGUpgrade.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	PostgresClusterName string `json:"postgresClusterName"`
+
+	// The major version of PostgreSQL that the cluster is running now. It must
+	// match the postgresVersion of the cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=13
+	FromPostgresVersion int `json:"fromPostgresVersion"`
+
+	// The major version of PostgreSQL to upgrade to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=11
+	// +kubebuilder:validation:Maximum=14
+	ToPostgresVersion int `json:"toPostgresVersion"`
+
+	// The image name to use for PostgreSQL containers after the upgrade. When
+	// omitted, the value comes from an operator environment variable. For
+	// standard PostgreSQL images, the format is RELATED_IMAGE_POSTGRES_{postgresVersion},
+	// e.g. RELATED_IMAGE_POSTGRES_14.
+	// The image must contain the same extensions as the current image.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resource requirements for the upgrade Job.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// PGUpgradeStatus defines the observed state of PGUpgrade
+type PGUpgradeStatus struct {
+
+	// observedGeneration represents the .metadata.generation on which the status was based.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// conditions represent the observations of the upgrade's current state.
+	// Known .status.conditions.type are: "Progressing", "Succeeded", "Validated"
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Specifies whether or not the upgrade is finished (does not indicate
+	// success or failure).
+	// +optional
+	Finished bool `json:"finished,omitempty"`
+
+	// Represents the time the PostgresCluster began the upgrade.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Represents the time the upgrade finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// PostgresUpgradeStatus is the progress of a major PostgreSQL upgrade of a
+// PostgresCluster.
+type PostgresUpgradeStatus struct {
+
+	// A unique identifier for the upgrade.
+	ID string `json:"id"`
+
+	// The step of the upgrade that is in progress or, once finished, the
+	// outcome: ShuttingDown, Upgrading, WaitingForVersion, StartingUp,
+	// Succeeded, or Failed.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// The major version of PostgreSQL before the upgrade.
+	FromPostgresVersion int `json:"fromPostgresVersion"`
+
+	// The major version of PostgreSQL after the upgrade.
+	ToPostgresVersion int `json:"toPostgresVersion"`
+
+	// Details about the current step or, when the upgrade failed, how to
+	// recover.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Specifies whether or not the upgrade is finished (does not indicate
+	// success or failure).
+	// +optional
+	Finished bool `json:"finished,omitempty"`
+
+	// Represents the time the upgrade began.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Represents the time the upgrade finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.postgresClusterName`
+// +kubebuilder:printcolumn:name="From",type=integer,JSONPath=`.spec.fromPostgresVersion`
+// +kubebuilder:printcolumn:name="To",type=integer,JSONPath=`.spec.toPostgresVersion`
+// +kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.conditions[?(@.type=="Progressing")].reason`
+// +kubebuilder:printcolumn:name="Succeeded",type=string,JSONPath=`.status.conditions[?(@.type=="Succeeded")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PGUpgrade is the Schema for the pgupgrades API. Each one performs a single
+// major version upgrade of a PostgresCluster using pg_upgrade.
+type PGUpgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PGUpgradeSpec   `json:"spec,omitempty"`
+	Status PGUpgradeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PGUpgradeList contains a list of PGUpgrade
+type PGUpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PGUpgrade `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PGUpgrade{}, &PGUpgradeList{})
+}
@@ -15,6 +15,8 @@
 
 package v1beta1
 
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 // PostgreSQL identifiers are limited in length but may contain any character.
 // More info: https://www.postgresql.org/docs/current/sql-syntax-lexical.html#SQL-SYNTAX-IDENTIFIERS
 //
@@ -30,6 +32,48 @@ type PostgresPasswordSpec struct {
 	// +kubebuilder:default=ASCII
 	// +kubebuilder:validation:Enum={ASCII,AlphaNumeric}
 	Type string `json:"type"`
+
+	// How long a password is used before a new one is generated, e.g. "2160h"
+	// for 90 days. A new password can also be requested by changing the
+	// rotate-password annotation on the user Secret. When omitted, passwords
+	// change only when requested.
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+
+	// How long the previous user and password in the user Secret keep working
+	// after a new password is generated. When set, the user has a second login
+	// role, and the two take turns holding the current password. When omitted,
+	// the old password stops working as soon as the new one is generated.
+	// Users with a certificate or a password from another Secret do not use it.
+	// +optional
+	RotationGracePeriod *metav1.Duration `json:"rotationGracePeriod,omitempty"`
+
+	// A Secret in the namespace of the cluster that holds the password of the
+	// user. When set, the password is copied from this Secret whenever it
+	// changes, and no password is generated or rotated.
+	// +optional
+	SecretRef *PostgresPasswordSecretReference `json:"secretRef,omitempty"`
+}
+
+// PostgresPasswordSecretReference identifies a password and SCRAM verifier in
+// a Secret that is managed outside of the operator.
+type PostgresPasswordSecretReference struct {
+	// Name of the Secret.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The key of the plaintext password in the Secret. Defaults to "password"
+	// when verifierKey is also omitted.
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+
+	// The key of a SCRAM-SHA-256 verifier of the password in the Secret. When
+	// omitted, the verifier is generated from the plaintext password. When
+	// there is no plaintext password, connection URIs do not contain one and
+	// the user cannot log into pgAdmin.
+	// +optional
+	VerifierKey string `json:"verifierKey,omitempty"`
 }
 
 // PostgresPasswordSpec types.
@@ -51,12 +95,28 @@ type PostgresUserSpec struct {
 	Name PostgresIdentifier `json:"name"`
 
 	// Databases to which this user can connect and create objects. Removing a
-	// database from this list does NOT revoke access. This field is ignored for
-	// the "postgres" user.
+	// database from this list does NOT revoke access unless users are managed.
+	// This field is ignored for the "postgres" user.
 	// +listType=set
 	// +optional
 	Databases []PostgresIdentifier `json:"databases,omitempty"`
 
+	// Roles of which this user is a member. Roles that do not exist are
+	// skipped. Removing a role from this list does NOT revoke membership
+	// unless users are managed. This field is ignored for the "postgres" user.
+	// More info: https://www.postgresql.org/docs/current/role-membership.html
+	// +listType=set
+	// +optional
+	Roles []PostgresIdentifier `json:"roles,omitempty"`
+
+	// Access to the tables and sequences of schemas in other databases. The
+	// user can connect to each of these databases. This field is ignored for
+	// the "postgres" user.
+	// +listType=map
+	// +listMapKey=database
+	// +optional
+	Privileges []PostgresUserPrivilegesSpec `json:"privileges,omitempty"`
+
 	// ALTER ROLE options except for PASSWORD. This field is ignored for the
 	// "postgres" user.
 	// More info: https://www.postgresql.org/docs/current/role-attributes.html
@@ -67,4 +127,219 @@ type PostgresUserSpec struct {
 	// Properties of the password generated for this user.
 	// +optional
 	Password *PostgresPasswordSpec `json:"password,omitempty"`
+
+	// Properties of a TLS client certificate for this user. When set, the
+	// operator issues a certificate with the user name as its common name and
+	// stores it in the user Secret as tls.crt, tls.key, and ca.crt. PostgreSQL
+	// then requires this certificate on every network connection by this user.
+	// The certificate is signed by the cluster's certificate authority, so it
+	// cannot be used with spec.customTLSSecret.
+	// +optional
+	Certificate *PostgresUserCertificateSpec `json:"certificate,omitempty"`
 }
+
+type PostgresUserPrivilegesSpec struct {
+	// The database containing the schemas.
+	// +kubebuilder:validation:Required
+	Database PostgresIdentifier `json:"database"`
+
+	// The schemas to access. Defaults to "public". Schemas that do not exist
+	// are skipped.
+	// +listType=set
+	// +optional
+	Schemas []PostgresIdentifier `json:"schemas,omitempty"`
+
+	// The kind of access to tables and sequences in the schemas, including
+	// those created later by users that own the database. Valid options are
+	// ReadOnly and ReadWrite.
+	// "ReadOnly" allows reading tables and sequences.
+	// "ReadWrite" also allows changing rows in tables and values of sequences.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum={ReadOnly,ReadWrite}
+	Access string `json:"access"`
+}
+
+// PostgresUserPrivilegesSpec access types.
+const (
+	PostgresUserAccessReadOnly  = "ReadOnly"
+	PostgresUserAccessReadWrite = "ReadWrite"
+)
+
+type PostgresUserManagementSpec struct {
+	// What happens to users that are removed from spec.users. Defaults to
+	// Disable. Valid options are Disable and Drop.
+	// "Disable" prevents the user from logging in.
+	// "Drop" gives the objects of the user to reassignOwnedTo, revokes its
+	// privileges, then drops the user.
+	// +kubebuilder:default=Disable
+	// +kubebuilder:validation:Enum={Disable,Drop}
+	// +optional
+	RemovedUsers string `json:"removedUsers,omitempty"`
+
+	// The role that takes ownership of the objects of users that are dropped.
+	// Defaults to "postgres".
+	// +optional
+	ReassignOwnedTo PostgresIdentifier `json:"reassignOwnedTo,omitempty"`
+}
+
+// PostgresUserManagementSpec removal types.
+const (
+	PostgresRemovedUsersDisable = "Disable"
+	PostgresRemovedUsersDrop    = "Drop"
+)
+
+type PostgresUserCertificateSpec struct {
+	// Whether or not connections by this user must present a password in
+	// addition to the certificate. When false, the user has no password.
+	// Defaults to false.
+	// +optional
+	RequirePassword bool `json:"requirePassword,omitempty"`
+}
+
+type PostgresAuthenticationSpec struct {
+	// PostgreSQL compares every new connection to these rules in the order
+	// they are defined. The first rule that matches determines if and how the
+	// connection must then authenticate. Rules required by the operator are
+	// always compared first. When any rule is invalid, none are used.
+	// More info: https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+	// +listType=atomic
+	// +optional
+	Rules []PostgresHBARuleSpec `json:"rules,omitempty"`
+}
+
+type PostgresHBARuleSpec struct {
+	// The connection transport this rule matches. Defaults to hostssl.
+	// "local" matches connections using Unix-domain sockets.
+	// "host" matches connections using TCP/IP, with or without TLS.
+	// "hostssl" matches connections using TCP/IP with TLS.
+	// "hostnossl" matches connections using TCP/IP without TLS.
+	// +kubebuilder:default=hostssl
+	// +kubebuilder:validation:Enum={local,host,hostssl,hostnossl}
+	// +optional
+	Connection string `json:"connection,omitempty"`
+
+	// The databases this rule matches. When omitted, the rule matches all
+	// databases.
+	// +listType=set
+	// +optional
+	Databases []PostgresIdentifier `json:"databases,omitempty"`
+
+	// The users this rule matches. When this and groups are omitted, the rule
+	// matches all users.
+	// +listType=set
+	// +optional
+	Users []PostgresIdentifier `json:"users,omitempty"`
+
+	// The roles whose members this rule matches, directly or indirectly.
+	// +listType=set
+	// +optional
+	Groups []PostgresIdentifier `json:"groups,omitempty"`
+
+	// The client IP addresses this rule matches, in CIDR notation. When
+	// omitted, the rule matches all addresses. This must be omitted when
+	// connection is local.
+	// +listType=set
+	// +optional
+	Networks []string `json:"networks,omitempty"`
+
+	// The authentication method to use when a connection matches this rule.
+	// The "cert" method requires a hostssl connection, "peer" requires a local
+	// connection, and "ldap" requires an ldapserver or ldapurl option.
+	// More info: https://www.postgresql.org/docs/current/auth-methods.html
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum={cert,gss,ident,ldap,md5,pam,password,peer,radius,reject,scram-sha-256,trust}
+	Method string `json:"method"`
+
+	// Options for the authentication method, e.g. clientcert or ldapserver.
+	// +mapType=granular
+	// +optional
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// PostgresUserPasswordStatus is the password rotation state of a PostgreSQL user.
+type PostgresUserPasswordStatus struct {
+	// The name of the user.
+	Name string `json:"name"`
+
+	// The last time a password was generated for the user.
+	// +optional
+	RotatedAt *metav1.Time `json:"rotatedAt,omitempty"`
+
+	// The value of the rotate-password annotation on the user Secret when a
+	// password was last generated.
+	// +optional
+	RotationTrigger string `json:"rotationTrigger,omitempty"`
+}
+
+type PostgresDatabaseSpec struct {
+	// The name of the database.
+	// +kubebuilder:validation:Required
+	Name PostgresIdentifier `json:"name"`
+
+	// The role that owns the database and its schemas. The role is created
+	// when it does not exist; it can login only when it is in spec.users.
+	// Defaults to "postgres".
+	// +optional
+	Owner PostgresIdentifier `json:"owner,omitempty"`
+
+	// The character set encoding of the database. This is used only when the
+	// database is created.
+	// More info: https://www.postgresql.org/docs/current/multibyte.html
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The collation and character classification of the database. This is
+	// used only when the database is created.
+	// More info: https://www.postgresql.org/docs/current/locale.html
+	// +optional
+	Locale string `json:"locale,omitempty"`
+
+	// The database from which to copy the database. This is used only when
+	// the database is created.
+	// More info: https://www.postgresql.org/docs/current/manage-ag-templatedbs.html
+	// +optional
+	Template PostgresIdentifier `json:"template,omitempty"`
+
+	// Extensions to install into the database. Extensions that are removed
+	// from this list are not removed from the database.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Extensions []PostgresExtensionSpec `json:"extensions,omitempty"`
+
+	// Schemas to create in the database. They are owned by the owner of the
+	// database. Schemas that are removed from this list are not dropped.
+	// +listType=set
+	// +optional
+	Schemas []PostgresIdentifier `json:"schemas,omitempty"`
+}
+
+type PostgresExtensionSpec struct {
+	// The name of the extension.
+	// +kubebuilder:validation:Required
+	Name PostgresIdentifier `json:"name"`
+
+	// The version of the extension. When omitted, the extension is kept at
+	// the default version of the installed packages, which may change when
+	// the image changes.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+type PostgresDatabaseManagementSpec struct {
+	// What happens to databases that are removed from spec.databases. Defaults
+	// to Keep. Valid options are Keep and Drop.
+	// "Keep" leaves the database and its data as they are.
+	// "Drop" disconnects everyone from the database and drops it. This
+	// cannot be undone.
+	// +kubebuilder:default=Keep
+	// +kubebuilder:validation:Enum={Keep,Drop}
+	// +optional
+	RemovedDatabases string `json:"removedDatabases,omitempty"`
+}
+
+// PostgresDatabaseManagementSpec removal types.
+const (
+	PostgresRemovedDatabasesKeep = "Keep"
+	PostgresRemovedDatabasesDrop = "Drop"
+)
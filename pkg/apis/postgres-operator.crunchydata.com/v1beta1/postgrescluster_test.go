@@ -27,6 +27,7 @@ import (
 
 func TestPostgresClusterWebhooks(t *testing.T) {
 	var _ webhook.Defaulter = new(PostgresCluster)
+	var _ webhook.Validator = new(PostgresCluster)
 }
 
 func TestPostgresClusterDefault(t *testing.T) {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -32,6 +33,10 @@ type PostgresClusterSpec struct {
 	// +optional
 	DataSource *DataSource `json:"dataSource,omitempty"`
 
+	// Rules for how clients must authenticate to PostgreSQL.
+	// +optional
+	Authentication *PostgresAuthenticationSpec `json:"authentication,omitempty"`
+
 	// PostgreSQL backup configuration
 	// +kubebuilder:validation:Required
 	Backups Backups `json:"backups"`
@@ -168,12 +173,35 @@ type PostgresClusterSpec struct {
 	// Users to create inside PostgreSQL and the databases they should access.
 	// The default creates one user that can access one database matching the
 	// PostgresCluster name. An empty list creates no users. Removing a user
-	// from this list does NOT drop the user nor revoke their access.
+	// from this list does NOT drop the user nor revoke their access unless
+	// userManagement is set.
 	// +listType=map
 	// +listMapKey=name
 	// +optional
 	Users []PostgresUserSpec `json:"users,omitempty"`
 
+	// When set, the operator also removes users and access that are removed
+	// from spec.users. Only users that the operator has managed this way are
+	// removed. When omitted, removing a user or its access from spec.users
+	// changes nothing in PostgreSQL.
+	// +optional
+	UserManagement *PostgresUserManagementSpec `json:"userManagement,omitempty"`
+
+	// Databases to create inside PostgreSQL along with their owners,
+	// extensions, and schemas. Databases are also created for the users in
+	// spec.users. Removing a database from this list does NOT drop it unless
+	// databaseManagement says so.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Databases []PostgresDatabaseSpec `json:"databases,omitempty"`
+
+	// When set, the operator also drops databases that are removed from
+	// spec.databases. Only databases that were once in spec.databases are
+	// dropped; those of spec.users are kept.
+	// +optional
+	DatabaseManagement *PostgresDatabaseManagementSpec `json:"databaseManagement,omitempty"`
+
 	Config PostgresAdditionalConfig `json:"config,omitempty"`
 }
 
@@ -215,6 +243,34 @@ type DataSourceVolumes struct {
 	// current PostgresCluster.
 	// +optional
 	PGBackRestVolume *DataSourceVolume `json:"pgBackRestVolume,omitempty"`
+
+	// Defines a VolumeSnapshot from which to create the pgData volume of the
+	// PostgresCluster. This cannot be combined with the other volumes.
+	// +optional
+	VolumeSnapshot *DataSourceVolumeSnapshot `json:"volumeSnapshot,omitempty"`
+}
+
+// DataSourceVolumeSnapshot defines a VolumeSnapshot of a PostgreSQL data volume from which
+// to bootstrap a new PostgresCluster. The snapshot must have been taken by the volumeSnapshots
+// backup method of a PostgresCluster in the same namespace. WAL is replayed from a pgBackRest
+// repository of that cluster.
+type DataSourceVolumeSnapshot struct {
+
+	// The name of the VolumeSnapshot of the PostgreSQL data volume.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The name of the pgBackRest repo of the source PostgresCluster from which to
+	// retrieve WAL.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^repo[1-4]
+	RepoName string `json:"repoName"`
+
+	// The point to which PostgreSQL should be recovered. When omitted, all
+	// archived WAL is replayed. A backupLabel cannot be used here.
+	// +optional
+	Target *PGBackRestRestoreTarget `json:"target,omitempty"`
 }
 
 // DataSourceVolume defines the PVC name and data diretory path for an existing cluster volume.
@@ -236,9 +292,64 @@ type DatabaseInitSQL struct {
 	// +required
 	Name string `json:"name"`
 
-	// Key is the ConfigMap data key that points to a SQL string
-	// +required
+	// Key is the ConfigMap data key that points to a SQL string. This SQL is
+	// run once. It is required unless migrations are specified.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// The database in which to run the SQL. Defaults to "postgres".
+	// +optional
+	Database PostgresIdentifier `json:"database,omitempty"`
+
+	// Migrations are SQL that is run in order after the SQL of key. Each one
+	// is run once, in a transaction, and recorded along with a checksum of
+	// its SQL in the "postgres_operator.migrations" table of its database.
+	// The SQL is sent to PostgreSQL as-is, without psql meta-commands or
+	// variables, and must not end its transaction. Changing the SQL of a
+	// migration that has run stops any more from running.
+	// +listType=map
+	// +listMapKey=version
+	// +optional
+	Migrations []DatabaseInitSQLMigration `json:"migrations,omitempty"`
+}
+
+// DatabaseInitSQLMigration is SQL in a ConfigMap that is run once per version.
+type DatabaseInitSQLMigration struct {
+	// A unique identifier of the migration, e.g. "001" or "2022-06-01".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// The name of the ConfigMap that contains the SQL. Defaults to the
+	// ConfigMap of databaseInitSQL.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// The ConfigMap data key that points to the SQL.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
 	Key string `json:"key"`
+
+	// The database in which to run the SQL. Defaults to the database of
+	// databaseInitSQL.
+	// +optional
+	Database PostgresIdentifier `json:"database,omitempty"`
+}
+
+// DatabaseInitSQLMigrationStatus identifies a migration that has been run.
+type DatabaseInitSQLMigrationStatus struct {
+	// The version of the migration.
+	Version string `json:"version"`
+
+	// The database in which the migration ran.
+	Database string `json:"database"`
+
+	// The SHA-256 checksum of the SQL of the migration.
+	Checksum string `json:"checksum"`
+
+	// When the migration ran.
+	// +optional
+	AppliedAt *metav1.Time `json:"appliedAt,omitempty"`
 }
 
 // PostgresClusterDataSource defines a data source for bootstrapping PostgreSQL clusters using a
@@ -319,6 +430,12 @@ type Backups struct {
 	// pgBackRest archive configuration
 	// +kubebuilder:validation:Required
 	PGBackRest PGBackRestArchive `json:"pgbackrest"`
+
+	// VolumeSnapshots defines an additional backup method that takes CSI
+	// VolumeSnapshots of the PostgreSQL data and WAL volumes of an instance.
+	// The WAL needed to recover a snapshot is archived by pgBackRest.
+	// +optional
+	VolumeSnapshots *VolumeSnapshots `json:"volumeSnapshots,omitempty"`
 }
 
 // PostgresClusterStatus defines the observed state of PostgresCluster
@@ -340,11 +457,19 @@ type PostgresClusterStatus struct {
 	// +optional
 	PGBackRest *PGBackRestStatus `json:"pgbackrest,omitempty"`
 
+	// Status information for volume snapshot backups
+	// +optional
+	VolumeSnapshots *VolumeSnapshotsStatus `json:"volumeSnapshots,omitempty"`
+
 	// Stores the current PostgreSQL major version following a successful
 	// major PostgreSQL upgrade.
 	// +optional
 	PostgresVersion int `json:"postgresVersion"`
 
+	// Status information for the latest major PostgreSQL upgrade
+	// +optional
+	PostgresUpgrade *PostgresUpgradeStatus `json:"postgresUpgrade,omitempty"`
+
 	// Current state of the PostgreSQL proxy.
 	// +optional
 	Proxy PostgresProxyStatus `json:"proxy,omitempty"`
@@ -365,6 +490,12 @@ type PostgresClusterStatus struct {
 	// Identifies the users that have been installed into PostgreSQL.
 	UsersRevision string `json:"usersRevision,omitempty"`
 
+	// The passwords that have been generated for users in spec.users.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	UserPasswords []PostgresUserPasswordStatus `json:"userPasswords,omitempty"`
+
 	// Current state of PostgreSQL cluster monitoring tool configuration
 	// +optional
 	Monitoring MonitoringStatus `json:"monitoring,omitempty"`
@@ -373,6 +504,12 @@ type PostgresClusterStatus struct {
 	// +optional
 	DatabaseInitSQL *string `json:"databaseInitSQL,omitempty"`
 
+	// The migrations of databaseInitSQL that have run, in order.
+	// +listType=map
+	// +listMapKey=version
+	// +optional
+	DatabaseInitSQLMigrations []DatabaseInitSQLMigrationStatus `json:"databaseInitSQLMigrations,omitempty"`
+
 	// observedGeneration represents the .metadata.generation on which the status was based.
 	// +optional
 	// +kubebuilder:validation:Minimum=0
@@ -390,6 +527,7 @@ type PostgresClusterStatus struct {
 
 // PostgresClusterStatus condition types.
 const (
+	DatabaseInitSQLMigrated    = "DatabaseInitSQLMigrated"
 	PersistentVolumeResizing   = "PersistentVolumeResizing"
 	PostgresClusterProgressing = "Progressing"
 	ProxyAvailable             = "ProxyAvailable"
@@ -459,6 +597,11 @@ type PostgresInstanceSetSpec struct {
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// How to recreate the pods of this set when their specification changes,
+	// e.g. when the image is updated.
+	// +optional
+	Rollout *PostgresInstanceRolloutStrategy `json:"rollout,omitempty"`
+
 	// Configuration for instance sidecar containers
 	// +optional
 	Sidecars *InstanceSidecars `json:"sidecars,omitempty"`
@@ -499,6 +642,36 @@ func (s *PostgresInstanceSetSpec) Default(i int) {
 	}
 }
 
+// PostgresInstanceRolloutStrategy controls how the pods of an instance set are
+// recreated to match a new specification. Replicas are recreated before the
+// primary.
+type PostgresInstanceRolloutStrategy struct {
+
+	// The maximum number of instances in the cluster that can be unavailable
+	// while pods of this set are recreated. Defaults to one.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+
+	// Stop recreating pods once this many instances of the set have the new
+	// specification. Raise or remove this value to continue. When zero, no
+	// pods of the set are recreated.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	PauseAfter *int32 `json:"pauseAfter,omitempty"`
+
+	// Wait to recreate each pod until every available replica in the cluster
+	// has replayed write-ahead log to within this many bytes of the primary.
+	// +optional
+	MaxReplicationLag *resource.Quantity `json:"maxReplicationLag,omitempty"`
+
+	// Whether or not to switch over to an updated replica before recreating
+	// the pod of the primary. When false, the primary is restarted in place
+	// and Patroni may fail over to any replica. Defaults to true.
+	// +optional
+	SwitchoverPrimary *bool `json:"switchoverPrimary,omitempty"`
+}
+
 type PostgresInstanceSetStatus struct {
 	Name string `json:"name"`
 
@@ -513,6 +686,22 @@ type PostgresInstanceSetStatus struct {
 	// Total number of pods that have the desired specification.
 	// +optional
 	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// The progress of recreating pods that do not have the desired specification.
+	// +optional
+	Rollout *PostgresInstanceSetRolloutStatus `json:"rollout,omitempty"`
+}
+
+// PostgresInstanceSetRolloutStatus describes why pods of an instance set are or
+// are not being recreated.
+type PostgresInstanceSetRolloutStatus struct {
+
+	// One of Progressing, Paused, WaitingForAvailability, or WaitingForReplication.
+	State string `json:"state"`
+
+	// Details about the state of the rollout.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // PostgresProxySpec is a union of the supported PostgreSQL proxies.
@@ -581,6 +770,16 @@ type PostgresUserInterfaceStatus struct {
 
 type PostgresAdditionalConfig struct {
 	Files []corev1.VolumeProjection `json:"files,omitempty"`
+
+	// Configuration parameters for the PostgreSQL server. Each is validated
+	// against the postgresVersion of the cluster. Parameters that the operator
+	// manages cannot be changed. These take precedence over parameters in
+	// spec.patroni.dynamicConfiguration. Changes to some parameters cause
+	// PostgreSQL to restart.
+	// More info: https://www.postgresql.org/docs/current/runtime-config.html
+	// +mapType=granular
+	// +optional
+	Parameters map[string]intstr.IntOrString `json:"parameters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,210 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// +kubebuilder:webhook:path=/mutate-postgres-operator-crunchydata-com-v1beta1-postgrescluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=postgres-operator.crunchydata.com,resources=postgresclusters,verbs=create;update,versions=v1beta1,name=mpostgrescluster.postgres-operator.crunchydata.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-postgres-operator-crunchydata-com-v1beta1-postgrescluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=postgres-operator.crunchydata.com,resources=postgresclusters,verbs=create;update,versions=v1beta1,name=vpostgrescluster.postgres-operator.crunchydata.com,admissionReviewVersions=v1
+
+// ValidateCreate implements "sigs.k8s.io/controller-runtime/pkg/webhook.Validator"
+// so a webhook can reject an invalid PostgresCluster before it is stored.
+func (c *PostgresCluster) ValidateCreate() error {
+	return c.validate(nil)
+}
+
+// ValidateUpdate implements "sigs.k8s.io/controller-runtime/pkg/webhook.Validator"
+// so a webhook can reject invalid changes to a PostgresCluster.
+func (c *PostgresCluster) ValidateUpdate(old runtime.Object) error {
+	previous, ok := old.(*PostgresCluster)
+	if !ok {
+		return fmt.Errorf("expected a PostgresCluster but got a %T", old)
+	}
+	return c.validate(previous)
+}
+
+// ValidateDelete implements "sigs.k8s.io/controller-runtime/pkg/webhook.Validator".
+// Every PostgresCluster can be deleted.
+func (c *PostgresCluster) ValidateDelete() error { return nil }
+
+// validate returns an Invalid error listing every problem with c. When old is
+// not nil, it also checks that c is an acceptable change from old.
+func (c *PostgresCluster) validate(old *PostgresCluster) error {
+	path := field.NewPath("spec")
+	errs := c.Spec.validate(path)
+
+	if old != nil {
+		errs = append(errs, c.Spec.validateUpdate(&old.Spec, path)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		GroupVersion.WithKind("PostgresCluster").GroupKind(), c.Name, errs)
+}
+
+// validate checks the relationships between fields of s that cannot be
+// expressed in the OpenAPI schema of the CRD.
+func (s *PostgresClusterSpec) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	instanceNames := make(map[string]bool, len(s.InstanceSets))
+	for i := range s.InstanceSets {
+		// Names are usually filled in by [PostgresClusterSpec.Default], but
+		// an explicit name might collide with a generated one.
+		name := s.InstanceSets[i].Name
+		if name == "" {
+			name = fmt.Sprintf("%02d", i)
+		}
+		if instanceNames[name] {
+			errs = append(errs, field.Duplicate(
+				path.Child("instances").Index(i).Child("name"), name))
+		}
+		instanceNames[name] = true
+	}
+
+	repoNames := make(map[string]bool, len(s.Backups.PGBackRest.Repos))
+	for i, repo := range s.Backups.PGBackRest.Repos {
+		if repoNames[repo.Name] {
+			errs = append(errs, field.Duplicate(
+				path.Child("backups", "pgbackrest", "repos").Index(i).Child("name"),
+				repo.Name))
+		}
+		repoNames[repo.Name] = true
+	}
+
+	if manual := s.Backups.PGBackRest.Manual; manual != nil && !repoNames[manual.RepoName] {
+		errs = append(errs, field.NotFound(
+			path.Child("backups", "pgbackrest", "manual", "repoName"), manual.RepoName))
+	}
+
+	if standby := s.Standby; standby != nil && standby.Enabled {
+		if standby.Host == "" && standby.RepoName == "" {
+			// When a standby cluster is requested but a repoName or host is not
+			// provided the cluster would be created as a non-standby.
+			errs = append(errs, field.Required(path.Child("standby"),
+				"Standby requires a host or repoName to be enabled"))
+		}
+		if standby.RepoName != "" && !repoNames[standby.RepoName] {
+			errs = append(errs, field.NotFound(
+				path.Child("standby", "repoName"), standby.RepoName))
+		}
+	}
+
+	// Without a key or migrations, there is no SQL to run.
+	if init := s.DatabaseInitSQL; init != nil && init.Key == "" && len(init.Migrations) == 0 {
+		errs = append(errs, field.Required(path.Child("databaseInitSQL", "key"),
+			"key is required unless migrations are specified"))
+	}
+
+	// The two custom certificates must be provided together so they can share
+	// a certificate authority.
+	if s.CustomTLSSecret != nil && s.CustomReplicationClientTLSSecret == nil {
+		errs = append(errs, field.Required(path.Child("customReplicationTLSSecret"),
+			"customReplicationTLSSecret is required when customTLSSecret is set"))
+	}
+	if s.CustomReplicationClientTLSSecret != nil && s.CustomTLSSecret == nil {
+		errs = append(errs, field.Required(path.Child("customTLSSecret"),
+			"customTLSSecret is required when customReplicationTLSSecret is set"))
+	}
+
+	// User certificates are signed by the cluster's certificate authority,
+	// which PostgreSQL does not trust when the custom certificates are used.
+	if s.CustomTLSSecret != nil {
+		for i := range s.Users {
+			if s.Users[i].Certificate != nil {
+				errs = append(errs, field.Forbidden(
+					path.Child("users").Index(i).Child("certificate"),
+					"cannot be used with customTLSSecret"))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateUpdate checks that s is an acceptable change from old.
+func (s *PostgresClusterSpec) validateUpdate(
+	old *PostgresClusterSpec, path *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+
+	oldInstances := make(map[string]*PostgresInstanceSetSpec, len(old.InstanceSets))
+	for i := range old.InstanceSets {
+		oldInstances[old.InstanceSets[i].Name] = &old.InstanceSets[i]
+	}
+	for i := range s.InstanceSets {
+		set, previous := &s.InstanceSets[i], oldInstances[s.InstanceSets[i].Name]
+		if previous == nil {
+			continue
+		}
+
+		setPath := path.Child("instances").Index(i)
+		errs = append(errs, validateVolumeNotShrunk(
+			&set.DataVolumeClaimSpec, &previous.DataVolumeClaimSpec,
+			setPath.Child("dataVolumeClaimSpec"))...)
+
+		if set.WALVolumeClaimSpec != nil && previous.WALVolumeClaimSpec != nil {
+			errs = append(errs, validateVolumeNotShrunk(
+				set.WALVolumeClaimSpec, previous.WALVolumeClaimSpec,
+				setPath.Child("walVolumeClaimSpec"))...)
+		}
+	}
+
+	oldRepos := make(map[string]*PGBackRestRepo, len(old.Backups.PGBackRest.Repos))
+	for i := range old.Backups.PGBackRest.Repos {
+		oldRepos[old.Backups.PGBackRest.Repos[i].Name] = &old.Backups.PGBackRest.Repos[i]
+	}
+	for i, repo := range s.Backups.PGBackRest.Repos {
+		previous := oldRepos[repo.Name]
+		if repo.Volume == nil || previous == nil || previous.Volume == nil {
+			continue
+		}
+		errs = append(errs, validateVolumeNotShrunk(
+			&repo.Volume.VolumeClaimSpec, &previous.Volume.VolumeClaimSpec,
+			path.Child("backups", "pgbackrest", "repos").Index(i).
+				Child("volume", "volumeClaimSpec"))...)
+	}
+
+	return errs
+}
+
+// validateVolumeNotShrunk returns an error when the storage requested by spec
+// is less than that requested by old. PersistentVolumeClaims can be expanded
+// but never shrunk.
+// - https://docs.k8s.io/concepts/storage/persistent-volumes/#expanding-persistent-volumes-claims
+func validateVolumeNotShrunk(
+	spec, old *corev1.PersistentVolumeClaimSpec, path *field.Path,
+) field.ErrorList {
+	request, hasRequest := spec.Resources.Requests[corev1.ResourceStorage]
+	previous, hadRequest := old.Resources.Requests[corev1.ResourceStorage]
+
+	if hasRequest && hadRequest && request.Cmp(previous) < 0 {
+		return field.ErrorList{field.Forbidden(
+			path.Child("resources", "requests", "storage"),
+			fmt.Sprintf("cannot be decreased from %s to %s",
+				previous.String(), request.String()))}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+func TestPostgresClusterValidateCreate(t *testing.T) {
+	t.Parallel()
+
+	base := func(t *testing.T) *PostgresCluster {
+		cluster := new(PostgresCluster)
+		cluster.Name = "hippo"
+		assert.NilError(t, yaml.Unmarshal([]byte(`{
+			postgresVersion: 14,
+			instances: [{
+				dataVolumeClaimSpec: {
+					accessModes: [ReadWriteOnce],
+					resources: { requests: { storage: 1Gi } },
+				},
+			}],
+			backups: { pgbackrest: { repos: [{ name: repo1 }] } },
+		}`), &cluster.Spec))
+		return cluster
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		cluster := base(t)
+		assert.NilError(t, cluster.ValidateCreate())
+
+		cluster.Default()
+		assert.NilError(t, cluster.ValidateCreate())
+	})
+
+	for _, tt := range []struct {
+		name, message string
+		mutate        func(*PostgresCluster)
+	}{
+		{
+			name:    "StandbyWithoutSource",
+			message: "spec.standby: Required value: Standby requires a host or repoName to be enabled",
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.Standby = &PostgresStandbySpec{Enabled: true}
+			},
+		},
+		{
+			name:    "StandbyUnknownRepo",
+			message: `spec.standby.repoName: Not found: "repo2"`,
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.Standby = &PostgresStandbySpec{Enabled: true, RepoName: "repo2"}
+			},
+		},
+		{
+			name:    "DuplicateRepos",
+			message: `spec.backups.pgbackrest.repos[1].name: Duplicate value: "repo1"`,
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.Backups.PGBackRest.Repos = append(
+					cluster.Spec.Backups.PGBackRest.Repos, PGBackRestRepo{Name: "repo1"})
+			},
+		},
+		{
+			name:    "DuplicateInstanceSets",
+			message: `spec.instances[1].name: Duplicate value: "00"`,
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.InstanceSets = append(cluster.Spec.InstanceSets,
+					PostgresInstanceSetSpec{Name: "00"})
+			},
+		},
+		{
+			name:    "ManualUnknownRepo",
+			message: `spec.backups.pgbackrest.manual.repoName: Not found: "repo3"`,
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.Backups.PGBackRest.Manual = &PGBackRestManualBackup{RepoName: "repo3"}
+			},
+		},
+		{
+			name:    "DatabaseInitSQLWithoutSQL",
+			message: "spec.databaseInitSQL.key: Required value",
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.DatabaseInitSQL = &DatabaseInitSQL{Name: "some-configmap"}
+			},
+		},
+		{
+			name:    "CustomTLSWithoutReplication",
+			message: "spec.customReplicationTLSSecret: Required value",
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.CustomTLSSecret = new(corev1.SecretProjection)
+			},
+		},
+		{
+			name:    "CustomReplicationWithoutTLS",
+			message: "spec.customTLSSecret: Required value",
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.CustomReplicationClientTLSSecret = new(corev1.SecretProjection)
+			},
+		},
+		{
+			name:    "UserCertificateWithCustomTLS",
+			message: "spec.users[1].certificate: Forbidden: cannot be used with customTLSSecret",
+			mutate: func(cluster *PostgresCluster) {
+				cluster.Spec.CustomTLSSecret = new(corev1.SecretProjection)
+				cluster.Spec.CustomReplicationClientTLSSecret = new(corev1.SecretProjection)
+				cluster.Spec.Users = []PostgresUserSpec{
+					{Name: "app"},
+					{Name: "cert", Certificate: &PostgresUserCertificateSpec{}},
+				}
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := base(t)
+			tt.mutate(cluster)
+
+			err := cluster.ValidateCreate()
+			assert.Assert(t, apierrors.IsInvalid(err), "got %#v", err)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
+
+func TestPostgresClusterValidateUpdate(t *testing.T) {
+	t.Parallel()
+
+	storage := func(spec *corev1.PersistentVolumeClaimSpec, value string) {
+		spec.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse(value),
+		}
+	}
+
+	old := new(PostgresCluster)
+	old.Name = "hippo"
+	old.Spec.InstanceSets = []PostgresInstanceSetSpec{{
+		Name: "one", WALVolumeClaimSpec: new(corev1.PersistentVolumeClaimSpec),
+	}}
+	old.Spec.Backups.PGBackRest.Repos = []PGBackRestRepo{{
+		Name: "repo1", Volume: new(RepoPVC),
+	}}
+	storage(&old.Spec.InstanceSets[0].DataVolumeClaimSpec, "2Gi")
+	storage(old.Spec.InstanceSets[0].WALVolumeClaimSpec, "2Gi")
+	storage(&old.Spec.Backups.PGBackRest.Repos[0].Volume.VolumeClaimSpec, "2Gi")
+
+	t.Run("Unchanged", func(t *testing.T) {
+		assert.NilError(t, old.DeepCopy().ValidateUpdate(old))
+	})
+
+	t.Run("WrongType", func(t *testing.T) {
+		assert.ErrorContains(t, old.ValidateUpdate(new(PostgresClusterList)), "expected")
+	})
+
+	t.Run("Expanded", func(t *testing.T) {
+		cluster := old.DeepCopy()
+		storage(&cluster.Spec.InstanceSets[0].DataVolumeClaimSpec, "3Gi")
+		storage(cluster.Spec.InstanceSets[0].WALVolumeClaimSpec, "3Gi")
+		storage(&cluster.Spec.Backups.PGBackRest.Repos[0].Volume.VolumeClaimSpec, "3Gi")
+
+		assert.NilError(t, cluster.ValidateUpdate(old))
+	})
+
+	t.Run("Shrunk", func(t *testing.T) {
+		cluster := old.DeepCopy()
+		storage(&cluster.Spec.InstanceSets[0].DataVolumeClaimSpec, "1Gi")
+		storage(cluster.Spec.InstanceSets[0].WALVolumeClaimSpec, "1Gi")
+		storage(&cluster.Spec.Backups.PGBackRest.Repos[0].Volume.VolumeClaimSpec, "1Gi")
+
+		err := cluster.ValidateUpdate(old)
+		assert.Assert(t, apierrors.IsInvalid(err), "got %#v", err)
+
+		status := err.(apierrors.APIStatus).Status()
+		assert.Equal(t, len(status.Details.Causes), 3)
+		assert.ErrorContains(t, err,
+			"spec.instances[0].dataVolumeClaimSpec.resources.requests.storage: "+
+				"Forbidden: cannot be decreased from 2Gi to 1Gi")
+		assert.ErrorContains(t, err, "spec.instances[0].walVolumeClaimSpec")
+		assert.ErrorContains(t, err, "spec.backups.pgbackrest.repos[0].volume.volumeClaimSpec")
+	})
+
+	t.Run("NewInstanceSet", func(t *testing.T) {
+		cluster := old.DeepCopy()
+		cluster.Spec.InstanceSets[0].Name = "two"
+		storage(&cluster.Spec.InstanceSets[0].DataVolumeClaimSpec, "1Gi")
+
+		assert.NilError(t, cluster.ValidateUpdate(old))
+	})
+}
@@ -0,0 +1,83 @@
+/*
+ Copyright 2021 - 2022 Crunchy Data Solutions, Inc.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VolumeSnapshots defines a backup method that uses the CSI snapshot API. A
+// snapshot is taken of the data volume, and the WAL volume when there is one, of
+// a replica while PostgreSQL is in backup mode.
+// - https://kubernetes.io/docs/concepts/storage/volume-snapshots/
+type VolumeSnapshots struct {
+
+	// The name of the VolumeSnapshotClass to use for every snapshot.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName"`
+
+	// The cron schedule on which to take snapshots, in the same format as a
+	// Kubernetes CronJob. A snapshot can also be taken on-demand by creating a
+	// Job from the CronJob.
+	// +optional
+	// +kubebuilder:validation:MinLength=6
+	Schedule *string `json:"schedule,omitempty"`
+
+	// The number of successful snapshots to keep. Older snapshots are deleted.
+	// Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Retention *int32 `json:"retention,omitempty"`
+}
+
+// VolumeSnapshotsStatus is the status of volume snapshot backups.
+type VolumeSnapshotsStatus struct {
+
+	// The most recent snapshot, which may still be in progress.
+	// +optional
+	Latest *VolumeSnapshotSetStatus `json:"latest,omitempty"`
+
+	// The name of the VolumeSnapshot of the data volume from the most recent
+	// successful snapshot. This can be used as a data source of a new PostgresCluster.
+	// +optional
+	LastSucceeded string `json:"lastSucceeded,omitempty"`
+}
+
+// VolumeSnapshotSetStatus is the status of a single snapshot of the volumes of an instance.
+type VolumeSnapshotSetStatus struct {
+
+	// The name of the snapshot. Every VolumeSnapshot taken for it starts with this name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// The name of the instance Pod that was snapshotted.
+	// +optional
+	Pod string `json:"pod,omitempty"`
+
+	// The step the snapshot has reached: Starting, Snapshotting, Stopping,
+	// Succeeded, or Failed.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// The time the snapshot started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time the snapshot succeeded or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
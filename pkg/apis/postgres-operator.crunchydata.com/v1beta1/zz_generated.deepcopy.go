@@ -64,6 +64,11 @@ func (in *BackupJobs) DeepCopy() *BackupJobs {
 func (in *Backups) DeepCopyInto(out *Backups) {
 	*out = *in
 	in.PGBackRest.DeepCopyInto(&out.PGBackRest)
+	if in.VolumeSnapshots != nil {
+		in, out := &in.VolumeSnapshots, &out.VolumeSnapshots
+		*out = new(VolumeSnapshots)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backups.
@@ -121,6 +126,26 @@ func (in *DataSourceVolume) DeepCopy() *DataSourceVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSourceVolumeSnapshot) DeepCopyInto(out *DataSourceVolumeSnapshot) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(PGBackRestRestoreTarget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSourceVolumeSnapshot.
+func (in *DataSourceVolumeSnapshot) DeepCopy() *DataSourceVolumeSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSourceVolumeSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataSourceVolumes) DeepCopyInto(out *DataSourceVolumes) {
 	*out = *in
@@ -139,6 +164,11 @@ func (in *DataSourceVolumes) DeepCopyInto(out *DataSourceVolumes) {
 		*out = new(DataSourceVolume)
 		**out = **in
 	}
+	if in.VolumeSnapshot != nil {
+		in, out := &in.VolumeSnapshot, &out.VolumeSnapshot
+		*out = new(DataSourceVolumeSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSourceVolumes.
@@ -154,6 +184,11 @@ func (in *DataSourceVolumes) DeepCopy() *DataSourceVolumes {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseInitSQL) DeepCopyInto(out *DatabaseInitSQL) {
 	*out = *in
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]DatabaseInitSQLMigration, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseInitSQL.
@@ -166,6 +201,40 @@ func (in *DatabaseInitSQL) DeepCopy() *DatabaseInitSQL {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseInitSQLMigration) DeepCopyInto(out *DatabaseInitSQLMigration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseInitSQLMigration.
+func (in *DatabaseInitSQLMigration) DeepCopy() *DatabaseInitSQLMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseInitSQLMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseInitSQLMigrationStatus) DeepCopyInto(out *DatabaseInitSQLMigrationStatus) {
+	*out = *in
+	if in.AppliedAt != nil {
+		in, out := &in.AppliedAt, &out.AppliedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseInitSQLMigrationStatus.
+func (in *DatabaseInitSQLMigrationStatus) DeepCopy() *DatabaseInitSQLMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseInitSQLMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExporterSpec) DeepCopyInto(out *ExporterSpec) {
 	*out = *in
@@ -432,6 +501,11 @@ func (in *PGBackRestArchive) DeepCopyInto(out *PGBackRestArchive) {
 		*out = new(PGBackRestSidecars)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(PGBackRestVerification)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestArchive.
@@ -444,6 +518,103 @@ func (in *PGBackRestArchive) DeepCopy() *PGBackRestArchive {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestBackup) DeepCopyInto(out *PGBackRestBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackup.
+func (in *PGBackRestBackup) DeepCopy() *PGBackRestBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PGBackRestBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestBackupInfo) DeepCopyInto(out *PGBackRestBackupInfo) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StopTime != nil {
+		in, out := &in.StopTime, &out.StopTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DatabaseSize != nil {
+		in, out := &in.DatabaseSize, &out.DatabaseSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.RepoSize != nil {
+		in, out := &in.RepoSize, &out.RepoSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackupInfo.
+func (in *PGBackRestBackupInfo) DeepCopy() *PGBackRestBackupInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestBackupInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestBackupList) DeepCopyInto(out *PGBackRestBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PGBackRestBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackupList.
+func (in *PGBackRestBackupList) DeepCopy() *PGBackRestBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PGBackRestBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PGBackRestBackupSchedules) DeepCopyInto(out *PGBackRestBackupSchedules) {
 	*out = *in
@@ -462,6 +633,16 @@ func (in *PGBackRestBackupSchedules) DeepCopyInto(out *PGBackRestBackupSchedules
 		*out = new(string)
 		**out = **in
 	}
+	if in.Expire != nil {
+		in, out := &in.Expire, &out.Expire
+		*out = new(string)
+		**out = **in
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackupSchedules.
@@ -474,6 +655,61 @@ func (in *PGBackRestBackupSchedules) DeepCopy() *PGBackRestBackupSchedules {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestBackupSpec) DeepCopyInto(out *PGBackRestBackupSpec) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackupSpec.
+func (in *PGBackRestBackupSpec) DeepCopy() *PGBackRestBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestBackupStatus) DeepCopyInto(out *PGBackRestBackupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(PGBackRestBackupInfo)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestBackupStatus.
+func (in *PGBackRestBackupStatus) DeepCopy() *PGBackRestBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PGBackRestDataSource) DeepCopyInto(out *PGBackRestDataSource) {
 	*out = *in
@@ -578,6 +814,11 @@ func (in *PGBackRestRepo) DeepCopyInto(out *PGBackRestRepo) {
 		*out = new(PGBackRestBackupSchedules)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(PGBackRestRetention)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Azure != nil {
 		in, out := &in.Azure, &out.Azure
 		*out = new(RepoAzure)
@@ -686,108 +927,351 @@ func (in *PGBackRestRestore) DeepCopy() *PGBackRestRestore {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGBackRestScheduledBackupStatus) DeepCopyInto(out *PGBackRestScheduledBackupStatus) {
+func (in *PGBackRestRestoreRequest) DeepCopyInto(out *PGBackRestRestoreRequest) {
 	*out = *in
-	if in.StartTime != nil {
-		in, out := &in.StartTime, &out.StartTime
-		*out = (*in).DeepCopy()
-	}
-	if in.CompletionTime != nil {
-		in, out := &in.CompletionTime, &out.CompletionTime
-		*out = (*in).DeepCopy()
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestScheduledBackupStatus.
-func (in *PGBackRestScheduledBackupStatus) DeepCopy() *PGBackRestScheduledBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestRestoreRequest.
+func (in *PGBackRestRestoreRequest) DeepCopy() *PGBackRestRestoreRequest {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBackRestScheduledBackupStatus)
+	out := new(PGBackRestRestoreRequest)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PGBackRestRestoreRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGBackRestSidecars) DeepCopyInto(out *PGBackRestSidecars) {
+func (in *PGBackRestRestoreRequestList) DeepCopyInto(out *PGBackRestRestoreRequestList) {
 	*out = *in
-	if in.PGBackRest != nil {
-		in, out := &in.PGBackRest, &out.PGBackRest
-		*out = new(Sidecar)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PGBackRestConfig != nil {
-		in, out := &in.PGBackRestConfig, &out.PGBackRestConfig
-		*out = new(Sidecar)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PGBackRestRestoreRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestSidecars.
-func (in *PGBackRestSidecars) DeepCopy() *PGBackRestSidecars {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestRestoreRequestList.
+func (in *PGBackRestRestoreRequestList) DeepCopy() *PGBackRestRestoreRequestList {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBackRestSidecars)
+	out := new(PGBackRestRestoreRequestList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PGBackRestRestoreRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGBackRestStatus) DeepCopyInto(out *PGBackRestStatus) {
+func (in *PGBackRestRestoreRequestSpec) DeepCopyInto(out *PGBackRestRestoreRequestSpec) {
 	*out = *in
-	if in.ManualBackup != nil {
-		in, out := &in.ManualBackup, &out.ManualBackup
-		*out = new(PGBackRestJobStatus)
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(PGBackRestRestoreTarget)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ScheduledBackups != nil {
-		in, out := &in.ScheduledBackups, &out.ScheduledBackups
-		*out = make([]PGBackRestScheduledBackupStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.RepoHost != nil {
-		in, out := &in.RepoHost, &out.RepoHost
-		*out = new(RepoHostStatus)
-		**out = **in
-	}
-	if in.Repos != nil {
-		in, out := &in.Repos, &out.Repos
-		*out = make([]RepoStatus, len(*in))
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Restore != nil {
-		in, out := &in.Restore, &out.Restore
-		*out = new(PGBackRestJobStatus)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestStatus.
-func (in *PGBackRestStatus) DeepCopy() *PGBackRestStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestRestoreRequestSpec.
+func (in *PGBackRestRestoreRequestSpec) DeepCopy() *PGBackRestRestoreRequestSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBackRestStatus)
+	out := new(PGBackRestRestoreRequestSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGBouncerConfiguration) DeepCopyInto(out *PGBouncerConfiguration) {
+func (in *PGBackRestRestoreRequestStatus) DeepCopyInto(out *PGBackRestRestoreRequestStatus) {
 	*out = *in
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = make([]v1.VolumeProjection, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Global != nil {
-		in, out := &in.Global, &out.Global
-		*out = make(map[string]string, len(*in))
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(PGBackRestBackupInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestRestoreRequestStatus.
+func (in *PGBackRestRestoreRequestStatus) DeepCopy() *PGBackRestRestoreRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestRestoreRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestRestoreTarget) DeepCopyInto(out *PGBackRestRestoreTarget) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestRestoreTarget.
+func (in *PGBackRestRestoreTarget) DeepCopy() *PGBackRestRestoreTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestRestoreTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestRetention) DeepCopyInto(out *PGBackRestRetention) {
+	*out = *in
+	if in.Full != nil {
+		in, out := &in.Full, &out.Full
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Differential != nil {
+		in, out := &in.Differential, &out.Differential
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestRetention.
+func (in *PGBackRestRetention) DeepCopy() *PGBackRestRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestScheduledBackupStatus) DeepCopyInto(out *PGBackRestScheduledBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestScheduledBackupStatus.
+func (in *PGBackRestScheduledBackupStatus) DeepCopy() *PGBackRestScheduledBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestScheduledBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestSidecars) DeepCopyInto(out *PGBackRestSidecars) {
+	*out = *in
+	if in.PGBackRest != nil {
+		in, out := &in.PGBackRest, &out.PGBackRest
+		*out = new(Sidecar)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PGBackRestConfig != nil {
+		in, out := &in.PGBackRestConfig, &out.PGBackRestConfig
+		*out = new(Sidecar)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestSidecars.
+func (in *PGBackRestSidecars) DeepCopy() *PGBackRestSidecars {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestSidecars)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestStatus) DeepCopyInto(out *PGBackRestStatus) {
+	*out = *in
+	if in.ManualBackup != nil {
+		in, out := &in.ManualBackup, &out.ManualBackup
+		*out = new(PGBackRestJobStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledBackups != nil {
+		in, out := &in.ScheduledBackups, &out.ScheduledBackups
+		*out = make([]PGBackRestScheduledBackupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RepoHost != nil {
+		in, out := &in.RepoHost, &out.RepoHost
+		*out = new(RepoHostStatus)
+		**out = **in
+	}
+	if in.Repos != nil {
+		in, out := &in.Repos, &out.Repos
+		*out = make([]RepoStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(PGBackRestJobStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestStatus.
+func (in *PGBackRestStatus) DeepCopy() *PGBackRestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBackRestVerification) DeepCopyInto(out *PGBackRestVerification) {
+	*out = *in
+	if in.VolumeClaimSpec != nil {
+		in, out := &in.VolumeClaimSpec, &out.VolumeClaimSpec
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBackRestVerification.
+func (in *PGBackRestVerification) DeepCopy() *PGBackRestVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBackRestVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerAuthenticationSpec) DeepCopyInto(out *PGBouncerAuthenticationSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]PGBouncerHBARuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerAuthenticationSpec.
+func (in *PGBouncerAuthenticationSpec) DeepCopy() *PGBouncerAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerAutoscalingSpec) DeepCopyInto(out *PGBouncerAutoscalingSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetClientConnections != nil {
+		in, out := &in.TargetClientConnections, &out.TargetClientConnections
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetWaitingClients != nil {
+		in, out := &in.TargetWaitingClients, &out.TargetWaitingClients
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerAutoscalingSpec.
+func (in *PGBouncerAutoscalingSpec) DeepCopy() *PGBouncerAutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerAutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerConfiguration) DeepCopyInto(out *PGBouncerConfiguration) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]v1.VolumeProjection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Global != nil {
+		in, out := &in.Global, &out.Global
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
@@ -806,6 +1290,11 @@ func (in *PGBouncerConfiguration) DeepCopyInto(out *PGBouncerConfiguration) {
 			(*out)[key] = val
 		}
 	}
+	if in.PauseTimeout != nil {
+		in, out := &in.PauseTimeout, &out.PauseTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerConfiguration.
@@ -818,6 +1307,72 @@ func (in *PGBouncerConfiguration) DeepCopy() *PGBouncerConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerExporterSpec) DeepCopyInto(out *PGBouncerExporterSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerExporterSpec.
+func (in *PGBouncerExporterSpec) DeepCopy() *PGBouncerExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerHBARuleSpec) DeepCopyInto(out *PGBouncerHBARuleSpec) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerHBARuleSpec.
+func (in *PGBouncerHBARuleSpec) DeepCopy() *PGBouncerHBARuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerHBARuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerMonitoringSpec) DeepCopyInto(out *PGBouncerMonitoringSpec) {
+	*out = *in
+	if in.Exporter != nil {
+		in, out := &in.Exporter, &out.Exporter
+		*out = new(PGBouncerExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerMonitoringSpec.
+func (in *PGBouncerMonitoringSpec) DeepCopy() *PGBouncerMonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerMonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PGBouncerPodSpec) DeepCopyInto(out *PGBouncerPodSpec) {
 	*out = *in
@@ -831,6 +1386,16 @@ func (in *PGBouncerPodSpec) DeepCopyInto(out *PGBouncerPodSpec) {
 		*out = new(v1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(PGBouncerAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(PGBouncerAutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Config.DeepCopyInto(&out.Config)
 	if in.Containers != nil {
 		in, out := &in.Containers, &out.Containers
@@ -854,6 +1419,11 @@ func (in *PGBouncerPodSpec) DeepCopyInto(out *PGBouncerPodSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(PGBouncerReadOnlySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
@@ -864,6 +1434,11 @@ func (in *PGBouncerPodSpec) DeepCopyInto(out *PGBouncerPodSpec) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(PGBouncerMonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.Service != nil {
 		in, out := &in.Service, &out.Service
@@ -891,67 +1466,211 @@ func (in *PGBouncerPodSpec) DeepCopyInto(out *PGBouncerPodSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerPodSpec.
-func (in *PGBouncerPodSpec) DeepCopy() *PGBouncerPodSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerPodSpec.
+func (in *PGBouncerPodSpec) DeepCopy() *PGBouncerPodSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerPodSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerPodStatus) DeepCopyInto(out *PGBouncerPodStatus) {
+	*out = *in
+	if in.PausedUntil != nil {
+		in, out := &in.PausedUntil, &out.PausedUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerPodStatus.
+func (in *PGBouncerPodStatus) DeepCopy() *PGBouncerPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerReadOnlySpec) DeepCopyInto(out *PGBouncerReadOnlySpec) {
+	*out = *in
+	if in.CustomTLSSecret != nil {
+		in, out := &in.CustomTLSSecret, &out.CustomTLSSecret
+		*out = new(v1.SecretProjection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerReadOnlySpec.
+func (in *PGBouncerReadOnlySpec) DeepCopy() *PGBouncerReadOnlySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerReadOnlySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGBouncerSidecars) DeepCopyInto(out *PGBouncerSidecars) {
+	*out = *in
+	if in.PGBouncerConfig != nil {
+		in, out := &in.PGBouncerConfig, &out.PGBouncerConfig
+		*out = new(Sidecar)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerSidecars.
+func (in *PGBouncerSidecars) DeepCopy() *PGBouncerSidecars {
+	if in == nil {
+		return nil
+	}
+	out := new(PGBouncerSidecars)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGMonitorSpec) DeepCopyInto(out *PGMonitorSpec) {
+	*out = *in
+	if in.Exporter != nil {
+		in, out := &in.Exporter, &out.Exporter
+		*out = new(ExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGMonitorSpec.
+func (in *PGMonitorSpec) DeepCopy() *PGMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PGMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PGUpgrade) DeepCopyInto(out *PGUpgrade) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGUpgrade.
+func (in *PGUpgrade) DeepCopy() *PGUpgrade {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBouncerPodSpec)
+	out := new(PGUpgrade)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PGUpgrade) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGBouncerPodStatus) DeepCopyInto(out *PGBouncerPodStatus) {
+func (in *PGUpgradeList) DeepCopyInto(out *PGUpgradeList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PGUpgrade, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerPodStatus.
-func (in *PGBouncerPodStatus) DeepCopy() *PGBouncerPodStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGUpgradeList.
+func (in *PGUpgradeList) DeepCopy() *PGUpgradeList {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBouncerPodStatus)
+	out := new(PGUpgradeList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PGUpgradeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGBouncerSidecars) DeepCopyInto(out *PGBouncerSidecars) {
+func (in *PGUpgradeSpec) DeepCopyInto(out *PGUpgradeSpec) {
 	*out = *in
-	if in.PGBouncerConfig != nil {
-		in, out := &in.PGBouncerConfig, &out.PGBouncerConfig
-		*out = new(Sidecar)
-		(*in).DeepCopyInto(*out)
-	}
+	in.Resources.DeepCopyInto(&out.Resources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGBouncerSidecars.
-func (in *PGBouncerSidecars) DeepCopy() *PGBouncerSidecars {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGUpgradeSpec.
+func (in *PGUpgradeSpec) DeepCopy() *PGUpgradeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PGBouncerSidecars)
+	out := new(PGUpgradeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PGMonitorSpec) DeepCopyInto(out *PGMonitorSpec) {
+func (in *PGUpgradeStatus) DeepCopyInto(out *PGUpgradeStatus) {
 	*out = *in
-	if in.Exporter != nil {
-		in, out := &in.Exporter, &out.Exporter
-		*out = new(ExporterSpec)
-		(*in).DeepCopyInto(*out)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGMonitorSpec.
-func (in *PGMonitorSpec) DeepCopy() *PGMonitorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PGUpgradeStatus.
+func (in *PGUpgradeStatus) DeepCopy() *PGUpgradeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PGMonitorSpec)
+	out := new(PGUpgradeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -980,6 +1699,11 @@ func (in *PatroniSpec) DeepCopyInto(out *PatroniSpec) {
 		*out = new(PatroniSwitchover)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Synchronous != nil {
+		in, out := &in.Synchronous, &out.Synchronous
+		*out = new(PatroniSynchronousReplication)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniSpec.
@@ -1005,6 +1729,11 @@ func (in *PatroniStatus) DeepCopyInto(out *PatroniStatus) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.SynchronousStandbys != nil {
+		in, out := &in.SynchronousStandbys, &out.SynchronousStandbys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniStatus.
@@ -1037,6 +1766,31 @@ func (in *PatroniSwitchover) DeepCopy() *PatroniSwitchover {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatroniSynchronousReplication) DeepCopyInto(out *PatroniSynchronousReplication) {
+	*out = *in
+	if in.StandbyCount != nil {
+		in, out := &in.StandbyCount, &out.StandbyCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InstanceSets != nil {
+		in, out := &in.InstanceSets, &out.InstanceSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatroniSynchronousReplication.
+func (in *PatroniSynchronousReplication) DeepCopy() *PatroniSynchronousReplication {
+	if in == nil {
+		return nil
+	}
+	out := new(PatroniSynchronousReplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresAdditionalConfig) DeepCopyInto(out *PostgresAdditionalConfig) {
 	*out = *in
@@ -1047,6 +1801,13 @@ func (in *PostgresAdditionalConfig) DeepCopyInto(out *PostgresAdditionalConfig)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresAdditionalConfig.
@@ -1059,6 +1820,28 @@ func (in *PostgresAdditionalConfig) DeepCopy() *PostgresAdditionalConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresAuthenticationSpec) DeepCopyInto(out *PostgresAuthenticationSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]PostgresHBARuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresAuthenticationSpec.
+func (in *PostgresAuthenticationSpec) DeepCopy() *PostgresAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresCluster) DeepCopyInto(out *PostgresCluster) {
 	*out = *in
@@ -1169,6 +1952,11 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 		*out = new(DataSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(PostgresAuthenticationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Backups.DeepCopyInto(&out.Backups)
 	if in.CustomTLSSecret != nil {
 		in, out := &in.CustomTLSSecret, &out.CustomTLSSecret
@@ -1183,7 +1971,7 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 	if in.DatabaseInitSQL != nil {
 		in, out := &in.DatabaseInitSQL, &out.DatabaseInitSQL
 		*out = new(DatabaseInitSQL)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.DisableDefaultPodScheduling != nil {
 		in, out := &in.DisableDefaultPodScheduling, &out.DisableDefaultPodScheduling
@@ -1264,6 +2052,23 @@ func (in *PostgresClusterSpec) DeepCopyInto(out *PostgresClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UserManagement != nil {
+		in, out := &in.UserManagement, &out.UserManagement
+		*out = new(PostgresUserManagementSpec)
+		**out = **in
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresDatabaseSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DatabaseManagement != nil {
+		in, out := &in.DatabaseManagement, &out.DatabaseManagement
+		*out = new(PostgresDatabaseManagementSpec)
+		**out = **in
+	}
 	in.Config.DeepCopyInto(&out.Config)
 }
 
@@ -1272,52 +2077,225 @@ func (in *PostgresClusterSpec) DeepCopy() *PostgresClusterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresClusterSpec)
+	out := new(PostgresClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
+	*out = *in
+	if in.InstanceSets != nil {
+		in, out := &in.InstanceSets, &out.InstanceSets
+		*out = make([]PostgresInstanceSetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Patroni.DeepCopyInto(&out.Patroni)
+	if in.PGBackRest != nil {
+		in, out := &in.PGBackRest, &out.PGBackRest
+		*out = new(PGBackRestStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeSnapshots != nil {
+		in, out := &in.VolumeSnapshots, &out.VolumeSnapshots
+		*out = new(VolumeSnapshotsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostgresUpgrade != nil {
+		in, out := &in.PostgresUpgrade, &out.PostgresUpgrade
+		*out = new(PostgresUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Proxy.DeepCopyInto(&out.Proxy)
+	if in.UserInterface != nil {
+		in, out := &in.UserInterface, &out.UserInterface
+		*out = new(PostgresUserInterfaceStatus)
+		**out = **in
+	}
+	if in.UserPasswords != nil {
+		in, out := &in.UserPasswords, &out.UserPasswords
+		*out = make([]PostgresUserPasswordStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Monitoring = in.Monitoring
+	if in.DatabaseInitSQL != nil {
+		in, out := &in.DatabaseInitSQL, &out.DatabaseInitSQL
+		*out = new(string)
+		**out = **in
+	}
+	if in.DatabaseInitSQLMigrations != nil {
+		in, out := &in.DatabaseInitSQLMigrations, &out.DatabaseInitSQLMigrations
+		*out = make([]DatabaseInitSQLMigrationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresClusterStatus.
+func (in *PostgresClusterStatus) DeepCopy() *PostgresClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseManagementSpec) DeepCopyInto(out *PostgresDatabaseManagementSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseManagementSpec.
+func (in *PostgresDatabaseManagementSpec) DeepCopy() *PostgresDatabaseManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresDatabaseSpec) DeepCopyInto(out *PostgresDatabaseSpec) {
+	*out = *in
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]PostgresExtensionSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schemas != nil {
+		in, out := &in.Schemas, &out.Schemas
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresDatabaseSpec.
+func (in *PostgresDatabaseSpec) DeepCopy() *PostgresDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresExtensionSpec) DeepCopyInto(out *PostgresExtensionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresExtensionSpec.
+func (in *PostgresExtensionSpec) DeepCopy() *PostgresExtensionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresExtensionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresHBARuleSpec) DeepCopyInto(out *PostgresHBARuleSpec) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresHBARuleSpec.
+func (in *PostgresHBARuleSpec) DeepCopy() *PostgresHBARuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresHBARuleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostgresClusterStatus) DeepCopyInto(out *PostgresClusterStatus) {
+func (in *PostgresInstanceRolloutStrategy) DeepCopyInto(out *PostgresInstanceRolloutStrategy) {
 	*out = *in
-	if in.InstanceSets != nil {
-		in, out := &in.InstanceSets, &out.InstanceSets
-		*out = make([]PostgresInstanceSetStatus, len(*in))
-		copy(*out, *in)
-	}
-	in.Patroni.DeepCopyInto(&out.Patroni)
-	if in.PGBackRest != nil {
-		in, out := &in.PGBackRest, &out.PGBackRest
-		*out = new(PGBackRestStatus)
-		(*in).DeepCopyInto(*out)
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int32)
+		**out = **in
 	}
-	out.Proxy = in.Proxy
-	if in.UserInterface != nil {
-		in, out := &in.UserInterface, &out.UserInterface
-		*out = new(PostgresUserInterfaceStatus)
+	if in.PauseAfter != nil {
+		in, out := &in.PauseAfter, &out.PauseAfter
+		*out = new(int32)
 		**out = **in
 	}
-	out.Monitoring = in.Monitoring
-	if in.DatabaseInitSQL != nil {
-		in, out := &in.DatabaseInitSQL, &out.DatabaseInitSQL
-		*out = new(string)
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.SwitchoverPrimary != nil {
+		in, out := &in.SwitchoverPrimary, &out.SwitchoverPrimary
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceRolloutStrategy.
+func (in *PostgresInstanceRolloutStrategy) DeepCopy() *PostgresInstanceRolloutStrategy {
+	if in == nil {
+		return nil
 	}
+	out := new(PostgresInstanceRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresClusterStatus.
-func (in *PostgresClusterStatus) DeepCopy() *PostgresClusterStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresInstanceSetRolloutStatus) DeepCopyInto(out *PostgresInstanceSetRolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceSetRolloutStatus.
+func (in *PostgresInstanceSetRolloutStatus) DeepCopy() *PostgresInstanceSetRolloutStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PostgresClusterStatus)
+	out := new(PostgresInstanceSetRolloutStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1359,6 +2337,11 @@ func (in *PostgresInstanceSetSpec) DeepCopyInto(out *PostgresInstanceSetSpec) {
 		**out = **in
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(PostgresInstanceRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Sidecars != nil {
 		in, out := &in.Sidecars, &out.Sidecars
 		*out = new(InstanceSidecars)
@@ -1398,6 +2381,11 @@ func (in *PostgresInstanceSetSpec) DeepCopy() *PostgresInstanceSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresInstanceSetStatus) DeepCopyInto(out *PostgresInstanceSetStatus) {
 	*out = *in
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(PostgresInstanceSetRolloutStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresInstanceSetStatus.
@@ -1410,9 +2398,39 @@ func (in *PostgresInstanceSetStatus) DeepCopy() *PostgresInstanceSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresPasswordSecretReference) DeepCopyInto(out *PostgresPasswordSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresPasswordSecretReference.
+func (in *PostgresPasswordSecretReference) DeepCopy() *PostgresPasswordSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresPasswordSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresPasswordSpec) DeepCopyInto(out *PostgresPasswordSpec) {
 	*out = *in
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RotationGracePeriod != nil {
+		in, out := &in.RotationGracePeriod, &out.RotationGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(PostgresPasswordSecretReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresPasswordSpec.
@@ -1448,7 +2466,7 @@ func (in *PostgresProxySpec) DeepCopy() *PostgresProxySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresProxyStatus) DeepCopyInto(out *PostgresProxyStatus) {
 	*out = *in
-	out.PGBouncer = in.PGBouncer
+	in.PGBouncer.DeepCopyInto(&out.PGBouncer)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresProxyStatus.
@@ -1481,6 +2499,44 @@ func (in *PostgresStandbySpec) DeepCopy() *PostgresStandbySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUpgradeStatus) DeepCopyInto(out *PostgresUpgradeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUpgradeStatus.
+func (in *PostgresUpgradeStatus) DeepCopy() *PostgresUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserCertificateSpec) DeepCopyInto(out *PostgresUserCertificateSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserCertificateSpec.
+func (in *PostgresUserCertificateSpec) DeepCopy() *PostgresUserCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresUserInterfaceStatus) DeepCopyInto(out *PostgresUserInterfaceStatus) {
 	*out = *in
@@ -1497,6 +2553,60 @@ func (in *PostgresUserInterfaceStatus) DeepCopy() *PostgresUserInterfaceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserManagementSpec) DeepCopyInto(out *PostgresUserManagementSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserManagementSpec.
+func (in *PostgresUserManagementSpec) DeepCopy() *PostgresUserManagementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserManagementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserPasswordStatus) DeepCopyInto(out *PostgresUserPasswordStatus) {
+	*out = *in
+	if in.RotatedAt != nil {
+		in, out := &in.RotatedAt, &out.RotatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserPasswordStatus.
+func (in *PostgresUserPasswordStatus) DeepCopy() *PostgresUserPasswordStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserPasswordStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresUserPrivilegesSpec) DeepCopyInto(out *PostgresUserPrivilegesSpec) {
+	*out = *in
+	if in.Schemas != nil {
+		in, out := &in.Schemas, &out.Schemas
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresUserPrivilegesSpec.
+func (in *PostgresUserPrivilegesSpec) DeepCopy() *PostgresUserPrivilegesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresUserPrivilegesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresUserSpec) DeepCopyInto(out *PostgresUserSpec) {
 	*out = *in
@@ -1505,9 +2615,26 @@ func (in *PostgresUserSpec) DeepCopyInto(out *PostgresUserSpec) {
 		*out = make([]PostgresIdentifier, len(*in))
 		copy(*out, *in)
 	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]PostgresIdentifier, len(*in))
+		copy(*out, *in)
+	}
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]PostgresUserPrivilegesSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Password != nil {
 		in, out := &in.Password, &out.Password
 		*out = new(PostgresPasswordSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(PostgresUserCertificateSpec)
 		**out = **in
 	}
 }
@@ -1568,10 +2695,56 @@ func (in *RepoHostStatus) DeepCopy() *RepoHostStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepoInventory) DeepCopyInto(out *RepoInventory) {
+	*out = *in
+	if in.UpdateTime != nil {
+		in, out := &in.UpdateTime, &out.UpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]PGBackRestBackupInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecoveryWindow != nil {
+		in, out := &in.RecoveryWindow, &out.RecoveryWindow
+		*out = new(RepoRecoveryWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeCapacity != nil {
+		in, out := &in.VolumeCapacity, &out.VolumeCapacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.VolumeUsed != nil {
+		in, out := &in.VolumeUsed, &out.VolumeUsed
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoInventory.
+func (in *RepoInventory) DeepCopy() *RepoInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(RepoInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoPVC) DeepCopyInto(out *RepoPVC) {
 	*out = *in
 	in.VolumeClaimSpec.DeepCopyInto(&out.VolumeClaimSpec)
+	if in.UsageThreshold != nil {
+		in, out := &in.UsageThreshold, &out.UsageThreshold
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoPVC.
@@ -1584,6 +2757,29 @@ func (in *RepoPVC) DeepCopy() *RepoPVC {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepoRecoveryWindow) DeepCopyInto(out *RepoRecoveryWindow) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoRecoveryWindow.
+func (in *RepoRecoveryWindow) DeepCopy() *RepoRecoveryWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(RepoRecoveryWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoS3) DeepCopyInto(out *RepoS3) {
 	*out = *in
@@ -1602,6 +2798,16 @@ func (in *RepoS3) DeepCopy() *RepoS3 {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoStatus) DeepCopyInto(out *RepoStatus) {
 	*out = *in
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(RepoInventory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(RepoVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoStatus.
@@ -1614,6 +2820,33 @@ func (in *RepoStatus) DeepCopy() *RepoStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepoVerificationStatus) DeepCopyInto(out *RepoVerificationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastPassedTime != nil {
+		in, out := &in.LastPassedTime, &out.LastPassedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoVerificationStatus.
+func (in *RepoVerificationStatus) DeepCopy() *RepoVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepoVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in SchemalessObject) DeepCopyInto(out *SchemalessObject) {
 	{
@@ -1687,3 +2920,71 @@ func (in *UserInterfaceSpec) DeepCopy() *UserInterfaceSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSnapshotSetStatus) DeepCopyInto(out *VolumeSnapshotSetStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotSetStatus.
+func (in *VolumeSnapshotSetStatus) DeepCopy() *VolumeSnapshotSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSnapshotSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSnapshots) DeepCopyInto(out *VolumeSnapshots) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(string)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshots.
+func (in *VolumeSnapshots) DeepCopy() *VolumeSnapshots {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSnapshots)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSnapshotsStatus) DeepCopyInto(out *VolumeSnapshotsStatus) {
+	*out = *in
+	if in.Latest != nil {
+		in, out := &in.Latest, &out.Latest
+		*out = new(VolumeSnapshotSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotsStatus.
+func (in *VolumeSnapshotsStatus) DeepCopy() *VolumeSnapshotsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSnapshotsStatus)
+	in.DeepCopyInto(out)
+	return out
+}